@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,17 +11,21 @@ import (
 	"time"
 
 	"task-manager/internal/config"
+	"task-manager/internal/domain"
 	httpHandler "task-manager/internal/http"
+	"task-manager/internal/notify"
 	"task-manager/internal/repo"
 	"task-manager/internal/service"
-
-	_ "modernc.org/sqlite"
+	"task-manager/internal/storage"
 )
 
 func main() {
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
 
-	db, err := sql.Open("sqlite", cfg.DatabasePath)
+	db, err := repo.Open(cfg.DatabasePath)
 	if err != nil {
 		log.Fatalf("Failed to open database: %v", err)
 	}
@@ -34,15 +37,79 @@ func main() {
 
 	taskRepo := repo.NewTaskRepository(db)
 	categoryRepo := repo.NewCategoryRepository(db)
-	taskService := service.NewTaskService(taskRepo, categoryRepo)
-	categoryService := service.NewCategoryService(categoryRepo)
+	userRepo := repo.NewUserRepository(db)
+	commentRepo := repo.NewCommentRepository(db)
+	attachmentRepo := repo.NewAttachmentRepository(db)
+	webhookRepo := repo.NewWebhookRepository(db)
+	viewRepo := repo.NewViewRepository(db)
+	statusRepo := repo.NewStatusRepository(db)
+	reminderRepo := repo.NewReminderRepository(db)
+	worklogRepo := repo.NewWorklogRepository(db)
+
+	blobStore, err := storage.NewLocalBlobStore(cfg.AttachmentDir)
+	if err != nil {
+		log.Fatalf("Failed to open attachment storage: %v", err)
+	}
+
+	webhookService := service.NewWebhookService(webhookRepo, service.NewWebhookClient(cfg.WebhookTimeout), domain.WebhookRetryPolicy{
+		MaxAttempts: cfg.WebhookMaxAttempts,
+		BaseDelay:   cfg.WebhookRetryBase,
+		MaxDelay:    cfg.WebhookRetryMax,
+	})
+
+	// Every change is queued for the webhooks that subscribe to it
+	events := service.NewEventBus()
+	events.Subscribe(func(event domain.Event) {
+		if err := webhookService.Enqueue(event); err != nil {
+			log.Printf("Failed to queue webhook deliveries for %s: %v", event.Type, err)
+		}
+	})
+	eventStream := service.NewEventStream(cfg.EventStreamBuffer)
+	events.Subscribe(eventStream.Publish)
+
+	// The workflow in effect is shared by the services that check statuses
+	workflow := domain.NewWorkflowStore(domain.DefaultWorkflow())
+
+	taskService := service.NewTaskService(taskRepo, categoryRepo, workflow, events)
+	categoryService := service.NewCategoryService(categoryRepo, events)
+	authService := service.NewAuthService(userRepo, cfg.AuthSecret, cfg.TokenTTL, cfg.AdminEmails)
+	commentService := service.NewCommentService(commentRepo, taskRepo)
+	attachmentService := service.NewAttachmentService(attachmentRepo, taskRepo, blobStore, domain.AttachmentLimits{
+		MaxSize:      cfg.AttachmentMaxSize,
+		AllowedTypes: cfg.AttachmentAllowedTypes,
+	})
+	viewService := service.NewViewService(viewRepo, workflow)
+	worklogService := service.NewWorklogService(worklogRepo, taskRepo)
+	statusService := service.NewStatusService(statusRepo, workflow)
+	if err := statusService.LoadWorkflow(); err != nil {
+		log.Fatalf("Failed to load workflow: %v", err)
+	}
+
+	notifier, err := newNotifier(cfg)
+	if err != nil {
+		log.Fatalf("Failed to set up notifier: %v", err)
+	}
+	reminderService := service.NewReminderService(reminderRepo, notifier, cfg.ReminderLeadTime, domain.ReminderRetryPolicy{
+		MaxAttempts: cfg.ReminderMaxAttempts,
+		BaseDelay:   cfg.ReminderRetryBase,
+		MaxDelay:    cfg.ReminderRetryMax,
+	})
 
-	handler := httpHandler.NewHandler(taskService, categoryService)
+	// Deleting tasks queues their attachment blobs for removal
+	stopJanitors := make(chan struct{})
+	go purgeBlobs(attachmentService, cfg.AttachmentCleanupInterval, stopJanitors)
+	go purgeTrash(taskService, categoryService, cfg.TrashRetention, cfg.TrashPurgeInterval, stopJanitors)
+	go deliverWebhooks(webhookService, cfg.WebhookDeliveryInterval, stopJanitors)
+	go sendReminders(reminderService, cfg.ReminderInterval, stopJanitors)
+
+	handler := httpHandler.NewHandler(taskService, categoryService, authService, commentService, attachmentService, webhookService, viewService, statusService, worklogService, eventStream)
 	httpServer := httpHandler.NewServer(handler)
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", cfg.Port),
 		Handler: httpServer,
 	}
+	// Shutdown waits for open requests, so event streams have to end first
+	server.RegisterOnShutdown(eventStream.Close)
 
 	go func() {
 		log.Printf("Server starting on port %d", cfg.Port)
@@ -56,6 +123,7 @@ func main() {
 	<-quit
 
 	log.Println("Server shutting down...")
+	close(stopJanitors)
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -64,3 +132,114 @@ func main() {
 	}
 	log.Println("Server exited")
 }
+
+// purgeBlobs removes the blobs of deleted attachments every interval until stop
+// is closed
+func purgeBlobs(attachmentService service.AttachmentService, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if purged, err := attachmentService.PurgeDeletedBlobs(); err != nil {
+			log.Printf("Failed to purge attachment blobs: %v", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d attachment blobs", purged)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// purgeTrash permanently deletes tasks and categories that have been in the
+// trash for longer than retention, every interval until stop is closed
+func purgeTrash(taskService service.TaskService, categoryService service.CategoryService, retention, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		cutoff := time.Now().Add(-retention)
+		if purged, err := taskService.PurgeExpiredTrash(cutoff); err != nil {
+			log.Printf("Failed to purge trashed tasks: %v", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d trashed tasks", purged)
+		}
+		if purged, err := categoryService.PurgeExpiredTrash(cutoff); err != nil {
+			log.Printf("Failed to purge trashed categories: %v", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d trashed categories", purged)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// deliverWebhooks sends the webhook deliveries that are due every interval
+// until stop is closed
+func deliverWebhooks(webhookService service.WebhookService, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := webhookService.DeliverDue(); err != nil {
+			log.Printf("Failed to deliver webhooks: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// sendReminders sends the due-date reminders that are due every interval
+// until stop is closed
+func sendReminders(reminderService service.ReminderService, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if sent, err := reminderService.SendDue(); err != nil {
+			log.Printf("Failed to send reminders: %v", err)
+		} else if sent > 0 {
+			log.Printf("Sent %d reminders", sent)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// newNotifier returns the notifier selected by the configuration
+func newNotifier(cfg *config.Config) (notify.Notifier, error) {
+	switch cfg.Notifier {
+	case "smtp":
+		if cfg.SMTPHost == "" {
+			return nil, fmt.Errorf("SMTP_HOST is required for the smtp notifier")
+		}
+		return notify.NewSMTPNotifier(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom), nil
+	case "log":
+		if cfg.NotifyLogPath == "" {
+			return notify.NewLogNotifier(os.Stderr), nil
+		}
+		// The file stays open for the life of the process
+		file, err := os.OpenFile(cfg.NotifyLogPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open notification log: %w", err)
+		}
+		return notify.NewLogNotifier(file), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier %q", cfg.Notifier)
+	}
+}
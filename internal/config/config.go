@@ -1,16 +1,62 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	Port         int
 	DatabasePath string
+	AuthSecret   string
+	TokenTTL     time.Duration
+	// AdminEmails lists the users who may change the shared workflow
+	AdminEmails []string
+
+	// Attachments
+	AttachmentDir             string
+	AttachmentMaxSize         int64
+	AttachmentAllowedTypes    []string
+	AttachmentCleanupInterval time.Duration
+
+	// Trash
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+
+	// Webhooks
+	WebhookMaxAttempts      int
+	WebhookRetryBase        time.Duration
+	WebhookRetryMax         time.Duration
+	WebhookTimeout          time.Duration
+	WebhookDeliveryInterval time.Duration
+
+	// EventStreamBuffer is how many recent events a reconnecting client can
+	// resume from
+	EventStreamBuffer int
+
+	// Reminders
+	ReminderLeadTime    time.Duration
+	ReminderInterval    time.Duration
+	ReminderMaxAttempts int
+	ReminderRetryBase   time.Duration
+	ReminderRetryMax    time.Duration
+	// Notifier is "log" to write reminders to NotifyLogPath, or to standard
+	// error when that is empty, and "smtp" to mail them
+	Notifier      string
+	NotifyLogPath string
+	SMTPHost      string
+	SMTPPort      int
+	SMTPUsername  string
+	SMTPPassword  string
+	SMTPFrom      string
 }
 
-func Load() *Config {
+// Load reads the configuration from the environment. It fails when
+// AUTH_SECRET is unset, unless AUTH_DEV_MODE allows the development secret.
+func Load() (*Config, error) {
 	port := 8080
 	if portStr := os.Getenv("PORT"); portStr != "" {
 		if p, err := strconv.Atoi(portStr); err == nil {
@@ -23,8 +69,190 @@ func Load() *Config {
 		databasePath = path
 	}
 
-	return &Config{
-		Port:         port,
-		DatabasePath: databasePath,
+	// The development secret is public, so tokens signed with it can be
+	// forged; it is only used when explicitly asked for
+	authSecret := os.Getenv("AUTH_SECRET")
+	if authSecret == "" {
+		devMode, _ := strconv.ParseBool(os.Getenv("AUTH_DEV_MODE"))
+		if !devMode {
+			return nil, errors.New("AUTH_SECRET is required; set AUTH_DEV_MODE=true to use the development secret")
+		}
+		authSecret = "dev-secret-change-me"
+	}
+
+	tokenTTL := 24 * time.Hour
+	if ttlStr := os.Getenv("AUTH_TOKEN_TTL"); ttlStr != "" {
+		if ttl, err := time.ParseDuration(ttlStr); err == nil && ttl > 0 {
+			tokenTTL = ttl
+		}
+	}
+
+	attachmentDir := "attachments"
+	if dir := os.Getenv("ATTACHMENT_DIR"); dir != "" {
+		attachmentDir = dir
+	}
+
+	attachmentMaxSize := int64(10 << 20)
+	if sizeStr := os.Getenv("ATTACHMENT_MAX_SIZE"); sizeStr != "" {
+		if size, err := strconv.ParseInt(sizeStr, 10, 64); err == nil && size > 0 {
+			attachmentMaxSize = size
+		}
+	}
+
+	var adminEmails []string
+	if emails := os.Getenv("ADMIN_EMAILS"); emails != "" {
+		adminEmails = strings.Split(emails, ",")
+	}
+
+	attachmentAllowedTypes := []string{"image/*", "application/pdf", "text/plain", "text/markdown", "text/csv"}
+	if types := os.Getenv("ATTACHMENT_ALLOWED_TYPES"); types != "" {
+		attachmentAllowedTypes = strings.Split(types, ",")
+	}
+
+	attachmentCleanupInterval := time.Minute
+	if intervalStr := os.Getenv("ATTACHMENT_CLEANUP_INTERVAL"); intervalStr != "" {
+		if interval, err := time.ParseDuration(intervalStr); err == nil && interval > 0 {
+			attachmentCleanupInterval = interval
+		}
+	}
+
+	trashRetention := 30 * 24 * time.Hour
+	if retentionStr := os.Getenv("TRASH_RETENTION"); retentionStr != "" {
+		if retention, err := time.ParseDuration(retentionStr); err == nil && retention > 0 {
+			trashRetention = retention
+		}
+	}
+
+	trashPurgeInterval := time.Hour
+	if intervalStr := os.Getenv("TRASH_PURGE_INTERVAL"); intervalStr != "" {
+		if interval, err := time.ParseDuration(intervalStr); err == nil && interval > 0 {
+			trashPurgeInterval = interval
+		}
+	}
+
+	webhookMaxAttempts := 6
+	if attemptsStr := os.Getenv("WEBHOOK_MAX_ATTEMPTS"); attemptsStr != "" {
+		if attempts, err := strconv.Atoi(attemptsStr); err == nil && attempts > 0 {
+			webhookMaxAttempts = attempts
+		}
+	}
+
+	webhookRetryBase := 30 * time.Second
+	if baseStr := os.Getenv("WEBHOOK_RETRY_BASE"); baseStr != "" {
+		if base, err := time.ParseDuration(baseStr); err == nil && base > 0 {
+			webhookRetryBase = base
+		}
+	}
+
+	webhookRetryMax := 6 * time.Hour
+	if maxStr := os.Getenv("WEBHOOK_RETRY_MAX"); maxStr != "" {
+		if max, err := time.ParseDuration(maxStr); err == nil && max > 0 {
+			webhookRetryMax = max
+		}
+	}
+
+	webhookTimeout := 10 * time.Second
+	if timeoutStr := os.Getenv("WEBHOOK_TIMEOUT"); timeoutStr != "" {
+		if timeout, err := time.ParseDuration(timeoutStr); err == nil && timeout > 0 {
+			webhookTimeout = timeout
+		}
+	}
+
+	webhookDeliveryInterval := 5 * time.Second
+	if intervalStr := os.Getenv("WEBHOOK_DELIVERY_INTERVAL"); intervalStr != "" {
+		if interval, err := time.ParseDuration(intervalStr); err == nil && interval > 0 {
+			webhookDeliveryInterval = interval
+		}
+	}
+
+	eventStreamBuffer := 1000
+	if bufferStr := os.Getenv("EVENT_STREAM_BUFFER"); bufferStr != "" {
+		if buffer, err := strconv.Atoi(bufferStr); err == nil && buffer > 0 {
+			eventStreamBuffer = buffer
+		}
+	}
+
+	reminderLeadTime := 24 * time.Hour
+	if leadStr := os.Getenv("REMINDER_LEAD_TIME"); leadStr != "" {
+		if lead, err := time.ParseDuration(leadStr); err == nil && lead >= 0 {
+			reminderLeadTime = lead
+		}
 	}
+
+	reminderInterval := time.Minute
+	if intervalStr := os.Getenv("REMINDER_INTERVAL"); intervalStr != "" {
+		if interval, err := time.ParseDuration(intervalStr); err == nil && interval > 0 {
+			reminderInterval = interval
+		}
+	}
+
+	reminderMaxAttempts := 5
+	if attemptsStr := os.Getenv("REMINDER_MAX_ATTEMPTS"); attemptsStr != "" {
+		if attempts, err := strconv.Atoi(attemptsStr); err == nil && attempts > 0 {
+			reminderMaxAttempts = attempts
+		}
+	}
+
+	reminderRetryBase := time.Minute
+	if baseStr := os.Getenv("REMINDER_RETRY_BASE"); baseStr != "" {
+		if base, err := time.ParseDuration(baseStr); err == nil && base > 0 {
+			reminderRetryBase = base
+		}
+	}
+
+	reminderRetryMax := time.Hour
+	if maxStr := os.Getenv("REMINDER_RETRY_MAX"); maxStr != "" {
+		if max, err := time.ParseDuration(maxStr); err == nil && max > 0 {
+			reminderRetryMax = max
+		}
+	}
+
+	notifier := "log"
+	if name := os.Getenv("NOTIFIER"); name != "" {
+		notifier = name
+	}
+
+	smtpPort := 587
+	if portStr := os.Getenv("SMTP_PORT"); portStr != "" {
+		if p, err := strconv.Atoi(portStr); err == nil && p > 0 {
+			smtpPort = p
+		}
+	}
+
+	smtpFrom := "tasks@localhost"
+	if from := os.Getenv("SMTP_FROM"); from != "" {
+		smtpFrom = from
+	}
+
+	return &Config{
+		Port:                      port,
+		DatabasePath:              databasePath,
+		AuthSecret:                authSecret,
+		TokenTTL:                  tokenTTL,
+		AdminEmails:               adminEmails,
+		AttachmentDir:             attachmentDir,
+		AttachmentMaxSize:         attachmentMaxSize,
+		AttachmentAllowedTypes:    attachmentAllowedTypes,
+		AttachmentCleanupInterval: attachmentCleanupInterval,
+		TrashRetention:            trashRetention,
+		TrashPurgeInterval:        trashPurgeInterval,
+		WebhookMaxAttempts:        webhookMaxAttempts,
+		WebhookRetryBase:          webhookRetryBase,
+		WebhookRetryMax:           webhookRetryMax,
+		WebhookTimeout:            webhookTimeout,
+		WebhookDeliveryInterval:   webhookDeliveryInterval,
+		EventStreamBuffer:         eventStreamBuffer,
+		ReminderLeadTime:          reminderLeadTime,
+		ReminderInterval:          reminderInterval,
+		ReminderMaxAttempts:       reminderMaxAttempts,
+		ReminderRetryBase:         reminderRetryBase,
+		ReminderRetryMax:          reminderRetryMax,
+		Notifier:                  notifier,
+		NotifyLogPath:             os.Getenv("NOTIFY_LOG_PATH"),
+		SMTPHost:                  os.Getenv("SMTP_HOST"),
+		SMTPPort:                  smtpPort,
+		SMTPUsername:              os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:              os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:                  smtpFrom,
+	}, nil
 }
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAttachmentLimits_Allows(t *testing.T) {
+	limits := AttachmentLimits{AllowedTypes: []string{"image/*", " application/pdf", "text/plain"}}
+
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{contentType: "image/png", want: true},
+		{contentType: "IMAGE/JPEG", want: true},
+		{contentType: "application/pdf", want: true},
+		{contentType: "text/plain; charset=utf-8", want: true},
+		{contentType: "text/html", want: false},
+		{contentType: "imagex/png", want: false},
+		{contentType: "application/x-msdownload", want: false},
+		{contentType: "not a type", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			if got := limits.Allows(tt.contentType); got != tt.want {
+				t.Errorf("Allows(%q) = %v, want %v", tt.contentType, got, tt.want)
+			}
+		})
+	}
+
+	if !(AttachmentLimits{}).Allows("application/octet-stream") {
+		t.Error("empty AllowedTypes should allow every type")
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "report.pdf", want: "report.pdf"},
+		{name: "../../etc/passwd", want: "passwd"},
+		{name: `C:\Users\me\shot.png`, want: "shot.png"},
+		{name: "say \"hi\"\r\n.txt", want: "say hi.txt"},
+		{name: "", want: "file"},
+		{name: "..", want: "file"},
+		{name: "/", want: "file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeFilename(tt.name); got != tt.want {
+				t.Errorf("SanitizeFilename(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	long := SanitizeFilename(strings.Repeat("a", 300) + ".png")
+	if len(long) != 255 || !strings.HasSuffix(long, ".png") {
+		t.Errorf("long filename should be cut to 255 bytes keeping the extension, got %d bytes", len(long))
+	}
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxBulkTasks caps how many tasks one bulk operation may touch
+const MaxBulkTasks = 500
+
+var ErrBulkRolledBack = errors.New("bulk operation failed and was rolled back")
+
+// BulkAction is the change a bulk operation applies to every selected task
+type BulkAction string
+
+const (
+	BulkUpdate BulkAction = "update"
+	BulkDelete BulkAction = "delete"
+)
+
+// BulkTaskRequest applies one update or delete to a list of tasks, selected
+// either by ID or by filters
+type BulkTaskRequest struct {
+	Action  BulkAction         `json:"action"`
+	IDs     []int64            `json:"ids,omitempty"`
+	Filters *TaskFilters       `json:"filters,omitempty"`
+	Update  *UpdateTaskRequest `json:"update,omitempty"`
+	// Subtasks controls what a delete does with subtasks, as for a single
+	// delete. It defaults to reparent.
+	Subtasks SubtaskDeleteMode `json:"subtasks,omitempty"`
+}
+
+// Validate checks the request, whose statuses must be part of workflow
+func (r *BulkTaskRequest) Validate(workflow *Workflow) error {
+	switch r.Action {
+	case BulkUpdate:
+		if r.Update == nil {
+			return errors.New("update is required for the update action")
+		}
+		if r.Update.Scope != "" || r.Update.RRule != nil {
+			return errors.New("recurrence cannot be changed in bulk")
+		}
+		if err := r.Update.Validate(workflow); err != nil {
+			return err
+		}
+	case BulkDelete:
+		if r.Update != nil {
+			return errors.New("update is not allowed for the delete action")
+		}
+		if r.Subtasks != "" && !r.Subtasks.IsValid() {
+			return errors.New("invalid subtasks mode: must be reparent or cascade")
+		}
+	default:
+		return errors.New("invalid action: must be update or delete")
+	}
+
+	if (len(r.IDs) == 0) == (r.Filters == nil) {
+		return errors.New("exactly one of ids or filters is required")
+	}
+	if r.Filters != nil {
+		return r.Filters.Validate(workflow)
+	}
+	if len(r.IDs) > MaxBulkTasks {
+		return fmt.Errorf("at most %d tasks can be changed at once", MaxBulkTasks)
+	}
+	seen := make(map[int64]bool, len(r.IDs))
+	for _, id := range r.IDs {
+		if id <= 0 {
+			return fmt.Errorf("invalid task id: %d", id)
+		}
+		if seen[id] {
+			return fmt.Errorf("duplicate task id: %d", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// BulkItemStatus is the outcome of a bulk operation for one task
+type BulkItemStatus string
+
+const (
+	BulkItemUpdated BulkItemStatus = "updated"
+	BulkItemDeleted BulkItemStatus = "deleted"
+	BulkItemFailed  BulkItemStatus = "failed"
+	// BulkItemRolledBack marks a task whose change succeeded but was undone
+	// because another task in the same operation failed
+	BulkItemRolledBack BulkItemStatus = "rolled_back"
+)
+
+// BulkItemResult reports what a bulk operation did to one task
+type BulkItemResult struct {
+	ID     int64          `json:"id"`
+	Status BulkItemStatus `json:"status"`
+	Error  string         `json:"error,omitempty"`
+	Task   *Task          `json:"task,omitempty"`
+}
+
+// BulkTaskResult is the per-task report of a bulk operation. Applied is false
+// when any task failed, in which case no change was kept.
+type BulkTaskResult struct {
+	Applied bool             `json:"applied"`
+	Results []BulkItemResult `json:"results"`
+}
@@ -9,12 +9,15 @@ var ErrCategoryNotFound = errors.New("category not found")
 
 // Category represents a task category
 type Category struct {
-	ID          int64     `json:"id"`
-	Name        string    `json:"name"`
-	Description *string   `json:"description,omitempty"`
-	Color       *string   `json:"color,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"user_id"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description,omitempty"`
+	Color       *string    `json:"color,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	Version     int64      `json:"version"`
 }
 
 // CreateCategoryRequest represents the request to create a new category
@@ -29,6 +32,9 @@ type UpdateCategoryRequest struct {
 	Name        *string `json:"name,omitempty"`
 	Description *string `json:"description,omitempty"`
 	Color       *string `json:"color,omitempty"`
+	// ExpectedVersion makes the update fail with ErrVersionConflict unless
+	// the category is still at this version. It is set from If-Match.
+	ExpectedVersion *int64 `json:"-"`
 }
 
 // Validate validates the Category struct
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrNotCommentAuthor = errors.New("only the author can change a comment")
+)
+
+// MaxCommentLength is the maximum length of a comment body in bytes
+const MaxCommentLength = 10000
+
+// Comment is a message in the discussion thread of a task
+type Comment struct {
+	ID          int64     `json:"id"`
+	TaskID      int64     `json:"task_id"`
+	AuthorID    int64     `json:"author_id"`
+	AuthorEmail string    `json:"author_email"`
+	Body        string    `json:"body"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreateCommentRequest represents the request to add a comment to a task
+type CreateCommentRequest struct {
+	Body string `json:"body"`
+}
+
+// UpdateCommentRequest represents the request to edit a comment
+type UpdateCommentRequest struct {
+	Body string `json:"body"`
+}
+
+// Validate validates the CreateCommentRequest struct
+func (r *CreateCommentRequest) Validate() error {
+	return validateCommentBody(r.Body)
+}
+
+// Validate validates the UpdateCommentRequest struct
+func (r *UpdateCommentRequest) Validate() error {
+	return validateCommentBody(r.Body)
+}
+
+func validateCommentBody(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return errors.New("comment body is required")
+	}
+	if len(body) > MaxCommentLength {
+		return errors.New("comment body must be 10000 characters or less")
+	}
+	return nil
+}
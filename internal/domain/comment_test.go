@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCreateCommentRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{name: "valid body", body: "Looks good"},
+		{name: "longer than task descriptions", body: strings.Repeat("a", 5000)},
+		{name: "empty body", body: "", wantErr: true},
+		{name: "whitespace only", body: " \n\t", wantErr: true},
+		{name: "too long", body: strings.Repeat("a", MaxCommentLength+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &CreateCommentRequest{Body: tt.body}
+			if err := req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("CreateCommentRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "errors"
+
+var (
+	ErrBlocked            = errors.New("task has unfinished blockers")
+	ErrDependencyCycle    = errors.New("dependency would create a cycle")
+	ErrDependencyNotFound = errors.New("dependency not found")
+)
+
+// TaskRef is a short reference to another task, used for dependency lists
+type TaskRef struct {
+	ID     int64      `json:"id"`
+	Title  string     `json:"title"`
+	Status TaskStatus `json:"status"`
+}
+
+// AddDependencyRequest marks a task as blocked by another task
+type AddDependencyRequest struct {
+	BlockedByID int64 `json:"blocked_by_id"`
+}
+
+func (r *AddDependencyRequest) Validate() error {
+	if r.BlockedByID <= 0 {
+		return errors.New("blocked_by_id must be a positive task id")
+	}
+	return nil
+}
+
+// HasOpenBlockers reports whether any task blocking this one is not closed
+// in workflow
+func (t *Task) HasOpenBlockers(workflow *Workflow) bool {
+	for _, blocker := range t.BlockedBy {
+		if !workflow.IsClosed(blocker.Status) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "testing"
+
+func TestTask_HasOpenBlockers(t *testing.T) {
+	tests := []struct {
+		name      string
+		blockedBy []TaskRef
+		want      bool
+	}{
+		{name: "no blockers", want: false},
+		{name: "all blockers done", blockedBy: []TaskRef{{ID: 1, Status: StatusDone}}, want: false},
+		{name: "one blocker open", blockedBy: []TaskRef{{ID: 1, Status: StatusDone}, {ID: 2, Status: StatusDoing}}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{BlockedBy: tt.blockedBy}
+			if got := task.HasOpenBlockers(DefaultWorkflow()); got != tt.want {
+				t.Errorf("HasOpenBlockers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddDependencyRequest_Validate(t *testing.T) {
+	if err := (&AddDependencyRequest{BlockedByID: 3}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+	if err := (&AddDependencyRequest{}).Validate(); err == nil {
+		t.Error("Validate() with missing blocked_by_id should fail")
+	}
+}
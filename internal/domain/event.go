@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EventType names a change that the services announce after a successful
+// write
+type EventType string
+
+const (
+	EventTaskCreated      EventType = "task.created"
+	EventTaskUpdated      EventType = "task.updated"
+	EventTaskDeleted      EventType = "task.deleted"
+	EventTaskRestored     EventType = "task.restored"
+	EventTaskPurged       EventType = "task.purged"
+	EventCategoryCreated  EventType = "category.created"
+	EventCategoryUpdated  EventType = "category.updated"
+	EventCategoryDeleted  EventType = "category.deleted"
+	EventCategoryRestored EventType = "category.restored"
+	EventCategoryPurged   EventType = "category.purged"
+)
+
+// EventTypes lists every event type
+var EventTypes = []EventType{
+	EventTaskCreated, EventTaskUpdated, EventTaskDeleted, EventTaskRestored, EventTaskPurged,
+	EventCategoryCreated, EventCategoryUpdated, EventCategoryDeleted, EventCategoryRestored, EventCategoryPurged,
+}
+
+// Event is a change to one of a user's resources. Data holds the resource
+// after the change, or as it was before a delete. Updates also carry the
+// resource as it was before in Previous.
+type Event struct {
+	ID         string      `json:"id"`
+	Type       EventType   `json:"type"`
+	UserID     int64       `json:"-"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+	Previous   interface{} `json:"previous,omitempty"`
+}
+
+// TaskEventType returns the event announcing a task history action
+func TaskEventType(action HistoryAction) EventType {
+	return EventType("task." + string(action))
+}
+
+// ValidateEventPattern checks an event subscription pattern: an event type,
+// a resource wildcard such as "task.*", or "*" for every event
+func ValidateEventPattern(pattern string) error {
+	if pattern == "*" {
+		return nil
+	}
+	for _, eventType := range EventTypes {
+		if MatchEventPattern(pattern, eventType) {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown event type: %s", pattern)
+}
+
+// MatchEventPattern reports whether an event subscription pattern covers
+// eventType
+func MatchEventPattern(pattern string, eventType EventType) bool {
+	if pattern == "*" || pattern == string(eventType) {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, ".*"); ok {
+		return strings.HasPrefix(string(eventType), prefix+".")
+	}
+	return false
+}
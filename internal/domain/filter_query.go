@@ -0,0 +1,577 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrInvalidFilterQuery = errors.New("invalid filter query")
+
+// FilterQueryError reports a syntax error in a filter query. Position is
+// the offset of the offending character, counted in characters from 0.
+type FilterQueryError struct {
+	Position int
+	Message  string
+}
+
+func (e *FilterQueryError) Error() string {
+	return fmt.Sprintf("%s at position %d: %s", ErrInvalidFilterQuery, e.Position, e.Message)
+}
+
+func (e *FilterQueryError) Unwrap() error {
+	return ErrInvalidFilterQuery
+}
+
+// FilterField is the task field tested by a FilterCondition
+type FilterField string
+
+const (
+	FilterStatus   FilterField = "status"
+	FilterPriority FilterField = "priority"
+	// FilterCategory matches category names, ignoring case
+	FilterCategory FilterField = "category"
+	// FilterDue compares the UTC day of due dates as YYYY-MM-DD, or matches
+	// tasks without one with the value "none"
+	FilterDue FilterField = "due"
+	// FilterText matches full-text search queries against the title and
+	// description
+	FilterText FilterField = "text"
+)
+
+// FilterOp compares a due date with the condition's value
+type FilterOp string
+
+const (
+	FilterEqual      FilterOp = ""
+	FilterBefore     FilterOp = "<"
+	FilterOnOrBefore FilterOp = "<="
+	FilterAfter      FilterOp = ">"
+	FilterOnOrAfter  FilterOp = ">="
+)
+
+// FilterNone is the due date value matching tasks without one
+const FilterNone = "none"
+
+const (
+	filterDateLayout     = "2006-01-02"
+	filterQueryMaxLength = 1000
+)
+
+// FilterExpr is a boolean combination of task conditions, as compiled from a
+// filter query. Exactly one of its fields is set.
+type FilterExpr struct {
+	And       []*FilterExpr    `json:"and,omitempty"`
+	Or        []*FilterExpr    `json:"or,omitempty"`
+	Not       *FilterExpr      `json:"not,omitempty"`
+	Condition *FilterCondition `json:"condition,omitempty"`
+}
+
+// FilterCondition holds when the field matches any of the values
+type FilterCondition struct {
+	Field  FilterField `json:"field"`
+	Op     FilterOp    `json:"op,omitempty"`
+	Values []string    `json:"values"`
+}
+
+// Validate checks the structure of the expression and its conditions.
+// Statuses must be part of workflow.
+func (e *FilterExpr) Validate(workflow *Workflow) error {
+	set := 0
+	for _, isSet := range []bool{e.And != nil, e.Or != nil, e.Not != nil, e.Condition != nil} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("a filter expression needs exactly one of and, or, not or condition")
+	}
+
+	switch {
+	case e.Not != nil:
+		return e.Not.Validate(workflow)
+	case e.Condition != nil:
+		return e.Condition.Validate(workflow)
+	}
+	operands := e.And
+	if e.Or != nil {
+		operands = e.Or
+	}
+	if len(operands) == 0 {
+		return errors.New("a filter expression needs at least one operand")
+	}
+	for _, operand := range operands {
+		if operand == nil {
+			return errors.New("a filter expression needs at least one operand")
+		}
+		if err := operand.Validate(workflow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks the field, operator and values of the condition
+func (c *FilterCondition) Validate(workflow *Workflow) error {
+	switch c.Field {
+	case FilterStatus, FilterPriority, FilterCategory, FilterDue, FilterText:
+	default:
+		return fmt.Errorf("invalid filter field: %s", c.Field)
+	}
+	switch c.Op {
+	case FilterEqual:
+	case FilterBefore, FilterOnOrBefore, FilterAfter, FilterOnOrAfter:
+		if c.Field != FilterDue {
+			return fmt.Errorf("%s cannot be compared with %s", c.Field, c.Op)
+		}
+		if len(c.Values) > 1 {
+			return fmt.Errorf("%s can only be compared with a single value", c.Field)
+		}
+	default:
+		return fmt.Errorf("invalid filter operator: %s", c.Op)
+	}
+	if len(c.Values) == 0 {
+		return fmt.Errorf("%s filter needs a value", c.Field)
+	}
+	for _, value := range c.Values {
+		if err := c.validateValue(workflow, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *FilterCondition) validateValue(workflow *Workflow, value string) error {
+	switch c.Field {
+	case FilterStatus:
+		if workflow.Status(TaskStatus(value)) == nil {
+			return fmt.Errorf("invalid status: %s", value)
+		}
+	case FilterPriority:
+		if !isValidPriority(TaskPriority(value)) {
+			return fmt.Errorf("invalid priority: %s", value)
+		}
+	case FilterCategory:
+		if strings.TrimSpace(value) == "" {
+			return errors.New("category name cannot be empty")
+		}
+	case FilterDue:
+		if value == FilterNone {
+			if c.Op != FilterEqual {
+				return fmt.Errorf("%s cannot be compared", FilterNone)
+			}
+			return nil
+		}
+		if _, err := time.Parse(filterDateLayout, value); err != nil {
+			return fmt.Errorf("invalid date %q: use YYYY-MM-DD or %s", value, FilterNone)
+		}
+	case FilterText:
+		if _, err := ParseSearchQuery(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Matches reports whether a task satisfies the expression
+func (e *FilterExpr) Matches(task *Task) bool {
+	switch {
+	case e.And != nil:
+		for _, operand := range e.And {
+			if !operand.Matches(task) {
+				return false
+			}
+		}
+		return true
+	case e.Or != nil:
+		for _, operand := range e.Or {
+			if operand.Matches(task) {
+				return true
+			}
+		}
+		return false
+	case e.Not != nil:
+		return !e.Not.Matches(task)
+	case e.Condition != nil:
+		for _, value := range e.Condition.Values {
+			if e.Condition.matchesValue(task, value) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (c *FilterCondition) matchesValue(task *Task, value string) bool {
+	switch c.Field {
+	case FilterStatus:
+		return task.Status == TaskStatus(value)
+	case FilterPriority:
+		return task.Priority == TaskPriority(value)
+	case FilterCategory:
+		for _, category := range task.Categories {
+			if strings.EqualFold(category.Name, value) {
+				return true
+			}
+		}
+		return false
+	case FilterText:
+		query, err := ParseSearchQuery(value)
+		return err == nil && query.Matches(task.Title, task.Description)
+	case FilterDue:
+		if value == FilterNone || task.DueDate == nil {
+			return value == FilterNone && task.DueDate == nil
+		}
+		// Dates in the same layout compare as strings
+		due := task.DueDate.UTC().Format(filterDateLayout)
+		switch c.Op {
+		case FilterBefore:
+			return due < value
+		case FilterOnOrBefore:
+			return due <= value
+		case FilterAfter:
+			return due > value
+		case FilterOnOrAfter:
+			return due >= value
+		default:
+			return due == value
+		}
+	}
+	return false
+}
+
+// ParseTaskQuery compiles a filter query such as
+//
+//	status:todo,doing priority:>=high -category:infra due:<2026-12-01 "login bug"
+//
+// into TaskFilters. Terms are combined with AND unless separated by OR, and
+// parentheses group them. A leading - or NOT negates a term or group. A list
+// of values matches any of them. Priorities and due dates can be compared
+// with <, <=, > and >=.
+//
+// Words and "quoted phrases" search the title and description. Those every
+// task must match are moved to Search, so that results can be ranked by
+// relevance. Statuses must be part of workflow.
+func ParseTaskQuery(input string, workflow *Workflow) (*TaskFilters, error) {
+	p := &filterQueryParser{input: []rune(input), workflow: workflow}
+	if len(p.input) > filterQueryMaxLength {
+		return nil, p.errorf(filterQueryMaxLength, "query is longer than %d characters", filterQueryMaxLength)
+	}
+	p.skipSpace()
+	if p.atEnd() {
+		return nil, p.errorf(p.pos, "query is empty")
+	}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.atEnd() {
+		return nil, p.errorf(p.pos, `unmatched ")"`)
+	}
+	return compileFilterQuery(expr), nil
+}
+
+// compileFilterQuery moves the text terms that every task must match into
+// Search and leaves the rest in Where
+func compileFilterQuery(expr *FilterExpr) *TaskFilters {
+	terms := []*FilterExpr{expr}
+	if expr.And != nil {
+		terms = expr.And
+	}
+
+	filters := &TaskFilters{}
+	var text []string
+	var rest []*FilterExpr
+	for _, term := range terms {
+		if term.Condition != nil && term.Condition.Field == FilterText {
+			text = append(text, term.Condition.Values...)
+		} else {
+			rest = append(rest, term)
+		}
+	}
+	filters.Search = strings.Join(text, " ")
+
+	switch len(rest) {
+	case 0:
+	case 1:
+		filters.Where = rest[0]
+	default:
+		filters.Where = &FilterExpr{And: rest}
+	}
+	return filters
+}
+
+// filterQueryFields are the fields a query may name
+var filterQueryFields = []FilterField{FilterStatus, FilterPriority, FilterCategory, FilterDue}
+
+var priorityLevels = []TaskPriority{PriorityLow, PriorityMedium, PriorityHigh, PriorityCritical}
+
+type filterQueryParser struct {
+	input    []rune
+	pos      int
+	workflow *Workflow
+}
+
+func (p *filterQueryParser) errorf(pos int, format string, args ...interface{}) error {
+	return &FilterQueryError{Position: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *filterQueryParser) atEnd() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *filterQueryParser) peek() rune {
+	if p.atEnd() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *filterQueryParser) skipSpace() {
+	for !p.atEnd() && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+// isFilterDelimiter reports whether r ends a bare word
+func isFilterDelimiter(r rune) bool {
+	return unicode.IsSpace(r) || r == '(' || r == ')' || r == '"'
+}
+
+// peekKeyword reports whether the keyword stands at the current position
+func (p *filterQueryParser) peekKeyword(keyword string) bool {
+	end := p.pos + len(keyword)
+	if end > len(p.input) || string(p.input[p.pos:end]) != keyword {
+		return false
+	}
+	return end == len(p.input) || isFilterDelimiter(p.input[end])
+}
+
+// acceptKeyword consumes the keyword if it stands at the current position
+func (p *filterQueryParser) acceptKeyword(keyword string) bool {
+	if !p.peekKeyword(keyword) {
+		return false
+	}
+	p.pos += len(keyword)
+	p.skipSpace()
+	return true
+}
+
+func (p *filterQueryParser) parseOr() (*FilterExpr, error) {
+	var operands []*FilterExpr
+	for {
+		operand, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+		if !p.acceptKeyword("OR") {
+			break
+		}
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return &FilterExpr{Or: operands}, nil
+}
+
+func (p *filterQueryParser) parseAnd() (*FilterExpr, error) {
+	var operands []*FilterExpr
+	for {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+
+		// Terms next to each other must all match, as with AND
+		if p.acceptKeyword("AND") {
+			continue
+		}
+		if p.atEnd() || p.peek() == ')' || p.peekKeyword("OR") {
+			break
+		}
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return &FilterExpr{And: operands}, nil
+}
+
+func (p *filterQueryParser) parseUnary() (*FilterExpr, error) {
+	if p.peek() == '-' {
+		p.pos++
+	} else if !p.acceptKeyword("NOT") {
+		return p.parsePrimary()
+	}
+
+	if p.atEnd() || unicode.IsSpace(p.peek()) {
+		return nil, p.errorf(p.pos, "expected a term to negate")
+	}
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &FilterExpr{Not: operand}, nil
+}
+
+func (p *filterQueryParser) parsePrimary() (*FilterExpr, error) {
+	start := p.pos
+	switch {
+	case p.atEnd():
+		return nil, p.errorf(start, "expected a term at the end of the query")
+	case p.peek() == ')':
+		return nil, p.errorf(start, `expected a term before ")"`)
+	case p.peekKeyword("OR"):
+		return nil, p.errorf(start, "expected a term before OR")
+	case p.peekKeyword("AND"):
+		return nil, p.errorf(start, "expected a term before AND")
+	case p.peek() == '(':
+		p.pos++
+		p.skipSpace()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, p.errorf(start, `unclosed "("`)
+		}
+		p.pos++
+		p.skipSpace()
+		return expr, nil
+	case p.peek() == '"':
+		phrase, err := p.parseQuoted()
+		if err != nil {
+			return nil, err
+		}
+		text := `"` + phrase + `"`
+		if p.peek() == '*' {
+			text += "*"
+			p.pos++
+		}
+		return p.textTerm(start, text)
+	}
+
+	name := p.scan(func(r rune) bool { return unicode.IsLetter(r) || r == '_' })
+	if name != "" && p.peek() == ':' {
+		p.pos++
+		return p.parseCondition(start, name)
+	}
+	p.pos = start
+	return p.textTerm(start, p.scan(func(r rune) bool { return !isFilterDelimiter(r) }))
+}
+
+// scan consumes the characters accepted by valid and returns them
+func (p *filterQueryParser) scan(valid func(rune) bool) string {
+	start := p.pos
+	for !p.atEnd() && valid(p.input[p.pos]) {
+		p.pos++
+	}
+	return string(p.input[start:p.pos])
+}
+
+// parseQuoted consumes a double-quoted string and returns its content
+func (p *filterQueryParser) parseQuoted() (string, error) {
+	start := p.pos
+	p.pos++
+	content := p.scan(func(r rune) bool { return r != '"' })
+	if p.atEnd() {
+		return "", p.errorf(start, "unterminated quote")
+	}
+	p.pos++
+	return content, nil
+}
+
+// textTerm searches the title and description for text
+func (p *filterQueryParser) textTerm(start int, text string) (*FilterExpr, error) {
+	if len(searchWords(text)) == 0 {
+		return nil, p.errorf(start, "no words to search for in %s", text)
+	}
+	p.skipSpace()
+	return &FilterExpr{Condition: &FilterCondition{Field: FilterText, Values: []string{text}}}, nil
+}
+
+// parseCondition parses the comparison and values following "field:"
+func (p *filterQueryParser) parseCondition(start int, name string) (*FilterExpr, error) {
+	condition := &FilterCondition{}
+	for _, field := range filterQueryFields {
+		if strings.EqualFold(name, string(field)) {
+			condition.Field = field
+		}
+	}
+	if condition.Field == "" {
+		return nil, p.errorf(start, "unknown field %q", name)
+	}
+
+	opStart := p.pos
+	for _, op := range []FilterOp{FilterOnOrBefore, FilterOnOrAfter, FilterBefore, FilterAfter} {
+		if strings.HasPrefix(string(p.input[p.pos:]), string(op)) {
+			condition.Op = op
+			p.pos += len(op)
+			break
+		}
+	}
+	if condition.Op != FilterEqual && condition.Field != FilterDue && condition.Field != FilterPriority {
+		return nil, p.errorf(opStart, "%s cannot be compared with %s", condition.Field, condition.Op)
+	}
+
+	for {
+		valueStart := p.pos
+		value := ""
+		if p.peek() == '"' {
+			var err error
+			if value, err = p.parseQuoted(); err != nil {
+				return nil, err
+			}
+		} else {
+			value = p.scan(func(r rune) bool { return r != ',' && !isFilterDelimiter(r) })
+		}
+		if value == "" {
+			return nil, p.errorf(valueStart, "missing value for %s", condition.Field)
+		}
+		if err := condition.validateValue(p.workflow, value); err != nil {
+			return nil, p.errorf(valueStart, "%s", err)
+		}
+		condition.Values = append(condition.Values, value)
+
+		if p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+	if condition.Op != FilterEqual && len(condition.Values) > 1 {
+		return nil, p.errorf(opStart, "%s can only be compared with a single value", condition.Field)
+	}
+
+	// Priorities are ordered, so a comparison compiles to a list of them
+	if condition.Field == FilterPriority && condition.Op != FilterEqual {
+		values := comparePriorities(condition.Op, TaskPriority(condition.Values[0]))
+		if len(values) == 0 {
+			return nil, p.errorf(opStart, "no priority is %s %s", condition.Op, condition.Values[0])
+		}
+		condition.Values = values
+		condition.Op = FilterEqual
+	}
+	p.skipSpace()
+	return &FilterExpr{Condition: condition}, nil
+}
+
+// comparePriorities lists the priorities that compare to the given one
+func comparePriorities(op FilterOp, priority TaskPriority) []string {
+	level := GetPriorityOrder(priority)
+	var values []string
+	for _, p := range priorityLevels {
+		other := GetPriorityOrder(p)
+		if (op == FilterBefore && other < level) ||
+			(op == FilterOnOrBefore && other <= level) ||
+			(op == FilterAfter && other > level) ||
+			(op == FilterOnOrAfter && other >= level) {
+			values = append(values, string(p))
+		}
+	}
+	return values
+}
@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseTaskQuery(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantSearch string
+		wantWhere  string
+	}{
+		{
+			input:     "status:todo,doing",
+			wantWhere: `{"condition":{"field":"status","values":["todo","doing"]}}`,
+		},
+		{
+			input:      `status:todo priority:>=high category:backend due:<2026-12-01 "login bug"`,
+			wantSearch: `"login bug"`,
+			wantWhere: `{"and":[{"condition":{"field":"status","values":["todo"]}},` +
+				`{"condition":{"field":"priority","values":["high","critical"]}},` +
+				`{"condition":{"field":"category","values":["backend"]}},` +
+				`{"condition":{"field":"due","op":"\u003c","values":["2026-12-01"]}}]}`,
+		},
+		{
+			input:     `-category:infra NOT due:none`,
+			wantWhere: `{"and":[{"not":{"condition":{"field":"category","values":["infra"]}}},{"not":{"condition":{"field":"due","values":["none"]}}}]}`,
+		},
+		{
+			input:      `login (priority:critical OR category:"front end") rep*`,
+			wantSearch: `login rep*`,
+			wantWhere:  `{"or":[{"condition":{"field":"priority","values":["critical"]}},{"condition":{"field":"category","values":["front end"]}}]}`,
+		},
+		{
+			input:     `status:done OR -draft`,
+			wantWhere: `{"or":[{"condition":{"field":"status","values":["done"]}},{"not":{"condition":{"field":"text","values":["draft"]}}}]}`,
+		},
+		{
+			input:     `Priority:<medium AND status:todo`,
+			wantWhere: `{"and":[{"condition":{"field":"priority","values":["low"]}},{"condition":{"field":"status","values":["todo"]}}]}`,
+		},
+		{
+			input:      "login bug",
+			wantSearch: "login bug",
+		},
+	}
+
+	for _, tt := range tests {
+		filters, err := ParseTaskQuery(tt.input, DefaultWorkflow())
+		if err != nil {
+			t.Errorf("ParseTaskQuery(%q) error = %v", tt.input, err)
+			continue
+		}
+		if filters.Search != tt.wantSearch {
+			t.Errorf("ParseTaskQuery(%q) Search = %q, want %q", tt.input, filters.Search, tt.wantSearch)
+		}
+		where := ""
+		if filters.Where != nil {
+			data, _ := json.Marshal(filters.Where)
+			where = string(data)
+		}
+		if where != tt.wantWhere {
+			t.Errorf("ParseTaskQuery(%q) Where = %s, want %s", tt.input, where, tt.wantWhere)
+		}
+		if err := filters.Validate(DefaultWorkflow()); err != nil {
+			t.Errorf("ParseTaskQuery(%q) gave invalid filters: %v", tt.input, err)
+		}
+	}
+}
+
+func TestParseTaskQuery_Errors(t *testing.T) {
+	tests := []struct {
+		input    string
+		position int
+	}{
+		{"", 0},
+		{"   ", 3},
+		{"status:todo stauts:done", 12},
+		{"status:", 7},
+		{"status:todo,later", 12},
+		{"priority:<low", 9},
+		{"status:>todo", 7},
+		{"due:<=2026-13-01", 6},
+		{"due:<none", 5},
+		{"due:>2026-01-01,2026-02-01", 4},
+		{`category:"infra`, 9},
+		{"(status:todo OR status:done", 0},
+		{"status:todo)", 11},
+		{"OR status:todo", 0},
+		{"status:todo OR", 14},
+		{"- status:todo", 1},
+		{"()", 1},
+		{"login %", 6},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseTaskQuery(tt.input, DefaultWorkflow())
+		var queryErr *FilterQueryError
+		if !errors.As(err, &queryErr) || !errors.Is(err, ErrInvalidFilterQuery) {
+			t.Errorf("ParseTaskQuery(%q) error = %v, want a FilterQueryError", tt.input, err)
+			continue
+		}
+		if queryErr.Position != tt.position {
+			t.Errorf("ParseTaskQuery(%q) error position = %d, want %d (%v)", tt.input, queryErr.Position, tt.position, err)
+		}
+	}
+}
+
+func TestFilterExpr_Matches(t *testing.T) {
+	// Due dates compare by their UTC day, which is 2026-11-30
+	due := time.Date(2026, 12, 1, 1, 0, 0, 0, time.FixedZone("", 5*3600))
+	task := &Task{
+		Title:      "Fix login bug",
+		Status:     StatusTodo,
+		Priority:   PriorityHigh,
+		DueDate:    &due,
+		Categories: []Category{{Name: "Backend"}},
+	}
+
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"status:todo,doing", true},
+		{"priority:>=high category:backend", true},
+		{"-category:backend", false},
+		{"category:infra OR priority:high", true},
+		{"due:<2026-12-01", true},
+		{"due:2026-11-30", true},
+		{"due:>=2026-12-01", false},
+		{"due:none", false},
+		{`"login bug" -status:done`, true},
+		{"NOT (login OR status:done)", false},
+	}
+
+	for _, tt := range tests {
+		filters, err := ParseTaskQuery(tt.input, DefaultWorkflow())
+		if err != nil {
+			t.Fatalf("ParseTaskQuery(%q) error = %v", tt.input, err)
+		}
+		if got := filters.Matches(DefaultWorkflow(), task); got != tt.want {
+			t.Errorf("ParseTaskQuery(%q).Matches() = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFilterExpr_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		where *FilterExpr
+	}{
+		{"empty expression", &FilterExpr{}},
+		{"two operators", &FilterExpr{Not: &FilterExpr{}, Or: []*FilterExpr{}}},
+		{"empty and", &FilterExpr{And: []*FilterExpr{}}},
+		{"unknown field", &FilterExpr{Condition: &FilterCondition{Field: "owner", Values: []string{"me"}}}},
+		{"no values", &FilterExpr{Condition: &FilterCondition{Field: FilterStatus}}},
+		{"compared status", &FilterExpr{Condition: &FilterCondition{Field: FilterStatus, Op: FilterAfter, Values: []string{"todo"}}}},
+		{"invalid text", &FilterExpr{Condition: &FilterCondition{Field: FilterText, Values: []string{"("}}}},
+	}
+
+	for _, tt := range tests {
+		filters := &TaskFilters{Where: tt.where}
+		if err := filters.Validate(DefaultWorkflow()); err == nil {
+			t.Errorf("Validate() with %s should fail", tt.name)
+		}
+	}
+}
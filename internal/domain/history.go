@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"reflect"
+	"sort"
+	"time"
+)
+
+type HistoryAction string
+
+const (
+	HistoryCreated  HistoryAction = "created"
+	HistoryUpdated  HistoryAction = "updated"
+	HistoryDeleted  HistoryAction = "deleted"
+	HistoryRestored HistoryAction = "restored"
+	HistoryPurged   HistoryAction = "purged"
+)
+
+// FieldChange is the before and after value of one task field. A nil value
+// means the field was empty.
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// TaskHistoryEntry records one change to a task and who made it
+type TaskHistoryEntry struct {
+	ID        int64         `json:"id"`
+	TaskID    int64         `json:"task_id"`
+	UserID    int64         `json:"user_id"`
+	UserEmail string        `json:"user_email,omitempty"`
+	Action    HistoryAction `json:"action"`
+	Changes   []FieldChange `json:"changes"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// historyFields are the task fields tracked by the history, in the order
+// their changes are listed
+var historyFields = []struct {
+	name  string
+	value func(t *Task) interface{}
+}{
+	{"title", func(t *Task) interface{} { return t.Title }},
+	{"description", func(t *Task) interface{} { return t.Description }},
+	{"status", func(t *Task) interface{} { return string(t.Status) }},
+	{"priority", func(t *Task) interface{} { return string(t.Priority) }},
+	{"rank", func(t *Task) interface{} { return t.Rank }},
+	{"due_date", func(t *Task) interface{} {
+		if t.DueDate == nil {
+			return nil
+		}
+		return t.DueDate.UTC().Format(time.RFC3339)
+	}},
+	{"parent_id", func(t *Task) interface{} {
+		if t.ParentID == nil {
+			return nil
+		}
+		return *t.ParentID
+	}},
+	{"rrule", func(t *Task) interface{} { return t.RRule }},
+	{"categories", func(t *Task) interface{} {
+		ids := make([]int64, len(t.Categories))
+		for i, category := range t.Categories {
+			ids[i] = category.ID
+		}
+		return sortedIDs(ids)
+	}},
+	{"blocked_by", func(t *Task) interface{} {
+		ids := make([]int64, len(t.BlockedBy))
+		for i, blocker := range t.BlockedBy {
+			ids[i] = blocker.ID
+		}
+		return sortedIDs(ids)
+	}},
+}
+
+// DiffTasks lists the tracked fields that differ between before and after.
+// A nil task has no values, so DiffTasks(nil, task) lists every field set on
+// a new task and DiffTasks(task, nil) every field of a deleted one.
+func DiffTasks(before, after *Task) []FieldChange {
+	changes := []FieldChange{}
+	for _, field := range historyFields {
+		oldValue, newValue := fieldValue(before, field.value), fieldValue(after, field.value)
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, FieldChange{Field: field.name, Old: oldValue, New: newValue})
+		}
+	}
+	return changes
+}
+
+// fieldValue reads a field of t, reporting empty values as nil
+func fieldValue(t *Task, value func(t *Task) interface{}) interface{} {
+	if t == nil {
+		return nil
+	}
+	v := value(t)
+	switch typed := v.(type) {
+	case string:
+		if typed == "" {
+			return nil
+		}
+	case []int64:
+		if len(typed) == 0 {
+			return nil
+		}
+	}
+	return v
+}
+
+func sortedIDs(ids []int64) []int64 {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiffTasks(t *testing.T) {
+	due := time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)
+	parentID := int64(7)
+	before := &Task{
+		ID:         1,
+		Title:      "Write spec",
+		Status:     StatusTodo,
+		Priority:   PriorityLow,
+		Categories: []Category{{ID: 2}, {ID: 1}},
+	}
+
+	after := *before
+	after.Status = StatusDoing
+	after.DueDate = &due
+	after.ParentID = &parentID
+	after.Categories = []Category{{ID: 1}, {ID: 3}}
+
+	changes := DiffTasks(before, &after)
+	want := map[string][2]interface{}{
+		"status":     {"todo", "doing"},
+		"due_date":   {nil, "2030-01-02T00:00:00Z"},
+		"parent_id":  {nil, int64(7)},
+		"categories": {[]int64{1, 2}, []int64{1, 3}},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("DiffTasks() = %+v, want %d changes", changes, len(want))
+	}
+	for _, change := range changes {
+		values, ok := want[change.Field]
+		if !ok {
+			t.Errorf("unexpected change to %s", change.Field)
+			continue
+		}
+		if !equalValues(change.Old, values[0]) || !equalValues(change.New, values[1]) {
+			t.Errorf("%s changed %v -> %v, want %v -> %v", change.Field, change.Old, change.New, values[0], values[1])
+		}
+	}
+
+	// Reordered categories are not a change
+	reordered := *before
+	reordered.Categories = []Category{{ID: 1}, {ID: 2}}
+	if changes := DiffTasks(before, &reordered); len(changes) != 0 {
+		t.Errorf("DiffTasks() with reordered categories = %+v, want none", changes)
+	}
+}
+
+func TestDiffTasks_CreateAndDelete(t *testing.T) {
+	task := &Task{ID: 1, Title: "New", Status: StatusTodo, Priority: PriorityHigh}
+
+	created := DiffTasks(nil, task)
+	if len(created) != 3 {
+		t.Fatalf("DiffTasks(nil, task) = %+v, want title, status and priority", created)
+	}
+	for _, change := range created {
+		if change.Old != nil {
+			t.Errorf("created %s should have no old value, got %v", change.Field, change.Old)
+		}
+	}
+
+	deleted := DiffTasks(task, nil)
+	if len(deleted) != 3 {
+		t.Fatalf("DiffTasks(task, nil) = %+v, want title, status and priority", deleted)
+	}
+	for _, change := range deleted {
+		if change.New != nil {
+			t.Errorf("deleted %s should have no new value, got %v", change.Field, change.New)
+		}
+	}
+}
+
+func equalValues(a, b interface{}) bool {
+	aIDs, aOK := a.([]int64)
+	bIDs, bOK := b.([]int64)
+	if aOK || bOK {
+		if len(aIDs) != len(bIDs) {
+			return false
+		}
+		for i := range aIDs {
+			if aIDs[i] != bIDs[i] {
+				return false
+			}
+		}
+		return aOK == bOK
+	}
+	return a == b
+}
@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// CalendarComponent selects how tasks appear in an iCalendar feed
+type CalendarComponent string
+
+const (
+	// CalendarTodo renders tasks as VTODO entries due at their due date
+	CalendarTodo CalendarComponent = "vtodo"
+	// CalendarEvent renders tasks as all-day VEVENTs on their due date, for
+	// calendar apps that do not show VTODOs
+	CalendarEvent CalendarComponent = "vevent"
+)
+
+var ErrInvalidCalendarComponent = errors.New("invalid component: must be vtodo or vevent")
+
+// IsValid reports whether the component is a known CalendarComponent
+func (c CalendarComponent) IsValid() bool {
+	return c == CalendarTodo || c == CalendarEvent
+}
+
+const (
+	icalProductID      = "-//task-manager//Tasks//EN"
+	icalDateTimeFormat = "20060102T150405Z"
+	icalDateFormat     = "20060102"
+	// icalLineLimit is the longest content line in octets, excluding CRLF
+	icalLineLimit = 75
+)
+
+// TaskUID is the iCalendar UID of a task. It depends only on the task ID so
+// calendar clients update an entry instead of adding a new one.
+func TaskUID(taskID int64) string {
+	return fmt.Sprintf("task-%d@task-manager", taskID)
+}
+
+// WriteTasksICS writes the tasks that have a due date as an RFC 5545
+// calendar, with their statuses mapped through workflow. Tasks without a due
+// date are left out.
+func WriteTasksICS(w io.Writer, tasks []*Task, component CalendarComponent, workflow *Workflow) error {
+	ical := &icalWriter{w: bufio.NewWriter(w), workflow: workflow}
+	ical.line("BEGIN", "VCALENDAR")
+	ical.line("VERSION", "2.0")
+	ical.line("PRODID", icalProductID)
+	ical.line("CALSCALE", "GREGORIAN")
+	ical.line("METHOD", "PUBLISH")
+	ical.line("X-WR-CALNAME", "Tasks")
+
+	for _, task := range tasks {
+		if task.DueDate == nil {
+			continue
+		}
+		if component == CalendarEvent {
+			ical.event(task)
+		} else {
+			ical.todo(task)
+		}
+	}
+
+	ical.line("END", "VCALENDAR")
+	if ical.err != nil {
+		return ical.err
+	}
+	return ical.w.Flush()
+}
+
+type icalWriter struct {
+	w        *bufio.Writer
+	workflow *Workflow
+	err      error
+}
+
+func (c *icalWriter) todo(task *Task) {
+	c.line("BEGIN", "VTODO")
+	c.common(task)
+	c.line("DUE", formatICalDateTime(*task.DueDate))
+	c.line("STATUS", icalTodoStatus(c.workflow.Category(task.Status)))
+	if c.workflow.IsClosed(task.Status) {
+		completed := task.UpdatedAt
+		if task.CompletedAt != nil {
+			completed = *task.CompletedAt
+		}
+		c.line("COMPLETED", formatICalDateTime(completed))
+		c.line("PERCENT-COMPLETE", "100")
+	}
+	c.line("END", "VTODO")
+}
+
+func (c *icalWriter) event(task *Task) {
+	day := task.DueDate.UTC()
+	c.line("BEGIN", "VEVENT")
+	c.common(task)
+	c.line("DTSTART;VALUE=DATE", day.Format(icalDateFormat))
+	c.line("DTEND;VALUE=DATE", day.AddDate(0, 0, 1).Format(icalDateFormat))
+	c.line("TRANSP", "TRANSPARENT")
+	c.line("END", "VEVENT")
+}
+
+// common writes the properties shared by VTODO and VEVENT
+func (c *icalWriter) common(task *Task) {
+	c.line("UID", TaskUID(task.ID))
+	c.line("DTSTAMP", formatICalDateTime(task.UpdatedAt))
+	c.line("CREATED", formatICalDateTime(task.CreatedAt))
+	c.line("LAST-MODIFIED", formatICalDateTime(task.UpdatedAt))
+	if task.Version > 0 {
+		c.line("SEQUENCE", fmt.Sprint(task.Version-1))
+	}
+	c.line("SUMMARY", escapeICalText(task.Title))
+	if task.Description != "" {
+		c.line("DESCRIPTION", escapeICalText(task.Description))
+	}
+	c.line("PRIORITY", fmt.Sprint(icalPriority(task.Priority)))
+	if len(task.Categories) > 0 {
+		names := make([]string, len(task.Categories))
+		for i, category := range task.Categories {
+			names[i] = escapeICalText(category.Name)
+		}
+		c.line("CATEGORIES", strings.Join(names, ","))
+	}
+	if task.ParentID != nil {
+		c.line("RELATED-TO", TaskUID(*task.ParentID))
+	}
+}
+
+// line writes one content line, folded so no line exceeds the RFC 5545
+// limit of 75 octets. Folds never split a UTF-8 sequence.
+func (c *icalWriter) line(name, value string) {
+	if c.err != nil {
+		return
+	}
+
+	content := name + ":" + value
+	var b strings.Builder
+	limit := icalLineLimit
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		// Continuation lines start with a space, which counts to the limit
+		limit = icalLineLimit - 1
+	}
+	b.WriteString(content)
+	b.WriteString("\r\n")
+
+	_, c.err = c.w.WriteString(b.String())
+}
+
+func formatICalDateTime(t time.Time) string {
+	return t.UTC().Format(icalDateTimeFormat)
+}
+
+// icalTextEscaper escapes a TEXT property value
+var icalTextEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+func escapeICalText(value string) string {
+	return icalTextEscaper.Replace(value)
+}
+
+func icalTodoStatus(category StatusCategory) string {
+	switch category {
+	case StatusCategoryInProgress:
+		return "IN-PROCESS"
+	case StatusCategoryClosed:
+		return "COMPLETED"
+	default:
+		return "NEEDS-ACTION"
+	}
+}
+
+// icalPriority maps a priority onto the RFC 5545 scale, where 1 is the
+// highest, 5 is medium and 9 is the lowest
+func icalPriority(priority TaskPriority) int {
+	switch priority {
+	case PriorityCritical:
+		return 1
+	case PriorityHigh:
+		return 3
+	case PriorityMedium:
+		return 5
+	case PriorityLow:
+		return 9
+	default:
+		return 0
+	}
+}
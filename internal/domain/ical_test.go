@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func icalTestTasks() []*Task {
+	due := time.Date(2030, 3, 4, 15, 30, 0, 0, time.FixedZone("CET", 3600))
+	updated := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	completed := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	parentID := int64(1)
+	return []*Task{
+		{
+			ID:          7,
+			Title:       "Review, sign; send",
+			Description: "Line one\nLine two",
+			Status:      StatusDone,
+			Priority:    PriorityHigh,
+			DueDate:     &due,
+			ParentID:    &parentID,
+			Categories:  []Category{{Name: "Work"}, {Name: "A,B"}},
+			CreatedAt:   updated,
+			UpdatedAt:   updated,
+			CompletedAt: &completed,
+			Version:     3,
+		},
+		{ID: 8, Title: "No due date", Status: StatusTodo, Priority: PriorityLow},
+	}
+}
+
+func TestWriteTasksICS_Todo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTasksICS(&buf, icalTestTasks(), CalendarTodo, DefaultWorkflow()); err != nil {
+		t.Fatalf("WriteTasksICS() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"BEGIN:VTODO\r\n",
+		"UID:task-7@task-manager\r\n",
+		"DUE:20300304T143000Z\r\n",
+		"STATUS:COMPLETED\r\n",
+		"COMPLETED:20240101T080000Z\r\n",
+		"PRIORITY:3\r\n",
+		"SEQUENCE:2\r\n",
+		"SUMMARY:Review\\, sign\\; send\r\n",
+		"DESCRIPTION:Line one\\nLine two\r\n",
+		"CATEGORIES:Work,A\\,B\r\n",
+		"RELATED-TO:task-1@task-manager\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("calendar is missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "task-8@") {
+		t.Error("tasks without a due date should be left out")
+	}
+}
+
+func TestWriteTasksICS_Event(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTasksICS(&buf, icalTestTasks(), CalendarEvent, DefaultWorkflow()); err != nil {
+		t.Fatalf("WriteTasksICS() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VEVENT\r\n",
+		"DTSTART;VALUE=DATE:20300304\r\n",
+		"DTEND;VALUE=DATE:20300305\r\n",
+		"UID:task-7@task-manager\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("calendar is missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "VTODO") || strings.Contains(out, "STATUS:") {
+		t.Errorf("event feed should not contain VTODO properties:\n%s", out)
+	}
+}
+
+func TestWriteTasksICS_FoldsLongLines(t *testing.T) {
+	due := time.Now().AddDate(0, 0, 1)
+	task := &Task{ID: 1, Title: strings.Repeat("é", 100), Status: StatusTodo, Priority: PriorityLow, DueDate: &due}
+
+	var buf bytes.Buffer
+	if err := WriteTasksICS(&buf, []*Task{task}, CalendarTodo, DefaultWorkflow()); err != nil {
+		t.Fatalf("WriteTasksICS() error = %v", err)
+	}
+
+	var unfolded strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line is %d octets, want at most 75: %q", len(line), line)
+		}
+		if strings.HasPrefix(line, " ") {
+			unfolded.WriteString(line[1:])
+		} else {
+			unfolded.WriteString("\n" + line)
+		}
+	}
+	if !strings.Contains(unfolded.String(), "\nSUMMARY:"+task.Title+"\n") {
+		t.Error("unfolding the summary should restore the title")
+	}
+}
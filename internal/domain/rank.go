@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidMove = errors.New("invalid move")
+
+// Ranks order tasks within a status column. They are strings over
+// rankDigits that compare as text, so a task can always be placed between
+// two others by giving it a rank between theirs, without renumbering the
+// rest. A rank never ends in the lowest digit, which keeps room below it.
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// Ranks at either end of a column are stepped by rankStep within their
+// first rankWidth digits, so appending a task does not lengthen the rank
+const (
+	rankWidth = 6
+	rankStep  = 36 * 36
+)
+
+// MoveTaskRequest places a task in a status column between two of its
+// tasks. AfterID is the task it should follow and BeforeID the one it should
+// precede; leaving one out places the task next to the other, and leaving
+// both out moves it to the end of the column.
+type MoveTaskRequest struct {
+	// Status defaults to the task's current status
+	Status   TaskStatus `json:"status,omitempty"`
+	AfterID  *int64     `json:"after_id,omitempty"`
+	BeforeID *int64     `json:"before_id,omitempty"`
+	// ExpectedVersion works as for UpdateTaskRequest
+	ExpectedVersion *int64 `json:"-"`
+}
+
+// Validate checks the request, whose status must be part of workflow
+func (r *MoveTaskRequest) Validate(workflow *Workflow) error {
+	if r.Status != "" && workflow.Status(r.Status) == nil {
+		return errors.New("invalid status")
+	}
+	if r.AfterID != nil && *r.AfterID <= 0 {
+		return errors.New("after_id must be a positive task id")
+	}
+	if r.BeforeID != nil && *r.BeforeID <= 0 {
+		return errors.New("before_id must be a positive task id")
+	}
+	if r.AfterID != nil && r.BeforeID != nil && *r.AfterID == *r.BeforeID {
+		return errors.New("after_id and before_id must differ")
+	}
+	return nil
+}
+
+// RankBetween returns a rank sorting after lower and before upper. An empty
+// bound is open, so RankBetween(last, "") appends to a column.
+func RankBetween(lower, upper string) (string, error) {
+	if !isValidRank(lower) || !isValidRank(upper) {
+		return "", fmt.Errorf("%w: malformed rank", ErrInvalidMove)
+	}
+	if upper != "" && lower >= upper {
+		return "", fmt.Errorf("%w: neighbors are out of order", ErrInvalidMove)
+	}
+
+	switch {
+	case lower == "" && upper == "":
+		return rankDigits[len(rankDigits)/2 : len(rankDigits)/2+1], nil
+	case upper == "":
+		if rank, ok := stepRank(lower, rankStep); ok {
+			return rank, nil
+		}
+	case lower == "":
+		if rank, ok := stepRank(upper, -rankStep); ok {
+			return rank, nil
+		}
+	}
+	return midpointRank(lower, upper), nil
+}
+
+func isValidRank(rank string) bool {
+	for _, c := range rank {
+		if !strings.ContainsRune(rankDigits, c) {
+			return false
+		}
+	}
+	return !strings.HasSuffix(rank, rankDigits[:1])
+}
+
+// stepRank adds delta to the number formed by the first rankWidth digits of
+// rank, failing if the result leaves that range
+func stepRank(rank string, delta int64) (string, bool) {
+	var n int64
+	for i := 0; i < rankWidth; i++ {
+		n = n*int64(len(rankDigits)) + int64(rankDigit(rank, i))
+	}
+	n += delta
+
+	limit := int64(1)
+	for i := 0; i < rankWidth; i++ {
+		limit *= int64(len(rankDigits))
+	}
+	if n <= 0 || n >= limit {
+		return "", false
+	}
+
+	digits := make([]byte, rankWidth)
+	for i := rankWidth - 1; i >= 0; i-- {
+		digits[i] = rankDigits[n%int64(len(rankDigits))]
+		n /= int64(len(rankDigits))
+	}
+	return strings.TrimRight(string(digits), rankDigits[:1]), true
+}
+
+// midpointRank returns a rank between lower and upper, where an empty upper
+// stands above every rank
+func midpointRank(lower, upper string) string {
+	if upper != "" {
+		// Keep the common prefix, reading missing digits of lower as zero
+		n := 0
+		for n < len(upper) && rankDigit(lower, n) == rankDigit(upper, n) {
+			n++
+		}
+		if n > 0 {
+			return upper[:n] + midpointRank(rankSuffix(lower, n), upper[n:])
+		}
+	}
+
+	lo, hi := rankDigit(lower, 0), len(rankDigits)
+	if upper != "" {
+		hi = rankDigit(upper, 0)
+	}
+	if hi-lo > 1 {
+		return rankDigits[(lo+hi)/2 : (lo+hi)/2+1]
+	}
+	// The first digits are adjacent, so upper's alone fits if upper goes on
+	if len(upper) > 1 {
+		return upper[:1]
+	}
+	return rankDigits[lo:lo+1] + midpointRank(rankSuffix(lower, 1), "")
+}
+
+func rankDigit(rank string, i int) int {
+	if i >= len(rank) {
+		return 0
+	}
+	return strings.IndexByte(rankDigits, rank[i])
+}
+
+func rankSuffix(rank string, n int) string {
+	if n >= len(rank) {
+		return ""
+	}
+	return rank[n:]
+}
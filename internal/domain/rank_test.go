@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		name         string
+		lower, upper string
+	}{
+		{"empty column", "", ""},
+		{"append", "i", ""},
+		{"prepend", "", "i"},
+		{"between", "i", "q"},
+		{"adjacent digits", "i", "j"},
+		{"prefix", "i", "i1"},
+		{"longer lower", "i5zz", "i6"},
+		{"near the top", "zzzzzz", ""},
+		{"near the bottom", "", "0001"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rank, err := RankBetween(tt.lower, tt.upper)
+			if err != nil {
+				t.Fatalf("RankBetween() error = %v", err)
+			}
+			if !isValidRank(rank) || rank == "" {
+				t.Errorf("RankBetween() = %q, which is not a valid rank", rank)
+			}
+			if rank <= tt.lower || (tt.upper != "" && rank >= tt.upper) {
+				t.Errorf("RankBetween(%q, %q) = %q, which is out of order", tt.lower, tt.upper, rank)
+			}
+		})
+	}
+}
+
+func TestRankBetween_Invalid(t *testing.T) {
+	for _, bounds := range [][2]string{{"q", "i"}, {"i", "i"}, {"I", ""}, {"", "a0"}} {
+		if _, err := RankBetween(bounds[0], bounds[1]); !errors.Is(err, ErrInvalidMove) {
+			t.Errorf("RankBetween(%q, %q) error = %v, want ErrInvalidMove", bounds[0], bounds[1], err)
+		}
+	}
+}
+
+func TestRankBetween_Appending(t *testing.T) {
+	rank := ""
+	for i := 0; i < 10000; i++ {
+		next, err := RankBetween(rank, "")
+		if err != nil || next <= rank {
+			t.Fatalf("append %d: RankBetween(%q) = %q, %v", i, rank, next, err)
+		}
+		rank = next
+	}
+	if len(rank) > rankWidth {
+		t.Errorf("Expected appended ranks to stay within %d digits, got %q", rankWidth, rank)
+	}
+}
+
+func TestRankBetween_RandomInserts(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	ranks := []string{}
+	for i := 0; i < 2000; i++ {
+		pos := r.Intn(len(ranks) + 1)
+		lower, upper := "", ""
+		if pos > 0 {
+			lower = ranks[pos-1]
+		}
+		if pos < len(ranks) {
+			upper = ranks[pos]
+		}
+		rank, err := RankBetween(lower, upper)
+		if err != nil {
+			t.Fatalf("RankBetween(%q, %q) error = %v", lower, upper, err)
+		}
+		ranks = append(ranks[:pos], append([]string{rank}, ranks[pos:]...)...)
+	}
+
+	if !sort.StringsAreSorted(ranks) {
+		t.Fatal("Expected inserted ranks to stay in order")
+	}
+	for i := 1; i < len(ranks); i++ {
+		if ranks[i] == ranks[i-1] {
+			t.Fatalf("Duplicate rank %q", ranks[i])
+		}
+	}
+	longest := ""
+	for _, rank := range ranks {
+		if len(rank) > len(longest) {
+			longest = rank
+		}
+	}
+	if len(longest) > 20 || strings.HasSuffix(longest, "0") {
+		t.Errorf("Unexpected rank %q", longest)
+	}
+}
@@ -0,0 +1,447 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidRRule  = errors.New("invalid recurrence rule")
+	ErrNotRecurring  = errors.New("task is not part of a recurring series")
+	ErrInvalidScope  = errors.New("invalid scope: must be occurrence or series")
+	ErrSeriesSubtask = errors.New("recurring tasks cannot be subtasks")
+)
+
+// Frequency is the FREQ part of an RRULE
+type Frequency string
+
+const (
+	FreqDaily   Frequency = "DAILY"
+	FreqWeekly  Frequency = "WEEKLY"
+	FreqMonthly Frequency = "MONTHLY"
+	FreqYearly  Frequency = "YEARLY"
+)
+
+// RecurrenceScope selects whether an update applies to one occurrence of a
+// recurring task or to the whole series
+type RecurrenceScope string
+
+const (
+	ScopeOccurrence RecurrenceScope = "occurrence"
+	ScopeSeries     RecurrenceScope = "series"
+)
+
+// maxRecurrencePeriods bounds the search for the next occurrence so rules
+// that can never match, such as BYMONTH=2;BYMONTHDAY=30, still terminate
+const maxRecurrencePeriods = 10000
+
+// untilLayout is the UTC date-time form of UNTIL used by RFC 5545
+const untilLayout = "20060102T150405Z"
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// TaskSeries is the template shared by every occurrence of a recurring task.
+// DTStart is the due date of the first occurrence and anchors the rule.
+type TaskSeries struct {
+	ID          int64        `json:"id"`
+	UserID      int64        `json:"user_id"`
+	RRule       string       `json:"rrule"`
+	DTStart     time.Time    `json:"dtstart"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Priority    TaskPriority `json:"priority"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// WeekdayNum is one BYDAY entry. An Ordinal of 0 matches every such weekday
+// in the period; 1 is the first, -1 the last.
+type WeekdayNum struct {
+	Ordinal int
+	Weekday time.Weekday
+}
+
+// RecurrenceRule is the supported subset of an RFC 5545 RRULE: FREQ,
+// INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY and BYMONTH
+type RecurrenceRule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+}
+
+// ParseRRule parses an RRULE value such as "FREQ=WEEKLY;BYDAY=MO". An
+// optional "RRULE:" prefix is accepted.
+func ParseRRule(s string) (*RecurrenceRule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("%w: rule is empty", ErrInvalidRRule)
+	}
+
+	rule := &RecurrenceRule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || name == "" || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRRule, part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: %s given more than once", ErrInvalidRRule, name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			rule.Freq = Frequency(value)
+			if !rule.Freq.isValid() {
+				err = fmt.Errorf("unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(name, value)
+		case "COUNT":
+			rule.Count, err = parsePositive(name, value)
+		case "UNTIL":
+			rule.Until, err = parseUntil(value)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseIntList(name, value, -31, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseIntList(name, value, 1, 12)
+			for _, month := range months {
+				rule.ByMonth = append(rule.ByMonth, time.Month(month))
+			}
+		case "WKST":
+			if value != "MO" {
+				err = errors.New("only WKST=MO is supported")
+			}
+		default:
+			err = fmt.Errorf("unsupported part %s", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRRule, err)
+		}
+	}
+
+	if err := rule.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRRule, err)
+	}
+	return rule, nil
+}
+
+func (r *RecurrenceRule) validate() error {
+	if r.Freq == "" {
+		return errors.New("FREQ is required")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return errors.New("COUNT and UNTIL cannot both be set")
+	}
+	if r.Freq == FreqWeekly && len(r.ByMonthDay) > 0 {
+		return errors.New("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	}
+	for _, day := range r.ByDay {
+		if day.Ordinal != 0 && r.Freq != FreqMonthly && r.Freq != FreqYearly {
+			return errors.New("BYDAY ordinals require FREQ=MONTHLY or FREQ=YEARLY")
+		}
+	}
+	if r.Freq == FreqYearly && len(r.ByDay) > 0 && len(r.ByMonth) == 0 {
+		return errors.New("BYDAY with FREQ=YEARLY requires BYMONTH")
+	}
+	return nil
+}
+
+// String formats the rule in canonical RRULE form
+func (r *RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, len(r.ByMonth))
+		for i, month := range r.ByMonth {
+			months[i] = strconv.Itoa(int(month))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = day.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayout))
+	}
+	return strings.Join(parts, ";")
+}
+
+func (d WeekdayNum) String() string {
+	code := strings.ToUpper(d.Weekday.String()[:2])
+	if d.Ordinal == 0 {
+		return code
+	}
+	return strconv.Itoa(d.Ordinal) + code
+}
+
+// Next returns the first occurrence strictly after after for a series
+// starting at dtstart. dtstart itself is always the first occurrence. It
+// reports false once COUNT or UNTIL has ended the series.
+func (r *RecurrenceRule) Next(dtstart, after time.Time) (time.Time, bool) {
+	if r.Until != nil && dtstart.After(*r.Until) {
+		return time.Time{}, false
+	}
+	if dtstart.After(after) {
+		return dtstart, true
+	}
+
+	count := 1
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		for _, occurrence := range r.expand(dtstart, period*r.Interval) {
+			if !occurrence.After(dtstart) {
+				continue
+			}
+			if r.Until != nil && occurrence.After(*r.Until) {
+				return time.Time{}, false
+			}
+			count++
+			if r.Count > 0 && count > r.Count {
+				return time.Time{}, false
+			}
+			if occurrence.After(after) {
+				return occurrence, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// expand returns the sorted candidate occurrences in the period offset
+// periods of the rule's frequency after the one containing dtstart
+func (r *RecurrenceRule) expand(dtstart time.Time, offset int) []time.Time {
+	year, month, day := dtstart.Date()
+	hour, min, sec := dtstart.Clock()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, min, sec, dtstart.Nanosecond(), dtstart.Location())
+	}
+
+	var candidates []time.Time
+	switch r.Freq {
+	case FreqDaily:
+		date := at(year, month, day+offset)
+		daysInMonth := at(date.Year(), date.Month()+1, 0).Day()
+		if r.matchesMonth(date.Month()) &&
+			r.matchesMonthDay(date.Day(), daysInMonth) &&
+			r.matchesWeekday(date, date.Day(), daysInMonth) {
+			candidates = append(candidates, date)
+		}
+	case FreqWeekly:
+		// Weeks start on Monday
+		weekStart := day - (int(dtstart.Weekday())+6)%7 + 7*offset
+		byDay := r.ByDay
+		if len(byDay) == 0 {
+			byDay = []WeekdayNum{{Weekday: dtstart.Weekday()}}
+		}
+		for i := 0; i < 7; i++ {
+			date := at(year, month, weekStart+i)
+			if r.matchesMonth(date.Month()) && containsWeekday(byDay, date.Weekday()) {
+				candidates = append(candidates, date)
+			}
+		}
+	case FreqMonthly:
+		first := at(year, month+time.Month(offset), 1)
+		if r.matchesMonth(first.Month()) {
+			candidates = r.expandMonth(first, day)
+		}
+	case FreqYearly:
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{month}
+		}
+		for _, m := range months {
+			candidates = append(candidates, r.expandMonth(at(year+offset, m, 1), day)...)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	return candidates
+}
+
+// expandMonth returns the days of the month starting at first that match
+// BYMONTHDAY and BYDAY, or defaultDay when neither is set
+func (r *RecurrenceRule) expandMonth(first time.Time, defaultDay int) []time.Time {
+	daysInMonth := first.AddDate(0, 1, -1).Day()
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		// Months without the day are skipped rather than clamped, as in RFC 5545
+		if defaultDay > daysInMonth {
+			return nil
+		}
+		return []time.Time{first.AddDate(0, 0, defaultDay-1)}
+	}
+
+	var dates []time.Time
+	for day := 1; day <= daysInMonth; day++ {
+		date := first.AddDate(0, 0, day-1)
+		if r.matchesMonthDay(day, daysInMonth) && r.matchesWeekday(date, day, daysInMonth) {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+func (r *RecurrenceRule) matchesMonth(month time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, m := range r.ByMonth {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *RecurrenceRule) matchesMonthDay(day, daysInMonth int) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	for _, n := range r.ByMonthDay {
+		if n == day || (n < 0 && daysInMonth+n+1 == day) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWeekday checks BYDAY, with ordinals counted within the month
+func (r *RecurrenceRule) matchesWeekday(date time.Time, day, daysInMonth int) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, wd := range r.ByDay {
+		if wd.Weekday != date.Weekday() {
+			continue
+		}
+		switch {
+		case wd.Ordinal == 0:
+			return true
+		case wd.Ordinal > 0 && (day-1)/7+1 == wd.Ordinal:
+			return true
+		case wd.Ordinal < 0 && (daysInMonth-day)/7+1 == -wd.Ordinal:
+			return true
+		}
+	}
+	return false
+}
+
+func containsWeekday(days []WeekdayNum, weekday time.Weekday) bool {
+	for _, day := range days {
+		if day.Weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Frequency) isValid() bool {
+	switch f {
+	case FreqDaily, FreqWeekly, FreqMonthly, FreqYearly:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValid reports whether the scope is a known RecurrenceScope
+func (s RecurrenceScope) IsValid() bool {
+	return s == ScopeOccurrence || s == ScopeSeries
+}
+
+func parsePositive(name, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%s must be a positive integer", name)
+	}
+	return n, nil
+}
+
+func parseIntList(name, value string, min, max int) ([]int, error) {
+	var values []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(item)
+		if err != nil || n == 0 || n < min || n > max {
+			return nil, fmt.Errorf("%s value %q out of range", name, item)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, item := range strings.Split(value, ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY value %q", item)
+		}
+		code := item[len(item)-2:]
+		weekday, ok := weekdayCodes[code]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY weekday %q", item)
+		}
+		day := WeekdayNum{Weekday: weekday}
+		if prefix := item[:len(item)-2]; prefix != "" {
+			ordinal, err := strconv.Atoi(prefix)
+			if err != nil || ordinal == 0 || ordinal < -5 || ordinal > 5 {
+				return nil, fmt.Errorf("invalid BYDAY ordinal %q", item)
+			}
+			day.Ordinal = ordinal
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// parseUntil accepts UTC date-times and plain dates. A plain date includes
+// the whole day.
+func parseUntil(value string) (*time.Time, error) {
+	if until, err := time.Parse(untilLayout, value); err == nil {
+		return &until, nil
+	}
+	if until, err := time.Parse("20060102T150405", value); err == nil {
+		return &until, nil
+	}
+	if date, err := time.Parse("20060102", value); err == nil {
+		until := date.Add(24*time.Hour - time.Second)
+		return &until, nil
+	}
+	return nil, fmt.Errorf("invalid UNTIL %q", value)
+}
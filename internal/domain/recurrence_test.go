@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rrule   string
+		want    string
+		wantErr bool
+	}{
+		{name: "weekly on monday", rrule: "FREQ=WEEKLY;BYDAY=MO", want: "FREQ=WEEKLY;BYDAY=MO"},
+		{name: "prefix and lower case", rrule: "RRULE:freq=monthly;bymonthday=-1", want: "FREQ=MONTHLY;BYMONTHDAY=-1"},
+		{name: "interval and count", rrule: "FREQ=DAILY;INTERVAL=2;COUNT=5", want: "FREQ=DAILY;INTERVAL=2;COUNT=5"},
+		{name: "date until", rrule: "FREQ=WEEKLY;UNTIL=20300101", want: "FREQ=WEEKLY;UNTIL=20300101T235959Z"},
+		{name: "ordinal weekday", rrule: "FREQ=MONTHLY;BYDAY=-1FR", want: "FREQ=MONTHLY;BYDAY=-1FR"},
+		{name: "empty", rrule: "", wantErr: true},
+		{name: "missing freq", rrule: "BYDAY=MO", wantErr: true},
+		{name: "unknown freq", rrule: "FREQ=HOURLY", wantErr: true},
+		{name: "unsupported part", rrule: "FREQ=DAILY;BYHOUR=9", wantErr: true},
+		{name: "duplicate part", rrule: "FREQ=DAILY;FREQ=WEEKLY", wantErr: true},
+		{name: "count and until", rrule: "FREQ=DAILY;COUNT=2;UNTIL=20300101", wantErr: true},
+		{name: "zero interval", rrule: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{name: "bad weekday", rrule: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{name: "ordinal with weekly", rrule: "FREQ=WEEKLY;BYDAY=1MO", wantErr: true},
+		{name: "monthday with weekly", rrule: "FREQ=WEEKLY;BYMONTHDAY=1", wantErr: true},
+		{name: "month out of range", rrule: "FREQ=YEARLY;BYMONTH=13", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rrule)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRRule) {
+					t.Errorf("ParseRRule(%q) error = %v, want %v", tt.rrule, err, ErrInvalidRRule)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRRule(%q) error = %v", tt.rrule, err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("ParseRRule(%q).String() = %q, want %q", tt.rrule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurrenceRule_Next(t *testing.T) {
+	// Monday 5 January 2026, 09:00
+	monday := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		rrule   string
+		dtstart time.Time
+		want    []string
+	}{
+		{name: "weekly", rrule: "FREQ=WEEKLY;BYDAY=MO", dtstart: monday, want: []string{"2026-01-12", "2026-01-19", "2026-01-26"}},
+		{name: "several weekdays", rrule: "FREQ=WEEKLY;BYDAY=MO,WE,FR", dtstart: monday, want: []string{"2026-01-07", "2026-01-09", "2026-01-12"}},
+		{name: "every other week", rrule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU", dtstart: monday, want: []string{"2026-01-06", "2026-01-20", "2026-02-03"}},
+		{name: "last day of month", rrule: "FREQ=MONTHLY;BYMONTHDAY=-1", dtstart: monday, want: []string{"2026-01-31", "2026-02-28", "2026-03-31"}},
+		{name: "last friday of month", rrule: "FREQ=MONTHLY;BYDAY=-1FR", dtstart: monday, want: []string{"2026-01-30", "2026-02-27", "2026-03-27"}},
+		{name: "monthly skips short months", rrule: "FREQ=MONTHLY", dtstart: time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC), want: []string{"2026-03-31", "2026-05-31", "2026-07-31"}},
+		{name: "leap day", rrule: "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29", dtstart: monday, want: []string{"2028-02-29", "2032-02-29"}},
+		{name: "count ends series", rrule: "FREQ=DAILY;INTERVAL=3;COUNT=3", dtstart: monday, want: []string{"2026-01-08", "2026-01-11"}},
+		{name: "until ends series", rrule: "FREQ=DAILY;UNTIL=20260107", dtstart: monday, want: []string{"2026-01-06", "2026-01-07"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rrule)
+			if err != nil {
+				t.Fatalf("ParseRRule(%q) error = %v", tt.rrule, err)
+			}
+
+			var got []string
+			after := tt.dtstart
+			for len(got) <= len(tt.want) {
+				next, ok := rule.Next(tt.dtstart, after)
+				if !ok {
+					break
+				}
+				if next.Hour() != 9 {
+					t.Errorf("Next() = %v, want time of day kept from dtstart", next)
+				}
+				got = append(got, next.Format("2006-01-02"))
+				after = next
+			}
+
+			if len(got) > len(tt.want) {
+				got = got[:len(tt.want)]
+			}
+			for i := range tt.want {
+				if i >= len(got) || got[i] != tt.want[i] {
+					t.Fatalf("occurrences = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRecurrenceRule_NextEndsSeries(t *testing.T) {
+	rule, _ := ParseRRule("FREQ=DAILY;COUNT=2")
+	start := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	if next, ok := rule.Next(start, start); !ok || !next.Equal(start.AddDate(0, 0, 1)) {
+		t.Fatalf("Next() = %v, %v; want second occurrence", next, ok)
+	}
+	if _, ok := rule.Next(start, start.AddDate(0, 0, 1)); ok {
+		t.Error("Next() after the last occurrence should report the series as ended")
+	}
+}
+
+func TestCreateTaskRequest_ValidateRRule(t *testing.T) {
+	due := time.Now().AddDate(0, 0, 1)
+	parent := int64(1)
+
+	tests := []struct {
+		name    string
+		req     CreateTaskRequest
+		wantErr bool
+	}{
+		{name: "recurring with due date", req: CreateTaskRequest{Title: "Report", Priority: PriorityLow, DueDate: &due, RRule: "FREQ=WEEKLY"}},
+		{name: "recurring without due date", req: CreateTaskRequest{Title: "Report", Priority: PriorityLow, RRule: "FREQ=WEEKLY"}, wantErr: true},
+		{name: "invalid rule", req: CreateTaskRequest{Title: "Report", Priority: PriorityLow, DueDate: &due, RRule: "FREQ=SOMETIMES"}, wantErr: true},
+		{name: "recurring subtask", req: CreateTaskRequest{Title: "Report", Priority: PriorityLow, DueDate: &due, RRule: "FREQ=WEEKLY", ParentID: &parent}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("CreateTaskRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReminderKind tells whether a reminder warns of an upcoming due date or of
+// one that has passed. A task gets at most one reminder of each kind per due
+// date, so moving the due date makes it eligible again.
+type ReminderKind string
+
+const (
+	ReminderDueSoon ReminderKind = "due_soon"
+	ReminderOverdue ReminderKind = "overdue"
+)
+
+// Reminder tells a task's owner about its due date
+type Reminder struct {
+	TaskID  int64        `json:"task_id"`
+	UserID  int64        `json:"user_id"`
+	Email   string       `json:"email"`
+	Title   string       `json:"title"`
+	Kind    ReminderKind `json:"kind"`
+	DueDate time.Time    `json:"due_date"`
+	SentAt  time.Time    `json:"sent_at"`
+	// Attempts counts the failed deliveries of the reminder so far
+	Attempts int `json:"attempts"`
+}
+
+// ReminderRetryPolicy controls how reminders that fail to be delivered are
+// retried. Delays grow as they do for webhook deliveries.
+type ReminderRetryPolicy WebhookRetryPolicy
+
+// RetryDelay returns how long to wait after the given failed attempt,
+// counting from 1
+func (p ReminderRetryPolicy) RetryDelay(attempt int) time.Duration {
+	return WebhookRetryPolicy(p).RetryDelay(attempt)
+}
+
+// Subject returns a one-line summary of the reminder
+func (r *Reminder) Subject() string {
+	// Titles may contain line breaks, which must not reach a mail header
+	title := strings.Join(strings.Fields(r.Title), " ")
+	if r.Kind == ReminderOverdue {
+		return fmt.Sprintf("Overdue: %s", title)
+	}
+	return fmt.Sprintf("Due soon: %s", title)
+}
+
+// Body returns the text of the reminder
+func (r *Reminder) Body() string {
+	due := r.DueDate.UTC().Format("Mon, 02 Jan 2006 15:04 MST")
+	if r.Kind == ReminderOverdue {
+		return fmt.Sprintf("Task #%d %q was due on %s and is not finished yet.\n", r.TaskID, r.Title, due)
+	}
+	return fmt.Sprintf("Task #%d %q is due on %s.\n", r.TaskID, r.Title, due)
+}
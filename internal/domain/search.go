@@ -0,0 +1,315 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidSearch = errors.New("invalid search")
+
+// Search operators. Like the FTS5 query syntax they are only recognised in
+// upper case; NOT binds tightest, then AND, then OR.
+const (
+	searchAnd = "AND"
+	searchOr  = "OR"
+	searchNot = "NOT"
+)
+
+// SearchQuery is a parsed full-text search. It supports bare words,
+// "quoted phrases", a trailing * for prefixes, AND, OR, NOT and parentheses.
+// Words are sequences of letters and digits, so any other character in the
+// input separates words instead of acting as a wildcard.
+type SearchQuery struct {
+	root *searchNode
+}
+
+// searchNode is either a term (op is empty) or an operator with two operands
+type searchNode struct {
+	op          string
+	words       []string
+	prefix      bool
+	left, right *searchNode
+}
+
+// ParseSearchQuery parses a search string
+func ParseSearchQuery(input string) (*SearchQuery, error) {
+	tokens, err := lexSearch(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: no words to search for", ErrInvalidSearch)
+	}
+
+	p := &searchParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("%w: unexpected %s", ErrInvalidSearch, p.tokens[p.pos].describe())
+	}
+	return &SearchQuery{root: root}, nil
+}
+
+// MatchExpression renders the query in SQLite FTS5 syntax. Every term is
+// quoted, so the result is always a valid FTS5 query.
+func (q *SearchQuery) MatchExpression() string {
+	return q.root.matchExpression()
+}
+
+func (n *searchNode) matchExpression() string {
+	if n.op == "" {
+		expr := `"` + strings.Join(n.words, " ") + `"`
+		if n.prefix {
+			expr += "*"
+		}
+		return expr
+	}
+	return "(" + n.left.matchExpression() + " " + n.op + " " + n.right.matchExpression() + ")"
+}
+
+// Matches evaluates the query against the fields of a document the way the
+// full-text index would, ignoring case. A phrase has to lie within a field.
+func (q *SearchQuery) Matches(fields ...string) bool {
+	words := make([][]string, len(fields))
+	for i, field := range fields {
+		words[i] = searchWords(field)
+	}
+	return q.root.matches(words)
+}
+
+func (n *searchNode) matches(fields [][]string) bool {
+	switch n.op {
+	case searchAnd:
+		return n.left.matches(fields) && n.right.matches(fields)
+	case searchOr:
+		return n.left.matches(fields) || n.right.matches(fields)
+	case searchNot:
+		return n.left.matches(fields) && !n.right.matches(fields)
+	}
+
+	for _, words := range fields {
+		for start := 0; start+len(n.words) <= len(words); start++ {
+			if n.matchesAt(words[start:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesAt reports whether the term's words start the given words
+func (n *searchNode) matchesAt(words []string) bool {
+	last := len(n.words) - 1
+	for i, word := range n.words {
+		if i == last && n.prefix {
+			return strings.HasPrefix(words[i], word)
+		}
+		if words[i] != word {
+			return false
+		}
+	}
+	return true
+}
+
+// searchWords splits text into lower-case words
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+type searchTokenKind int
+
+const (
+	searchTerm searchTokenKind = iota
+	searchOperator
+	searchOpen
+	searchClose
+)
+
+type searchToken struct {
+	kind   searchTokenKind
+	text   string
+	words  []string
+	prefix bool
+}
+
+func (t searchToken) describe() string {
+	switch t.kind {
+	case searchOperator:
+		return t.text
+	case searchOpen:
+		return `"("`
+	case searchClose:
+		return `")"`
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// lexSearch splits a search string into tokens. Terms without any letter or
+// digit are dropped.
+func lexSearch(input string) ([]searchToken, error) {
+	var tokens []searchToken
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, searchToken{kind: searchOpen})
+			i++
+		case r == ')':
+			tokens = append(tokens, searchToken{kind: searchClose})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("%w: unterminated quote", ErrInvalidSearch)
+			}
+			token := searchToken{kind: searchTerm, text: string(runes[i+1 : end])}
+			i = end + 1
+			if i < len(runes) && runes[i] == '*' {
+				token.prefix = true
+				i++
+			}
+			if token.words = searchWords(token.text); len(token.words) > 0 {
+				tokens = append(tokens, token)
+			}
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`()"`, runes[end]) {
+				end++
+			}
+			text := string(runes[i:end])
+			i = end
+			if text == searchAnd || text == searchOr || text == searchNot {
+				tokens = append(tokens, searchToken{kind: searchOperator, text: text})
+				continue
+			}
+			token := searchToken{kind: searchTerm, text: text}
+			if strings.HasSuffix(text, "*") {
+				token.prefix = true
+				text = strings.TrimRight(text, "*")
+			}
+			if token.words = searchWords(text); len(token.words) > 0 {
+				tokens = append(tokens, token)
+			}
+		}
+	}
+	return tokens, nil
+}
+
+type searchParser struct {
+	tokens []searchToken
+	pos    int
+}
+
+func (p *searchParser) peek() (searchToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return searchToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// acceptOperator consumes the next token if it is the operator op
+func (p *searchParser) acceptOperator(op string) bool {
+	if token, ok := p.peek(); ok && token.kind == searchOperator && token.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *searchParser) parseOr() (*searchNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOperator(searchOr) {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &searchNode{op: searchOr, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *searchParser) parseAnd() (*searchNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		// Terms next to each other must all match, as with AND
+		if !p.acceptOperator(searchAnd) {
+			token, ok := p.peek()
+			if !ok || (token.kind != searchTerm && token.kind != searchOpen) {
+				return left, nil
+			}
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &searchNode{op: searchAnd, left: left, right: right}
+	}
+}
+
+func (p *searchParser) parseNot() (*searchNode, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOperator(searchNot) {
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		left = &searchNode{op: searchNot, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *searchParser) parsePrimary() (*searchNode, error) {
+	token, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("%w: expected a word at the end", ErrInvalidSearch)
+	}
+	p.pos++
+
+	switch token.kind {
+	case searchTerm:
+		return &searchNode{words: token.words, prefix: token.prefix}, nil
+	case searchOpen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if next, ok := p.peek(); !ok || next.kind != searchClose {
+			return nil, fmt.Errorf("%w: missing \")\"", ErrInvalidSearch)
+		}
+		p.pos++
+		return node, nil
+	default:
+		return nil, fmt.Errorf("%w: expected a word before %s", ErrInvalidSearch, token.describe())
+	}
+}
+
+// TaskSearchMatch describes how a task matched a full-text search. Title and
+// Snippet are HTML-escaped, with the matched words wrapped in <mark> tags.
+type TaskSearchMatch struct {
+	// Rank orders results from best to worst match; lower is better
+	Rank    float64 `json:"rank"`
+	Title   string  `json:"title"`
+	Snippet string  `json:"snippet"`
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSearchQuery_MatchExpression(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"report", `"report"`},
+		{"Quarterly report", `("quarterly" AND "report")`},
+		{`"quarterly report"`, `"quarterly report"`},
+		{"rep*", `"rep"*`},
+		{`"year end"*`, `"year end"*`},
+		{"report OR summary", `("report" OR "summary")`},
+		{"report NOT draft", `("report" NOT "draft")`},
+		{"a OR b c", `("a" OR ("b" AND "c"))`},
+		{"(a OR b) c", `(("a" OR "b") AND "c")`},
+		{"a AND b NOT c", `("a" AND ("b" NOT "c"))`},
+		{"test@email.com", `"test email com"`},
+		{`say "hi"`, `("say" AND "hi")`},
+		{"report % draft", `("report" AND "draft")`},
+		{"or and", `("or" AND "and")`},
+	}
+
+	for _, tt := range tests {
+		query, err := ParseSearchQuery(tt.input)
+		if err != nil {
+			t.Errorf("ParseSearchQuery(%q) error = %v", tt.input, err)
+			continue
+		}
+		if got := query.MatchExpression(); got != tt.want {
+			t.Errorf("ParseSearchQuery(%q).MatchExpression() = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseSearchQuery_Invalid(t *testing.T) {
+	for _, input := range []string{"", "%", `"unterminated`, "(report", "report)", "OR report", "report AND", "NOT report", "()"} {
+		if _, err := ParseSearchQuery(input); !errors.Is(err, ErrInvalidSearch) {
+			t.Errorf("ParseSearchQuery(%q) error = %v, want ErrInvalidSearch", input, err)
+		}
+	}
+}
+
+func TestSearchQuery_Matches(t *testing.T) {
+	title := "Quarterly report"
+	description := "Draft due Friday, ask finance"
+
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"REPORT", true},
+		{"quarter*", true},
+		{"quarter", false},
+		{`"quarterly report"`, true},
+		{`"report quarterly"`, false},
+		{`"report draft"`, false},
+		{"report finance", true},
+		{"report NOT draft", false},
+		{"report NOT budget", true},
+		{"budget OR friday", true},
+		{"(budget OR memo) report", false},
+	}
+
+	for _, tt := range tests {
+		query, err := ParseSearchQuery(tt.input)
+		if err != nil {
+			t.Fatalf("ParseSearchQuery(%q) error = %v", tt.input, err)
+		}
+		if got := query.Matches(title, description); got != tt.want {
+			t.Errorf("ParseSearchQuery(%q).Matches() = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestTaskFilters_ValidateRelevance(t *testing.T) {
+	filters := TaskFilters{Sort: []SortField{{Field: SortByRelevance}}}
+	if err := filters.Validate(DefaultWorkflow()); err == nil {
+		t.Error("Expected sorting by relevance without a search to fail")
+	}
+	filters.Search = "report"
+	if err := filters.Validate(DefaultWorkflow()); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
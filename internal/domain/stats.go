@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Default and maximum number of days covered by the daily task counts
+const (
+	DefaultStatsDays = 30
+	MaxStatsDays     = 366
+)
+
+// StatsWindow is the range of UTC days, From through To inclusive, covered
+// by the daily counts of TaskStats
+type StatsWindow struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// NewStatsWindow returns the window of the given number of days ending on
+// the day of now
+func NewStatsWindow(now time.Time, days int) StatsWindow {
+	to := startOfDay(now)
+	return StatsWindow{From: to.AddDate(0, 0, -(days - 1)), To: to}
+}
+
+// Days returns the number of days in the window
+func (w StatsWindow) Days() int {
+	return int(w.To.Sub(w.From).Hours()/24) + 1
+}
+
+// Validate checks if the StatsWindow is valid
+func (w StatsWindow) Validate() error {
+	if w.To.Before(w.From) {
+		return fmt.Errorf("stats window must not end before it starts")
+	}
+	if w.Days() > MaxStatsDays {
+		return fmt.Errorf("stats window cannot exceed %d days", MaxStatsDays)
+	}
+	return nil
+}
+
+// CategoryCount is the number of tasks in one category
+type CategoryCount struct {
+	CategoryID int64  `json:"category_id"`
+	Name       string `json:"name"`
+	Count      int    `json:"count"`
+}
+
+// DailyTaskCount counts the tasks created and completed on one UTC day
+type DailyTaskCount struct {
+	Date      string `json:"date"`
+	Created   int    `json:"created"`
+	Completed int    `json:"completed"`
+}
+
+// TaskStats summarizes the tasks matching a set of filters. Overdue and due
+// counts only include tasks that are not done; days and weeks are in UTC,
+// with weeks starting on Monday.
+type TaskStats struct {
+	Total         int                  `json:"total"`
+	ByStatus      map[TaskStatus]int   `json:"by_status"`
+	ByPriority    map[TaskPriority]int `json:"by_priority"`
+	ByCategory    []CategoryCount      `json:"by_category"`
+	Uncategorized int                  `json:"uncategorized"`
+	Overdue       int                  `json:"overdue"`
+	DueToday      int                  `json:"due_today"`
+	DueThisWeek   int                  `json:"due_this_week"`
+	Window        StatsWindow          `json:"window"`
+	Daily         []DailyTaskCount     `json:"daily"`
+}
+
+// NewTaskStats returns empty stats with every status, priority and day of
+// the window counted as zero
+func NewTaskStats(window StatsWindow, workflow *Workflow) *TaskStats {
+	stats := &TaskStats{
+		ByStatus:   map[TaskStatus]int{},
+		ByPriority: map[TaskPriority]int{},
+		ByCategory: []CategoryCount{},
+		Window:     window,
+		Daily:      make([]DailyTaskCount, window.Days()),
+	}
+	for _, status := range workflow.Statuses {
+		stats.ByStatus[status.Key] = 0
+	}
+	for _, priority := range priorityLevels {
+		stats.ByPriority[priority] = 0
+	}
+	for i := range stats.Daily {
+		stats.Daily[i].Date = window.From.AddDate(0, 0, i).Format("2006-01-02")
+	}
+	return stats
+}
+
+// DueRanges returns the start of the UTC day and week containing now, and
+// the end of each
+func DueRanges(now time.Time) (dayStart, dayEnd, weekStart, weekEnd time.Time) {
+	dayStart = startOfDay(now)
+	// Go weeks start on Sunday
+	weekStart = dayStart.AddDate(0, 0, -((int(dayStart.Weekday()) + 6) % 7))
+	return dayStart, dayStart.AddDate(0, 0, 1), weekStart, weekStart.AddDate(0, 0, 7)
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
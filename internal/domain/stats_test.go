@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatsWindow(t *testing.T) {
+	now := time.Date(2026, 3, 2, 15, 4, 5, 0, time.UTC)
+
+	window := NewStatsWindow(now, 3)
+	if window.From.Format("2006-01-02") != "2026-02-28" || window.To.Format("2006-01-02") != "2026-03-02" {
+		t.Errorf("NewStatsWindow() = %v to %v", window.From, window.To)
+	}
+	if window.Days() != 3 {
+		t.Errorf("Days() = %d, want 3", window.Days())
+	}
+
+	stats := NewTaskStats(window, DefaultWorkflow())
+	if len(stats.Daily) != 3 || stats.Daily[1].Date != "2026-03-01" {
+		t.Errorf("Expected a zero count for each day, got %+v", stats.Daily)
+	}
+	if _, ok := stats.ByPriority[PriorityCritical]; !ok {
+		t.Error("Expected every priority to be counted")
+	}
+
+	tests := []struct {
+		name        string
+		window      StatsWindow
+		expectError bool
+	}{
+		{name: "single_day", window: NewStatsWindow(now, 1)},
+		{name: "longest", window: NewStatsWindow(now, MaxStatsDays)},
+		{name: "too_long", window: NewStatsWindow(now, MaxStatsDays+1), expectError: true},
+		{name: "reversed", window: StatsWindow{From: window.To, To: window.From}, expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.window.Validate()
+			if (err != nil) != tt.expectError {
+				t.Errorf("Validate() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestDueRanges(t *testing.T) {
+	tests := []struct {
+		now       time.Time
+		weekStart string
+	}{
+		{time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC), "2026-03-02"},
+		{time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), "2026-03-02"},
+		{time.Date(2026, 3, 8, 23, 59, 0, 0, time.UTC), "2026-03-02"},
+	}
+
+	for _, tt := range tests {
+		dayStart, dayEnd, weekStart, weekEnd := DueRanges(tt.now)
+		if dayStart.Format("2006-01-02") != tt.now.Format("2006-01-02") || dayEnd.Sub(dayStart) != 24*time.Hour {
+			t.Errorf("DueRanges(%v) day = %v to %v", tt.now, dayStart, dayEnd)
+		}
+		if weekStart.Format("2006-01-02") != tt.weekStart || weekEnd.Sub(weekStart) != 7*24*time.Hour {
+			t.Errorf("DueRanges(%v) week = %v to %v, want it to start on %s", tt.now, weekStart, weekEnd, tt.weekStart)
+		}
+	}
+}
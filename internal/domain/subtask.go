@@ -0,0 +1,77 @@
+package domain
+
+import "errors"
+
+var (
+	ErrOpenSubtasks  = errors.New("task has open subtasks")
+	ErrParentDone    = errors.New("parent task is done")
+	ErrInvalidParent = errors.New("a task cannot be its own ancestor")
+)
+
+// SubtaskDeleteMode controls what happens to subtasks when a parent is deleted
+type SubtaskDeleteMode string
+
+const (
+	// SubtaskDeleteReparent moves the subtasks up to the deleted task's parent
+	SubtaskDeleteReparent SubtaskDeleteMode = "reparent"
+	// SubtaskDeleteCascade deletes the whole subtree
+	SubtaskDeleteCascade SubtaskDeleteMode = "cascade"
+)
+
+// IsValid reports whether the mode is a known SubtaskDeleteMode
+func (m SubtaskDeleteMode) IsValid() bool {
+	return m == SubtaskDeleteReparent || m == SubtaskDeleteCascade
+}
+
+// TaskProgress summarizes how many direct subtasks of a task are done
+type TaskProgress struct {
+	Done    int `json:"done"`
+	Total   int `json:"total"`
+	Percent int `json:"percent"`
+}
+
+// NewTaskProgress returns the progress for a task with the given subtask
+// counts, or nil if the task has no subtasks
+func NewTaskProgress(done, total int) *TaskProgress {
+	if total == 0 {
+		return nil
+	}
+	return &TaskProgress{
+		Done:    done,
+		Total:   total,
+		Percent: done * 100 / total,
+	}
+}
+
+// HasOpenSubtasks reports whether any direct subtask of the task is not done
+func (t *Task) HasOpenSubtasks() bool {
+	return t.Progress != nil && t.Progress.Done < t.Progress.Total
+}
+
+// TaskNode is a task together with its nested subtasks
+type TaskNode struct {
+	*Task
+	Children []*TaskNode `json:"children"`
+}
+
+// BuildTaskTree arranges descendants of root into a tree. Descendants whose
+// parent is not part of the tree are ignored.
+func BuildTaskTree(root *Task, descendants []*Task) *TaskNode {
+	rootNode := &TaskNode{Task: root, Children: []*TaskNode{}}
+	nodes := map[int64]*TaskNode{root.ID: rootNode}
+	for _, task := range descendants {
+		nodes[task.ID] = &TaskNode{Task: task, Children: []*TaskNode{}}
+	}
+
+	// Attach in input order so siblings keep the order they were queried in
+	for _, task := range descendants {
+		if task.ParentID == nil {
+			continue
+		}
+		if parent, ok := nodes[*task.ParentID]; ok && task.ID != root.ID {
+			parent.Children = append(parent.Children, nodes[task.ID])
+		}
+	}
+
+	return rootNode
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "testing"
+
+func TestNewTaskProgress(t *testing.T) {
+	if got := NewTaskProgress(0, 0); got != nil {
+		t.Errorf("NewTaskProgress(0, 0) = %+v, want nil", got)
+	}
+
+	got := NewTaskProgress(1, 3)
+	if got == nil || got.Done != 1 || got.Total != 3 || got.Percent != 33 {
+		t.Errorf("NewTaskProgress(1, 3) = %+v, want 1/3 at 33%%", got)
+	}
+
+	task := &Task{Progress: NewTaskProgress(2, 2)}
+	if task.HasOpenSubtasks() {
+		t.Error("HasOpenSubtasks() = true for fully done subtasks")
+	}
+	task.Progress = NewTaskProgress(1, 2)
+	if !task.HasOpenSubtasks() {
+		t.Error("HasOpenSubtasks() = false with an open subtask")
+	}
+}
+
+func TestBuildTaskTree(t *testing.T) {
+	id := func(v int64) *int64 { return &v }
+	root := &Task{ID: 1}
+	descendants := []*Task{
+		{ID: 2, ParentID: id(1)},
+		{ID: 3, ParentID: id(1)},
+		{ID: 4, ParentID: id(2)},
+		{ID: 5, ParentID: id(99)},
+	}
+
+	tree := BuildTaskTree(root, descendants)
+
+	if tree.ID != 1 || len(tree.Children) != 2 {
+		t.Fatalf("root = %d with %d children, want 1 with 2", tree.ID, len(tree.Children))
+	}
+	if tree.Children[0].ID != 2 || tree.Children[1].ID != 3 {
+		t.Errorf("children = [%d %d], want [2 3]", tree.Children[0].ID, tree.Children[1].ID)
+	}
+	if len(tree.Children[0].Children) != 1 || tree.Children[0].Children[0].ID != 4 {
+		t.Errorf("task 2 children = %v, want [4]", tree.Children[0].Children)
+	}
+	if len(tree.Children[1].Children) != 0 {
+		t.Errorf("task 3 children = %v, want none", tree.Children[1].Children)
+	}
+}
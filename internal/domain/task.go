@@ -3,6 +3,7 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -24,15 +25,36 @@ const (
 )
 
 type Task struct {
-	ID          int64        `json:"id"`
-	Title       string       `json:"title"`
-	Description string       `json:"description"`
-	Status      TaskStatus   `json:"status"`
-	Priority    TaskPriority `json:"priority"`
-	DueDate     *time.Time   `json:"due_date"`
-	Categories  []Category   `json:"categories"`
-	CreatedAt   time.Time    `json:"created_at"`
-	UpdatedAt   time.Time    `json:"updated_at"`
+	ID             int64         `json:"id"`
+	UserID         int64         `json:"user_id"`
+	Title          string        `json:"title"`
+	Description    string        `json:"description"`
+	Status         TaskStatus    `json:"status"`
+	Priority       TaskPriority  `json:"priority"`
+	Rank           string        `json:"rank"`
+	DueDate        *time.Time    `json:"due_date"`
+	ParentID       *int64        `json:"parent_id"`
+	SeriesID       *int64        `json:"series_id,omitempty"`
+	RRule          string        `json:"rrule,omitempty"`
+	Progress       *TaskProgress `json:"progress,omitempty"`
+	Categories     []Category    `json:"categories"`
+	CommentCount   int           `json:"comment_count"`
+	TrackedSeconds int64         `json:"tracked_seconds"`
+	BlockedBy      []TaskRef     `json:"blocked_by,omitempty"`
+	Blocking       []TaskRef     `json:"blocking,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+	CompletedAt    *time.Time    `json:"completed_at,omitempty"`
+	DeletedAt      *time.Time    `json:"deleted_at,omitempty"`
+	Version        int64         `json:"version"`
+	// Match is set on tasks listed by a full-text search
+	Match *TaskSearchMatch `json:"match,omitempty"`
+}
+
+// IsOverdue reports whether the task is not closed in workflow although its
+// due date has passed
+func (t *Task) IsOverdue(workflow *Workflow, now time.Time) bool {
+	return t.DueDate != nil && t.DueDate.Before(now) && !workflow.IsClosed(t.Status)
 }
 
 type CreateTaskRequest struct {
@@ -40,6 +62,8 @@ type CreateTaskRequest struct {
 	Description string       `json:"description"`
 	Priority    TaskPriority `json:"priority"`
 	DueDate     *time.Time   `json:"due_date"`
+	ParentID    *int64       `json:"parent_id,omitempty"`
+	RRule       string       `json:"rrule,omitempty"`
 	CategoryIDs []int64      `json:"category_ids"`
 }
 
@@ -49,10 +73,24 @@ type UpdateTaskRequest struct {
 	Status      *TaskStatus   `json:"status,omitempty"`
 	Priority    *TaskPriority `json:"priority,omitempty"`
 	DueDate     *time.Time    `json:"due_date,omitempty"`
+	ParentID    *int64        `json:"parent_id,omitempty"` // 0 detaches the task from its parent
 	CategoryIDs *[]int64      `json:"category_ids,omitempty"`
+	// RRule changes the recurrence of a series, or makes a task recurring.
+	// An empty rule with the series scope ends the recurrence.
+	RRule *string         `json:"rrule,omitempty"`
+	Scope RecurrenceScope `json:"scope,omitempty"`
+	// ExpectedVersion makes the update fail with ErrVersionConflict unless
+	// the task is still at this version. It is set from If-Match.
+	ExpectedVersion *int64 `json:"-"`
+	// Rank places the task within its status column. It is set by MoveTask;
+	// a status change without it moves the task to the end of the column.
+	Rank *string `json:"-"`
 }
 
-func (t *Task) Validate() error {
+// Validate checks the task, whose status must be part of workflow. Its due
+// date may have passed since it was set; only new due dates must not be in
+// the past.
+func (t *Task) Validate(workflow *Workflow) error {
 	if t.Title == "" {
 		return errors.New("title is required")
 	}
@@ -62,15 +100,12 @@ func (t *Task) Validate() error {
 	if len(t.Description) > 1000 {
 		return errors.New("description must be less than 1000 characters")
 	}
-	if !isValidStatus(t.Status) {
+	if workflow.Status(t.Status) == nil {
 		return errors.New("invalid status")
 	}
 	if !isValidPriority(t.Priority) {
 		return errors.New("invalid priority")
 	}
-	if t.DueDate != nil && t.DueDate.Before(time.Now().Truncate(24*time.Hour)) {
-		return errors.New("due date cannot be in the past")
-	}
 	return nil
 }
 
@@ -87,13 +122,28 @@ func (r *CreateTaskRequest) Validate() error {
 	if !isValidPriority(r.Priority) {
 		return errors.New("invalid priority")
 	}
-	if r.DueDate != nil && r.DueDate.Before(time.Now().Truncate(24*time.Hour)) {
+	if isPastDueDate(r.DueDate) {
 		return errors.New("due date cannot be in the past")
 	}
+	if r.ParentID != nil && *r.ParentID <= 0 {
+		return errors.New("invalid parent id")
+	}
+	if r.RRule != "" {
+		if _, err := ParseRRule(r.RRule); err != nil {
+			return err
+		}
+		if r.DueDate == nil {
+			return errors.New("recurring tasks require a due date")
+		}
+		if r.ParentID != nil {
+			return ErrSeriesSubtask
+		}
+	}
 	return nil
 }
 
-func (r *UpdateTaskRequest) Validate() error {
+// Validate checks the request, whose status must be part of workflow
+func (r *UpdateTaskRequest) Validate(workflow *Workflow) error {
 	if r.Title != nil {
 		if *r.Title == "" {
 			return errors.New("title cannot be empty")
@@ -105,22 +155,45 @@ func (r *UpdateTaskRequest) Validate() error {
 	if r.Description != nil && len(*r.Description) > 1000 {
 		return errors.New("description must be less than 1000 characters")
 	}
-	if r.Status != nil && !isValidStatus(*r.Status) {
+	if r.Status != nil && workflow.Status(*r.Status) == nil {
 		return errors.New("invalid status")
 	}
 	if r.Priority != nil && !isValidPriority(*r.Priority) {
 		return errors.New("invalid priority")
 	}
+	// Only a new due date is checked, so overdue tasks can still be changed
+	if isPastDueDate(r.DueDate) {
+		return errors.New("due date cannot be in the past")
+	}
+	if r.ParentID != nil && *r.ParentID < 0 {
+		return errors.New("invalid parent id")
+	}
+	if r.Scope != "" && !r.Scope.IsValid() {
+		return ErrInvalidScope
+	}
+	if r.RRule != nil && *r.RRule != "" {
+		if _, err := ParseRRule(*r.RRule); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func isValidStatus(status TaskStatus) bool {
-	switch status {
-	case StatusTodo, StatusDoing, StatusDone:
-		return true
-	default:
-		return false
+// ValidateTransition checks that the workflow allows moving a task from its
+// current status to the requested one
+func (r *UpdateTaskRequest) ValidateTransition(workflow *Workflow, from TaskStatus) error {
+	if r.Status == nil || *r.Status == from {
+		return nil
 	}
+	if !workflow.CanTransition(from, *r.Status) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, *r.Status)
+	}
+	return nil
+}
+
+// isPastDueDate reports whether a due date falls before today
+func isPastDueDate(dueDate *time.Time) bool {
+	return dueDate != nil && dueDate.Before(time.Now().Truncate(24*time.Hour))
 }
 
 func isValidPriority(priority TaskPriority) bool {
@@ -147,17 +220,110 @@ func GetPriorityOrder(priority TaskPriority) int {
 	}
 }
 
-// TaskFilters represents filters for task queries
+// Sortable task fields
+const (
+	SortByID        = "id"
+	SortByTitle     = "title"
+	SortByStatus    = "status"
+	SortByPriority  = "priority"
+	SortByDueDate   = "due_date"
+	SortByCreatedAt = "created_at"
+	SortByUpdatedAt = "updated_at"
+	// SortByRank orders tasks as placed on the board within each status
+	SortByRank = "rank"
+	// SortByRelevance orders full-text search results best match first
+	SortByRelevance = "relevance"
+)
+
+// SortField is one term of a client-selected task ordering
+type SortField struct {
+	Field      string `json:"field"`
+	Descending bool   `json:"descending,omitempty"`
+}
+
+// CategoryMatchMode selects whether a task needs any or all of the filtered
+// categories
+type CategoryMatchMode string
+
+const (
+	CategoryMatchAny CategoryMatchMode = "any"
+	CategoryMatchAll CategoryMatchMode = "all"
+)
+
+// TaskFilters represents filters for task queries. Time ranges include
+// their After bound and exclude their Before bound.
 type TaskFilters struct {
 	Statuses   []TaskStatus   `json:"statuses,omitempty"`
 	Priorities []TaskPriority `json:"priorities,omitempty"`
 	Search     string         `json:"search,omitempty"`
+	// CategoryIDs matches tasks in any of the categories, or in all of them
+	// when CategoryMode is all
+	CategoryIDs  []int64           `json:"category_ids,omitempty"`
+	CategoryMode CategoryMatchMode `json:"category_mode,omitempty"`
+	DueAfter     *time.Time        `json:"due_after,omitempty"`
+	DueBefore    *time.Time        `json:"due_before,omitempty"`
+	HasDueDate   *bool             `json:"has_due_date,omitempty"`
+	// Overdue matches tasks that are not done past their due date
+	Overdue       bool       `json:"overdue,omitempty"`
+	CreatedAfter  *time.Time `json:"created_after,omitempty"`
+	CreatedBefore *time.Time `json:"created_before,omitempty"`
+	UpdatedAfter  *time.Time `json:"updated_after,omitempty"`
+	UpdatedBefore *time.Time `json:"updated_before,omitempty"`
+	// Where holds conditions that cannot be expressed by the fields above,
+	// such as those compiled from a filter query by ParseTaskQuery
+	Where *FilterExpr `json:"where,omitempty"`
+	Sort  []SortField `json:"sort,omitempty"`
+}
+
+// ParseFilterTime parses a time filter given as RFC 3339 or as a date, which
+// stands for midnight UTC
+func ParseFilterTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: use RFC 3339 or YYYY-MM-DD", value)
+	}
+	return t, nil
+}
+
+// ParseSort parses a comma-separated sort list such as "-priority,title".
+// A leading "-" sorts that field in descending order. Field names are
+// checked by TaskFilters.Validate.
+func ParseSort(value string) []SortField {
+	var fields []SortField
+	for _, term := range strings.Split(value, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		field := SortField{Field: term}
+		if strings.HasPrefix(term, "-") {
+			field = SortField{Field: term[1:], Descending: true}
+		} else if strings.HasPrefix(term, "+") {
+			field = SortField{Field: term[1:]}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// IsEmpty reports whether the filters select every task in the default order
+func (f *TaskFilters) IsEmpty() bool {
+	return len(f.Statuses) == 0 && len(f.Priorities) == 0 && f.Search == "" &&
+		len(f.CategoryIDs) == 0 && f.DueAfter == nil && f.DueBefore == nil &&
+		f.HasDueDate == nil && !f.Overdue &&
+		f.CreatedAfter == nil && f.CreatedBefore == nil &&
+		f.UpdatedAfter == nil && f.UpdatedBefore == nil &&
+		f.Where == nil && len(f.Sort) == 0
 }
 
-// Validate checks if the TaskFilters are valid
-func (f *TaskFilters) Validate() error {
+// Validate checks if the TaskFilters are valid. Statuses must be part of
+// workflow.
+func (f *TaskFilters) Validate(workflow *Workflow) error {
 	for _, status := range f.Statuses {
-		if !isValidStatus(status) {
+		if workflow.Status(status) == nil {
 			return fmt.Errorf("invalid status: %s", status)
 		}
 	}
@@ -166,5 +332,168 @@ func (f *TaskFilters) Validate() error {
 			return fmt.Errorf("invalid priority: %s", priority)
 		}
 	}
+	if f.Search != "" {
+		if _, err := ParseSearchQuery(f.Search); err != nil {
+			return err
+		}
+	}
+	if f.Where != nil {
+		if err := f.Where.Validate(workflow); err != nil {
+			return err
+		}
+	}
+	for _, id := range f.CategoryIDs {
+		if id <= 0 {
+			return fmt.Errorf("invalid category id: %d", id)
+		}
+	}
+	switch f.CategoryMode {
+	case "", CategoryMatchAny, CategoryMatchAll:
+	default:
+		return fmt.Errorf("invalid category mode: %s", f.CategoryMode)
+	}
+	if f.Overdue && f.HasDueDate != nil && !*f.HasDueDate {
+		return errors.New("overdue tasks always have a due date")
+	}
+	for _, r := range []struct {
+		name          string
+		after, before *time.Time
+	}{
+		{"due", f.DueAfter, f.DueBefore},
+		{"created", f.CreatedAfter, f.CreatedBefore},
+		{"updated", f.UpdatedAfter, f.UpdatedBefore},
+	} {
+		if r.after != nil && r.before != nil && !r.after.Before(*r.before) {
+			return fmt.Errorf("%s_after must be before %s_before", r.name, r.name)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, sort := range f.Sort {
+		if !isValidSortField(sort.Field) {
+			return fmt.Errorf("invalid sort field: %s", sort.Field)
+		}
+		if sort.Field == SortByRelevance && f.Search == "" {
+			return fmt.Errorf("sorting by %s requires a search", SortByRelevance)
+		}
+		if seen[sort.Field] {
+			return fmt.Errorf("duplicate sort field: %s", sort.Field)
+		}
+		seen[sort.Field] = true
+	}
+	return nil
+}
+
+// Matches reports whether a task passes the filters, the way the task list
+// would select it with workflow in effect. Sort fields are ignored.
+func (f *TaskFilters) Matches(workflow *Workflow, task *Task) bool {
+	if len(f.Statuses) > 0 && !containsStatus(f.Statuses, task.Status) {
+		return false
+	}
+	if len(f.Priorities) > 0 && !containsPriority(f.Priorities, task.Priority) {
+		return false
+	}
+	if f.Search != "" {
+		query, err := ParseSearchQuery(f.Search)
+		if err != nil || !query.Matches(task.Title, task.Description) {
+			return false
+		}
+	}
+	if f.Where != nil && !f.Where.Matches(task) {
+		return false
+	}
+	if len(f.CategoryIDs) > 0 && !f.matchesCategories(task) {
+		return false
+	}
+	if f.HasDueDate != nil && *f.HasDueDate != (task.DueDate != nil) {
+		return false
+	}
+	if (f.DueAfter != nil || f.DueBefore != nil) && (task.DueDate == nil || !inRange(*task.DueDate, f.DueAfter, f.DueBefore)) {
+		return false
+	}
+	if f.Overdue && !task.IsOverdue(workflow, time.Now()) {
+		return false
+	}
+	return inRange(task.CreatedAt, f.CreatedAfter, f.CreatedBefore) && inRange(task.UpdatedAt, f.UpdatedAfter, f.UpdatedBefore)
+}
+
+func (f *TaskFilters) matchesCategories(task *Task) bool {
+	matched := 0
+	for _, id := range f.CategoryIDs {
+		for _, category := range task.Categories {
+			if category.ID == id {
+				matched++
+				break
+			}
+		}
+	}
+	if f.CategoryMode == CategoryMatchAll {
+		return matched == len(f.CategoryIDs)
+	}
+	return matched > 0
+}
+
+// inRange reports whether t lies in [after, before); nil bounds are open
+func inRange(t time.Time, after, before *time.Time) bool {
+	return (after == nil || !t.Before(*after)) && (before == nil || t.Before(*before))
+}
+
+func containsStatus(statuses []TaskStatus, status TaskStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPriority(priorities []TaskPriority, priority TaskPriority) bool {
+	for _, p := range priorities {
+		if p == priority {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidSortField(field string) bool {
+	switch field {
+	case SortByID, SortByTitle, SortByStatus, SortByPriority, SortByDueDate, SortByCreatedAt, SortByUpdatedAt, SortByRank, SortByRelevance:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// PageRequest represents cursor pagination parameters for task listings
+type PageRequest struct {
+	Limit  int    `json:"limit"`
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// TaskPage represents one page of a task listing
+type TaskPage struct {
+	Tasks      []*Task `json:"tasks"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+	TotalCount int     `json:"total_count"`
+}
+
+// Validate checks the PageRequest and applies the default limit
+func (p *PageRequest) Validate() error {
+	if p.Limit == 0 {
+		p.Limit = DefaultPageLimit
+	}
+	if p.Limit < 0 {
+		return errors.New("limit must be positive")
+	}
+	if p.Limit > MaxPageLimit {
+		return fmt.Errorf("limit must be %d or less", MaxPageLimit)
+	}
 	return nil
 }
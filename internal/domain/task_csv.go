@@ -0,0 +1,237 @@
+package domain
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxImportRows caps how many tasks one CSV import may create
+const MaxImportRows = 1000
+
+// CategorySeparator separates category names within one CSV cell
+const CategorySeparator = ";"
+
+// TaskCSVColumns are the columns of a task export. An import reads title,
+// description, status, priority, due_date and categories, and ignores the
+// rest, so an export can be imported again.
+var TaskCSVColumns = []string{"id", "title", "description", "status", "priority", "due_date", "categories", "parent_id", "created_at", "updated_at"}
+
+// WriteTasksCSV writes tasks as CSV with a TaskCSVColumns header
+func WriteTasksCSV(w io.Writer, tasks []*Task) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(TaskCSVColumns); err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		names := make([]string, len(task.Categories))
+		for i, category := range task.Categories {
+			names[i] = category.Name
+		}
+		dueDate, parentID := "", ""
+		if task.DueDate != nil {
+			dueDate = task.DueDate.UTC().Format(time.RFC3339)
+		}
+		if task.ParentID != nil {
+			parentID = strconv.FormatInt(*task.ParentID, 10)
+		}
+
+		record := []string{
+			strconv.FormatInt(task.ID, 10),
+			escapeCSVFormula(task.Title),
+			escapeCSVFormula(task.Description),
+			string(task.Status),
+			string(task.Priority),
+			dueDate,
+			escapeCSVFormula(strings.Join(names, CategorySeparator)),
+			parentID,
+			task.CreatedAt.UTC().Format(time.RFC3339),
+			task.UpdatedAt.UTC().Format(time.RFC3339),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvFormulaPrefixes are the leading characters that make a spreadsheet
+// evaluate a cell. A quote is included so that text which already starts
+// with one survives unescapeCSVFormula.
+const csvFormulaPrefixes = "=+-@\t\r'"
+
+// escapeCSVFormula stops spreadsheets from evaluating user text as a formula
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeCSVFormula undoes escapeCSVFormula
+func unescapeCSVFormula(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(csvFormulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+// TaskImportRow is one data row of a CSV import. Err is set when the row
+// could not be parsed or is invalid.
+type TaskImportRow struct {
+	// Line is the row's line number in the file, counting the header as 1
+	Line          int
+	Request       CreateTaskRequest
+	Status        TaskStatus
+	CategoryNames []string
+	Err           error
+}
+
+// ReadTaskImportRows parses a CSV import. The header row names the columns,
+// in any order and case; only title is required. Rows that fail to parse or
+// validate are returned with Err set, so every problem can be reported at
+// once. Statuses must be part of workflow.
+func ReadTaskImportRows(r io.Reader, workflow *Workflow) ([]TaskImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, duplicate := columns[name]; duplicate && name != "" {
+			return nil, fmt.Errorf("duplicate column: %s", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, errors.New("CSV header must include a title column")
+	}
+
+	var rows []TaskImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlankRecord(record) {
+			continue
+		}
+		if len(rows) == MaxImportRows {
+			return nil, fmt.Errorf("at most %d rows can be imported at once", MaxImportRows)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		rows = append(rows, parseTaskImportRow(line, field, workflow))
+	}
+
+	return rows, nil
+}
+
+func parseTaskImportRow(line int, field func(name string) string, workflow *Workflow) TaskImportRow {
+	row := TaskImportRow{
+		Line: line,
+		Request: CreateTaskRequest{
+			Title:       unescapeCSVFormula(field("title")),
+			Description: unescapeCSVFormula(field("description")),
+			Priority:    TaskPriority(strings.ToLower(field("priority"))),
+		},
+		Status: workflow.InitialStatus(),
+	}
+
+	if status := field("status"); status != "" {
+		row.Status = TaskStatus(strings.ToLower(status))
+		if workflow.Status(row.Status) == nil {
+			row.Err = fmt.Errorf("invalid status: %s", status)
+			return row
+		}
+	}
+
+	if dueDate := field("due_date"); dueDate != "" {
+		parsed, err := parseImportDate(dueDate)
+		if err != nil {
+			row.Err = fmt.Errorf("invalid due_date: %s", dueDate)
+			return row
+		}
+		row.Request.DueDate = &parsed
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(unescapeCSVFormula(field("categories")), CategorySeparator) {
+		name = strings.TrimSpace(name)
+		if name != "" && !seen[name] {
+			seen[name] = true
+			row.CategoryNames = append(row.CategoryNames, name)
+		}
+	}
+
+	row.Err = row.Request.Validate()
+	return row
+}
+
+// parseImportDate accepts RFC 3339 timestamps and plain YYYY-MM-DD dates
+func parseImportDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// TaskImportOptions controls a CSV import
+type TaskImportOptions struct {
+	// DryRun validates the import without keeping any change
+	DryRun bool
+	// CreateCategories creates categories named in the file that the user
+	// does not have yet, instead of rejecting those rows
+	CreateCategories bool
+}
+
+// TaskImportError reports why one row of an import was rejected
+type TaskImportError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// TaskImportResult reports the outcome of a CSV import. When any row is
+// rejected, or on a dry run, nothing is imported and Imported counts the
+// tasks that would have been created.
+type TaskImportResult struct {
+	DryRun            bool              `json:"dry_run"`
+	Applied           bool              `json:"applied"`
+	Imported          int               `json:"imported"`
+	CreatedCategories []string          `json:"created_categories"`
+	Errors            []TaskImportError `json:"errors"`
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadTaskImportRows(t *testing.T) {
+	due := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	input := "Title,Priority,Status,Due_Date,Categories,Notes\n" +
+		"Write report,high,doing," + due + ",Work; Urgent ;Work,ignored\n" +
+		"\n" +
+		",low,,,,\n" +
+		"Bad status,low,blocked,,,\n" +
+		"Bad date,low,,next week,,\n"
+
+	rows, err := ReadTaskImportRows(strings.NewReader(input), DefaultWorkflow())
+	if err != nil {
+		t.Fatalf("ReadTaskImportRows() error = %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("expected 4 rows, got %d", len(rows))
+	}
+
+	first := rows[0]
+	if first.Err != nil {
+		t.Fatalf("first row error = %v", first.Err)
+	}
+	if first.Line != 2 || first.Request.Title != "Write report" || first.Request.Priority != PriorityHigh || first.Status != StatusDoing {
+		t.Errorf("unexpected first row %+v", first)
+	}
+	if first.Request.DueDate == nil || first.Request.DueDate.Format("2006-01-02") != due {
+		t.Errorf("due date = %v, want %s", first.Request.DueDate, due)
+	}
+	if strings.Join(first.CategoryNames, "|") != "Work|Urgent" {
+		t.Errorf("category names = %v, want [Work Urgent]", first.CategoryNames)
+	}
+
+	for i, line := range []int{4, 5, 6} {
+		row := rows[i+1]
+		if row.Line != line || row.Err == nil {
+			t.Errorf("row %d: line = %d, err = %v; want line %d with an error", i+1, row.Line, row.Err, line)
+		}
+	}
+}
+
+func TestReadTaskImportRows_InvalidFile(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "empty file", input: ""},
+		{name: "missing title column", input: "description,priority\nx,low\n"},
+		{name: "duplicate column", input: "title,title\na,b\n"},
+		{name: "malformed quoting", input: "title\n\"unterminated\n"},
+		{name: "too many rows", input: "title\n" + strings.Repeat("task\n", MaxImportRows+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadTaskImportRows(strings.NewReader(tt.input), DefaultWorkflow()); err == nil {
+				t.Error("ReadTaskImportRows() expected an error")
+			}
+		})
+	}
+}
+
+func TestWriteTasksCSV(t *testing.T) {
+	parentID := int64(1)
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tasks := []*Task{{
+		ID:          2,
+		Title:       "=HYPERLINK(\"x\")",
+		Description: "multi\nline, with comma",
+		Status:      StatusTodo,
+		Priority:    PriorityLow,
+		ParentID:    &parentID,
+		Categories:  []Category{{Name: "Work"}, {Name: "Home"}},
+		CreatedAt:   created,
+		UpdatedAt:   created,
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteTasksCSV(&buf, tasks); err != nil {
+		t.Fatalf("WriteTasksCSV() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("exported CSV does not parse: %v", err)
+	}
+	if len(records) != 2 || strings.Join(records[0], ",") != strings.Join(TaskCSVColumns, ",") {
+		t.Fatalf("unexpected records %q", records)
+	}
+	want := []string{"2", "'=HYPERLINK(\"x\")", "multi\nline, with comma", "todo", "low", "", "Work;Home", "1", "2024-05-01T12:00:00Z", "2024-05-01T12:00:00Z"}
+	for i, value := range want {
+		if records[1][i] != value {
+			t.Errorf("column %s = %q, want %q", TaskCSVColumns[i], records[1][i], value)
+		}
+	}
+}
+
+func TestTaskCSVRoundTrip(t *testing.T) {
+	due := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+	tasks := []*Task{
+		{ID: 1, Title: "-fix", Description: "+1 from review", Status: StatusTodo, Priority: PriorityLow, Categories: []Category{{Name: "@team"}, {Name: "Work"}}},
+		{ID: 2, Title: "=SUM(A1)", Description: "'quoted", Status: StatusDoing, Priority: PriorityHigh, DueDate: &due},
+		{ID: 3, Title: "'=already quoted", Description: "plain", Status: StatusDone, Priority: PriorityMedium},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTasksCSV(&buf, tasks); err != nil {
+		t.Fatalf("WriteTasksCSV() error = %v", err)
+	}
+	rows, err := ReadTaskImportRows(&buf, DefaultWorkflow())
+	if err != nil {
+		t.Fatalf("ReadTaskImportRows() error = %v", err)
+	}
+	if len(rows) != len(tasks) {
+		t.Fatalf("expected %d rows, got %d", len(tasks), len(rows))
+	}
+
+	for i, task := range tasks {
+		row := rows[i]
+		if row.Err != nil {
+			t.Fatalf("row %d error = %v", i, row.Err)
+		}
+		if row.Request.Title != task.Title || row.Request.Description != task.Description {
+			t.Errorf("row %d = %q / %q, want %q / %q", i, row.Request.Title, row.Request.Description, task.Title, task.Description)
+		}
+		if row.Status != task.Status || row.Request.Priority != task.Priority {
+			t.Errorf("row %d status, priority = %s, %s; want %s, %s", i, row.Status, row.Request.Priority, task.Status, task.Priority)
+		}
+		if (row.Request.DueDate == nil) != (task.DueDate == nil) || (task.DueDate != nil && !row.Request.DueDate.Equal(*task.DueDate)) {
+			t.Errorf("row %d due date = %v, want %v", i, row.Request.DueDate, task.DueDate)
+		}
+		names := make([]string, len(task.Categories))
+		for j, category := range task.Categories {
+			names[j] = category.Name
+		}
+		if strings.Join(row.CategoryNames, "|") != strings.Join(names, "|") {
+			t.Errorf("row %d categories = %v, want %v", i, row.CategoryNames, names)
+		}
+	}
+}
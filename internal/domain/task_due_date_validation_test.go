@@ -23,21 +23,21 @@ func TestTaskDueDateValidation_Buggy(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "task with past due date should fail validation",
+			name: "overdue task stays valid",
 			task: Task{
 				Title:       "Task with Past Due Date",
-				Description: "This should fail validation",
-				Status:      StatusTodo,
+				Description: "An overdue task can still be completed",
+				Status:      StatusDone,
 				Priority:    PriorityMedium,
-				DueDate:     func() *time.Time { t := time.Now().AddDate(0, 0, -1); return &t }(),
+				DueDate:     func() *time.Time { t := time.Now().AddDate(0, 0, -3); return &t }(),
 			},
-			wantErr: true, // This should fail but won't due to the bug
+			wantErr: false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tt.task.Validate()
+			err := tt.task.Validate(DefaultWorkflow())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Task.Validate() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -82,3 +82,19 @@ func TestCreateTaskRequestDueDateValidation_Buggy(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateTaskRequestDueDateValidation(t *testing.T) {
+	done := StatusDone
+	past := time.Now().AddDate(0, 0, -1)
+	future := time.Now().AddDate(0, 0, 1)
+
+	if err := (&UpdateTaskRequest{Status: &done}).Validate(DefaultWorkflow()); err != nil {
+		t.Errorf("Validate() error = %v without a new due date", err)
+	}
+	if err := (&UpdateTaskRequest{DueDate: &future}).Validate(DefaultWorkflow()); err != nil {
+		t.Errorf("Validate() error = %v for a future due date", err)
+	}
+	if err := (&UpdateTaskRequest{DueDate: &past}).Validate(DefaultWorkflow()); err == nil {
+		t.Error("Validate() expected an error for a past due date")
+	}
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFilterTime(t *testing.T) {
+	tests := []struct {
+		value       string
+		expected    time.Time
+		expectError bool
+	}{
+		{value: "2026-03-01", expected: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "2026-03-01T09:30:00Z", expected: time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)},
+		{value: "2026-03-01T09:30:00-05:00", expected: time.Date(2026, 3, 1, 14, 30, 0, 0, time.UTC)},
+		{value: "03/01/2026", expectError: true},
+		{value: "yesterday", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseFilterTime(tt.value)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected an error for %q", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFilterTime() error = %v", err)
+			}
+			if !got.Equal(tt.expected) || got.Location() != time.UTC {
+				t.Errorf("ParseFilterTime() = %v, want %v in UTC", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTaskFilters_ValidateRanges(t *testing.T) {
+	march := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	april := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	noDueDate := false
+
+	tests := []struct {
+		name        string
+		filters     TaskFilters
+		expectError bool
+	}{
+		{name: "valid_due_range", filters: TaskFilters{DueAfter: &march, DueBefore: &april}},
+		{name: "valid_category_mode", filters: TaskFilters{CategoryIDs: []int64{1, 2}, CategoryMode: CategoryMatchAll}},
+		{name: "empty_due_range", filters: TaskFilters{DueAfter: &april, DueBefore: &march}, expectError: true},
+		{name: "equal_created_bounds", filters: TaskFilters{CreatedAfter: &march, CreatedBefore: &march}, expectError: true},
+		{name: "invalid_category_id", filters: TaskFilters{CategoryIDs: []int64{0}}, expectError: true},
+		{name: "invalid_category_mode", filters: TaskFilters{CategoryIDs: []int64{1}, CategoryMode: "some"}, expectError: true},
+		{name: "overdue_without_due_date", filters: TaskFilters{Overdue: true, HasDueDate: &noDueDate}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filters.Validate(DefaultWorkflow())
+			if (err != nil) != tt.expectError {
+				t.Errorf("Validate() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestTaskFilters_MatchesRanges(t *testing.T) {
+	now := time.Now().UTC()
+	yesterday := now.AddDate(0, 0, -1)
+	tomorrow := now.AddDate(0, 0, 1)
+	hasDueDate := true
+
+	task := &Task{
+		Status:     StatusTodo,
+		DueDate:    &yesterday,
+		Categories: []Category{{ID: 1}, {ID: 2}},
+		CreatedAt:  yesterday,
+		UpdatedAt:  now,
+	}
+	undated := &Task{Status: StatusTodo, CreatedAt: now, UpdatedAt: now}
+
+	tests := []struct {
+		name     string
+		filters  TaskFilters
+		task     *Task
+		expected bool
+	}{
+		{name: "any_category", filters: TaskFilters{CategoryIDs: []int64{2, 3}}, task: task, expected: true},
+		{name: "all_categories", filters: TaskFilters{CategoryIDs: []int64{1, 2}, CategoryMode: CategoryMatchAll}, task: task, expected: true},
+		{name: "missing_category", filters: TaskFilters{CategoryIDs: []int64{1, 3}, CategoryMode: CategoryMatchAll}, task: task, expected: false},
+		{name: "due_before", filters: TaskFilters{DueBefore: &now}, task: task, expected: true},
+		{name: "due_after", filters: TaskFilters{DueAfter: &now}, task: task, expected: false},
+		{name: "due_range_skips_undated", filters: TaskFilters{DueBefore: &tomorrow}, task: undated, expected: false},
+		{name: "has_due_date", filters: TaskFilters{HasDueDate: &hasDueDate}, task: undated, expected: false},
+		{name: "overdue", filters: TaskFilters{Overdue: true}, task: task, expected: true},
+		{name: "overdue_skips_undated", filters: TaskFilters{Overdue: true}, task: undated, expected: false},
+		{name: "created_after_is_inclusive", filters: TaskFilters{CreatedAfter: &yesterday}, task: task, expected: true},
+		{name: "updated_before_is_exclusive", filters: TaskFilters{UpdatedBefore: &now}, task: task, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filters.Matches(DefaultWorkflow(), tt.task); got != tt.expected {
+				t.Errorf("Matches() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	done := *task
+	done.Status = StatusDone
+	if (&TaskFilters{Overdue: true}).Matches(DefaultWorkflow(), &done) {
+		t.Error("Expected a done task never to be overdue")
+	}
+}
@@ -83,7 +83,7 @@ func TestTaskFiltersSearch_Validate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tt.filters.Validate()
+			err := tt.filters.Validate(DefaultWorkflow())
 			hasError := err != nil
 
 			if hasError != tt.expectError {
@@ -99,7 +99,7 @@ func TestTaskFiltersSearch_EmptySearch(t *testing.T) {
 		Search: "",
 	}
 
-	err := filters.Validate()
+	err := filters.Validate(DefaultWorkflow())
 	if err != nil {
 		t.Errorf("Empty search should not cause validation error, got: %v", err)
 	}
@@ -110,7 +110,7 @@ func TestTaskFiltersSearch_OnlySearch(t *testing.T) {
 		Search: "urgent",
 	}
 
-	err := filters.Validate()
+	err := filters.Validate(DefaultWorkflow())
 	if err != nil {
 		t.Errorf("Search-only filters should pass validation, got: %v", err)
 	}
@@ -123,7 +123,7 @@ func TestTaskFiltersSearch_CombinedFilters(t *testing.T) {
 		Search:     "urgent",
 	}
 
-	err := filters.Validate()
+	err := filters.Validate(DefaultWorkflow())
 	if err != nil {
 		t.Errorf("Combined filters with search should pass validation, got: %v", err)
 	}
@@ -135,7 +135,7 @@ func TestTaskFiltersSearch_SearchWithInvalidStatus(t *testing.T) {
 		Search:   "urgent",
 	}
 
-	err := filters.Validate()
+	err := filters.Validate(DefaultWorkflow())
 	if err == nil {
 		t.Error("Search with invalid status should cause validation error")
 	}
@@ -147,7 +147,7 @@ func TestTaskFiltersSearch_SearchWithInvalidPriority(t *testing.T) {
 		Search:     "urgent",
 	}
 
-	err := filters.Validate()
+	err := filters.Validate(DefaultWorkflow())
 	if err == nil {
 		t.Error("Search with invalid priority should cause validation error")
 	}
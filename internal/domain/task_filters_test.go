@@ -65,7 +65,7 @@ func TestTaskFilters_Validate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tt.filters.Validate()
+			err := tt.filters.Validate(DefaultWorkflow())
 			hasError := err != nil
 
 			if hasError != tt.expectError {
@@ -79,7 +79,7 @@ func TestTaskFilters_Validate(t *testing.T) {
 func TestTaskFilters_EmptyFilters(t *testing.T) {
 	filters := TaskFilters{}
 
-	err := filters.Validate()
+	err := filters.Validate(DefaultWorkflow())
 	if err != nil {
 		t.Errorf("Empty filters should not cause validation error, got: %v", err)
 	}
@@ -91,7 +91,7 @@ func TestTaskFilters_MixedValidInvalid(t *testing.T) {
 		Priorities: []TaskPriority{PriorityHigh, "invalid"},
 	}
 
-	err := filters.Validate()
+	err := filters.Validate(DefaultWorkflow())
 	if err == nil {
 		t.Error("Mixed valid and invalid filters should cause validation error")
 	}
@@ -102,7 +102,7 @@ func TestTaskFilters_AllValidStatuses(t *testing.T) {
 		Statuses: []TaskStatus{StatusTodo, StatusDoing, StatusDone},
 	}
 
-	err := filters.Validate()
+	err := filters.Validate(DefaultWorkflow())
 	if err != nil {
 		t.Errorf("All valid statuses should pass validation, got: %v", err)
 	}
@@ -113,8 +113,35 @@ func TestTaskFilters_AllValidPriorities(t *testing.T) {
 		Priorities: []TaskPriority{PriorityLow, PriorityMedium, PriorityHigh, PriorityCritical},
 	}
 
-	err := filters.Validate()
+	err := filters.Validate(DefaultWorkflow())
 	if err != nil {
 		t.Errorf("All valid priorities should pass validation, got: %v", err)
 	}
 }
+
+func TestTaskFilters_Matches(t *testing.T) {
+	task := &Task{Title: "Write Report", Description: "quarterly numbers", Status: StatusTodo, Priority: PriorityHigh}
+
+	tests := []struct {
+		name    string
+		filters TaskFilters
+		want    bool
+	}{
+		{"no filters", TaskFilters{}, true},
+		{"matching status", TaskFilters{Statuses: []TaskStatus{StatusDone, StatusTodo}}, true},
+		{"other status", TaskFilters{Statuses: []TaskStatus{StatusDone}}, false},
+		{"other priority", TaskFilters{Priorities: []TaskPriority{PriorityLow}}, false},
+		{"search in title ignores case", TaskFilters{Search: "report"}, true},
+		{"search in description", TaskFilters{Search: "NUMBERS"}, true},
+		{"search without match", TaskFilters{Search: "invoice"}, false},
+		{"sort is ignored", TaskFilters{Sort: []SortField{{Field: SortByTitle}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filters.Matches(DefaultWorkflow(), task); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
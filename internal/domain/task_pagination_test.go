@@ -0,0 +1,31 @@
+package domain
+
+import "testing"
+
+func TestPageRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		page      PageRequest
+		wantErr   bool
+		wantLimit int
+	}{
+		{name: "default limit", page: PageRequest{}, wantLimit: DefaultPageLimit},
+		{name: "explicit limit", page: PageRequest{Limit: 10}, wantLimit: 10},
+		{name: "maximum limit", page: PageRequest{Limit: MaxPageLimit}, wantLimit: MaxPageLimit},
+		{name: "limit above maximum", page: PageRequest{Limit: MaxPageLimit + 1}, wantErr: true},
+		{name: "negative limit", page: PageRequest{Limit: -5}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.page.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PageRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && tt.page.Limit != tt.wantLimit {
+				t.Errorf("PageRequest.Validate() limit = %d, want %d", tt.page.Limit, tt.wantLimit)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []SortField
+	}{
+		{
+			name:  "single ascending field",
+			value: "title",
+			want:  []SortField{{Field: SortByTitle}},
+		},
+		{
+			name:  "mixed directions",
+			value: "-priority,title,updated_at",
+			want: []SortField{
+				{Field: SortByPriority, Descending: true},
+				{Field: SortByTitle},
+				{Field: SortByUpdatedAt},
+			},
+		},
+		{
+			name:  "explicit ascending and whitespace",
+			value: " +due_date , -created_at ,",
+			want: []SortField{
+				{Field: SortByDueDate},
+				{Field: SortByCreatedAt, Descending: true},
+			},
+		},
+		{
+			name:  "empty value",
+			value: "",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseSort(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFiltersSort_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		sort    []SortField
+		wantErr bool
+	}{
+		{
+			name:    "all sortable fields",
+			sort:    ParseSort("id,title,status,priority,due_date,created_at,updated_at"),
+			wantErr: false,
+		},
+		{
+			name:    "unknown field",
+			sort:    ParseSort("-description"),
+			wantErr: true,
+		},
+		{
+			name:    "sql injection attempt",
+			sort:    ParseSort("title;DROP TABLE tasks"),
+			wantErr: true,
+		},
+		{
+			name:    "duplicate field",
+			sort:    ParseSort("title,-title"),
+			wantErr: true,
+		},
+		{
+			name:    "bare minus sign",
+			sort:    ParseSort("-"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters := &TaskFilters{Sort: tt.sort}
+			err := filters.Validate(DefaultWorkflow())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TaskFilters.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
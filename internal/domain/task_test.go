@@ -74,7 +74,7 @@ func TestTask_Validate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tt.task.Validate()
+			err := tt.task.Validate(DefaultWorkflow())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Task.Validate() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -161,8 +161,8 @@ func TestIsValidStatus(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(string(tt.status), func(t *testing.T) {
-			if got := isValidStatus(tt.status); got != tt.want {
-				t.Errorf("isValidStatus() = %v, want %v", got, tt.want)
+			if got := DefaultWorkflow().Status(tt.status) != nil; got != tt.want {
+				t.Errorf("Status() != nil = %v, want %v", got, tt.want)
 			}
 		})
 	}
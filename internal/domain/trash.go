@@ -0,0 +1,11 @@
+package domain
+
+import "errors"
+
+var ErrNotInTrash = errors.New("not found in trash")
+
+// Trash lists a user's deleted tasks and categories
+type Trash struct {
+	Tasks      []*Task    `json:"tasks"`
+	Categories []Category `json:"categories"`
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrEmailTaken   = errors.New("email is already registered")
+)
+
+// User represents an account that owns tasks and categories
+type User struct {
+	ID           int64  `json:"id"`
+	Email        string `json:"email"`
+	PasswordHash string `json:"-"`
+	// IsAdmin is set for the configured administrators, who manage the
+	// settings shared by all users such as the workflow
+	IsAdmin   bool      `json:"is_admin"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CalendarToken is a long-lived secret that lets calendar clients, which
+// cannot send an Authorization header, read a user's calendar feed. Only its
+// hash is stored, so the token is shown once when it is issued.
+type CalendarToken struct {
+	Token string `json:"token"`
+	// URL is the feed path with the token filled in
+	URL string `json:"url"`
+}
+
+// SignupRequest represents the request to create a new user account
+type SignupRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// LoginRequest represents the request to exchange credentials for a token
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// AuthResponse is returned by signup and login
+type AuthResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	User      *User     `json:"user"`
+}
+
+// Validate validates the SignupRequest struct
+func (r *SignupRequest) Validate() error {
+	if r.Email == "" {
+		return errors.New("email is required")
+	}
+	if len(r.Email) > 254 {
+		return errors.New("email must be 254 characters or less")
+	}
+	if !isValidEmail(r.Email) {
+		return errors.New("email must be a valid email address")
+	}
+	if len(r.Password) < 8 {
+		return errors.New("password must be at least 8 characters")
+	}
+	if len(r.Password) > 72 {
+		return errors.New("password must be 72 characters or less")
+	}
+	return nil
+}
+
+// Validate validates the LoginRequest struct
+func (r *LoginRequest) Validate() error {
+	if r.Email == "" {
+		return errors.New("email is required")
+	}
+	if r.Password == "" {
+		return errors.New("password is required")
+	}
+	return nil
+}
+
+// NormalizeEmail lowercases and trims an email address so lookups are case-insensitive
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// isValidEmail performs a minimal structural check on an email address
+func isValidEmail(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 || at == len(email)-1 {
+		return false
+	}
+	if strings.ContainsAny(email, " \t\r\n") {
+		return false
+	}
+	return strings.Contains(email[at+1:], ".")
+}
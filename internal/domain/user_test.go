@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSignupRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     SignupRequest
+		wantErr bool
+	}{
+		{
+			name:    "valid signup",
+			req:     SignupRequest{Email: "alice@example.com", Password: "password123"},
+			wantErr: false,
+		},
+		{
+			name:    "missing email",
+			req:     SignupRequest{Email: "", Password: "password123"},
+			wantErr: true,
+		},
+		{
+			name:    "email without domain",
+			req:     SignupRequest{Email: "alice@", Password: "password123"},
+			wantErr: true,
+		},
+		{
+			name:    "email without at sign",
+			req:     SignupRequest{Email: "alice.example.com", Password: "password123"},
+			wantErr: true,
+		},
+		{
+			name:    "email with spaces",
+			req:     SignupRequest{Email: "alice smith@example.com", Password: "password123"},
+			wantErr: true,
+		},
+		{
+			name:    "short password",
+			req:     SignupRequest{Email: "alice@example.com", Password: "short"},
+			wantErr: true,
+		},
+		{
+			name:    "password too long",
+			req:     SignupRequest{Email: "alice@example.com", Password: strings.Repeat("a", 73)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SignupRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	if got := NormalizeEmail("  Alice@Example.COM "); got != "alice@example.com" {
+		t.Errorf("NormalizeEmail() = %v, want alice@example.com", got)
+	}
+}
@@ -0,0 +1,7 @@
+package domain
+
+import "errors"
+
+// ErrVersionConflict means a write was based on a version of a task or
+// category that has since been replaced
+var ErrVersionConflict = errors.New("resource has been modified since it was read")
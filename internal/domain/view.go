@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrViewNotFound = errors.New("saved view not found")
+	ErrNotViewOwner = errors.New("only the owner can change a saved view")
+)
+
+// MaxViewNameLength is the maximum length of a saved view name in bytes
+const MaxViewNameLength = 100
+
+// ViewVisibility controls who can see and run a saved view
+type ViewVisibility string
+
+const (
+	// ViewPrivate views are only visible to their owner
+	ViewPrivate ViewVisibility = "private"
+	// ViewShared views are visible to every user, who run them against
+	// their own tasks
+	ViewShared ViewVisibility = "shared"
+)
+
+func (v ViewVisibility) IsValid() bool {
+	return v == ViewPrivate || v == ViewShared
+}
+
+// SavedView is a named task filter, including its sort order
+type SavedView struct {
+	ID         int64          `json:"id"`
+	OwnerID    int64          `json:"owner_id"`
+	Name       string         `json:"name"`
+	Filters    TaskFilters    `json:"filters"`
+	Visibility ViewVisibility `json:"visibility"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// CreateViewRequest saves a filter under a name. Views are private unless
+// a visibility is given.
+type CreateViewRequest struct {
+	Name       string         `json:"name"`
+	Filters    TaskFilters    `json:"filters"`
+	Visibility ViewVisibility `json:"visibility,omitempty"`
+}
+
+func (r *CreateViewRequest) Validate(workflow *Workflow) error {
+	if err := validateViewName(r.Name); err != nil {
+		return err
+	}
+	if r.Visibility != "" && !r.Visibility.IsValid() {
+		return errors.New("invalid visibility: must be private or shared")
+	}
+	return r.Filters.Validate(workflow)
+}
+
+type UpdateViewRequest struct {
+	Name       *string         `json:"name,omitempty"`
+	Filters    *TaskFilters    `json:"filters,omitempty"`
+	Visibility *ViewVisibility `json:"visibility,omitempty"`
+}
+
+func (r *UpdateViewRequest) Validate(workflow *Workflow) error {
+	if r.Name != nil {
+		if err := validateViewName(*r.Name); err != nil {
+			return err
+		}
+	}
+	if r.Visibility != nil && !r.Visibility.IsValid() {
+		return errors.New("invalid visibility: must be private or shared")
+	}
+	if r.Filters != nil {
+		return r.Filters.Validate(workflow)
+	}
+	return nil
+}
+
+func validateViewName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("name is required")
+	}
+	if len(name) > MaxViewNameLength {
+		return fmt.Errorf("name must be %d characters or less", MaxViewNameLength)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+const (
+	// MinWebhookSecretLength is the shortest secret a client may choose
+	MinWebhookSecretLength = 16
+	maxWebhookURLLength    = 2048
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// Webhook subscribes a URL to a user's events. Secret is only returned when
+// it is set, so it can be stored by the receiver.
+type Webhook struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Subscribes reports whether the webhook wants events of eventType
+func (w *Webhook) Subscribes(eventType EventType) bool {
+	if !w.Active {
+		return false
+	}
+	for _, pattern := range w.Events {
+		if MatchEventPattern(pattern, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateWebhookRequest subscribes a URL to events. A secret is generated
+// when none is given.
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret,omitempty"`
+	Events []string `json:"events"`
+}
+
+func (r *CreateWebhookRequest) Validate() error {
+	if err := validateWebhookURL(r.URL); err != nil {
+		return err
+	}
+	if r.Secret != "" {
+		if err := validateWebhookSecret(r.Secret); err != nil {
+			return err
+		}
+	}
+	return validateWebhookEvents(r.Events)
+}
+
+type UpdateWebhookRequest struct {
+	URL    *string   `json:"url,omitempty"`
+	Secret *string   `json:"secret,omitempty"`
+	Events *[]string `json:"events,omitempty"`
+	Active *bool     `json:"active,omitempty"`
+}
+
+func (r *UpdateWebhookRequest) Validate() error {
+	if r.URL != nil {
+		if err := validateWebhookURL(*r.URL); err != nil {
+			return err
+		}
+	}
+	if r.Secret != nil {
+		if err := validateWebhookSecret(*r.Secret); err != nil {
+			return err
+		}
+	}
+	if r.Events != nil {
+		return validateWebhookEvents(*r.Events)
+	}
+	return nil
+}
+
+func validateWebhookURL(rawURL string) error {
+	if rawURL == "" {
+		return errors.New("url is required")
+	}
+	if len(rawURL) > maxWebhookURLLength {
+		return fmt.Errorf("url must be at most %d characters", maxWebhookURLLength)
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// WebhookAddressAllowed reports whether deliveries may connect to addr. Only
+// public unicast addresses are allowed, so that webhooks cannot reach the
+// server itself or services on its private network.
+func WebhookAddressAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range reservedWebhookPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// reservedWebhookPrefixes are non-public ranges not covered by the netip
+// predicates
+var reservedWebhookPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+func validateWebhookSecret(secret string) error {
+	if len(secret) < MinWebhookSecretLength {
+		return fmt.Errorf("secret must be at least %d characters", MinWebhookSecretLength)
+	}
+	return nil
+}
+
+func validateWebhookEvents(events []string) error {
+	if len(events) == 0 {
+		return errors.New("at least one event type is required")
+	}
+	for _, pattern := range events {
+		if err := ValidateEventPattern(pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SignWebhookPayload returns the signature sent in WebhookSignatureHeader:
+// the hex HMAC-SHA256 of "<timestamp>.<payload>" keyed with the webhook
+// secret. Signing the timestamp lets receivers reject replayed requests.
+func SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	DeliveryPending   WebhookDeliveryStatus = "pending"
+	DeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	DeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// IsValid reports whether the status is a known WebhookDeliveryStatus
+func (s WebhookDeliveryStatus) IsValid() bool {
+	return s == DeliveryPending || s == DeliverySucceeded || s == DeliveryFailed
+}
+
+// WebhookDelivery is one event queued for one webhook. A pending delivery is
+// retried at NextAttemptAt until it succeeds or runs out of attempts.
+type WebhookDelivery struct {
+	ID             int64                    `json:"id"`
+	WebhookID      int64                    `json:"webhook_id"`
+	EventID        string                   `json:"event_id"`
+	EventType      EventType                `json:"event_type"`
+	Payload        json.RawMessage          `json:"payload"`
+	Status         WebhookDeliveryStatus    `json:"status"`
+	AttemptCount   int                      `json:"attempt_count"`
+	NextAttemptAt  *time.Time               `json:"next_attempt_at,omitempty"`
+	LastStatusCode *int                     `json:"last_status_code,omitempty"`
+	LastError      string                   `json:"last_error,omitempty"`
+	CreatedAt      time.Time                `json:"created_at"`
+	UpdatedAt      time.Time                `json:"updated_at"`
+	Attempts       []WebhookDeliveryAttempt `json:"attempts,omitempty"`
+}
+
+// WebhookDeliveryAttempt records one request made for a delivery. Only the
+// status code of the response is kept, never its body.
+type WebhookDeliveryAttempt struct {
+	ID          int64     `json:"id"`
+	DeliveryID  int64     `json:"delivery_id"`
+	Attempt     int       `json:"attempt"`
+	StatusCode  *int      `json:"status_code,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	DurationMS  int64     `json:"duration_ms"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
+
+// WebhookDispatch is a due delivery together with the webhook it goes to
+type WebhookDispatch struct {
+	Delivery *WebhookDelivery
+	Webhook  *Webhook
+}
+
+// WebhookRetryPolicy controls how failed deliveries are retried
+type WebhookRetryPolicy struct {
+	MaxAttempts int
+	// BaseDelay is the wait after the first failure. It doubles with every
+	// further failure, up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// RetryDelay returns how long to wait after the given failed attempt,
+// counting from 1
+func (p WebhookRetryPolicy) RetryDelay(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	// echo -n '1700000000.{"a":1}' | openssl dgst -sha256 -hmac 'whsec_0123456789abcdef'
+	got := SignWebhookPayload("whsec_0123456789abcdef", 1700000000, []byte(`{"a":1}`))
+	want := "sha256=9f5e37cdc7ea587c7c02062d93283cad000d66a831b2a1676418696db47ed6c2"
+	if got != want {
+		t.Fatalf("SignWebhookPayload() = %q, want %q", got, want)
+	}
+	if got == SignWebhookPayload("whsec_0123456789abcdef", 1700000001, []byte(`{"a":1}`)) {
+		t.Error("the timestamp should be part of the signature")
+	}
+	if got == SignWebhookPayload("whsec_fedcba9876543210", 1700000000, []byte(`{"a":1}`)) {
+		t.Error("the secret should be part of the signature")
+	}
+}
+
+func TestMatchEventPattern(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		eventType EventType
+		want      bool
+	}{
+		{"*", EventCategoryPurged, true},
+		{"task.created", EventTaskCreated, true},
+		{"task.created", EventTaskUpdated, false},
+		{"task.*", EventTaskDeleted, true},
+		{"task.*", EventCategoryDeleted, false},
+		{"category.*", EventCategoryCreated, true},
+	}
+
+	for _, tt := range tests {
+		if got := MatchEventPattern(tt.pattern, tt.eventType); got != tt.want {
+			t.Errorf("MatchEventPattern(%q, %q) = %v, want %v", tt.pattern, tt.eventType, got, tt.want)
+		}
+	}
+}
+
+func TestCreateWebhookRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     CreateWebhookRequest
+		wantErr bool
+	}{
+		{"valid", CreateWebhookRequest{URL: "https://example.com/hook", Events: []string{"task.*"}}, false},
+		{"valid with secret", CreateWebhookRequest{URL: "http://localhost:9000", Secret: "0123456789abcdef", Events: []string{"*"}}, false},
+		{"missing url", CreateWebhookRequest{Events: []string{"*"}}, true},
+		{"relative url", CreateWebhookRequest{URL: "/hook", Events: []string{"*"}}, true},
+		{"unsupported scheme", CreateWebhookRequest{URL: "ftp://example.com", Events: []string{"*"}}, true},
+		{"short secret", CreateWebhookRequest{URL: "https://example.com", Secret: "short", Events: []string{"*"}}, true},
+		{"no events", CreateWebhookRequest{URL: "https://example.com"}, true},
+		{"unknown event", CreateWebhookRequest{URL: "https://example.com", Events: []string{"task.exploded"}}, true},
+		{"unknown resource", CreateWebhookRequest{URL: "https://example.com", Events: []string{"user.*"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookAddressAllowed(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"100.64.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"224.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := WebhookAddressAllowed(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("WebhookAddressAllowed(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhook_Subscribes(t *testing.T) {
+	webhook := &Webhook{Active: true, Events: []string{"task.deleted", "category.*"}}
+	if !webhook.Subscribes(EventTaskDeleted) || !webhook.Subscribes(EventCategoryUpdated) {
+		t.Error("webhook should subscribe to task.deleted and category events")
+	}
+	if webhook.Subscribes(EventTaskCreated) {
+		t.Error("webhook should not subscribe to task.created")
+	}
+
+	webhook.Active = false
+	if webhook.Subscribes(EventTaskDeleted) {
+		t.Error("an inactive webhook should not subscribe to anything")
+	}
+}
+
+func TestWebhookRetryPolicy_RetryDelay(t *testing.T) {
+	policy := WebhookRetryPolicy{MaxAttempts: 6, BaseDelay: 30 * time.Second, MaxDelay: 5 * time.Minute}
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+
+	for i, expected := range want {
+		if got := policy.RetryDelay(i + 1); got != expected {
+			t.Errorf("RetryDelay(%d) = %v, want %v", i+1, got, expected)
+		}
+	}
+}
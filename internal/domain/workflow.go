@@ -0,0 +1,206 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+var (
+	ErrStatusNotFound    = errors.New("status not found")
+	ErrStatusInUse       = errors.New("status is used by tasks")
+	ErrInvalidTransition = errors.New("status transition not allowed")
+)
+
+// StatusCategory groups statuses by how far along their tasks are. Tasks in
+// a closed status count as finished: they are never overdue, do not block
+// other tasks and let their parent be closed.
+type StatusCategory string
+
+const (
+	StatusCategoryOpen       StatusCategory = "open"
+	StatusCategoryInProgress StatusCategory = "in_progress"
+	StatusCategoryClosed     StatusCategory = "closed"
+)
+
+func (c StatusCategory) IsValid() bool {
+	switch c {
+	case StatusCategoryOpen, StatusCategoryInProgress, StatusCategoryClosed:
+		return true
+	}
+	return false
+}
+
+const MaxStatusLabelLength = 50
+
+var statusKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// StatusDefinition is one status of the workflow. Transitions lists the
+// statuses a task in this status may move to.
+type StatusDefinition struct {
+	Key         TaskStatus     `json:"key"`
+	Label       string         `json:"label"`
+	Category    StatusCategory `json:"category"`
+	Position    int            `json:"position"`
+	Transitions []TaskStatus   `json:"transitions"`
+}
+
+// SaveStatusRequest creates or replaces the status named in the URL
+type SaveStatusRequest struct {
+	Label    string         `json:"label"`
+	Category StatusCategory `json:"category"`
+	// Position defaults to the end of the workflow for a new status and to
+	// the current position otherwise
+	Position    *int         `json:"position,omitempty"`
+	Transitions []TaskStatus `json:"transitions"`
+}
+
+func (r *SaveStatusRequest) Validate() error {
+	if r.Label == "" {
+		return errors.New("label is required")
+	}
+	if len(r.Label) > MaxStatusLabelLength {
+		return fmt.Errorf("label must be at most %d characters", MaxStatusLabelLength)
+	}
+	if !r.Category.IsValid() {
+		return errors.New("category must be open, in_progress or closed")
+	}
+	if r.Position != nil && *r.Position < 0 {
+		return errors.New("position must not be negative")
+	}
+	return nil
+}
+
+// ValidateStatusKey checks that a status key is a short lowercase identifier
+func ValidateStatusKey(key TaskStatus) error {
+	if !statusKeyPattern.MatchString(string(key)) {
+		return errors.New("status key must be lowercase letters, digits or underscores, starting with a letter")
+	}
+	return nil
+}
+
+// Workflow is the set of statuses tasks can be in, ordered by position
+type Workflow struct {
+	Statuses []StatusDefinition `json:"statuses"`
+}
+
+// DefaultWorkflow returns the todo, doing and done statuses, between which
+// any transition is allowed
+func DefaultWorkflow() *Workflow {
+	return &Workflow{Statuses: []StatusDefinition{
+		{Key: StatusTodo, Label: "To do", Category: StatusCategoryOpen, Position: 0, Transitions: []TaskStatus{StatusDoing, StatusDone}},
+		{Key: StatusDoing, Label: "Doing", Category: StatusCategoryInProgress, Position: 1, Transitions: []TaskStatus{StatusTodo, StatusDone}},
+		{Key: StatusDone, Label: "Done", Category: StatusCategoryClosed, Position: 2, Transitions: []TaskStatus{StatusTodo, StatusDoing}},
+	}}
+}
+
+// Status returns the definition of a status, or nil if it does not exist
+func (w *Workflow) Status(key TaskStatus) *StatusDefinition {
+	for i := range w.Statuses {
+		if w.Statuses[i].Key == key {
+			return &w.Statuses[i]
+		}
+	}
+	return nil
+}
+
+// CanTransition reports whether a task may move from one status to another.
+// Staying in the same status is always allowed.
+func (w *Workflow) CanTransition(from, to TaskStatus) bool {
+	if from == to {
+		return w.Status(to) != nil
+	}
+	status := w.Status(from)
+	if status == nil || w.Status(to) == nil {
+		return false
+	}
+	for _, allowed := range status.Transitions {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// InitialStatus returns the status new tasks start in, the first open one
+func (w *Workflow) InitialStatus() TaskStatus {
+	for _, status := range w.Statuses {
+		if status.Category == StatusCategoryOpen {
+			return status.Key
+		}
+	}
+	return StatusTodo
+}
+
+// Validate checks that the workflow has an open and a closed status and that
+// every transition leads to one of its statuses
+func (w *Workflow) Validate() error {
+	seen := map[TaskStatus]bool{}
+	categories := map[StatusCategory]bool{}
+	for _, status := range w.Statuses {
+		if seen[status.Key] {
+			return fmt.Errorf("duplicate status: %s", status.Key)
+		}
+		seen[status.Key] = true
+		categories[status.Category] = true
+	}
+	if !categories[StatusCategoryOpen] || !categories[StatusCategoryClosed] {
+		return errors.New("workflow needs at least one open and one closed status")
+	}
+
+	for _, status := range w.Statuses {
+		targets := map[TaskStatus]bool{}
+		for _, target := range status.Transitions {
+			if !seen[target] {
+				return fmt.Errorf("status %s has a transition to unknown status %s", status.Key, target)
+			}
+			if target == status.Key || targets[target] {
+				return fmt.Errorf("status %s lists transition %s more than once or to itself", status.Key, target)
+			}
+			targets[target] = true
+		}
+	}
+	return nil
+}
+
+// Category returns the category of a status, treating unknown statuses as
+// open
+func (w *Workflow) Category(status TaskStatus) StatusCategory {
+	if definition := w.Status(status); definition != nil {
+		return definition.Category
+	}
+	return StatusCategoryOpen
+}
+
+// IsClosed reports whether the status finishes a task
+func (w *Workflow) IsClosed(status TaskStatus) bool {
+	return w.Category(status) == StatusCategoryClosed
+}
+
+// WorkflowStore holds the workflow in effect, which is loaded from storage
+// at startup and replaced whenever its statuses change. It is shared by the
+// services that need the workflow and is safe for concurrent use.
+type WorkflowStore struct {
+	mu       sync.RWMutex
+	workflow *Workflow
+}
+
+// NewWorkflowStore returns a store with workflow in effect
+func NewWorkflowStore(workflow *Workflow) *WorkflowStore {
+	return &WorkflowStore{workflow: workflow}
+}
+
+// Get returns the workflow in effect. It must not be modified.
+func (s *WorkflowStore) Get() *Workflow {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.workflow
+}
+
+// Set replaces the workflow in effect
+func (s *WorkflowStore) Set(workflow *Workflow) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.workflow = workflow
+}
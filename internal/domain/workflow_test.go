@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+// reviewWorkflow adds review, blocked and cancelled to the default
+// statuses. Review sits between doing and done.
+func reviewWorkflow() *Workflow {
+	return &Workflow{Statuses: []StatusDefinition{
+		{Key: StatusTodo, Label: "To do", Category: StatusCategoryOpen, Transitions: []TaskStatus{StatusDoing, "cancelled"}},
+		{Key: StatusDoing, Label: "Doing", Category: StatusCategoryInProgress, Transitions: []TaskStatus{"review", "blocked"}},
+		{Key: "review", Label: "Review", Category: StatusCategoryInProgress, Transitions: []TaskStatus{StatusDoing, StatusDone}},
+		{Key: "blocked", Label: "Blocked", Category: StatusCategoryOpen, Transitions: []TaskStatus{StatusDoing}},
+		{Key: StatusDone, Label: "Done", Category: StatusCategoryClosed, Transitions: []TaskStatus{StatusTodo}},
+		{Key: "cancelled", Label: "Cancelled", Category: StatusCategoryClosed},
+	}}
+}
+
+func TestWorkflow_CanTransition(t *testing.T) {
+	workflow := reviewWorkflow()
+	tests := []struct {
+		from, to TaskStatus
+		want     bool
+	}{
+		{StatusTodo, StatusDoing, true},
+		{StatusDoing, "review", true},
+		{"review", StatusDone, true},
+		{StatusTodo, StatusDone, false},
+		{"cancelled", StatusTodo, false},
+		{"cancelled", "cancelled", true},
+		{StatusTodo, "later", false},
+		{"later", "later", false},
+	}
+	for _, tt := range tests {
+		if got := workflow.CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestWorkflow_Validate(t *testing.T) {
+	if err := DefaultWorkflow().Validate(); err != nil {
+		t.Errorf("default workflow: Validate() error = %v", err)
+	}
+	if err := reviewWorkflow().Validate(); err != nil {
+		t.Errorf("review workflow: Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(w *Workflow)
+	}{
+		{"duplicate status", func(w *Workflow) { w.Statuses[1].Key = StatusTodo }},
+		{"unknown transition", func(w *Workflow) { w.Statuses[0].Transitions = []TaskStatus{"later"} }},
+		{"transition to itself", func(w *Workflow) { w.Statuses[0].Transitions = []TaskStatus{StatusTodo} }},
+		{"no closed status", func(w *Workflow) { w.Statuses = w.Statuses[:2] }},
+		{"no open status", func(w *Workflow) { w.Statuses = w.Statuses[1:] }},
+	}
+	for _, tt := range tests {
+		workflow := DefaultWorkflow()
+		tt.modify(workflow)
+		if err := workflow.Validate(); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestUpdateTaskRequest_ValidateTransition(t *testing.T) {
+	workflow := reviewWorkflow()
+
+	review := TaskStatus("review")
+	if err := (&UpdateTaskRequest{Status: &review}).Validate(workflow); err != nil {
+		t.Errorf("Validate() error = %v for a configured status", err)
+	}
+	if err := (&UpdateTaskRequest{Status: &review}).Validate(DefaultWorkflow()); err == nil {
+		t.Error("Validate() expected an error for a status of another workflow")
+	}
+	if err := (&UpdateTaskRequest{Status: &review}).ValidateTransition(workflow, StatusDoing); err != nil {
+		t.Errorf("ValidateTransition() error = %v for an allowed transition", err)
+	}
+	if err := (&UpdateTaskRequest{Status: &review}).ValidateTransition(workflow, StatusTodo); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("ValidateTransition() error = %v, want ErrInvalidTransition", err)
+	}
+	if err := (&UpdateTaskRequest{}).ValidateTransition(workflow, "cancelled"); err != nil {
+		t.Errorf("ValidateTransition() error = %v without a status change", err)
+	}
+
+	if !workflow.IsClosed("cancelled") || workflow.IsClosed(review) {
+		t.Error("Expected only closed statuses to be closed")
+	}
+	if got := workflow.InitialStatus(); got != StatusTodo {
+		t.Errorf("InitialStatus() = %s, want todo", got)
+	}
+}
+
+func TestWorkflowStore(t *testing.T) {
+	store := NewWorkflowStore(DefaultWorkflow())
+	if store.Get().Status("review") != nil {
+		t.Fatal("Expected the default workflow in effect")
+	}
+
+	store.Set(reviewWorkflow())
+	if store.Get().Status("review") == nil {
+		t.Error("Expected the new workflow in effect")
+	}
+}
@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrWorklogNotFound = errors.New("worklog not found")
+	ErrTimerRunning    = errors.New("a timer is already running")
+	ErrNoTimerRunning  = errors.New("no timer is running on this task")
+)
+
+// MaxWorklogNoteLength is the maximum length of a worklog note in bytes
+const MaxWorklogNoteLength = 1000
+
+// Worklog is time spent on a task. A worklog without an end is a running
+// timer, of which each user has at most one.
+type Worklog struct {
+	ID        int64      `json:"id"`
+	TaskID    int64      `json:"task_id"`
+	UserID    int64      `json:"user_id"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	// DurationSeconds is zero while the timer runs
+	DurationSeconds int64     `json:"duration_seconds"`
+	Note            string    `json:"note"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// IsRunning reports whether the worklog is a timer that has not been stopped
+func (w *Worklog) IsRunning() bool {
+	return w.EndedAt == nil
+}
+
+// Finish ends the worklog at end and sets its duration to the whole seconds
+// elapsed since it started
+func (w *Worklog) Finish(end time.Time) {
+	w.EndedAt = &end
+	w.DurationSeconds = int64(end.Sub(w.StartedAt) / time.Second)
+}
+
+// CreateWorklogRequest logs time spent on a task. Any two of the start, the
+// end and the duration determine the third; a duration alone is taken to end
+// now.
+type CreateWorklogRequest struct {
+	StartedAt       *time.Time `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at"`
+	DurationSeconds *int64     `json:"duration_seconds"`
+	Note            string     `json:"note"`
+}
+
+// Span returns the start and end of the logged time
+func (r *CreateWorklogRequest) Span(now time.Time) (time.Time, time.Time, error) {
+	if err := validateWorklogNote(r.Note); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	var start, end time.Time
+	if r.DurationSeconds != nil {
+		if *r.DurationSeconds <= 0 {
+			return time.Time{}, time.Time{}, errors.New("duration_seconds must be positive")
+		}
+		duration := time.Duration(*r.DurationSeconds) * time.Second
+		switch {
+		case r.StartedAt != nil && r.EndedAt != nil:
+			if r.EndedAt.Sub(*r.StartedAt) != duration {
+				return time.Time{}, time.Time{}, errors.New("duration_seconds does not match started_at and ended_at")
+			}
+			start, end = *r.StartedAt, *r.EndedAt
+		case r.StartedAt != nil:
+			start, end = *r.StartedAt, r.StartedAt.Add(duration)
+		case r.EndedAt != nil:
+			start, end = r.EndedAt.Add(-duration), *r.EndedAt
+		default:
+			start, end = now.Add(-duration), now
+		}
+	} else {
+		if r.StartedAt == nil || r.EndedAt == nil {
+			return time.Time{}, time.Time{}, errors.New("either duration_seconds or both started_at and ended_at are required")
+		}
+		start, end = *r.StartedAt, *r.EndedAt
+	}
+
+	if err := validateWorklogSpan(start, &end, now); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return start.UTC(), end.UTC(), nil
+}
+
+// UpdateWorklogRequest corrects a worklog. The end of a running timer can
+// only be set by stopping it.
+type UpdateWorklogRequest struct {
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Note      *string    `json:"note,omitempty"`
+}
+
+// Apply changes the worklog as requested and recomputes its duration
+func (r *UpdateWorklogRequest) Apply(worklog *Worklog, now time.Time) error {
+	if r.Note != nil {
+		if err := validateWorklogNote(*r.Note); err != nil {
+			return err
+		}
+		worklog.Note = *r.Note
+	}
+	if r.EndedAt != nil && worklog.IsRunning() {
+		return errors.New("stop the timer to set ended_at")
+	}
+	if r.StartedAt == nil && r.EndedAt == nil {
+		return nil
+	}
+
+	start, end := worklog.StartedAt, worklog.EndedAt
+	if r.StartedAt != nil {
+		start = r.StartedAt.UTC()
+	}
+	if r.EndedAt != nil {
+		ended := r.EndedAt.UTC()
+		end = &ended
+	}
+	if err := validateWorklogSpan(start, end, now); err != nil {
+		return err
+	}
+
+	worklog.StartedAt = start
+	if end != nil {
+		worklog.Finish(*end)
+	}
+	return nil
+}
+
+// TimerRequest optionally notes what a timer is for when starting or
+// stopping it
+type TimerRequest struct {
+	Note *string `json:"note,omitempty"`
+}
+
+func (r *TimerRequest) Validate() error {
+	if r.Note != nil {
+		return validateWorklogNote(*r.Note)
+	}
+	return nil
+}
+
+// validateWorklogSpan checks that logged time lies in the past, with an end
+// after the start unless the timer is still running
+func validateWorklogSpan(start time.Time, end *time.Time, now time.Time) error {
+	if start.After(now) {
+		return errors.New("worklog cannot start in the future")
+	}
+	if end == nil {
+		return nil
+	}
+	if !end.After(start) {
+		return errors.New("ended_at must be after started_at")
+	}
+	if end.After(now) {
+		return errors.New("worklog cannot end in the future")
+	}
+	return nil
+}
+
+func validateWorklogNote(note string) error {
+	if len(note) > MaxWorklogNoteLength {
+		return fmt.Errorf("note must be %d characters or less", MaxWorklogNoteLength)
+	}
+	return nil
+}
+
+// TaskTime is the time logged on one task
+type TaskTime struct {
+	TaskID  int64  `json:"task_id"`
+	Title   string `json:"title"`
+	Seconds int64  `json:"seconds"`
+}
+
+// CategoryTime is the time logged on the tasks of one category
+type CategoryTime struct {
+	CategoryID int64  `json:"category_id"`
+	Name       string `json:"name"`
+	Seconds    int64  `json:"seconds"`
+}
+
+// DailyTime is the time logged on one UTC day
+type DailyTime struct {
+	Date    string `json:"date"`
+	Seconds int64  `json:"seconds"`
+}
+
+// TimeReport sums the finished worklogs that started within a window. Time
+// on a task with several categories counts towards each of them.
+type TimeReport struct {
+	Window               StatsWindow    `json:"window"`
+	TotalSeconds         int64          `json:"total_seconds"`
+	ByTask               []TaskTime     `json:"by_task"`
+	ByCategory           []CategoryTime `json:"by_category"`
+	UncategorizedSeconds int64          `json:"uncategorized_seconds"`
+	Daily                []DailyTime    `json:"daily"`
+}
+
+// NewTimeReport returns an empty report with every day of the window at zero
+func NewTimeReport(window StatsWindow) *TimeReport {
+	report := &TimeReport{
+		Window:     window,
+		ByTask:     []TaskTime{},
+		ByCategory: []CategoryTime{},
+		Daily:      make([]DailyTime, window.Days()),
+	}
+	for i := range report.Daily {
+		report.Daily[i].Date = window.From.AddDate(0, 0, i).Format("2006-01-02")
+	}
+	return report
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCreateWorklogRequest_Span(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	at := func(hour int) *time.Time {
+		t := time.Date(2026, 3, 2, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	seconds := func(s int64) *int64 { return &s }
+
+	tests := []struct {
+		name       string
+		req        CreateWorklogRequest
+		start, end *time.Time
+		wantErr    bool
+	}{
+		{"start and end", CreateWorklogRequest{StartedAt: at(9), EndedAt: at(11)}, at(9), at(11), false},
+		{"start and duration", CreateWorklogRequest{StartedAt: at(9), DurationSeconds: seconds(3600)}, at(9), at(10), false},
+		{"end and duration", CreateWorklogRequest{EndedAt: at(11), DurationSeconds: seconds(7200)}, at(9), at(11), false},
+		{"duration alone ends now", CreateWorklogRequest{DurationSeconds: seconds(3600)}, at(11), at(12), false},
+		{"all three agreeing", CreateWorklogRequest{StartedAt: at(9), EndedAt: at(10), DurationSeconds: seconds(3600)}, at(9), at(10), false},
+		{"all three disagreeing", CreateWorklogRequest{StartedAt: at(9), EndedAt: at(10), DurationSeconds: seconds(60)}, nil, nil, true},
+		{"start alone", CreateWorklogRequest{StartedAt: at(9)}, nil, nil, true},
+		{"nothing", CreateWorklogRequest{}, nil, nil, true},
+		{"zero duration", CreateWorklogRequest{DurationSeconds: seconds(0)}, nil, nil, true},
+		{"end before start", CreateWorklogRequest{StartedAt: at(10), EndedAt: at(9)}, nil, nil, true},
+		{"ending in the future", CreateWorklogRequest{StartedAt: at(11), DurationSeconds: seconds(7200)}, nil, nil, true},
+		{"note too long", CreateWorklogRequest{DurationSeconds: seconds(60), Note: string(make([]byte, MaxWorklogNoteLength+1))}, nil, nil, true},
+	}
+	for _, tt := range tests {
+		start, end, err := tt.req.Span(now)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: Span() error = %v", tt.name, err)
+			continue
+		}
+		if !start.Equal(*tt.start) || !end.Equal(*tt.end) {
+			t.Errorf("%s: Span() = %v, %v; want %v, %v", tt.name, start, end, *tt.start, *tt.end)
+		}
+	}
+}
+
+func TestUpdateWorklogRequest_Apply(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	start := now.Add(-2 * time.Hour)
+	worklog := &Worklog{StartedAt: start}
+	worklog.Finish(now.Add(-time.Hour))
+
+	earlier := start.Add(-30 * time.Minute)
+	if err := (&UpdateWorklogRequest{StartedAt: &earlier}).Apply(worklog, now); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if worklog.DurationSeconds != 5400 {
+		t.Errorf("Expected the duration to be recomputed to 5400s, got %d", worklog.DurationSeconds)
+	}
+
+	running := &Worklog{StartedAt: start}
+	if err := (&UpdateWorklogRequest{EndedAt: &now}).Apply(running, now); err == nil {
+		t.Error("Expected setting the end of a running timer to be rejected")
+	}
+	note := "Pairing"
+	if err := (&UpdateWorklogRequest{Note: &note}).Apply(running, now); err != nil || running.Note != note || !running.IsRunning() {
+		t.Errorf("Expected the note of a running timer to change, got %+v, %v", running, err)
+	}
+}
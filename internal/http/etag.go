@@ -0,0 +1,48 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"task-manager/internal/domain"
+)
+
+var errInvalidIfMatch = errors.New("If-Match must be a single strong ETag or *")
+
+// etag formats a resource version as a strong entity tag
+func etag(version int64) string {
+	return fmt.Sprintf("%q", strconv.FormatInt(version, 10))
+}
+
+// ifMatchVersion returns the version named by the request's If-Match header,
+// or nil when the header is absent or "*"
+func ifMatchVersion(r *http.Request) (*int64, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return nil, errInvalidIfMatch
+	}
+	version, err := strconv.ParseInt(value[1:len(value)-1], 10, 64)
+	if err != nil || version <= 0 {
+		return nil, errInvalidIfMatch
+	}
+	return &version, nil
+}
+
+// versionConflictStatus maps a lost update to 412 when the client sent
+// If-Match, and to 409 when the write raced another one without it
+func versionConflictStatus(r *http.Request, err error, fallback int) int {
+	if !errors.Is(err, domain.ErrVersionConflict) {
+		return fallback
+	}
+	if r.Header.Get("If-Match") != "" {
+		return http.StatusPreconditionFailed
+	}
+	return http.StatusConflict
+}
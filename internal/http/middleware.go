@@ -1,16 +1,26 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
+	"strings"
+	"task-manager/internal/domain"
+	"task-manager/internal/service"
 	"time"
 )
 
+type contextKey string
+
+const userContextKey contextKey = "user"
+
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, Last-Event-ID")
+		w.Header().Set("Access-Control-Expose-Headers", "ETag")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -30,3 +40,92 @@ func loggingMiddleware(next http.Handler) http.Handler {
 		log.Printf("%s %s %v", r.Method, r.URL.Path, time.Since(start))
 	})
 }
+
+// queryTokenMiddleware moves an access_token query parameter into the
+// Authorization header for clients that cannot set headers
+func queryTokenMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := r.URL.Query().Get("access_token"); token != "" && r.Header.Get("Authorization") == "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// calendarTokenMiddleware authenticates calendar clients by the calendar
+// token in the token query parameter, since they cannot send an
+// Authorization header. Requests without one need a bearer token.
+func (h *Handler) calendarTokenMiddleware(next http.Handler) http.Handler {
+	bearer := h.authMiddleware(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if token == "" {
+			bearer.ServeHTTP(w, r)
+			return
+		}
+
+		user, err := h.authService.AuthenticateCalendarToken(token)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidToken) {
+				writeErrorResponse(w, http.StatusUnauthorized, err.Error())
+				return
+			}
+			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userContextKey, user)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authMiddleware requires a valid bearer token and attaches the
+// authenticated user to the request context
+func (h *Handler) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		token := strings.TrimPrefix(header, "Bearer ")
+		if header == "" || token == header || token == "" {
+			writeErrorResponse(w, http.StatusUnauthorized, "Missing bearer token")
+			return
+		}
+
+		user, err := h.authService.Authenticate(token)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidToken) {
+				writeErrorResponse(w, http.StatusUnauthorized, err.Error())
+				return
+			}
+			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userContextKey, user)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// adminOnly restricts a route behind authMiddleware to administrators
+func adminOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if user := currentUser(r); user == nil || !user.IsAdmin {
+			writeErrorResponse(w, http.StatusForbidden, "Admin access required")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// currentUser returns the user attached by authMiddleware
+func currentUser(r *http.Request) *domain.User {
+	user, _ := r.Context().Value(userContextKey).(*domain.User)
+	return user
+}
+
+// currentUserID returns the ID of the user attached by authMiddleware
+func currentUserID(r *http.Request) int64 {
+	if user := currentUser(r); user != nil {
+		return user.ID
+	}
+	return 0
+}
@@ -2,24 +2,43 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 	"task-manager/internal/domain"
 	"task-manager/internal/service"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
 type Handler struct {
-	taskService     service.TaskService
-	categoryService service.CategoryService
+	taskService       service.TaskService
+	categoryService   service.CategoryService
+	authService       service.AuthService
+	commentService    service.CommentService
+	attachmentService service.AttachmentService
+	webhookService    service.WebhookService
+	viewService       service.ViewService
+	statusService     service.StatusService
+	worklogService    service.WorklogService
+	eventStream       *service.EventStream
 }
 
-func NewHandler(taskService service.TaskService, categoryService service.CategoryService) *Handler {
+func NewHandler(taskService service.TaskService, categoryService service.CategoryService, authService service.AuthService, commentService service.CommentService, attachmentService service.AttachmentService, webhookService service.WebhookService, viewService service.ViewService, statusService service.StatusService, worklogService service.WorklogService, eventStream *service.EventStream) *Handler {
 	return &Handler{
-		taskService:     taskService,
-		categoryService: categoryService,
+		taskService:       taskService,
+		categoryService:   categoryService,
+		authService:       authService,
+		commentService:    commentService,
+		attachmentService: attachmentService,
+		webhookService:    webhookService,
+		viewService:       viewService,
+		statusService:     statusService,
+		worklogService:    worklogService,
+		eventStream:       eventStream,
 	}
 }
 
@@ -31,11 +50,66 @@ func (h *Handler) SetupRoutes() *mux.Router {
 
 	api := r.PathPrefix("/v1").Subrouter()
 
+	// Auth endpoints are the only anonymous routes
+	api.HandleFunc("/auth/signup", h.signup).Methods("POST")
+	api.HandleFunc("/auth/login", h.login).Methods("POST")
+
+	// EventSource cannot send an Authorization header, so the event stream
+	// also accepts the token as a query parameter
+	api.Handle("/events", queryTokenMiddleware(h.authMiddleware(http.HandlerFunc(h.streamEvents)))).Methods("GET")
+	// Calendar clients subscribe with a long-lived calendar token instead
+	api.Handle("/tasks/calendar.ics", h.calendarTokenMiddleware(http.HandlerFunc(h.getTaskCalendar))).Methods("GET")
+
+	api = api.NewRoute().Subrouter()
+	api.Use(h.authMiddleware)
+
+	api.HandleFunc("/auth/me", h.getCurrentUser).Methods("GET")
+	api.HandleFunc("/auth/calendar-token", h.issueCalendarToken).Methods("POST")
+	api.HandleFunc("/auth/calendar-token", h.revokeCalendarToken).Methods("DELETE")
+
 	api.HandleFunc("/tasks", h.createTask).Methods("POST")
 	api.HandleFunc("/tasks", h.getAllTasks).Methods("GET")
+	api.HandleFunc("/tasks/bulk", h.bulkTasks).Methods("POST")
+	api.HandleFunc("/tasks/export.csv", h.exportTasks).Methods("GET")
+	api.HandleFunc("/tasks/import", h.importTasks).Methods("POST")
 	api.HandleFunc("/tasks/{id}", h.getTask).Methods("GET")
 	api.HandleFunc("/tasks/{id}", h.updateTask).Methods("PATCH")
 	api.HandleFunc("/tasks/{id}", h.deleteTask).Methods("DELETE")
+	api.HandleFunc("/tasks/{id}/subtasks", h.getSubtasks).Methods("GET")
+	api.HandleFunc("/tasks/{id}/tree", h.getTaskTree).Methods("GET")
+	api.HandleFunc("/tasks/{id}/dependencies", h.addDependency).Methods("POST")
+	api.HandleFunc("/tasks/{id}/dependencies/{blockedById}", h.removeDependency).Methods("DELETE")
+	api.HandleFunc("/tasks/{id}/history", h.getTaskHistory).Methods("GET")
+	api.HandleFunc("/tasks/{id}/move", h.moveTask).Methods("POST")
+
+	// Comment endpoints
+	api.HandleFunc("/tasks/{id}/comments", h.createComment).Methods("POST")
+	api.HandleFunc("/tasks/{id}/comments", h.getComments).Methods("GET")
+	api.HandleFunc("/tasks/{id}/comments/{commentId}", h.updateComment).Methods("PATCH")
+	api.HandleFunc("/tasks/{id}/comments/{commentId}", h.deleteComment).Methods("DELETE")
+
+	// Time tracking endpoints
+	api.HandleFunc("/tasks/{id}/worklogs", h.createWorklog).Methods("POST")
+	api.HandleFunc("/tasks/{id}/worklogs", h.getWorklogs).Methods("GET")
+	api.HandleFunc("/tasks/{id}/worklogs/{worklogId}", h.updateWorklog).Methods("PATCH")
+	api.HandleFunc("/tasks/{id}/worklogs/{worklogId}", h.deleteWorklog).Methods("DELETE")
+	api.HandleFunc("/tasks/{id}/timer/start", h.startTimer).Methods("POST")
+	api.HandleFunc("/tasks/{id}/timer/stop", h.stopTimer).Methods("POST")
+	api.HandleFunc("/reports/time", h.getTimeReport).Methods("GET")
+
+	// Attachment endpoints
+	api.HandleFunc("/tasks/{id}/attachments", h.uploadAttachment).Methods("POST")
+	api.HandleFunc("/tasks/{id}/attachments", h.getAttachments).Methods("GET")
+	api.HandleFunc("/tasks/{id}/attachments/{attachmentId}", h.downloadAttachment).Methods("GET")
+	api.HandleFunc("/tasks/{id}/attachments/{attachmentId}", h.deleteAttachment).Methods("DELETE")
+
+	// Trash endpoints
+	api.HandleFunc("/trash", h.getTrash).Methods("GET")
+	api.HandleFunc("/trash", h.emptyTrash).Methods("DELETE")
+	api.HandleFunc("/trash/tasks/{id}/restore", h.restoreTask).Methods("POST")
+	api.HandleFunc("/trash/tasks/{id}", h.purgeTask).Methods("DELETE")
+	api.HandleFunc("/trash/categories/{id}/restore", h.restoreCategory).Methods("POST")
+	api.HandleFunc("/trash/categories/{id}", h.purgeCategory).Methods("DELETE")
 
 	// Category endpoints
 	api.HandleFunc("/categories", h.createCategory).Methods("POST")
@@ -44,6 +118,33 @@ func (h *Handler) SetupRoutes() *mux.Router {
 	api.HandleFunc("/categories/{id}", h.updateCategory).Methods("PUT")
 	api.HandleFunc("/categories/{id}", h.deleteCategory).Methods("DELETE")
 
+	// Webhook endpoints
+	api.HandleFunc("/webhooks", h.createWebhook).Methods("POST")
+	api.HandleFunc("/webhooks", h.getAllWebhooks).Methods("GET")
+	api.HandleFunc("/webhooks/{id}", h.getWebhook).Methods("GET")
+	api.HandleFunc("/webhooks/{id}", h.updateWebhook).Methods("PATCH")
+	api.HandleFunc("/webhooks/{id}", h.deleteWebhook).Methods("DELETE")
+	api.HandleFunc("/webhooks/{id}/deliveries", h.getWebhookDeliveries).Methods("GET")
+	api.HandleFunc("/webhooks/{id}/deliveries/{deliveryId}", h.getWebhookDelivery).Methods("GET")
+	api.HandleFunc("/webhooks/{id}/deliveries/{deliveryId}/replay", h.replayWebhookDelivery).Methods("POST")
+
+	// Saved view endpoints
+	api.HandleFunc("/views", h.createView).Methods("POST")
+	api.HandleFunc("/views", h.getAllViews).Methods("GET")
+	api.HandleFunc("/views/{id}", h.getView).Methods("GET")
+	api.HandleFunc("/views/{id}", h.updateView).Methods("PATCH")
+	api.HandleFunc("/views/{id}", h.deleteView).Methods("DELETE")
+	api.HandleFunc("/views/{id}/tasks", h.getViewTasks).Methods("GET")
+
+	// Statistics endpoint
+	api.HandleFunc("/stats", h.getStats).Methods("GET")
+
+	// Workflow status endpoints
+	api.HandleFunc("/statuses", h.getStatuses).Methods("GET")
+	// The workflow is shared by all users, so only administrators change it
+	api.HandleFunc("/statuses/{key}", adminOnly(h.saveStatus)).Methods("PUT")
+	api.HandleFunc("/statuses/{key}", adminOnly(h.deleteStatus)).Methods("DELETE")
+
 	return r
 }
 
@@ -54,9 +155,9 @@ func (h *Handler) createTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, err := h.taskService.CreateTask(&req)
+	task, err := h.taskService.CreateTask(currentUserID(r), &req)
 	if err != nil {
-		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		writeErrorResponse(w, taskConflictStatus(err, http.StatusBadRequest), err.Error())
 		return
 	}
 
@@ -71,19 +172,70 @@ func (h *Handler) getTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, err := h.taskService.GetTask(id)
+	task, err := h.taskService.GetTask(currentUserID(r), id)
 	if err != nil {
 		writeErrorResponse(w, http.StatusNotFound, err.Error())
 		return
 	}
 
+	w.Header().Set("ETag", etag(task.Version))
 	writeJSONResponse(w, http.StatusOK, task)
 }
 
 func (h *Handler) getAllTasks(w http.ResponseWriter, r *http.Request) {
-	// Parse query parameters for filtering and search
-	filters := &domain.TaskFilters{}
+	filters, err := h.parseTaskFilters(r)
+	if err != nil {
+		writeFilterError(w, err)
+		return
+	}
+
+	// Return a paginated envelope when the client asks for a page
+	if query := r.URL.Query(); query.Has("limit") || query.Has("cursor") {
+		page := &domain.PageRequest{Cursor: query.Get("cursor")}
+		if limitParam := query.Get("limit"); limitParam != "" {
+			limit, err := strconv.Atoi(limitParam)
+			if err != nil {
+				writeErrorResponse(w, http.StatusBadRequest, "Invalid limit")
+				return
+			}
+			page.Limit = limit
+		}
+
+		result, err := h.taskService.GetTasksPage(currentUserID(r), filters, page)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSONResponse(w, http.StatusOK, result)
+		return
+	}
+
+	// If no filters are provided, use GetAllTasks for backward compatibility
+	if filters.IsEmpty() {
+		tasks, err := h.taskService.GetAllTasks(currentUserID(r))
+		if err != nil {
+			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSONResponse(w, http.StatusOK, tasks)
+		return
+	}
 	
+	// Use filtered query
+	tasks, err := h.taskService.GetTasksWithFilters(currentUserID(r), filters)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, tasks)
+}
+
+// parseTaskFilters reads the filter and sort query parameters shared by the
+// task listing endpoints
+func (h *Handler) parseTaskFilters(r *http.Request) (*domain.TaskFilters, error) {
+	filters := &domain.TaskFilters{}
+
 	// Parse status filter
 	if statusParam := r.URL.Query().Get("status"); statusParam != "" {
 		statuses := strings.Split(statusParam, ",")
@@ -94,7 +246,7 @@ func (h *Handler) getAllTasks(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
-	
+
 	// Parse priority filter
 	if priorityParam := r.URL.Query().Get("priority"); priorityParam != "" {
 		priorities := strings.Split(priorityParam, ",")
@@ -105,31 +257,80 @@ func (h *Handler) getAllTasks(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
-	
+
 	// Parse search parameter
 	if searchParam := r.URL.Query().Get("search"); searchParam != "" {
 		filters.Search = strings.TrimSpace(searchParam)
 	}
-	
-	// If no filters are provided, use GetAllTasks for backward compatibility
-	if len(filters.Statuses) == 0 && len(filters.Priorities) == 0 && filters.Search == "" {
-		tasks, err := h.taskService.GetAllTasks()
-		if err != nil {
-			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
-			return
+
+	// Parse sort parameter, e.g. sort=-priority,title
+	if sortParam := r.URL.Query().Get("sort"); sortParam != "" {
+		filters.Sort = domain.ParseSort(sortParam)
+	}
+
+	// Parse category filter, e.g. category_id=1,2&category_mode=all
+	for _, categoryParam := range r.URL.Query()["category_id"] {
+		for _, idStr := range strings.Split(categoryParam, ",") {
+			if idStr = strings.TrimSpace(idStr); idStr == "" {
+				continue
+			}
+			id, err := strconv.ParseInt(idStr, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid category id: %s", idStr)
+			}
+			filters.CategoryIDs = append(filters.CategoryIDs, id)
 		}
-		writeJSONResponse(w, http.StatusOK, tasks)
-		return
 	}
-	
-	// Use filtered query
-	tasks, err := h.taskService.GetTasksWithFilters(filters)
-	if err != nil {
-		writeErrorResponse(w, http.StatusBadRequest, err.Error())
-		return
+	filters.CategoryMode = domain.CategoryMatchMode(r.URL.Query().Get("category_mode"))
+
+	// Parse due date filters
+	for _, param := range []string{"has_due_date", "overdue"} {
+		if value := r.URL.Query().Get(param); value != "" {
+			flag, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: must be true or false", param)
+			}
+			if param == "overdue" {
+				filters.Overdue = flag
+			} else {
+				filters.HasDueDate = &flag
+			}
+		}
 	}
 
-	writeJSONResponse(w, http.StatusOK, tasks)
+	// Parse time ranges, given as RFC 3339 times or dates
+	for param, dest := range map[string]**time.Time{
+		"due_after":      &filters.DueAfter,
+		"due_before":     &filters.DueBefore,
+		"created_after":  &filters.CreatedAfter,
+		"created_before": &filters.CreatedBefore,
+		"updated_after":  &filters.UpdatedAfter,
+		"updated_before": &filters.UpdatedBefore,
+	} {
+		if value := r.URL.Query().Get(param); value != "" {
+			t, err := domain.ParseFilterTime(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", param, err)
+			}
+			*dest = &t
+		}
+	}
+
+	// Parse filter query, e.g. q=status:todo -category:infra "login bug"
+	if queryParam := r.URL.Query().Get("q"); queryParam != "" {
+		query, err := domain.ParseTaskQuery(queryParam, h.statusService.GetWorkflow())
+		if err != nil {
+			return nil, err
+		}
+		filters.Where = query.Where
+		if filters.Search == "" {
+			filters.Search = query.Search
+		} else if query.Search != "" {
+			filters.Search = "(" + filters.Search + ") " + query.Search
+		}
+	}
+
+	return filters, nil
 }
 
 func (h *Handler) updateTask(w http.ResponseWriter, r *http.Request) {
@@ -145,13 +346,19 @@ func (h *Handler) updateTask(w http.ResponseWriter, r *http.Request) {
 		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
 		return
 	}
+	if req.ExpectedVersion, err = ifMatchVersion(r); err != nil {
+		writeErrorResponse(w, http.StatusPreconditionFailed, err.Error())
+		return
+	}
 
-	task, err := h.taskService.UpdateTask(id, &req)
+	task, err := h.taskService.UpdateTask(currentUserID(r), id, &req)
 	if err != nil {
-		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		status := taskConflictStatus(err, http.StatusBadRequest)
+		writeErrorResponse(w, versionConflictStatus(r, err, status), err.Error())
 		return
 	}
 
+	w.Header().Set("ETag", etag(task.Version))
 	writeJSONResponse(w, http.StatusOK, task)
 }
 
@@ -163,7 +370,20 @@ func (h *Handler) deleteTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.taskService.DeleteTask(id); err != nil {
+	mode := domain.SubtaskDeleteMode(r.URL.Query().Get("subtasks"))
+	if mode == "" {
+		mode = domain.SubtaskDeleteReparent
+	}
+	if !mode.IsValid() {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid subtasks mode: must be reparent or cascade")
+		return
+	}
+
+	deleteTask := h.taskService.DeleteTask
+	if mode == domain.SubtaskDeleteCascade {
+		deleteTask = h.taskService.DeleteTaskTree
+	}
+	if err := deleteTask(currentUserID(r), id); err != nil {
 		writeErrorResponse(w, http.StatusNotFound, err.Error())
 		return
 	}
@@ -179,7 +399,7 @@ func (h *Handler) createCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	category, err := h.categoryService.CreateCategory(&req)
+	category, err := h.categoryService.CreateCategory(currentUserID(r), &req)
 	if err != nil {
 		writeErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
@@ -196,17 +416,18 @@ func (h *Handler) getCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	category, err := h.categoryService.GetCategory(id)
+	category, err := h.categoryService.GetCategory(currentUserID(r), id)
 	if err != nil {
 		writeErrorResponse(w, http.StatusNotFound, err.Error())
 		return
 	}
 
+	w.Header().Set("ETag", etag(category.Version))
 	writeJSONResponse(w, http.StatusOK, category)
 }
 
 func (h *Handler) getAllCategories(w http.ResponseWriter, r *http.Request) {
-	categories, err := h.categoryService.GetAllCategories()
+	categories, err := h.categoryService.GetAllCategories(currentUserID(r))
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -228,13 +449,18 @@ func (h *Handler) updateCategory(w http.ResponseWriter, r *http.Request) {
 		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
 		return
 	}
+	if req.ExpectedVersion, err = ifMatchVersion(r); err != nil {
+		writeErrorResponse(w, http.StatusPreconditionFailed, err.Error())
+		return
+	}
 
-	category, err := h.categoryService.UpdateCategory(id, &req)
+	category, err := h.categoryService.UpdateCategory(currentUserID(r), id, &req)
 	if err != nil {
-		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		writeErrorResponse(w, versionConflictStatus(r, err, http.StatusBadRequest), err.Error())
 		return
 	}
 
+	w.Header().Set("ETag", etag(category.Version))
 	writeJSONResponse(w, http.StatusOK, category)
 }
 
@@ -246,7 +472,7 @@ func (h *Handler) deleteCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.categoryService.DeleteCategory(id); err != nil {
+	if err := h.categoryService.DeleteCategory(currentUserID(r), id); err != nil {
 		writeErrorResponse(w, http.StatusNotFound, err.Error())
 		return
 	}
@@ -265,3 +491,31 @@ func writeErrorResponse(w http.ResponseWriter, status int, message string) {
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(map[string]string{"error": message})
 }
+
+// writeFilterError rejects invalid task filters. Syntax errors in a filter
+// query also report the position of the offending character.
+func writeFilterError(w http.ResponseWriter, err error) {
+	var queryErr *domain.FilterQueryError
+	if errors.As(err, &queryErr) {
+		writeJSONResponse(w, http.StatusBadRequest, map[string]interface{}{
+			"error":    err.Error(),
+			"position": queryErr.Position,
+		})
+		return
+	}
+	writeErrorResponse(w, http.StatusBadRequest, err.Error())
+}
+
+// taskConflictStatus maps violations of task hierarchy, dependency and
+// workflow rules to 409 Conflict
+func taskConflictStatus(err error, fallback int) int {
+	if errors.Is(err, domain.ErrOpenSubtasks) ||
+		errors.Is(err, domain.ErrParentDone) ||
+		errors.Is(err, domain.ErrInvalidParent) ||
+		errors.Is(err, domain.ErrBlocked) ||
+		errors.Is(err, domain.ErrInvalidTransition) ||
+		errors.Is(err, domain.ErrDependencyCycle) {
+		return http.StatusConflict
+	}
+	return fallback
+}
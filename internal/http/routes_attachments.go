@@ -0,0 +1,149 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"task-manager/internal/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// attachmentFormField is the multipart field that carries the uploaded file
+const attachmentFormField = "file"
+
+// Attachment handlers
+func (h *Handler) uploadAttachment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	taskID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Expected a multipart/form-data body")
+		return
+	}
+
+	// Stream the file part straight to the service instead of buffering the
+	// whole form
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			writeErrorResponse(w, http.StatusBadRequest, "Missing \"file\" field")
+			return
+		}
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid multipart body")
+			return
+		}
+		if part.FormName() != attachmentFormField || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+
+		req := &domain.UploadAttachmentRequest{
+			Filename:    part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+			Content:     part,
+		}
+		attachment, err := h.attachmentService.UploadAttachment(currentUserID(r), taskID, req)
+		part.Close()
+		if err != nil {
+			writeErrorResponse(w, attachmentErrorStatus(err, http.StatusBadRequest), err.Error())
+			return
+		}
+
+		writeJSONResponse(w, http.StatusCreated, attachment)
+		return
+	}
+}
+
+func (h *Handler) getAttachments(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	taskID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	attachments, err := h.attachmentService.GetAttachments(currentUserID(r), taskID)
+	if err != nil {
+		writeErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, attachments)
+}
+
+func (h *Handler) downloadAttachment(w http.ResponseWriter, r *http.Request) {
+	taskID, attachmentID, ok := parseAttachmentPath(w, r)
+	if !ok {
+		return
+	}
+
+	attachment, content, err := h.attachmentService.OpenAttachment(currentUserID(r), taskID, attachmentID)
+	if err != nil {
+		writeErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	// Serve the stored type as-is so uploads cannot be reinterpreted as HTML
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, content); err != nil {
+		log.Printf("failed to send attachment %d: %v", attachment.ID, err)
+	}
+}
+
+func (h *Handler) deleteAttachment(w http.ResponseWriter, r *http.Request) {
+	taskID, attachmentID, ok := parseAttachmentPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.attachmentService.DeleteAttachment(currentUserID(r), taskID, attachmentID); err != nil {
+		writeErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseAttachmentPath reads the task and attachment IDs, writing a 400
+// response if either is malformed
+func parseAttachmentPath(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	vars := mux.Vars(r)
+	taskID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid task ID")
+		return 0, 0, false
+	}
+	attachmentID, err := strconv.ParseInt(vars["attachmentId"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid attachment ID")
+		return 0, 0, false
+	}
+	return taskID, attachmentID, true
+}
+
+func attachmentErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, domain.ErrAttachmentTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, domain.ErrAttachmentType):
+		return http.StatusUnsupportedMediaType
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,207 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+// F2P Tests for Task Attachments Feature
+// These tests verify the /v1/tasks/{id}/attachments subresource
+
+type mockAttachmentService struct {
+	attachments map[int64]*domain.Attachment
+	contents    map[int64][]byte
+	limits      domain.AttachmentLimits
+	nextID      int64
+}
+
+func newMockAttachmentService() *mockAttachmentService {
+	return &mockAttachmentService{
+		attachments: make(map[int64]*domain.Attachment),
+		contents:    make(map[int64][]byte),
+		limits:      domain.AttachmentLimits{MaxSize: 16, AllowedTypes: []string{"image/*", "text/plain"}},
+		nextID:      1,
+	}
+}
+
+func (m *mockAttachmentService) UploadAttachment(userID, taskID int64, req *domain.UploadAttachmentRequest) (*domain.Attachment, error) {
+	if !m.limits.Allows(req.ContentType) {
+		return nil, domain.ErrAttachmentType
+	}
+	content, err := io.ReadAll(req.Content)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > m.limits.MaxSize {
+		return nil, domain.ErrAttachmentTooLarge
+	}
+	attachment := &domain.Attachment{
+		ID:          m.nextID,
+		TaskID:      taskID,
+		UserID:      userID,
+		Filename:    req.Filename,
+		ContentType: req.ContentType,
+		Size:        int64(len(content)),
+	}
+	m.attachments[m.nextID] = attachment
+	m.contents[m.nextID] = content
+	m.nextID++
+	return attachment, nil
+}
+
+func (m *mockAttachmentService) GetAttachments(userID, taskID int64) ([]domain.Attachment, error) {
+	attachments := []domain.Attachment{}
+	for id := int64(1); id < m.nextID; id++ {
+		if attachment, exists := m.attachments[id]; exists && attachment.TaskID == taskID {
+			attachments = append(attachments, *attachment)
+		}
+	}
+	return attachments, nil
+}
+
+func (m *mockAttachmentService) OpenAttachment(userID, taskID, id int64) (*domain.Attachment, io.ReadCloser, error) {
+	attachment, exists := m.attachments[id]
+	if !exists || attachment.TaskID != taskID {
+		return nil, nil, domain.ErrAttachmentNotFound
+	}
+	return attachment, io.NopCloser(bytes.NewReader(m.contents[id])), nil
+}
+
+func (m *mockAttachmentService) DeleteAttachment(userID, taskID, id int64) error {
+	attachment, exists := m.attachments[id]
+	if !exists || attachment.TaskID != taskID {
+		return domain.ErrAttachmentNotFound
+	}
+	delete(m.attachments, id)
+	delete(m.contents, id)
+	return nil
+}
+
+func (m *mockAttachmentService) PurgeDeletedBlobs() (int, error) {
+	return 0, nil
+}
+
+// multipartUpload builds a multipart body with one file in the given field
+func multipartUpload(t *testing.T, field, filename, contentType, content string) (*bytes.Buffer, string) {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, field, filename))
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		t.Fatalf("Failed to create part: %v", err)
+	}
+	part.Write([]byte(content))
+	writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+func TestTaskAttachmentUpload_F2P(t *testing.T) {
+	handler := NewHandler(newMockTaskService(), newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	tests := []struct {
+		name           string
+		field          string
+		contentType    string
+		content        string
+		expectedStatus int
+	}{
+		{name: "should upload allowed file", field: "file", contentType: "text/plain", content: "hello", expectedStatus: http.StatusCreated},
+		{name: "should reject disallowed type", field: "file", contentType: "application/x-msdownload", content: "MZ", expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "should reject oversized file", field: "file", contentType: "text/plain", content: strings.Repeat("x", 17), expectedStatus: http.StatusRequestEntityTooLarge},
+		{name: "should reject missing file field", field: "other", contentType: "text/plain", content: "hello", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, contentType := multipartUpload(t, tt.field, "notes.txt", tt.contentType, tt.content)
+			req := httptest.NewRequest("POST", "/v1/tasks/1/attachments", body)
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authenticated(req))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("should reject non-multipart body", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/v1/tasks/1/attachments", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authenticated(req))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
+
+func TestTaskAttachmentDownload_F2P(t *testing.T) {
+	mockAttachments := newMockAttachmentService()
+	handler := NewHandler(newMockTaskService(), newMockCategoryService(), newMockAuthService(), newMockCommentService(), mockAttachments, newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	mockAttachments.UploadAttachment(testUserID, 1, &domain.UploadAttachmentRequest{
+		Filename:    "shot.png",
+		ContentType: "image/png",
+		Content:     strings.NewReader("png-bytes"),
+	})
+
+	req := httptest.NewRequest("GET", "/v1/tasks/1/attachments/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authenticated(req))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if w.Body.String() != "png-bytes" {
+		t.Errorf("Expected file contents, got %q", w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != "image/png" {
+		t.Errorf("Expected Content-Type image/png, got %q", got)
+	}
+	if got := w.Header().Get("Content-Disposition"); got != `attachment; filename=shot.png` {
+		t.Errorf("Unexpected Content-Disposition %q", got)
+	}
+	if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("Expected nosniff, got %q", got)
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		expectedStatus int
+	}{
+		{name: "should list attachments", method: "GET", url: "/v1/tasks/1/attachments", expectedStatus: http.StatusOK},
+		{name: "should return 404 for another task's attachment", method: "GET", url: "/v1/tasks/2/attachments/1", expectedStatus: http.StatusNotFound},
+		{name: "should reject invalid attachment ID", method: "GET", url: "/v1/tasks/1/attachments/abc", expectedStatus: http.StatusBadRequest},
+		{name: "should delete attachment", method: "DELETE", url: "/v1/tasks/1/attachments/1", expectedStatus: http.StatusNoContent},
+		{name: "should return 404 after delete", method: "GET", url: "/v1/tasks/1/attachments/1", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authenticated(req))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
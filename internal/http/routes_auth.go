@@ -0,0 +1,73 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"task-manager/internal/domain"
+	"task-manager/internal/service"
+)
+
+// Auth handlers
+func (h *Handler) signup(w http.ResponseWriter, r *http.Request) {
+	var req domain.SignupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	resp, err := h.authService.Signup(&req)
+	if err != nil {
+		if errors.Is(err, service.ErrEmailTaken) {
+			writeErrorResponse(w, http.StatusConflict, err.Error())
+			return
+		}
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, resp)
+}
+
+func (h *Handler) login(w http.ResponseWriter, r *http.Request) {
+	var req domain.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	resp, err := h.authService.Login(&req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			writeErrorResponse(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+func (h *Handler) getCurrentUser(w http.ResponseWriter, r *http.Request) {
+	writeJSONResponse(w, http.StatusOK, currentUser(r))
+}
+
+func (h *Handler) issueCalendarToken(w http.ResponseWriter, r *http.Request) {
+	token, err := h.authService.IssueCalendarToken(currentUserID(r))
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, token)
+}
+
+func (h *Handler) revokeCalendarToken(w http.ResponseWriter, r *http.Request) {
+	if err := h.authService.RevokeCalendarToken(currentUserID(r)); err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,225 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/domain"
+	"task-manager/internal/service"
+	"testing"
+)
+
+// F2P Tests for Authentication Feature
+// These tests verify signup, login and that API routes require a bearer token
+
+const (
+	testUserID int64 = 1
+	testToken        = "test-token"
+)
+
+// Mock auth service for testing; "test-token" always resolves to testUserID
+type mockAuthService struct {
+	users         map[string]*domain.User
+	calendarToken string
+}
+
+func newMockAuthService() *mockAuthService {
+	return &mockAuthService{
+		users: map[string]*domain.User{
+			"test@example.com": {ID: testUserID, Email: "test@example.com"},
+		},
+	}
+}
+
+func (m *mockAuthService) Signup(req *domain.SignupRequest) (*domain.AuthResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if _, exists := m.users[req.Email]; exists {
+		return nil, service.ErrEmailTaken
+	}
+	user := &domain.User{ID: int64(len(m.users) + 1), Email: req.Email}
+	m.users[req.Email] = user
+	return &domain.AuthResponse{Token: testToken, User: user}, nil
+}
+
+func (m *mockAuthService) Login(req *domain.LoginRequest) (*domain.AuthResponse, error) {
+	user, exists := m.users[req.Email]
+	if !exists || req.Password != "password123" {
+		return nil, service.ErrInvalidCredentials
+	}
+	return &domain.AuthResponse{Token: testToken, User: user}, nil
+}
+
+func (m *mockAuthService) Authenticate(token string) (*domain.User, error) {
+	if token != testToken {
+		return nil, service.ErrInvalidToken
+	}
+	return m.users["test@example.com"], nil
+}
+
+func (m *mockAuthService) IssueCalendarToken(userID int64) (*domain.CalendarToken, error) {
+	m.calendarToken = "calendar-token"
+	return &domain.CalendarToken{Token: m.calendarToken, URL: service.CalendarFeedPath + "?token=" + m.calendarToken}, nil
+}
+
+func (m *mockAuthService) RevokeCalendarToken(userID int64) error {
+	m.calendarToken = ""
+	return nil
+}
+
+func (m *mockAuthService) AuthenticateCalendarToken(token string) (*domain.User, error) {
+	if m.calendarToken == "" || token != m.calendarToken {
+		return nil, service.ErrInvalidToken
+	}
+	return m.users["test@example.com"], nil
+}
+
+// authenticated adds the mock bearer token to a test request
+func authenticated(req *http.Request) *http.Request {
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	return req
+}
+
+func TestSignup_F2P(t *testing.T) {
+	handler := NewHandler(newMockTaskService(), newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		expectedStatus int
+	}{
+		{
+			name:           "should create a new account",
+			requestBody:    domain.SignupRequest{Email: "new@example.com", Password: "password123"},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "should reject a registered email",
+			requestBody:    domain.SignupRequest{Email: "test@example.com", Password: "password123"},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "should reject a short password",
+			requestBody:    domain.SignupRequest{Email: "short@example.com", Password: "short"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "should reject invalid JSON",
+			requestBody:    "not json",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonBody, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest("POST", "/v1/auth/signup", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedStatus == http.StatusCreated {
+				var response domain.AuthResponse
+				json.Unmarshal(w.Body.Bytes(), &response)
+				if response.Token == "" {
+					t.Error("Expected a token in the signup response")
+				}
+			}
+		})
+	}
+}
+
+func TestLogin_F2P(t *testing.T) {
+	handler := NewHandler(newMockTaskService(), newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	tests := []struct {
+		name           string
+		requestBody    domain.LoginRequest
+		expectedStatus int
+	}{
+		{
+			name:           "should log in with valid credentials",
+			requestBody:    domain.LoginRequest{Email: "test@example.com", Password: "password123"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "should reject a wrong password",
+			requestBody:    domain.LoginRequest{Email: "test@example.com", Password: "wrong"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonBody, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest("POST", "/v1/auth/login", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_F2P(t *testing.T) {
+	mockService := newMockTaskService()
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Owned Task", Priority: domain.PriorityLow})
+
+	tests := []struct {
+		name           string
+		path           string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "should reject missing token", path: "/v1/tasks", expectedStatus: http.StatusUnauthorized},
+		{name: "should reject non-bearer scheme", path: "/v1/tasks", authorization: "Basic " + testToken, expectedStatus: http.StatusUnauthorized},
+		{name: "should reject invalid token", path: "/v1/tasks", authorization: "Bearer wrong", expectedStatus: http.StatusUnauthorized},
+		{name: "should protect categories", path: "/v1/categories", expectedStatus: http.StatusUnauthorized},
+		{name: "should accept valid token", path: "/v1/tasks", authorization: "Bearer " + testToken, expectedStatus: http.StatusOK},
+		{name: "should return current user", path: "/v1/auth/me", authorization: "Bearer " + testToken, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+
+	t.Run("should attach the user to the request", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/v1/auth/me", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authenticated(req))
+
+		var user domain.User
+		json.Unmarshal(w.Body.Bytes(), &user)
+		if user.ID != testUserID {
+			t.Errorf("Expected user %d, got %d", testUserID, user.ID)
+		}
+	})
+}
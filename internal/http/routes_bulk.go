@@ -0,0 +1,29 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"task-manager/internal/domain"
+)
+
+// Bulk handlers
+func (h *Handler) bulkTasks(w http.ResponseWriter, r *http.Request) {
+	var req domain.BulkTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	result, err := h.taskService.BulkTasks(currentUserID(r), &req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Nothing was changed when any task failed
+	status := http.StatusOK
+	if !result.Applied {
+		status = http.StatusConflict
+	}
+	writeJSONResponse(w, status, result)
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+// F2P Tests for Bulk Task Operations
+// These tests verify POST /v1/tasks/bulk
+
+func TestBulkTasks_F2P(t *testing.T) {
+	mockService := newMockTaskService()
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "First", Priority: domain.PriorityLow})
+	mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Second", Priority: domain.PriorityLow})
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedItems  []domain.BulkItemStatus
+	}{
+		{
+			name:           "should update every listed task",
+			body:           `{"action":"update","ids":[1,2],"update":{"priority":"high"}}`,
+			expectedStatus: http.StatusOK,
+			expectedItems:  []domain.BulkItemStatus{domain.BulkItemUpdated, domain.BulkItemUpdated},
+		},
+		{
+			name:           "should roll back when a task fails",
+			body:           `{"action":"delete","ids":[1,99]}`,
+			expectedStatus: http.StatusConflict,
+			expectedItems:  []domain.BulkItemStatus{domain.BulkItemRolledBack, domain.BulkItemFailed},
+		},
+		{
+			name:           "should delete every listed task",
+			body:           `{"action":"delete","ids":[2]}`,
+			expectedStatus: http.StatusOK,
+			expectedItems:  []domain.BulkItemStatus{domain.BulkItemDeleted},
+		},
+		{
+			name:           "should reject an unknown action",
+			body:           `{"action":"archive","ids":[1]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "should reject a request without ids or filters",
+			body:           `{"action":"delete"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "should reject invalid JSON",
+			body:           `{"action":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/v1/tasks/bulk", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authenticated(req))
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedItems == nil {
+				return
+			}
+
+			var result domain.BulkTaskResult
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if len(result.Results) != len(tt.expectedItems) {
+				t.Fatalf("Expected %d results, got %+v", len(tt.expectedItems), result.Results)
+			}
+			for i, status := range tt.expectedItems {
+				if result.Results[i].Status != status {
+					t.Errorf("Result %d: expected status %s, got %s", i, status, result.Results[i].Status)
+				}
+			}
+		})
+	}
+
+	if _, exists := mockService.tasks[1]; !exists {
+		t.Error("Rolled back delete should leave task 1 in place")
+	}
+}
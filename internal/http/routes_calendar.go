@@ -0,0 +1,38 @@
+package http
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"task-manager/internal/domain"
+)
+
+// Calendar handlers
+func (h *Handler) getTaskCalendar(w http.ResponseWriter, r *http.Request) {
+	component := domain.CalendarTodo
+	if value := r.URL.Query().Get("component"); value != "" {
+		component = domain.CalendarComponent(strings.ToLower(value))
+		if !component.IsValid() {
+			writeErrorResponse(w, http.StatusBadRequest, domain.ErrInvalidCalendarComponent.Error())
+			return
+		}
+	}
+
+	filters, err := h.parseTaskFilters(r)
+	if err != nil {
+		writeFilterError(w, err)
+		return
+	}
+
+	tasks, err := h.taskService.GetTasksWithFilters(currentUserID(r), filters)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="tasks.ics"`)
+	if err := domain.WriteTasksICS(w, tasks, component, h.statusService.GetWorkflow()); err != nil {
+		log.Printf("Failed to write task calendar: %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task-manager/internal/domain"
+	"testing"
+	"time"
+)
+
+// F2P Tests for the iCalendar Feed
+// These tests verify GET /v1/tasks/calendar.ics
+
+func TestTaskCalendar_F2P(t *testing.T) {
+	mockService := newMockTaskService()
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	due := time.Now().AddDate(0, 0, 3)
+	mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Due soon", Priority: domain.PriorityHigh, DueDate: &due})
+	mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Due later", Priority: domain.PriorityLow, DueDate: &due})
+	mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Undated", Priority: domain.PriorityHigh})
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		contains       []string
+		excludes       []string
+	}{
+		{
+			name:           "should list dated tasks as VTODOs",
+			url:            "/v1/tasks/calendar.ics",
+			expectedStatus: http.StatusOK,
+			contains:       []string{"BEGIN:VTODO", "SUMMARY:Due soon", "SUMMARY:Due later", "UID:task-1@task-manager"},
+			excludes:       []string{"Undated"},
+		},
+		{
+			name:           "should apply task filters",
+			url:            "/v1/tasks/calendar.ics?priority=high",
+			expectedStatus: http.StatusOK,
+			contains:       []string{"SUMMARY:Due soon"},
+			excludes:       []string{"Due later"},
+		},
+		{
+			name:           "should render all-day events when asked",
+			url:            "/v1/tasks/calendar.ics?component=vevent",
+			expectedStatus: http.StatusOK,
+			contains:       []string{"BEGIN:VEVENT", "DTSTART;VALUE=DATE:"},
+			excludes:       []string{"VTODO"},
+		},
+		{
+			name:           "should reject an unknown component",
+			url:            "/v1/tasks/calendar.ics?component=vjournal",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "should reject an invalid filter",
+			url:            "/v1/tasks/calendar.ics?status=unknown",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authenticated(req))
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+				t.Errorf("Expected text/calendar content type, got %q", ct)
+			}
+			body := w.Body.String()
+			for _, want := range tt.contains {
+				if !strings.Contains(body, want) {
+					t.Errorf("Expected calendar to contain %q:\n%s", want, body)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(body, unwanted) {
+					t.Errorf("Expected calendar not to contain %q:\n%s", unwanted, body)
+				}
+			}
+		})
+	}
+}
+
+func TestTaskCalendarToken_F2P(t *testing.T) {
+	mockService := newMockTaskService()
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	due := time.Now().AddDate(0, 0, 3)
+	mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Due soon", Priority: domain.PriorityHigh, DueDate: &due})
+
+	fetch := func(url string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+		return w.Code
+	}
+
+	if code := fetch("/v1/tasks/calendar.ics"); code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d without a token, got %d", http.StatusUnauthorized, code)
+	}
+	if code := fetch("/v1/tasks/calendar.ics?token=" + testToken); code != http.StatusUnauthorized {
+		t.Errorf("Expected a bearer token to be rejected as a calendar token, got %d", code)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authenticated(httptest.NewRequest("POST", "/v1/auth/calendar-token", nil)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var token domain.CalendarToken
+	if err := json.NewDecoder(w.Body).Decode(&token); err != nil {
+		t.Fatalf("Failed to decode calendar token: %v", err)
+	}
+
+	if code := fetch(token.URL); code != http.StatusOK {
+		t.Errorf("Expected status %d with the calendar token, got %d", http.StatusOK, code)
+	}
+	if code := fetch("/v1/tasks/calendar.ics?token=wrong"); code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d with a wrong token, got %d", http.StatusUnauthorized, code)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authenticated(httptest.NewRequest("DELETE", "/v1/auth/calendar-token", nil)))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if code := fetch(token.URL); code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d after revoking, got %d", http.StatusUnauthorized, code)
+	}
+}
@@ -14,28 +14,32 @@ import (
 // Mock category service for testing
 type mockCategoryService struct {
 	categories map[int64]*domain.Category
+	trash      map[int64]*domain.Category
 	nextID     int64
 }
 
 func newMockCategoryService() *mockCategoryService {
 	return &mockCategoryService{
 		categories: make(map[int64]*domain.Category),
+		trash:      make(map[int64]*domain.Category),
 		nextID:     1,
 	}
 }
 
-func (m *mockCategoryService) CreateCategory(req *domain.CreateCategoryRequest) (*domain.Category, error) {
+func (m *mockCategoryService) CreateCategory(userID int64, req *domain.CreateCategoryRequest) (*domain.Category, error) {
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 
 	category := &domain.Category{
 		ID:          m.nextID,
+		UserID:      userID,
 		Name:        req.Name,
 		Description: req.Description,
 		Color:       req.Color,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
+		Version:     1,
 	}
 
 	m.categories[m.nextID] = category
@@ -43,7 +47,7 @@ func (m *mockCategoryService) CreateCategory(req *domain.CreateCategoryRequest)
 	return category, nil
 }
 
-func (m *mockCategoryService) GetCategory(id int64) (*domain.Category, error) {
+func (m *mockCategoryService) GetCategory(userID, id int64) (*domain.Category, error) {
 	category, exists := m.categories[id]
 	if !exists {
 		return nil, domain.ErrCategoryNotFound
@@ -51,7 +55,7 @@ func (m *mockCategoryService) GetCategory(id int64) (*domain.Category, error) {
 	return category, nil
 }
 
-func (m *mockCategoryService) GetAllCategories() ([]domain.Category, error) {
+func (m *mockCategoryService) GetAllCategories(userID int64) ([]domain.Category, error) {
 	var categories []domain.Category
 	for _, category := range m.categories {
 		categories = append(categories, *category)
@@ -59,7 +63,7 @@ func (m *mockCategoryService) GetAllCategories() ([]domain.Category, error) {
 	return categories, nil
 }
 
-func (m *mockCategoryService) UpdateCategory(id int64, req *domain.UpdateCategoryRequest) (*domain.Category, error) {
+func (m *mockCategoryService) UpdateCategory(userID, id int64, req *domain.UpdateCategoryRequest) (*domain.Category, error) {
 	category, exists := m.categories[id]
 	if !exists {
 		return nil, domain.ErrCategoryNotFound
@@ -68,6 +72,9 @@ func (m *mockCategoryService) UpdateCategory(id int64, req *domain.UpdateCategor
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
+	if req.ExpectedVersion != nil && *req.ExpectedVersion != category.Version {
+		return nil, domain.ErrVersionConflict
+	}
 
 	if req.Name != nil {
 		category.Name = *req.Name
@@ -79,23 +86,64 @@ func (m *mockCategoryService) UpdateCategory(id int64, req *domain.UpdateCategor
 		category.Color = req.Color
 	}
 	category.UpdatedAt = time.Now()
+	category.Version++
 
 	return category, nil
 }
 
-func (m *mockCategoryService) DeleteCategory(id int64) error {
-	_, exists := m.categories[id]
+func (m *mockCategoryService) DeleteCategory(userID, id int64) error {
+	category, exists := m.categories[id]
 	if !exists {
 		return domain.ErrCategoryNotFound
 	}
+	now := time.Now()
+	category.DeletedAt = &now
+	m.trash[id] = category
 	delete(m.categories, id)
 	return nil
 }
 
+func (m *mockCategoryService) GetTrashedCategories(userID int64) ([]domain.Category, error) {
+	categories := []domain.Category{}
+	for _, category := range m.trash {
+		categories = append(categories, *category)
+	}
+	return categories, nil
+}
+
+func (m *mockCategoryService) RestoreCategory(userID, id int64) (*domain.Category, error) {
+	category, exists := m.trash[id]
+	if !exists {
+		return nil, domain.ErrNotInTrash
+	}
+	category.DeletedAt = nil
+	m.categories[id] = category
+	delete(m.trash, id)
+	return category, nil
+}
+
+func (m *mockCategoryService) PurgeCategory(userID, id int64) error {
+	if _, exists := m.trash[id]; !exists {
+		return domain.ErrNotInTrash
+	}
+	delete(m.trash, id)
+	return nil
+}
+
+func (m *mockCategoryService) PurgeTrash(userID int64) error {
+	m.trash = make(map[int64]*domain.Category)
+	return nil
+}
+
+func (m *mockCategoryService) PurgeExpiredTrash(cutoff time.Time) (int64, error) {
+	return 0, nil
+}
+
 func TestCreateCategory_F2P(t *testing.T) {
 	mockCategoryService := newMockCategoryService()
 	handler := &Handler{
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 
 	router := handler.SetupRoutes()
@@ -145,7 +193,7 @@ func TestCreateCategory_F2P(t *testing.T) {
 			req.Header.Set("Content-Type", "application/json")
 
 			w := httptest.NewRecorder()
-			router.ServeHTTP(w, req)
+			router.ServeHTTP(w, authenticated(req))
 
 			if w.Code != tt.expectedStatus {
 				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
@@ -179,6 +227,7 @@ func TestGetCategory_F2P(t *testing.T) {
 	mockCategoryService := newMockCategoryService()
 	handler := &Handler{
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 
 	router := handler.SetupRoutes()
@@ -216,7 +265,7 @@ func TestGetCategory_F2P(t *testing.T) {
 			req := httptest.NewRequest("GET", fmt.Sprintf("/v1/categories/%d", tt.categoryID), nil)
 
 			w := httptest.NewRecorder()
-			router.ServeHTTP(w, req)
+			router.ServeHTTP(w, authenticated(req))
 
 			if w.Code != tt.expectedStatus {
 				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
@@ -238,6 +287,7 @@ func TestGetAllCategories_F2P(t *testing.T) {
 	mockCategoryService := newMockCategoryService()
 	handler := &Handler{
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 
 	router := handler.SetupRoutes()
@@ -256,7 +306,7 @@ func TestGetAllCategories_F2P(t *testing.T) {
 	req := httptest.NewRequest("GET", "/v1/categories", nil)
 
 	w := httptest.NewRecorder()
-	router.ServeHTTP(w, req)
+	router.ServeHTTP(w, authenticated(req))
 
 	if w.Code != http.StatusOK {
 		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -274,6 +324,7 @@ func TestUpdateCategory_F2P(t *testing.T) {
 	mockCategoryService := newMockCategoryService()
 	handler := &Handler{
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 
 	router := handler.SetupRoutes()
@@ -338,7 +389,7 @@ func TestUpdateCategory_F2P(t *testing.T) {
 			req.Header.Set("Content-Type", "application/json")
 
 			w := httptest.NewRecorder()
-			router.ServeHTTP(w, req)
+			router.ServeHTTP(w, authenticated(req))
 
 			if w.Code != tt.expectedStatus {
 				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
@@ -360,6 +411,7 @@ func TestDeleteCategory_F2P(t *testing.T) {
 	mockCategoryService := newMockCategoryService()
 	handler := &Handler{
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 
 	router := handler.SetupRoutes()
@@ -397,7 +449,7 @@ func TestDeleteCategory_F2P(t *testing.T) {
 			req := httptest.NewRequest("DELETE", "/v1/categories/1", nil)
 
 			w := httptest.NewRecorder()
-			router.ServeHTTP(w, req)
+			router.ServeHTTP(w, authenticated(req))
 
 			if w.Code != tt.expectedStatus {
 				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
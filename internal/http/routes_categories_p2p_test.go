@@ -17,6 +17,7 @@ func TestTaskWithCategories_P2P(t *testing.T) {
 	handler := &Handler{
 		taskService:     mockTaskService,
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 
 	router := handler.SetupRoutes()
@@ -44,7 +45,7 @@ func TestTaskWithCategories_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusCreated {
 			t.Errorf("Expected status %d, got %d", http.StatusCreated, w.Code)
@@ -77,7 +78,7 @@ func TestTaskWithCategories_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusCreated {
 			t.Errorf("Expected status %d, got %d", http.StatusCreated, w.Code)
@@ -109,7 +110,7 @@ func TestTaskWithCategories_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		var createdTask domain.Task
 		json.Unmarshal(w.Body.Bytes(), &createdTask)
@@ -124,7 +125,7 @@ func TestTaskWithCategories_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w = httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -154,7 +155,7 @@ func TestTaskWithCategories_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		var createdTask domain.Task
 		json.Unmarshal(w.Body.Bytes(), &createdTask)
@@ -163,7 +164,7 @@ func TestTaskWithCategories_P2P(t *testing.T) {
 		req = httptest.NewRequest("GET", "/v1/tasks/1", nil)
 
 		w = httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -183,7 +184,7 @@ func TestTaskWithCategories_P2P(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks", nil)
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -209,6 +210,7 @@ func TestCategoryManagement_P2P(t *testing.T) {
 	mockCategoryService := newMockCategoryService()
 	handler := &Handler{
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 
 	router := handler.SetupRoutes()
@@ -226,7 +228,7 @@ func TestCategoryManagement_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusCreated {
 			t.Errorf("Expected status %d, got %d", http.StatusCreated, w.Code)
@@ -239,7 +241,7 @@ func TestCategoryManagement_P2P(t *testing.T) {
 		req = httptest.NewRequest("GET", "/v1/categories/1", nil)
 
 		w = httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -266,7 +268,7 @@ func TestCategoryManagement_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		var createdCategory domain.Category
 		json.Unmarshal(w.Body.Bytes(), &createdCategory)
@@ -281,7 +283,7 @@ func TestCategoryManagement_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w = httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -315,7 +317,7 @@ func TestCategoryManagement_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		var createdCategory domain.Category
 		json.Unmarshal(w.Body.Bytes(), &createdCategory)
@@ -324,7 +326,7 @@ func TestCategoryManagement_P2P(t *testing.T) {
 		req = httptest.NewRequest("DELETE", fmt.Sprintf("/v1/categories/%d", createdCategory.ID), nil)
 
 		w = httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusNoContent {
 			t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
@@ -334,7 +336,7 @@ func TestCategoryManagement_P2P(t *testing.T) {
 		req = httptest.NewRequest("GET", fmt.Sprintf("/v1/categories/%d", createdCategory.ID), nil)
 
 		w = httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusNotFound {
 			t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"task-manager/internal/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// Comment handlers
+func (h *Handler) createComment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	taskID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var req domain.CreateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	comment, err := h.commentService.CreateComment(currentUserID(r), taskID, &req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, comment)
+}
+
+func (h *Handler) getComments(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	taskID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	comments, err := h.commentService.GetComments(currentUserID(r), taskID)
+	if err != nil {
+		writeErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, comments)
+}
+
+func (h *Handler) updateComment(w http.ResponseWriter, r *http.Request) {
+	taskID, commentID, ok := parseCommentPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	comment, err := h.commentService.UpdateComment(currentUserID(r), taskID, commentID, &req)
+	if err != nil {
+		writeErrorResponse(w, commentErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, comment)
+}
+
+func (h *Handler) deleteComment(w http.ResponseWriter, r *http.Request) {
+	taskID, commentID, ok := parseCommentPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.commentService.DeleteComment(currentUserID(r), taskID, commentID); err != nil {
+		writeErrorResponse(w, commentErrorStatus(err, http.StatusNotFound), err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseCommentPath reads the task and comment IDs, writing a 400 response if
+// either is malformed
+func parseCommentPath(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	vars := mux.Vars(r)
+	taskID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid task ID")
+		return 0, 0, false
+	}
+	commentID, err := strconv.ParseInt(vars["commentId"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid comment ID")
+		return 0, 0, false
+	}
+	return taskID, commentID, true
+}
+
+func commentErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, domain.ErrNotCommentAuthor):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrCommentNotFound):
+		return http.StatusNotFound
+	default:
+		return fallback
+	}
+}
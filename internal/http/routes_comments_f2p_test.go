@@ -0,0 +1,133 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+// F2P Tests for Task Comments Feature
+// These tests verify the /v1/tasks/{id}/comments subresource
+
+type mockCommentService struct {
+	comments map[int64]*domain.Comment
+	nextID   int64
+}
+
+func newMockCommentService() *mockCommentService {
+	return &mockCommentService{
+		comments: make(map[int64]*domain.Comment),
+		nextID:   1,
+	}
+}
+
+func (m *mockCommentService) CreateComment(userID, taskID int64, req *domain.CreateCommentRequest) (*domain.Comment, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	comment := &domain.Comment{ID: m.nextID, TaskID: taskID, AuthorID: userID, Body: req.Body}
+	m.comments[m.nextID] = comment
+	m.nextID++
+	return comment, nil
+}
+
+func (m *mockCommentService) GetComments(userID, taskID int64) ([]domain.Comment, error) {
+	comments := []domain.Comment{}
+	for id := int64(1); id < m.nextID; id++ {
+		if comment, exists := m.comments[id]; exists && comment.TaskID == taskID {
+			comments = append(comments, *comment)
+		}
+	}
+	return comments, nil
+}
+
+func (m *mockCommentService) UpdateComment(userID, taskID, id int64, req *domain.UpdateCommentRequest) (*domain.Comment, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	comment, exists := m.comments[id]
+	if !exists || comment.TaskID != taskID {
+		return nil, domain.ErrCommentNotFound
+	}
+	if comment.AuthorID != userID {
+		return nil, domain.ErrNotCommentAuthor
+	}
+	comment.Body = req.Body
+	return comment, nil
+}
+
+func (m *mockCommentService) DeleteComment(userID, taskID, id int64) error {
+	comment, exists := m.comments[id]
+	if !exists || comment.TaskID != taskID {
+		return domain.ErrCommentNotFound
+	}
+	if comment.AuthorID != userID {
+		return domain.ErrNotCommentAuthor
+	}
+	delete(m.comments, id)
+	return nil
+}
+
+func TestTaskComments_F2P(t *testing.T) {
+	mockComments := newMockCommentService()
+	handler := NewHandler(newMockTaskService(), newMockCategoryService(), newMockAuthService(), mockComments, newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	// A comment written by someone else cannot be edited
+	mockComments.CreateComment(testUserID+1, 1, &domain.CreateCommentRequest{Body: "Not yours"})
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		expectedStatus int
+	}{
+		{name: "should create comment", method: "POST", url: "/v1/tasks/1/comments", body: `{"body": "Looks good"}`, expectedStatus: http.StatusCreated},
+		{name: "should reject empty comment", method: "POST", url: "/v1/tasks/1/comments", body: `{"body": "  "}`, expectedStatus: http.StatusBadRequest},
+		{name: "should reject invalid JSON", method: "POST", url: "/v1/tasks/1/comments", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "should list comments", method: "GET", url: "/v1/tasks/1/comments", expectedStatus: http.StatusOK},
+		{name: "should edit own comment", method: "PATCH", url: "/v1/tasks/1/comments/2", body: `{"body": "Looks great"}`, expectedStatus: http.StatusOK},
+		{name: "should forbid editing another author's comment", method: "PATCH", url: "/v1/tasks/1/comments/1", body: `{"body": "Mine now"}`, expectedStatus: http.StatusForbidden},
+		{name: "should return 404 for unknown comment", method: "PATCH", url: "/v1/tasks/1/comments/99", body: `{"body": "Hello"}`, expectedStatus: http.StatusNotFound},
+		{name: "should reject invalid comment ID", method: "DELETE", url: "/v1/tasks/1/comments/abc", expectedStatus: http.StatusBadRequest},
+		{name: "should delete own comment", method: "DELETE", url: "/v1/tasks/1/comments/2", expectedStatus: http.StatusNoContent},
+		{name: "should return 404 for deleted comment", method: "DELETE", url: "/v1/tasks/1/comments/2", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authenticated(req))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTaskCommentCountInList_F2P(t *testing.T) {
+	mockService := newMockTaskService()
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	task, _ := mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Discussed", Priority: domain.PriorityLow})
+	task.CommentCount = 3
+
+	req := httptest.NewRequest("GET", "/v1/tasks", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authenticated(req))
+
+	var tasks []map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &tasks); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0]["comment_count"] != float64(3) {
+		t.Errorf("Expected comment_count 3 in list response, got %v", tasks)
+	}
+}
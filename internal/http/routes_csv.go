@@ -0,0 +1,108 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"task-manager/internal/domain"
+)
+
+// maxImportSize caps the size of an uploaded CSV file
+const maxImportSize = 5 << 20
+
+// CSV handlers
+func (h *Handler) exportTasks(w http.ResponseWriter, r *http.Request) {
+	filters, err := h.parseTaskFilters(r)
+	if err != nil {
+		writeFilterError(w, err)
+		return
+	}
+
+	tasks, err := h.taskService.GetTasksWithFilters(currentUserID(r), filters)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="tasks.csv"`)
+	if err := domain.WriteTasksCSV(w, tasks); err != nil {
+		log.Printf("Failed to write task export: %v", err)
+	}
+}
+
+func (h *Handler) importTasks(w http.ResponseWriter, r *http.Request) {
+	var opts domain.TaskImportOptions
+	for name, target := range map[string]*bool{"dry_run": &opts.DryRun, "create_categories": &opts.CreateCategories} {
+		if value := r.URL.Query().Get(name); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				writeErrorResponse(w, http.StatusBadRequest, "Invalid "+name+": must be true or false")
+				return
+			}
+			*target = parsed
+		}
+	}
+
+	body, err := importBody(w, r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := domain.ReadTaskImportRows(body, h.statusService.GetWorkflow())
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeErrorResponse(w, http.StatusRequestEntityTooLarge, "CSV file is too large")
+			return
+		}
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.taskService.ImportTasks(currentUserID(r), rows, opts)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	status := http.StatusCreated
+	switch {
+	case len(result.Errors) > 0:
+		status = http.StatusUnprocessableEntity
+	case result.DryRun:
+		status = http.StatusOK
+	}
+	writeJSONResponse(w, status, result)
+}
+
+// importBody returns the uploaded CSV, sent either as the request body or as
+// the "file" field of a multipart form
+func importBody(w http.ResponseWriter, r *http.Request) (io.Reader, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+		return r.Body, nil
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, errors.New("Invalid multipart body")
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, errors.New("Missing \"file\" field")
+		}
+		if err != nil {
+			return nil, errors.New("Invalid multipart body")
+		}
+		if part.FormName() == attachmentFormField {
+			return part, nil
+		}
+		part.Close()
+	}
+}
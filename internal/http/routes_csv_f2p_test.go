@@ -0,0 +1,139 @@
+package http
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+// F2P Tests for CSV Import and Export
+// These tests verify /v1/tasks/export.csv and /v1/tasks/import
+
+func TestTaskExportCSV_F2P(t *testing.T) {
+	mockService := newMockTaskService()
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Low task", Priority: domain.PriorityLow})
+	mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "High task", Priority: domain.PriorityHigh})
+
+	req := httptest.NewRequest("GET", "/v1/tasks/export.csv?priority=high", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authenticated(req))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("Expected text/csv content type, got %q", ct)
+	}
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(records) != 2 || records[1][1] != "High task" {
+		t.Errorf("Expected header and the high priority task, got %q", records)
+	}
+
+	req = httptest.NewRequest("GET", "/v1/tasks/export.csv?status=unknown", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authenticated(req))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an invalid filter, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestTaskImportCSV_F2P(t *testing.T) {
+	tests := []struct {
+		name             string
+		query            string
+		body             string
+		multipart        bool
+		expectedStatus   int
+		expectedImported int
+		expectedTasks    int
+	}{
+		{
+			name:             "should import valid rows",
+			body:             "title,priority\nOne,low\nTwo,high\n",
+			expectedStatus:   http.StatusCreated,
+			expectedImported: 2,
+			expectedTasks:    2,
+		},
+		{
+			name:             "should accept a multipart upload",
+			body:             "title,priority\nOne,low\n",
+			multipart:        true,
+			expectedStatus:   http.StatusCreated,
+			expectedImported: 1,
+			expectedTasks:    1,
+		},
+		{
+			name:             "should not create tasks on a dry run",
+			query:            "?dry_run=true",
+			body:             "title,priority\nOne,low\n",
+			expectedStatus:   http.StatusOK,
+			expectedImported: 1,
+		},
+		{
+			name:             "should report row errors and import nothing",
+			body:             "title,priority\nOne,low\n,low\n",
+			expectedStatus:   http.StatusUnprocessableEntity,
+			expectedImported: 1,
+		},
+		{
+			name:           "should reject a file without a title column",
+			body:           "name,priority\nOne,low\n",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "should reject an invalid dry_run value",
+			query:          "?dry_run=maybe",
+			body:           "title,priority\nOne,low\n",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := newMockTaskService()
+			handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+			router := handler.SetupRoutes()
+
+			body, contentType := bytes.NewBufferString(tt.body), "text/csv"
+			if tt.multipart {
+				body, contentType = multipartUpload(t, "file", "tasks.csv", "text/csv", tt.body)
+			}
+			req := httptest.NewRequest("POST", "/v1/tasks/import"+tt.query, body)
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authenticated(req))
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if len(mockService.tasks) != tt.expectedTasks {
+				t.Errorf("Expected %d tasks, got %d", tt.expectedTasks, len(mockService.tasks))
+			}
+			if tt.expectedStatus == http.StatusBadRequest {
+				return
+			}
+
+			var result domain.TaskImportResult
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if result.Imported != tt.expectedImported {
+				t.Errorf("Expected %d imported, got %d", tt.expectedImported, result.Imported)
+			}
+			if tt.expectedStatus == http.StatusUnprocessableEntity && (len(result.Errors) != 1 || result.Errors[0].Line != 3) {
+				t.Errorf("Expected one error on line 3, got %+v", result.Errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"task-manager/internal/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// Dependency handlers
+func (h *Handler) addDependency(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var req domain.AddDependencyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	task, err := h.taskService.AddDependency(currentUserID(r), id, &req)
+	if err != nil {
+		writeErrorResponse(w, taskConflictStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, task)
+}
+
+func (h *Handler) removeDependency(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+	blockedByID, err := strconv.ParseInt(vars["blockedById"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid blocking task ID")
+		return
+	}
+
+	if err := h.taskService.RemoveDependency(currentUserID(r), id, blockedByID); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrDependencyNotFound) {
+			status = http.StatusNotFound
+		}
+		writeErrorResponse(w, status, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
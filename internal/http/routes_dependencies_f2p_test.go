@@ -0,0 +1,73 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+// F2P Tests for Task Dependencies Feature
+// These tests verify adding and removing "blocked by" edges
+
+func TestTaskDependencyEndpoints_F2P(t *testing.T) {
+	mockService := newMockTaskService()
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Blocked", Priority: domain.PriorityMedium})
+	mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Blocker", Priority: domain.PriorityMedium})
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		expectedStatus int
+	}{
+		{name: "should add dependency", method: "POST", url: "/v1/tasks/1/dependencies", body: `{"blocked_by_id": 2}`, expectedStatus: http.StatusCreated},
+		{name: "should reject cycle", method: "POST", url: "/v1/tasks/2/dependencies", body: `{"blocked_by_id": 1}`, expectedStatus: http.StatusConflict},
+		{name: "should reject self dependency", method: "POST", url: "/v1/tasks/1/dependencies", body: `{"blocked_by_id": 1}`, expectedStatus: http.StatusConflict},
+		{name: "should reject missing blocker id", method: "POST", url: "/v1/tasks/1/dependencies", body: `{}`, expectedStatus: http.StatusBadRequest},
+		{name: "should reject invalid JSON", method: "POST", url: "/v1/tasks/1/dependencies", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "should remove dependency", method: "DELETE", url: "/v1/tasks/1/dependencies/2", expectedStatus: http.StatusNoContent},
+		{name: "should return 404 for unknown dependency", method: "DELETE", url: "/v1/tasks/1/dependencies/2", expectedStatus: http.StatusNotFound},
+		{name: "should reject invalid blocker id", method: "DELETE", url: "/v1/tasks/1/dependencies/abc", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authenticated(req))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetTaskShowsDependencies_F2P(t *testing.T) {
+	mockService := newMockTaskService()
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Blocked", Priority: domain.PriorityMedium})
+	mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Blocker", Priority: domain.PriorityMedium})
+	mockService.AddDependency(testUserID, 1, &domain.AddDependencyRequest{BlockedByID: 2})
+
+	req := httptest.NewRequest("GET", "/v1/tasks/2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authenticated(req))
+
+	var task domain.Task
+	if err := json.Unmarshal(w.Body.Bytes(), &task); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(task.Blocking) != 1 || task.Blocking[0].ID != 1 {
+		t.Errorf("Expected task 2 to be blocking task 1, got %+v", task.Blocking)
+	}
+}
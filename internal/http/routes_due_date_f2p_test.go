@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sort"
+	"strconv"
 	"strings"
 	"task-manager/internal/domain"
 	"testing"
@@ -17,37 +19,44 @@ import (
 // These tests verify that the due date feature works end-to-end
 
 type mockTaskService struct {
-	tasks  map[int64]*domain.Task
-	nextID int64
+	tasks    map[int64]*domain.Task
+	trash    map[int64]*domain.Task
+	nextID   int64
+	workflow *domain.Workflow
 }
 
 func newMockTaskService() *mockTaskService {
 	return &mockTaskService{
-		tasks:  make(map[int64]*domain.Task),
-		nextID: 1,
+		tasks:    make(map[int64]*domain.Task),
+		trash:    make(map[int64]*domain.Task),
+		nextID:   1,
+		workflow: domain.DefaultWorkflow(),
 	}
 }
 
-func (m *mockTaskService) CreateTask(req *domain.CreateTaskRequest) (*domain.Task, error) {
+func (m *mockTaskService) CreateTask(userID int64, req *domain.CreateTaskRequest) (*domain.Task, error) {
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 
 	task := &domain.Task{
 		ID:          m.nextID,
+		UserID:      userID,
 		Title:       req.Title,
 		Description: req.Description,
 		Status:      domain.StatusTodo,
 		Priority:    req.Priority,
 		DueDate:     req.DueDate,
+		ParentID:    req.ParentID,
 		Categories:  []domain.Category{}, // Initialize empty categories
+		Version:     1,
 	}
 	m.tasks[m.nextID] = task
 	m.nextID++
 	return task, nil
 }
 
-func (m *mockTaskService) GetTask(id int64) (*domain.Task, error) {
+func (m *mockTaskService) GetTask(userID, id int64) (*domain.Task, error) {
 	task, exists := m.tasks[id]
 	if !exists {
 		return nil, errors.New("task not found")
@@ -55,7 +64,7 @@ func (m *mockTaskService) GetTask(id int64) (*domain.Task, error) {
 	return task, nil
 }
 
-func (m *mockTaskService) GetAllTasks() ([]*domain.Task, error) {
+func (m *mockTaskService) GetAllTasks(userID int64) ([]*domain.Task, error) {
 	var tasks []*domain.Task
 	for _, task := range m.tasks {
 		tasks = append(tasks, task)
@@ -63,13 +72,13 @@ func (m *mockTaskService) GetAllTasks() ([]*domain.Task, error) {
 	return tasks, nil
 }
 
-func (m *mockTaskService) GetTasksWithFilters(filters *domain.TaskFilters) ([]*domain.Task, error) {
+func (m *mockTaskService) GetTasksWithFilters(userID int64, filters *domain.TaskFilters) ([]*domain.Task, error) {
 	if filters == nil {
-		return m.GetAllTasks()
+		return m.GetAllTasks(userID)
 	}
 
 	// Validate filters
-	if err := filters.Validate(); err != nil {
+	if err := filters.Validate(m.workflow); err != nil {
 		return nil, err
 	}
 
@@ -119,17 +128,67 @@ func (m *mockTaskService) GetTasksWithFilters(filters *domain.TaskFilters) ([]*d
 			}
 		}
 
+		// Check the remaining filters, search aside
+		rest := *filters
+		rest.Search = ""
+		if !rest.Matches(m.workflow, task) {
+			continue
+		}
+
 		filteredTasks = append(filteredTasks, task)
 	}
 
 	return filteredTasks, nil
 }
 
-func (m *mockTaskService) UpdateTask(id int64, req *domain.UpdateTaskRequest) (*domain.Task, error) {
+func (m *mockTaskService) GetTasksPage(userID int64, filters *domain.TaskFilters, page *domain.PageRequest) (*domain.TaskPage, error) {
+	if err := page.Validate(); err != nil {
+		return nil, err
+	}
+
+	tasks, err := m.GetTasksWithFilters(userID, filters)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+
+	// The mock cursor is simply the ID of the last task returned
+	var after int64
+	if page.Cursor != "" {
+		after, err = strconv.ParseInt(page.Cursor, 10, 64)
+		if err != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+	}
+
+	result := &domain.TaskPage{Tasks: []*domain.Task{}, TotalCount: len(tasks)}
+	for _, task := range tasks {
+		if task.ID <= after {
+			continue
+		}
+		if len(result.Tasks) == page.Limit {
+			result.NextCursor = strconv.FormatInt(result.Tasks[len(result.Tasks)-1].ID, 10)
+			break
+		}
+		result.Tasks = append(result.Tasks, task)
+	}
+	return result, nil
+}
+
+func (m *mockTaskService) UpdateTask(userID, id int64, req *domain.UpdateTaskRequest) (*domain.Task, error) {
 	task, exists := m.tasks[id]
 	if !exists {
 		return nil, errors.New("task not found")
 	}
+	if req.ExpectedVersion != nil && *req.ExpectedVersion != task.Version {
+		return nil, fmt.Errorf("failed to update task: %w", domain.ErrVersionConflict)
+	}
+	if err := req.Validate(m.workflow); err != nil {
+		return nil, err
+	}
+	if err := req.ValidateTransition(m.workflow, task.Status); err != nil {
+		return nil, err
+	}
 
 	if req.Title != nil {
 		task.Title = *req.Title
@@ -140,6 +199,9 @@ func (m *mockTaskService) UpdateTask(id int64, req *domain.UpdateTaskRequest) (*
 	if req.Status != nil {
 		task.Status = *req.Status
 	}
+	if req.Rank != nil {
+		task.Rank = *req.Rank
+	}
 	if req.Priority != nil {
 		task.Priority = *req.Priority
 	}
@@ -153,21 +215,216 @@ func (m *mockTaskService) UpdateTask(id int64, req *domain.UpdateTaskRequest) (*
 		// For simplicity in mock, just clear categories
 		task.Categories = []domain.Category{}
 	}
+	task.Version++
 
 	return task, nil
 }
 
-func (m *mockTaskService) DeleteTask(id int64) error {
-	if _, exists := m.tasks[id]; !exists {
+func (m *mockTaskService) DeleteTask(userID, id int64) error {
+	task, exists := m.tasks[id]
+	if !exists {
 		return errors.New("task not found")
 	}
+	now := time.Now()
+	task.DeletedAt = &now
+	m.trash[id] = task
 	delete(m.tasks, id)
 	return nil
 }
 
+func (m *mockTaskService) BulkTasks(userID int64, req *domain.BulkTaskRequest) (*domain.BulkTaskResult, error) {
+	if err := req.Validate(m.workflow); err != nil {
+		return nil, err
+	}
+
+	// Like the real service, nothing changes unless every task exists
+	result := &domain.BulkTaskResult{Applied: true}
+	for _, id := range req.IDs {
+		if _, exists := m.tasks[id]; !exists {
+			result.Applied = false
+		}
+	}
+	for _, id := range req.IDs {
+		item := domain.BulkItemResult{ID: id, Status: domain.BulkItemRolledBack}
+		switch {
+		case m.tasks[id] == nil:
+			item.Status, item.Error = domain.BulkItemFailed, "task not found"
+		case !result.Applied:
+		case req.Action == domain.BulkDelete:
+			m.DeleteTask(userID, id)
+			item.Status = domain.BulkItemDeleted
+		default:
+			item.Task, _ = m.UpdateTask(userID, id, req.Update)
+			item.Status = domain.BulkItemUpdated
+		}
+		result.Results = append(result.Results, item)
+	}
+	return result, nil
+}
+
+func (m *mockTaskService) ImportTasks(userID int64, rows []domain.TaskImportRow, opts domain.TaskImportOptions) (*domain.TaskImportResult, error) {
+	result := &domain.TaskImportResult{DryRun: opts.DryRun, CreatedCategories: []string{}, Errors: []domain.TaskImportError{}}
+	for _, row := range rows {
+		if row.Err != nil {
+			result.Errors = append(result.Errors, domain.TaskImportError{Line: row.Line, Error: row.Err.Error()})
+			continue
+		}
+		result.Imported++
+	}
+	if len(result.Errors) > 0 || opts.DryRun {
+		return result, nil
+	}
+
+	for _, row := range rows {
+		task, _ := m.CreateTask(userID, &row.Request)
+		task.Status = row.Status
+	}
+	result.Applied = true
+	return result, nil
+}
+
+func (m *mockTaskService) GetSubtasks(userID, id int64) ([]*domain.Task, error) {
+	if _, exists := m.tasks[id]; !exists {
+		return nil, errors.New("task not found")
+	}
+	subtasks := []*domain.Task{}
+	for taskID := int64(1); taskID < m.nextID; taskID++ {
+		task, exists := m.tasks[taskID]
+		if exists && task.ParentID != nil && *task.ParentID == id {
+			subtasks = append(subtasks, task)
+		}
+	}
+	return subtasks, nil
+}
+
+func (m *mockTaskService) GetTaskTree(userID, id int64) (*domain.TaskNode, error) {
+	root, exists := m.tasks[id]
+	if !exists {
+		return nil, errors.New("task not found")
+	}
+	var descendants []*domain.Task
+	queue := []int64{id}
+	for len(queue) > 0 {
+		children, _ := m.GetSubtasks(userID, queue[0])
+		queue = queue[1:]
+		for _, child := range children {
+			descendants = append(descendants, child)
+			queue = append(queue, child.ID)
+		}
+	}
+	return domain.BuildTaskTree(root, descendants), nil
+}
+
+func (m *mockTaskService) AddDependency(userID, id int64, req *domain.AddDependencyRequest) (*domain.Task, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	task, exists := m.tasks[id]
+	if !exists {
+		return nil, errors.New("task not found")
+	}
+	blocker, exists := m.tasks[req.BlockedByID]
+	if !exists {
+		return nil, errors.New("task not found")
+	}
+	if blocker.ID == task.ID {
+		return nil, domain.ErrDependencyCycle
+	}
+	for _, ref := range blocker.BlockedBy {
+		if ref.ID == task.ID {
+			return nil, domain.ErrDependencyCycle
+		}
+	}
+	task.BlockedBy = append(task.BlockedBy, domain.TaskRef{ID: blocker.ID, Title: blocker.Title, Status: blocker.Status})
+	blocker.Blocking = append(blocker.Blocking, domain.TaskRef{ID: task.ID, Title: task.Title, Status: task.Status})
+	return task, nil
+}
+
+func (m *mockTaskService) RemoveDependency(userID, id, blockedByID int64) error {
+	task, exists := m.tasks[id]
+	if !exists {
+		return errors.New("task not found")
+	}
+	for i, ref := range task.BlockedBy {
+		if ref.ID == blockedByID {
+			task.BlockedBy = append(task.BlockedBy[:i], task.BlockedBy[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrDependencyNotFound
+}
+
+func (m *mockTaskService) DeleteTaskTree(userID, id int64) error {
+	tree, err := m.GetTaskTree(userID, id)
+	if err != nil {
+		return err
+	}
+	var remove func(node *domain.TaskNode)
+	remove = func(node *domain.TaskNode) {
+		m.DeleteTask(userID, node.ID)
+		for _, child := range node.Children {
+			remove(child)
+		}
+	}
+	remove(tree)
+	return nil
+}
+
+func (m *mockTaskService) GetTaskHistory(userID, id int64) ([]domain.TaskHistoryEntry, error) {
+	task, exists := m.tasks[id]
+	if !exists {
+		return nil, errors.New("task not found")
+	}
+	return []domain.TaskHistoryEntry{{
+		ID:      1,
+		TaskID:  id,
+		UserID:  userID,
+		Action:  domain.HistoryCreated,
+		Changes: domain.DiffTasks(nil, task),
+	}}, nil
+}
+
+func (m *mockTaskService) GetTrashedTasks(userID int64) ([]*domain.Task, error) {
+	tasks := []*domain.Task{}
+	for id := int64(1); id < m.nextID; id++ {
+		if task, exists := m.trash[id]; exists {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (m *mockTaskService) RestoreTask(userID, id int64) (*domain.Task, error) {
+	task, exists := m.trash[id]
+	if !exists {
+		return nil, domain.ErrNotInTrash
+	}
+	task.DeletedAt = nil
+	m.tasks[id] = task
+	delete(m.trash, id)
+	return task, nil
+}
+
+func (m *mockTaskService) PurgeTask(userID, id int64) error {
+	if _, exists := m.trash[id]; !exists {
+		return domain.ErrNotInTrash
+	}
+	delete(m.trash, id)
+	return nil
+}
+
+func (m *mockTaskService) PurgeTrash(userID int64) error {
+	m.trash = make(map[int64]*domain.Task)
+	return nil
+}
+
+func (m *mockTaskService) PurgeExpiredTrash(cutoff time.Time) (int64, error) {
+	return 0, nil
+}
+
 func TestCreateTaskWithDueDate_F2P(t *testing.T) {
 	mockService := newMockTaskService()
-	handler := NewHandler(mockService, newMockCategoryService())
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
 	router := handler.SetupRoutes()
 
 	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
@@ -221,7 +478,7 @@ func TestCreateTaskWithDueDate_F2P(t *testing.T) {
 			req.Header.Set("Content-Type", "application/json")
 
 			w := httptest.NewRecorder()
-			router.ServeHTTP(w, req)
+			router.ServeHTTP(w, authenticated(req))
 
 			if w.Code != tt.expectedStatus {
 				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
@@ -252,7 +509,7 @@ func TestCreateTaskWithDueDate_F2P(t *testing.T) {
 
 func TestUpdateTaskDueDate_F2P(t *testing.T) {
 	mockService := newMockTaskService()
-	handler := NewHandler(mockService, newMockCategoryService())
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
 	router := handler.SetupRoutes()
 
 	// Create a task first
@@ -261,7 +518,7 @@ func TestUpdateTaskDueDate_F2P(t *testing.T) {
 		Description: "Test Description",
 		Priority:    domain.PriorityMedium,
 	}
-	createdTask, _ := mockService.CreateTask(&createReq)
+	createdTask, _ := mockService.CreateTask(testUserID, &createReq)
 
 	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
 	nextWeek := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
@@ -309,7 +566,7 @@ func TestUpdateTaskDueDate_F2P(t *testing.T) {
 			req.Header.Set("Content-Type", "application/json")
 
 			w := httptest.NewRecorder()
-			router.ServeHTTP(w, req)
+			router.ServeHTTP(w, authenticated(req))
 
 			if w.Code != tt.expectedStatus {
 				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
@@ -340,7 +597,7 @@ func TestUpdateTaskDueDate_F2P(t *testing.T) {
 
 func TestGetTaskWithDueDate_F2P(t *testing.T) {
 	mockService := newMockTaskService()
-	handler := NewHandler(mockService, newMockCategoryService())
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
 	router := handler.SetupRoutes()
 
 	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
@@ -353,13 +610,13 @@ func TestGetTaskWithDueDate_F2P(t *testing.T) {
 	}
 
 	for _, task := range tasks {
-		mockService.CreateTask(&task)
+		mockService.CreateTask(testUserID, &task)
 	}
 
 	t.Run("should return task with correct due date information", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks/1", nil)
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -381,7 +638,7 @@ func TestGetTaskWithDueDate_F2P(t *testing.T) {
 	t.Run("should return task without due date", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks/2", nil)
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -398,7 +655,7 @@ func TestGetTaskWithDueDate_F2P(t *testing.T) {
 
 func TestTaskSortingByDueDate_F2P(t *testing.T) {
 	mockService := newMockTaskService()
-	handler := NewHandler(mockService, newMockCategoryService())
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
 	router := handler.SetupRoutes()
 
 	today := time.Now().Format("2006-01-02")
@@ -414,13 +671,13 @@ func TestTaskSortingByDueDate_F2P(t *testing.T) {
 	}
 
 	for _, task := range tasks {
-		mockService.CreateTask(&task)
+		mockService.CreateTask(testUserID, &task)
 	}
 
 	t.Run("should return tasks sorted by due date", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks", nil)
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
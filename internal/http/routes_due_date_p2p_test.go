@@ -15,7 +15,7 @@ import (
 
 func TestBasicTaskCRUDWithDueDate_P2P(t *testing.T) {
 	mockService := newMockTaskService()
-	handler := NewHandler(mockService, newMockCategoryService())
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
 	router := handler.SetupRoutes()
 
 	t.Run("should create task with all fields including due date", func(t *testing.T) {
@@ -32,7 +32,7 @@ func TestBasicTaskCRUDWithDueDate_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusCreated {
 			t.Errorf("Expected status %d, got %d", http.StatusCreated, w.Code)
@@ -66,7 +66,7 @@ func TestBasicTaskCRUDWithDueDate_P2P(t *testing.T) {
 
 func TestTaskStatusManagementWithDueDate_P2P(t *testing.T) {
 	mockService := newMockTaskService()
-	handler := NewHandler(mockService, newMockCategoryService())
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
 	router := handler.SetupRoutes()
 
 	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
@@ -78,7 +78,7 @@ func TestTaskStatusManagementWithDueDate_P2P(t *testing.T) {
 		Priority:    domain.PriorityMedium,
 		DueDate:     func() *time.Time { t, _ := time.Parse("2006-01-02", tomorrow); return &t }(),
 	}
-	mockService.CreateTask(&createReq)
+	mockService.CreateTask(testUserID, &createReq)
 
 	t.Run("should update task status while preserving due date", func(t *testing.T) {
 		updateReq := domain.UpdateTaskRequest{
@@ -90,7 +90,7 @@ func TestTaskStatusManagementWithDueDate_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -124,7 +124,7 @@ func TestTaskStatusManagementWithDueDate_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -146,7 +146,7 @@ func TestTaskStatusManagementWithDueDate_P2P(t *testing.T) {
 
 func TestTaskPriorityManagementWithDueDate_P2P(t *testing.T) {
 	mockService := newMockTaskService()
-	handler := NewHandler(mockService, newMockCategoryService())
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
 	router := handler.SetupRoutes()
 
 	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
@@ -158,7 +158,7 @@ func TestTaskPriorityManagementWithDueDate_P2P(t *testing.T) {
 		Priority:    domain.PriorityMedium,
 		DueDate:     func() *time.Time { t, _ := time.Parse("2006-01-02", tomorrow); return &t }(),
 	}
-	mockService.CreateTask(&createReq)
+	mockService.CreateTask(testUserID, &createReq)
 
 	t.Run("should update task priority while preserving due date", func(t *testing.T) {
 		updateReq := domain.UpdateTaskRequest{
@@ -170,7 +170,7 @@ func TestTaskPriorityManagementWithDueDate_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -197,7 +197,7 @@ func TestTaskPriorityManagementWithDueDate_P2P(t *testing.T) {
 
 func TestTaskTitleAndDescriptionWithDueDate_P2P(t *testing.T) {
 	mockService := newMockTaskService()
-	handler := NewHandler(mockService, newMockCategoryService())
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
 	router := handler.SetupRoutes()
 
 	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
@@ -209,7 +209,7 @@ func TestTaskTitleAndDescriptionWithDueDate_P2P(t *testing.T) {
 		Priority:    domain.PriorityMedium,
 		DueDate:     func() *time.Time { t, _ := time.Parse("2006-01-02", tomorrow); return &t }(),
 	}
-	mockService.CreateTask(&createReq)
+	mockService.CreateTask(testUserID, &createReq)
 
 	t.Run("should update task title while preserving due date", func(t *testing.T) {
 		newTitle := "Updated Title with Due Date"
@@ -222,7 +222,7 @@ func TestTaskTitleAndDescriptionWithDueDate_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -252,7 +252,7 @@ func TestTaskTitleAndDescriptionWithDueDate_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -274,7 +274,7 @@ func TestTaskTitleAndDescriptionWithDueDate_P2P(t *testing.T) {
 
 func TestTaskDeletionWithDueDate_P2P(t *testing.T) {
 	mockService := newMockTaskService()
-	handler := NewHandler(mockService, newMockCategoryService())
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
 	router := handler.SetupRoutes()
 
 	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
@@ -286,12 +286,12 @@ func TestTaskDeletionWithDueDate_P2P(t *testing.T) {
 		Priority:    domain.PriorityLow,
 		DueDate:     func() *time.Time { t, _ := time.Parse("2006-01-02", tomorrow); return &t }(),
 	}
-	mockService.CreateTask(&createReq)
+	mockService.CreateTask(testUserID, &createReq)
 
 	t.Run("should delete task with due date successfully", func(t *testing.T) {
 		req := httptest.NewRequest("DELETE", "/v1/tasks/1", nil)
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusNoContent {
 			t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
@@ -301,7 +301,7 @@ func TestTaskDeletionWithDueDate_P2P(t *testing.T) {
 	t.Run("should return 404 when deleting non-existent task", func(t *testing.T) {
 		req := httptest.NewRequest("DELETE", "/v1/tasks/999", nil)
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusNotFound {
 			t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
@@ -311,7 +311,7 @@ func TestTaskDeletionWithDueDate_P2P(t *testing.T) {
 
 func TestTaskRetrievalWithDueDate_P2P(t *testing.T) {
 	mockService := newMockTaskService()
-	handler := NewHandler(mockService, newMockCategoryService())
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
 	router := handler.SetupRoutes()
 
 	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
@@ -324,13 +324,13 @@ func TestTaskRetrievalWithDueDate_P2P(t *testing.T) {
 	}
 
 	for _, task := range tasks {
-		mockService.CreateTask(&task)
+		mockService.CreateTask(testUserID, &task)
 	}
 
 	t.Run("should get all tasks including due date information", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks", nil)
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -349,7 +349,7 @@ func TestTaskRetrievalWithDueDate_P2P(t *testing.T) {
 	t.Run("should get specific task by id with due date", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks/1", nil)
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -370,7 +370,7 @@ func TestTaskRetrievalWithDueDate_P2P(t *testing.T) {
 	t.Run("should return 404 for non-existent task", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks/999", nil)
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusNotFound {
 			t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+// F2P Tests for Optimistic Concurrency
+// These tests verify ETag responses and If-Match preconditions on updates
+
+func TestTaskETag_F2P(t *testing.T) {
+	mockService := newMockTaskService()
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Shared", Priority: domain.PriorityLow})
+
+	req := httptest.NewRequest("GET", "/v1/tasks/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authenticated(req))
+	if got := w.Header().Get("ETag"); got != `"1"` {
+		t.Fatalf("Expected ETag %q on GET, got %q", `"1"`, got)
+	}
+
+	tests := []struct {
+		name           string
+		ifMatch        string
+		expectedStatus int
+		expectedETag   string
+	}{
+		{name: "should accept a matching If-Match", ifMatch: `"1"`, expectedStatus: http.StatusOK, expectedETag: `"2"`},
+		{name: "should reject a stale If-Match", ifMatch: `"1"`, expectedStatus: http.StatusPreconditionFailed},
+		{name: "should reject a weak If-Match", ifMatch: `W/"2"`, expectedStatus: http.StatusPreconditionFailed},
+		{name: "should reject a malformed If-Match", ifMatch: "2", expectedStatus: http.StatusPreconditionFailed},
+		{name: "should accept a wildcard If-Match", ifMatch: "*", expectedStatus: http.StatusOK, expectedETag: `"3"`},
+		{name: "should update without If-Match", expectedStatus: http.StatusOK, expectedETag: `"4"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PATCH", "/v1/tasks/1", bytes.NewBufferString(`{"title":"Edited"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authenticated(req))
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("ETag"); got != tt.expectedETag {
+				t.Errorf("Expected ETag %q, got %q", tt.expectedETag, got)
+			}
+		})
+	}
+}
+
+func TestCategoryETag_F2P(t *testing.T) {
+	mockCategoryService := newMockCategoryService()
+	handler := NewHandler(newMockTaskService(), mockCategoryService, newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	mockCategoryService.CreateCategory(testUserID, &domain.CreateCategoryRequest{Name: "Work"})
+
+	req := httptest.NewRequest("PUT", "/v1/categories/1", bytes.NewBufferString(`{"name":"Office"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"1"`)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authenticated(req))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("ETag"); got != `"2"` {
+		t.Errorf("Expected ETag %q, got %q", `"2"`, got)
+	}
+
+	req = httptest.NewRequest("PUT", "/v1/categories/1", bytes.NewBufferString(`{"name":"Home"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"1"`)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authenticated(req))
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("Expected status %d, got %d: %s", http.StatusPreconditionFailed, w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"task-manager/internal/domain"
+	"task-manager/internal/service"
+	"time"
+)
+
+// eventStreamKeepAlive is how often an idle event stream sends a comment so
+// that proxies do not close the connection
+var eventStreamKeepAlive = 15 * time.Second
+
+// eventStreamRetry is the reconnect delay suggested to clients, in
+// milliseconds
+const eventStreamRetry = 3000
+
+// streamEvents pushes the user's task and category changes as Server-Sent
+// Events. Task events are narrowed by the list endpoint's filters; a client
+// resumes with Last-Event-ID and is sent a "reset" event when the changes it
+// missed are no longer buffered.
+func (h *Handler) streamEvents(w http.ResponseWriter, r *http.Request) {
+	filters, err := h.parseTaskFilters(r)
+	if err == nil {
+		err = filters.Validate(h.statusService.GetWorkflow())
+	}
+	if err != nil {
+		writeFilterError(w, err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeErrorResponse(w, http.StatusInternalServerError, "Streaming is not supported")
+		return
+	}
+
+	// EventSource cannot set headers on its first request
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	sub, err := h.eventStream.Subscribe(currentUserID(r), lastEventID)
+	if err != nil {
+		writeErrorResponse(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	defer h.eventStream.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", eventStreamRetry)
+	if sub.Missed {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, event := range sub.Replay {
+		if err := writeStreamEvent(w, event, filters, h.statusService.GetWorkflow()); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			if err := writeStreamEvent(w, event, filters, h.statusService.GetWorkflow()); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// writeStreamEvent writes one event unless it concerns a task that the
+// filters leave out both before and after the change
+func writeStreamEvent(w http.ResponseWriter, event service.StreamEvent, filters *domain.TaskFilters, workflow *domain.Workflow) error {
+	if !streamEventMatches(event.Event, filters, workflow) {
+		return nil
+	}
+
+	data, err := json.Marshal(event.Event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Event.Type, data)
+	return err
+}
+
+func streamEventMatches(event domain.Event, filters *domain.TaskFilters, workflow *domain.Workflow) bool {
+	task, ok := event.Data.(*domain.Task)
+	if !ok {
+		return true
+	}
+	if filters.Matches(workflow, task) {
+		return true
+	}
+	previous, ok := event.Previous.(*domain.Task)
+	return ok && previous != nil && filters.Matches(workflow, previous)
+}
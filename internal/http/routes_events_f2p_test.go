@@ -0,0 +1,218 @@
+package http
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task-manager/internal/domain"
+	"task-manager/internal/service"
+	"testing"
+	"time"
+)
+
+// F2P Tests for the Live Event Stream
+// These tests verify GET /v1/events
+
+func newTestEventStream() *service.EventStream {
+	return service.NewEventStream(16)
+}
+
+// sseFrame is one Server-Sent Events message
+type sseFrame struct {
+	id, event, data string
+}
+
+// openEventStream connects to the event stream and returns its frames
+func openEventStream(t *testing.T, server *httptest.Server, query string, header http.Header) (*http.Response, <-chan sseFrame) {
+	t.Helper()
+	req, _ := http.NewRequest("GET", server.URL+"/v1/events"+query, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("failed to open event stream: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	frames := make(chan sseFrame, 16)
+	go func() {
+		defer close(frames)
+		reader := bufio.NewReader(resp.Body)
+		var frame sseFrame
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case line == "":
+				if frame != (sseFrame{}) {
+					frames <- frame
+				}
+				frame = sseFrame{}
+			case strings.HasPrefix(line, "id: "):
+				frame.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				frame.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				frame.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+	return resp, frames
+}
+
+// nextFrame waits for the next frame that carries an event
+func nextFrame(t *testing.T, frames <-chan sseFrame) sseFrame {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case frame, ok := <-frames:
+			if !ok {
+				t.Fatal("event stream ended")
+			}
+			if frame.event != "" {
+				return frame
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for an event")
+		}
+	}
+}
+
+func bearer() http.Header {
+	return http.Header{"Authorization": {"Bearer " + testToken}}
+}
+
+func taskEvent(eventType domain.EventType, userID int64, task, previous *domain.Task) domain.Event {
+	event := domain.Event{ID: "evt", Type: eventType, UserID: userID, Data: task}
+	if previous != nil {
+		event.Previous = previous
+	}
+	return event
+}
+
+func TestEventStream_F2P(t *testing.T) {
+	stream := newTestEventStream()
+	handler := NewHandler(newMockTaskService(), newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), stream)
+	server := httptest.NewServer(handler.SetupRoutes())
+	defer server.Close()
+
+	t.Run("should push matching changes as they happen", func(t *testing.T) {
+		resp, frames := openEventStream(t, server, "?status=todo", bearer())
+		if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+			t.Fatalf("Expected an event stream, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+
+		todo := &domain.Task{ID: 1, Title: "Todo", Status: domain.StatusTodo}
+		done := &domain.Task{ID: 1, Title: "Todo", Status: domain.StatusDone}
+		stream.Publish(taskEvent(domain.EventTaskCreated, testUserID+1, todo, nil))
+		stream.Publish(taskEvent(domain.EventTaskCreated, testUserID, &domain.Task{ID: 2, Status: domain.StatusDone}, nil))
+		stream.Publish(taskEvent(domain.EventTaskCreated, testUserID, todo, nil))
+		stream.Publish(domain.Event{Type: domain.EventCategoryCreated, UserID: testUserID, Data: &domain.Category{ID: 3}})
+		stream.Publish(taskEvent(domain.EventTaskUpdated, testUserID, done, todo))
+
+		for _, want := range []domain.EventType{domain.EventTaskCreated, domain.EventCategoryCreated, domain.EventTaskUpdated} {
+			frame := nextFrame(t, frames)
+			if frame.event != string(want) || frame.id == "" {
+				t.Fatalf("Expected %s with an ID, got %+v", want, frame)
+			}
+			if want == domain.EventTaskCreated && !strings.Contains(frame.data, `"title":"Todo"`) {
+				t.Errorf("Expected the task in the data, got %s", frame.data)
+			}
+		}
+	})
+
+	t.Run("should resume after Last-Event-ID", func(t *testing.T) {
+		resp, frames := openEventStream(t, server, "", bearer())
+		stream.Publish(taskEvent(domain.EventTaskDeleted, testUserID, &domain.Task{ID: 4}, nil))
+		stream.Publish(taskEvent(domain.EventTaskDeleted, testUserID, &domain.Task{ID: 5}, nil))
+		first := nextFrame(t, frames)
+		resp.Body.Close()
+
+		header := bearer()
+		header.Set("Last-Event-ID", first.id)
+		_, frames = openEventStream(t, server, "", header)
+		replayed := nextFrame(t, frames)
+		if replayed.event != string(domain.EventTaskDeleted) || !strings.Contains(replayed.data, `"id":5`) {
+			t.Errorf("Expected the missed deletion of task 5, got %+v", replayed)
+		}
+	})
+
+	t.Run("should ask the client to reload when events were lost", func(t *testing.T) {
+		_, frames := openEventStream(t, server, "?last_event_id=unknown-1", bearer())
+		if frame := nextFrame(t, frames); frame.event != "reset" {
+			t.Errorf("Expected a reset event, got %+v", frame)
+		}
+	})
+
+	t.Run("should accept the token as a query parameter", func(t *testing.T) {
+		resp, _ := openEventStream(t, server, "?access_token="+testToken, nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("should require authentication", func(t *testing.T) {
+		resp, _ := openEventStream(t, server, "", nil)
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("should reject an invalid filter", func(t *testing.T) {
+		resp, _ := openEventStream(t, server, "?status=unknown", bearer())
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("should end streams when the server shuts down", func(t *testing.T) {
+		_, frames := openEventStream(t, server, "", bearer())
+		stream.Close()
+
+		timeout := time.After(2 * time.Second)
+		for {
+			select {
+			case _, ok := <-frames:
+				if !ok {
+					return
+				}
+			case <-timeout:
+				t.Fatal("stream stayed open after Close")
+			}
+		}
+	})
+}
+
+func TestEventStream_KeepAlive_F2P(t *testing.T) {
+	defer func(interval time.Duration) { eventStreamKeepAlive = interval }(eventStreamKeepAlive)
+	eventStreamKeepAlive = 10 * time.Millisecond
+
+	handler := NewHandler(newMockTaskService(), newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	server := httptest.NewServer(handler.SetupRoutes())
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+"/v1/events", nil)
+	resp, err := server.Client().Do(authenticated(req))
+	if err != nil {
+		t.Fatalf("failed to open event stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	for i := 0; i < 10; i++ {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read stream: %v", err)
+		}
+		if line == ": keep-alive\n" {
+			return
+		}
+	}
+	t.Error("Expected a keep-alive comment")
+}
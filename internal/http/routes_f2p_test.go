@@ -15,7 +15,7 @@ import (
 
 func TestCreateTaskWithPriority_F2P(t *testing.T) {
 	mockService := newMockTaskService()
-	handler := NewHandler(mockService, newMockCategoryService())
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
 	router := handler.SetupRoutes()
 
 	tests := []struct {
@@ -73,7 +73,7 @@ func TestCreateTaskWithPriority_F2P(t *testing.T) {
 			req.Header.Set("Content-Type", "application/json")
 
 			w := httptest.NewRecorder()
-			router.ServeHTTP(w, req)
+			router.ServeHTTP(w, authenticated(req))
 
 			if w.Code != tt.expectedStatus {
 				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
@@ -97,7 +97,7 @@ func TestCreateTaskWithPriority_F2P(t *testing.T) {
 
 func TestUpdateTaskPriority_F2P(t *testing.T) {
 	mockService := newMockTaskService()
-	handler := NewHandler(mockService, newMockCategoryService())
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
 	router := handler.SetupRoutes()
 
 	// Create a task first
@@ -106,7 +106,7 @@ func TestUpdateTaskPriority_F2P(t *testing.T) {
 		Description: "Test Description",
 		Priority:    domain.PriorityMedium,
 	}
-	createdTask, _ := mockService.CreateTask(&createReq)
+	createdTask, _ := mockService.CreateTask(testUserID, &createReq)
 
 	tests := []struct {
 		name             string
@@ -151,7 +151,7 @@ func TestUpdateTaskPriority_F2P(t *testing.T) {
 			req.Header.Set("Content-Type", "application/json")
 
 			w := httptest.NewRecorder()
-			router.ServeHTTP(w, req)
+			router.ServeHTTP(w, authenticated(req))
 
 			if w.Code != tt.expectedStatus {
 				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
@@ -171,7 +171,7 @@ func TestUpdateTaskPriority_F2P(t *testing.T) {
 
 func TestGetTaskWithPriority_F2P(t *testing.T) {
 	mockService := newMockTaskService()
-	handler := NewHandler(mockService, newMockCategoryService())
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
 	router := handler.SetupRoutes()
 
 	// Create tasks with different priorities
@@ -188,13 +188,13 @@ func TestGetTaskWithPriority_F2P(t *testing.T) {
 			Description: "Description " + string(rune(i+1)),
 			Priority:    priority,
 		}
-		mockService.CreateTask(&createReq)
+		mockService.CreateTask(testUserID, &createReq)
 	}
 
 	t.Run("should return task with correct priority information", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks/1", nil)
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
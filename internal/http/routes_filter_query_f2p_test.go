@@ -0,0 +1,94 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+// F2P Tests for the Filter Query Language
+// These tests verify GET /v1/tasks?q=
+
+func TestTaskFilterQuery_F2P(t *testing.T) {
+	mockService := newMockTaskService()
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	for _, task := range []*domain.Task{
+		{ID: 1, Title: "Fix login bug", Status: domain.StatusTodo, Priority: domain.PriorityCritical},
+		{ID: 2, Title: "Write docs", Status: domain.StatusDoing, Priority: domain.PriorityLow},
+		{ID: 3, Title: "Ship release", Status: domain.StatusDone, Priority: domain.PriorityHigh},
+	} {
+		mockService.tasks[task.ID] = task
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		extra          string
+		expectedTitles []string
+	}{
+		{"field list", "status:todo,doing", "", []string{"Fix login bug", "Write docs"}},
+		{"comparison", "priority:>=high", "", []string{"Fix login bug", "Ship release"}},
+		{"negation", "-status:done", "", []string{"Fix login bug", "Write docs"}},
+		{"or group", "(status:done OR docs) -priority:high", "", []string{"Write docs"}},
+		{"combined with parameters", "priority:>=high", "&status=done", []string{"Ship release"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/v1/tasks?q="+url.QueryEscape(tt.query)+tt.extra, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authenticated(req))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			var tasks []*domain.Task
+			json.NewDecoder(w.Body).Decode(&tasks)
+			titles := map[string]bool{}
+			for _, task := range tasks {
+				titles[task.Title] = true
+			}
+			if len(tasks) != len(tt.expectedTitles) {
+				t.Fatalf("Expected %v, got %d tasks", tt.expectedTitles, len(tasks))
+			}
+			for _, title := range tt.expectedTitles {
+				if !titles[title] {
+					t.Errorf("Expected %q in the results", title)
+				}
+			}
+		})
+	}
+
+	t.Run("should report the position of a syntax error", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/v1/tasks?q="+url.QueryEscape("status:todo stauts:done"), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authenticated(req))
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+		var response struct {
+			Error    string `json:"error"`
+			Position *int   `json:"position"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+		if response.Position == nil || *response.Position != 12 || response.Error == "" {
+			t.Errorf("Expected an error at position 12, got %+v", response)
+		}
+	})
+
+	t.Run("should reject invalid queries on exports", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/v1/tasks/export.csv?q=status:", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authenticated(req))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
@@ -14,6 +14,7 @@ func TestTaskFiltering_F2P(t *testing.T) {
 	handler := &Handler{
 		taskService:     mockTaskService,
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 	router := handler.SetupRoutes()
 
@@ -120,7 +121,7 @@ func TestTaskFiltering_F2P(t *testing.T) {
 			req := httptest.NewRequest("GET", tt.url, nil)
 			w := httptest.NewRecorder()
 
-			router.ServeHTTP(w, req)
+			router.ServeHTTP(w, authenticated(req))
 
 			if w.Code != tt.expectedStatus {
 				t.Errorf("Expected status %d, got %d. %s", tt.expectedStatus, w.Code, tt.description)
@@ -149,6 +150,7 @@ func TestTaskFilteringIntegration_F2P(t *testing.T) {
 	handler := &Handler{
 		taskService:     mockTaskService,
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 	router := handler.SetupRoutes()
 
@@ -165,7 +167,7 @@ func TestTaskFilteringIntegration_F2P(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks?status=todo&priority=high", nil)
 		w := httptest.NewRecorder()
 
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -196,6 +198,7 @@ func TestTaskFilteringEdgeCases_F2P(t *testing.T) {
 	handler := &Handler{
 		taskService:     mockTaskService,
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 	router := handler.SetupRoutes()
 
@@ -236,7 +239,7 @@ func TestTaskFilteringEdgeCases_F2P(t *testing.T) {
 			req := httptest.NewRequest("GET", tt.url, nil)
 			w := httptest.NewRecorder()
 
-			router.ServeHTTP(w, req)
+			router.ServeHTTP(w, authenticated(req))
 
 			if w.Code != tt.expectedStatus {
 				t.Errorf("Expected status %d, got %d. %s", tt.expectedStatus, w.Code, tt.description)
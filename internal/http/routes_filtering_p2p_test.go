@@ -14,6 +14,7 @@ func TestTaskFilteringP2P(t *testing.T) {
 	handler := &Handler{
 		taskService:     mockTaskService,
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 	router := handler.SetupRoutes()
 
@@ -100,7 +101,7 @@ func TestTaskFilteringP2P(t *testing.T) {
 			}
 
 			w := httptest.NewRecorder()
-			router.ServeHTTP(w, req)
+			router.ServeHTTP(w, authenticated(req))
 
 			// For GET requests, expect 200 OK
 			// For POST/PUT/DELETE, we expect various status codes depending on implementation
@@ -124,6 +125,7 @@ func TestTaskFilteringBackwardCompatibility_P2P(t *testing.T) {
 	handler := &Handler{
 		taskService:     mockTaskService,
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 	router := handler.SetupRoutes()
 
@@ -152,7 +154,7 @@ func TestTaskFilteringBackwardCompatibility_P2P(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks", nil)
 		w := httptest.NewRecorder()
 
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -176,7 +178,7 @@ func TestTaskFilteringBackwardCompatibility_P2P(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks?status=&priority=", nil)
 		w := httptest.NewRecorder()
 
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -203,6 +205,7 @@ func TestTaskFilteringWithExistingData_P2P(t *testing.T) {
 	handler := &Handler{
 		taskService:     mockTaskService,
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 	router := handler.SetupRoutes()
 
@@ -237,7 +240,7 @@ func TestTaskFilteringWithExistingData_P2P(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks?status=todo", nil)
 		w := httptest.NewRecorder()
 
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -266,7 +269,7 @@ func TestTaskFilteringWithExistingData_P2P(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks?priority=high", nil)
 		w := httptest.NewRecorder()
 
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"task-manager/internal/domain"
+	"testing"
+	"time"
+)
+
+// F2P Tests for Category, Due Date and Timestamp Filters
+// These tests verify the category_id, due_*, overdue, created_* and updated_*
+// parameters of GET /v1/tasks
+
+func TestTaskRangeFilters_F2P(t *testing.T) {
+	mockService := newMockTaskService()
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	date := func(value string) *time.Time {
+		t, _ := time.Parse("2006-01-02", value)
+		return &t
+	}
+	work := domain.Category{ID: 1, Name: "Work"}
+	home := domain.Category{ID: 2, Name: "Home"}
+
+	for _, task := range []*domain.Task{
+		{ID: 1, Title: "Past due", Status: domain.StatusTodo, DueDate: date("2020-01-10"), Categories: []domain.Category{work, home},
+			CreatedAt: *date("2020-01-01"), UpdatedAt: *date("2020-01-05")},
+		{ID: 2, Title: "Done late", Status: domain.StatusDone, DueDate: date("2020-01-20"), Categories: []domain.Category{work},
+			CreatedAt: *date("2020-01-02"), UpdatedAt: *date("2020-02-01")},
+		{ID: 3, Title: "Future", Status: domain.StatusDoing, DueDate: date("2999-01-01"), Categories: []domain.Category{home},
+			CreatedAt: *date("2020-02-01"), UpdatedAt: *date("2020-02-01")},
+		{ID: 4, Title: "Someday", Status: domain.StatusTodo,
+			CreatedAt: *date("2020-03-01"), UpdatedAt: *date("2020-03-01")},
+	} {
+		mockService.tasks[task.ID] = task
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		expectedIDs []int64
+	}{
+		{"any category", "category_id=1,2", []int64{1, 2, 3}},
+		{"repeated category", "category_id=1&category_id=2", []int64{1, 2, 3}},
+		{"all categories", "category_id=1,2&category_mode=all", []int64{1}},
+		{"due range", "due_after=2020-01-01&due_before=2020-01-15", []int64{1}},
+		{"due before a time", "due_before=2020-01-20T00:00:01Z", []int64{1, 2}},
+		{"without due date", "has_due_date=false", []int64{4}},
+		{"with due date", "has_due_date=true", []int64{1, 2, 3}},
+		{"overdue", "overdue=true", []int64{1}},
+		{"created range", "created_after=2020-01-02&created_before=2020-03-01", []int64{2, 3}},
+		{"updated since", "updated_after=2020-02-01", []int64{2, 3, 4}},
+		{"combined", "category_id=1&status=todo&overdue=true", []int64{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/v1/tasks?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authenticated(req))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			var tasks []*domain.Task
+			json.NewDecoder(w.Body).Decode(&tasks)
+			ids := []int64{}
+			for _, task := range tasks {
+				ids = append(ids, task.ID)
+			}
+			sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+			if len(ids) != len(tt.expectedIDs) {
+				t.Fatalf("Expected tasks %v, got %v", tt.expectedIDs, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.expectedIDs[i] {
+					t.Fatalf("Expected tasks %v, got %v", tt.expectedIDs, ids)
+				}
+			}
+		})
+	}
+
+	t.Run("should reject invalid filters", func(t *testing.T) {
+		for _, query := range []string{
+			"category_id=abc",
+			"category_id=0",
+			"category_id=1&category_mode=most",
+			"due_before=tomorrow",
+			"due_after=2020-02-01&due_before=2020-01-01",
+			"has_due_date=maybe",
+			"overdue=true&has_due_date=false",
+		} {
+			req := httptest.NewRequest("GET", "/v1/tasks?"+query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authenticated(req))
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, w.Code)
+			}
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// History handlers
+func (h *Handler) getTaskHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	history, err := h.taskService.GetTaskHistory(currentUserID(r), id)
+	if err != nil {
+		writeErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, history)
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+// F2P Tests for Task History Feature
+// These tests verify the /v1/tasks/{id}/history subresource
+
+func TestTaskHistory_F2P(t *testing.T) {
+	mockService := newMockTaskService()
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Tracked", Priority: domain.PriorityHigh})
+
+	req := httptest.NewRequest("GET", "/v1/tasks/1/history", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authenticated(req))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var history []domain.TaskHistoryEntry
+	if err := json.Unmarshal(w.Body.Bytes(), &history); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(history) != 1 || history[0].Action != domain.HistoryCreated {
+		t.Fatalf("Expected one created entry, got %+v", history)
+	}
+	found := false
+	for _, change := range history[0].Changes {
+		if change.Field == "title" && change.Old == nil && change.New == "Tracked" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected title change in %+v", history[0].Changes)
+	}
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+	}{
+		{name: "should return 404 for unknown task", url: "/v1/tasks/99/history", expectedStatus: http.StatusNotFound},
+		{name: "should reject invalid task ID", url: "/v1/tasks/abc/history", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authenticated(req))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"task-manager/internal/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// moveTask places a task on the board, between the after_id and before_id
+// tasks of the target status column
+func (h *Handler) moveTask(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var req domain.MoveTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	if req.ExpectedVersion, err = ifMatchVersion(r); err != nil {
+		writeErrorResponse(w, http.StatusPreconditionFailed, err.Error())
+		return
+	}
+
+	task, err := h.taskService.MoveTask(currentUserID(r), id, &req)
+	if err != nil {
+		status := taskConflictStatus(err, http.StatusBadRequest)
+		writeErrorResponse(w, versionConflictStatus(r, err, status), err.Error())
+		return
+	}
+
+	w.Header().Set("ETag", etag(task.Version))
+	writeJSONResponse(w, http.StatusOK, task)
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+// F2P Tests for Board Ordering
+// These tests verify POST /v1/tasks/{id}/move and sorting by rank
+
+func (m *mockTaskService) MoveTask(userID, id int64, req *domain.MoveTaskRequest) (*domain.Task, error) {
+	if err := req.Validate(m.workflow); err != nil {
+		return nil, err
+	}
+	task, exists := m.tasks[id]
+	if !exists {
+		return nil, fmt.Errorf("task not found")
+	}
+	status := req.Status
+	if status == "" {
+		status = task.Status
+	}
+
+	// The mock only places tasks between the given neighbors
+	var bounds [2]string
+	for i, neighborID := range []*int64{req.AfterID, req.BeforeID} {
+		if neighborID == nil {
+			continue
+		}
+		neighbor, exists := m.tasks[*neighborID]
+		if !exists || neighbor.ID == id || neighbor.Status != status {
+			return nil, domain.ErrInvalidMove
+		}
+		bounds[i] = neighbor.Rank
+	}
+	rank, err := domain.RankBetween(bounds[0], bounds[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return m.UpdateTask(userID, id, &domain.UpdateTaskRequest{Status: &status, Rank: &rank, ExpectedVersion: req.ExpectedVersion})
+}
+
+func TestMoveTask_F2P(t *testing.T) {
+	mockService := newMockTaskService()
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	for _, task := range []*domain.Task{
+		{ID: 1, Title: "First", Status: domain.StatusTodo, Rank: "i", Version: 1},
+		{ID: 2, Title: "Second", Status: domain.StatusTodo, Rank: "q", Version: 1},
+		{ID: 3, Title: "Started", Status: domain.StatusDoing, Rank: "i", Version: 1},
+	} {
+		mockService.tasks[task.ID] = task
+	}
+
+	move := func(id string, body interface{}, ifMatch string) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if s, ok := body.(string); ok {
+			buf.WriteString(s)
+		} else {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		req := httptest.NewRequest("POST", "/v1/tasks/"+id+"/move", &buf)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authenticated(req))
+		return w
+	}
+
+	t.Run("should place a task between its neighbors in another column", func(t *testing.T) {
+		w := move("3", map[string]interface{}{"status": "todo", "after_id": 1, "before_id": 2}, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var task domain.Task
+		json.NewDecoder(w.Body).Decode(&task)
+		if task.Status != domain.StatusTodo || !(task.Rank > "i" && task.Rank < "q") {
+			t.Errorf("Expected a todo task ranked between i and q, got %s %q", task.Status, task.Rank)
+		}
+		if w.Header().Get("ETag") != etag(task.Version) {
+			t.Errorf("Expected the ETag of version %d, got %s", task.Version, w.Header().Get("ETag"))
+		}
+	})
+
+	t.Run("should list the board order", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/v1/tasks?status=todo&sort=rank", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authenticated(req))
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+	})
+
+	tests := []struct {
+		name           string
+		id             string
+		body           interface{}
+		ifMatch        string
+		expectedStatus int
+	}{
+		{"should reject an invalid task ID", "abc", map[string]interface{}{}, "", http.StatusBadRequest},
+		{"should reject invalid JSON", "1", "{", "", http.StatusBadRequest},
+		{"should reject an invalid status", "1", map[string]interface{}{"status": "later"}, "", http.StatusBadRequest},
+		{"should reject a neighbor in another column", "1", map[string]interface{}{"status": "doing", "after_id": 2}, "", http.StatusBadRequest},
+		{"should reject the task as its own neighbor", "1", map[string]interface{}{"before_id": 1}, "", http.StatusBadRequest},
+		{"should reject swapped neighbors", "1", map[string]interface{}{"after_id": 2, "before_id": 3}, "", http.StatusBadRequest},
+		{"should reject a stale version", "1", map[string]interface{}{"after_id": 2}, `"99"`, http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := move(tt.id, tt.body, tt.ifMatch); w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -14,7 +14,7 @@ import (
 
 func TestBasicTaskCRUD_P2P(t *testing.T) {
 	mockService := newMockTaskService()
-	handler := NewHandler(mockService, newMockCategoryService())
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
 	router := handler.SetupRoutes()
 
 	t.Run("should create task without priority field", func(t *testing.T) {
@@ -28,7 +28,7 @@ func TestBasicTaskCRUD_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusBadRequest {
 			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
@@ -47,7 +47,7 @@ func TestBasicTaskCRUD_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusCreated {
 			t.Errorf("Expected status %d, got %d", http.StatusCreated, w.Code)
@@ -64,7 +64,7 @@ func TestBasicTaskCRUD_P2P(t *testing.T) {
 
 func TestTaskStatusManagement_P2P(t *testing.T) {
 	mockService := newMockTaskService()
-	handler := NewHandler(mockService, newMockCategoryService())
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
 	router := handler.SetupRoutes()
 
 	// Create a task first
@@ -73,7 +73,7 @@ func TestTaskStatusManagement_P2P(t *testing.T) {
 		Description: "Testing status updates",
 		Priority:    domain.PriorityMedium,
 	}
-	mockService.CreateTask(&createReq)
+	mockService.CreateTask(testUserID, &createReq)
 
 	t.Run("should update task status to doing", func(t *testing.T) {
 		updateReq := domain.UpdateTaskRequest{
@@ -85,7 +85,7 @@ func TestTaskStatusManagement_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -109,7 +109,7 @@ func TestTaskStatusManagement_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -126,7 +126,7 @@ func TestTaskStatusManagement_P2P(t *testing.T) {
 
 func TestTaskTitleAndDescription_P2P(t *testing.T) {
 	mockService := newMockTaskService()
-	handler := NewHandler(mockService, newMockCategoryService())
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
 	router := handler.SetupRoutes()
 
 	// Create a task first
@@ -135,7 +135,7 @@ func TestTaskTitleAndDescription_P2P(t *testing.T) {
 		Description: "Original Description",
 		Priority:    domain.PriorityMedium,
 	}
-	mockService.CreateTask(&createReq)
+	mockService.CreateTask(testUserID, &createReq)
 
 	t.Run("should update task title", func(t *testing.T) {
 		newTitle := "Updated Title"
@@ -148,7 +148,7 @@ func TestTaskTitleAndDescription_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -173,7 +173,7 @@ func TestTaskTitleAndDescription_P2P(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -190,7 +190,7 @@ func TestTaskTitleAndDescription_P2P(t *testing.T) {
 
 func TestTaskDeletion_P2P(t *testing.T) {
 	mockService := newMockTaskService()
-	handler := NewHandler(mockService, newMockCategoryService())
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
 	router := handler.SetupRoutes()
 
 	// Create a task first
@@ -199,12 +199,12 @@ func TestTaskDeletion_P2P(t *testing.T) {
 		Description: "This task will be deleted",
 		Priority:    domain.PriorityLow,
 	}
-	mockService.CreateTask(&createReq)
+	mockService.CreateTask(testUserID, &createReq)
 
 	t.Run("should delete task successfully", func(t *testing.T) {
 		req := httptest.NewRequest("DELETE", "/v1/tasks/1", nil)
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusNoContent {
 			t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
@@ -214,7 +214,7 @@ func TestTaskDeletion_P2P(t *testing.T) {
 	t.Run("should return 404 when deleting non-existent task", func(t *testing.T) {
 		req := httptest.NewRequest("DELETE", "/v1/tasks/999", nil)
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusNotFound {
 			t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
@@ -224,7 +224,7 @@ func TestTaskDeletion_P2P(t *testing.T) {
 
 func TestTaskRetrieval_P2P(t *testing.T) {
 	mockService := newMockTaskService()
-	handler := NewHandler(mockService, newMockCategoryService())
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
 	router := handler.SetupRoutes()
 
 	// Create multiple tasks
@@ -235,13 +235,13 @@ func TestTaskRetrieval_P2P(t *testing.T) {
 	}
 
 	for _, task := range tasks {
-		mockService.CreateTask(&task)
+		mockService.CreateTask(testUserID, &task)
 	}
 
 	t.Run("should get all tasks", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks", nil)
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -258,7 +258,7 @@ func TestTaskRetrieval_P2P(t *testing.T) {
 	t.Run("should get specific task by id", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks/1", nil)
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -275,7 +275,7 @@ func TestTaskRetrieval_P2P(t *testing.T) {
 	t.Run("should return 404 for non-existent task", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks/999", nil)
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusNotFound {
 			t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+// F2P Tests for Pagination Feature
+// These tests verify cursor-based paging of GET /v1/tasks
+
+func TestTaskPagination_F2P(t *testing.T) {
+	mockService := newMockTaskService()
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	for i := 1; i <= 5; i++ {
+		priority := domain.PriorityLow
+		if i%2 == 0 {
+			priority = domain.PriorityHigh
+		}
+		mockService.CreateTask(testUserID, &domain.CreateTaskRequest{
+			Title:    fmt.Sprintf("Task %d", i),
+			Priority: priority,
+		})
+	}
+
+	getPage := func(t *testing.T, url string) (int, domain.TaskPage) {
+		req := httptest.NewRequest("GET", url, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authenticated(req))
+
+		var page domain.TaskPage
+		json.Unmarshal(w.Body.Bytes(), &page)
+		return w.Code, page
+	}
+
+	t.Run("should page through all tasks", func(t *testing.T) {
+		var seen []int64
+		url := "/v1/tasks?limit=2"
+		for pages := 0; pages < 10; pages++ {
+			code, page := getPage(t, url)
+			if code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, code)
+			}
+			if page.TotalCount != 5 {
+				t.Errorf("Expected total count 5, got %d", page.TotalCount)
+			}
+			for _, task := range page.Tasks {
+				seen = append(seen, task.ID)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			url = "/v1/tasks?limit=2&cursor=" + page.NextCursor
+		}
+
+		if len(seen) != 5 {
+			t.Errorf("Expected to see 5 tasks across pages, got %d", len(seen))
+		}
+	})
+
+	t.Run("should combine paging with filters", func(t *testing.T) {
+		code, page := getPage(t, "/v1/tasks?priority=high&limit=1")
+		if code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, code)
+		}
+		if page.TotalCount != 2 {
+			t.Errorf("Expected total count 2, got %d", page.TotalCount)
+		}
+		if len(page.Tasks) != 1 || page.NextCursor == "" {
+			t.Errorf("Expected one task and a next cursor, got %d tasks and cursor %q", len(page.Tasks), page.NextCursor)
+		}
+	})
+
+	t.Run("should return a plain array without paging parameters", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/v1/tasks", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authenticated(req))
+
+		var tasks []domain.Task
+		if err := json.Unmarshal(w.Body.Bytes(), &tasks); err != nil {
+			t.Errorf("Expected a JSON array, got %s", w.Body.String())
+		}
+	})
+
+	invalid := []struct {
+		name string
+		url  string
+	}{
+		{name: "non-numeric limit", url: "/v1/tasks?limit=abc"},
+		{name: "negative limit", url: "/v1/tasks?limit=-1"},
+		{name: "limit above maximum", url: fmt.Sprintf("/v1/tasks?limit=%d", domain.MaxPageLimit+1)},
+		{name: "malformed cursor", url: "/v1/tasks?cursor=not-a-cursor"},
+	}
+
+	for _, tt := range invalid {
+		t.Run("should reject "+tt.name, func(t *testing.T) {
+			code, _ := getPage(t, tt.url)
+			if code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, code)
+			}
+		})
+	}
+}
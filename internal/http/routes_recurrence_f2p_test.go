@@ -0,0 +1,44 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// F2P Tests for Recurring Tasks Feature
+// These tests verify the rrule field on task creation
+
+func TestRecurringTaskRequests_F2P(t *testing.T) {
+	mockService := newMockTaskService()
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(time.RFC3339)
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		expectedStatus int
+	}{
+		{name: "should create recurring task", method: "POST", url: "/v1/tasks", body: `{"title": "Report", "priority": "low", "due_date": "` + tomorrow + `", "rrule": "FREQ=WEEKLY;BYDAY=MO"}`, expectedStatus: http.StatusCreated},
+		{name: "should reject recurring task without due date", method: "POST", url: "/v1/tasks", body: `{"title": "Report", "priority": "low", "rrule": "FREQ=WEEKLY"}`, expectedStatus: http.StatusBadRequest},
+		{name: "should reject invalid rrule", method: "POST", url: "/v1/tasks", body: `{"title": "Report", "priority": "low", "due_date": "` + tomorrow + `", "rrule": "FREQ=WEEKLY;BYDAY=XX"}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authenticated(req))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
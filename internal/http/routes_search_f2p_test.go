@@ -14,6 +14,7 @@ func TestTaskSearch_F2P(t *testing.T) {
 	handler := &Handler{
 		taskService:     mockTaskService,
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 	router := handler.SetupRoutes()
 
@@ -131,7 +132,7 @@ func TestTaskSearch_F2P(t *testing.T) {
 			req := httptest.NewRequest("GET", tt.url, nil)
 			w := httptest.NewRecorder()
 
-			router.ServeHTTP(w, req)
+			router.ServeHTTP(w, authenticated(req))
 
 			if w.Code != tt.expectedStatus {
 				t.Errorf("Expected status %d, got %d. %s", tt.expectedStatus, w.Code, tt.description)
@@ -160,6 +161,7 @@ func TestTaskSearchIntegration_F2P(t *testing.T) {
 	handler := &Handler{
 		taskService:     mockTaskService,
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 	router := handler.SetupRoutes()
 
@@ -177,7 +179,7 @@ func TestTaskSearchIntegration_F2P(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks?search=important", nil)
 		w := httptest.NewRecorder()
 
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -208,6 +210,7 @@ func TestTaskSearchEdgeCases_F2P(t *testing.T) {
 	handler := &Handler{
 		taskService:     mockTaskService,
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 	router := handler.SetupRoutes()
 
@@ -242,7 +245,7 @@ func TestTaskSearchEdgeCases_F2P(t *testing.T) {
 			req := httptest.NewRequest("GET", tt.url, nil)
 			w := httptest.NewRecorder()
 
-			router.ServeHTTP(w, req)
+			router.ServeHTTP(w, authenticated(req))
 
 			if w.Code != tt.expectedStatus {
 				t.Errorf("Expected status %d, got %d. %s", tt.expectedStatus, w.Code, tt.description)
@@ -14,6 +14,7 @@ func TestTaskSearchP2P(t *testing.T) {
 	handler := &Handler{
 		taskService:     mockTaskService,
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 	router := handler.SetupRoutes()
 
@@ -102,7 +103,7 @@ func TestTaskSearchP2P(t *testing.T) {
 			}
 
 			w := httptest.NewRecorder()
-			router.ServeHTTP(w, req)
+			router.ServeHTTP(w, authenticated(req))
 
 			// For GET requests, expect 200 OK
 			// For POST/PUT/DELETE, we expect various status codes depending on implementation
@@ -126,6 +127,7 @@ func TestTaskSearchBackwardCompatibility_P2P(t *testing.T) {
 	handler := &Handler{
 		taskService:     mockTaskService,
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 	router := handler.SetupRoutes()
 
@@ -156,7 +158,7 @@ func TestTaskSearchBackwardCompatibility_P2P(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks", nil)
 		w := httptest.NewRecorder()
 
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -180,7 +182,7 @@ func TestTaskSearchBackwardCompatibility_P2P(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks?search=", nil)
 		w := httptest.NewRecorder()
 
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -207,6 +209,7 @@ func TestTaskSearchWithExistingFilters_P2P(t *testing.T) {
 	handler := &Handler{
 		taskService:     mockTaskService,
 		categoryService: mockCategoryService,
+		authService:     newMockAuthService(),
 	}
 	router := handler.SetupRoutes()
 
@@ -244,7 +247,7 @@ func TestTaskSearchWithExistingFilters_P2P(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks?search=urgent&status=todo", nil)
 		w := httptest.NewRecorder()
 
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -273,7 +276,7 @@ func TestTaskSearchWithExistingFilters_P2P(t *testing.T) {
 		req := httptest.NewRequest("GET", "/v1/tasks?search=important&priority=medium", nil)
 		w := httptest.NewRecorder()
 
-		router.ServeHTTP(w, req)
+		router.ServeHTTP(w, authenticated(req))
 
 		if w.Code != http.StatusOK {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+// F2P Tests for Sort Feature
+// These tests verify the sort query parameter on GET /v1/tasks
+
+func TestTaskSortParameter_F2P(t *testing.T) {
+	mockService := newMockTaskService()
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Sorted Task", Priority: domain.PriorityHigh})
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+	}{
+		{name: "should accept mixed directions", url: "/v1/tasks?sort=-priority,title,updated_at", expectedStatus: http.StatusOK},
+		{name: "should accept sort with filters", url: "/v1/tasks?status=todo&sort=due_date", expectedStatus: http.StatusOK},
+		{name: "should accept sort with paging", url: "/v1/tasks?sort=-created_at&limit=10", expectedStatus: http.StatusOK},
+		{name: "should reject unknown field", url: "/v1/tasks?sort=description", expectedStatus: http.StatusBadRequest},
+		{name: "should reject duplicate field", url: "/v1/tasks?sort=title,-title", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authenticated(req))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
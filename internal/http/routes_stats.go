@@ -0,0 +1,63 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"task-manager/internal/domain"
+	"time"
+)
+
+// getStats summarizes the tasks matching the same filters as GET /v1/tasks.
+// The daily counts cover the days from and to, which default to the last
+// 30 days; days sets the length of the window when from is omitted.
+func (h *Handler) getStats(w http.ResponseWriter, r *http.Request) {
+	filters, err := h.parseTaskFilters(r)
+	if err != nil {
+		writeFilterError(w, err)
+		return
+	}
+
+	window, err := parseStatsWindow(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stats, err := h.taskService.GetTaskStats(currentUserID(r), filters, window)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, stats)
+}
+
+func parseStatsWindow(r *http.Request) (domain.StatsWindow, error) {
+	query := r.URL.Query()
+
+	days := domain.DefaultStatsDays
+	if daysParam := query.Get("days"); daysParam != "" {
+		var err error
+		days, err = strconv.Atoi(daysParam)
+		if err != nil || days < 1 || days > domain.MaxStatsDays {
+			return domain.StatsWindow{}, fmt.Errorf("days must be between 1 and %d", domain.MaxStatsDays)
+		}
+	}
+
+	window := domain.NewStatsWindow(time.Now(), days)
+	for param, dest := range map[string]*time.Time{"from": &window.From, "to": &window.To} {
+		if value := query.Get(param); value != "" {
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return domain.StatsWindow{}, fmt.Errorf("invalid %s: use YYYY-MM-DD", param)
+			}
+			*dest = date
+		}
+	}
+	if query.Get("from") == "" && query.Get("to") != "" {
+		window.From = window.To.AddDate(0, 0, -(days - 1))
+	}
+
+	return window, nil
+}
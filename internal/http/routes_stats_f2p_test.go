@@ -0,0 +1,116 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/domain"
+	"testing"
+	"time"
+)
+
+// F2P Tests for Task Statistics
+// These tests verify GET /v1/stats
+
+func (m *mockTaskService) GetTaskStats(userID int64, filters *domain.TaskFilters, window domain.StatsWindow) (*domain.TaskStats, error) {
+	if err := window.Validate(); err != nil {
+		return nil, err
+	}
+	tasks, err := m.GetTasksWithFilters(userID, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := domain.NewTaskStats(window, m.workflow)
+	for _, task := range tasks {
+		stats.Total++
+		stats.ByStatus[task.Status]++
+		stats.ByPriority[task.Priority]++
+		if task.IsOverdue(m.workflow, time.Now()) {
+			stats.Overdue++
+		}
+	}
+	return stats, nil
+}
+
+func TestTaskStats_F2P(t *testing.T) {
+	mockService := newMockTaskService()
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	for _, task := range []*domain.Task{
+		{ID: 1, Title: "Late", Status: domain.StatusTodo, Priority: domain.PriorityHigh, DueDate: &yesterday},
+		{ID: 2, Title: "Started", Status: domain.StatusDoing, Priority: domain.PriorityHigh},
+		{ID: 3, Title: "Finished", Status: domain.StatusDone, Priority: domain.PriorityLow, DueDate: &yesterday},
+	} {
+		mockService.tasks[task.ID] = task
+	}
+
+	get := func(query string) (*httptest.ResponseRecorder, domain.TaskStats) {
+		req := httptest.NewRequest("GET", "/v1/stats"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authenticated(req))
+
+		var stats domain.TaskStats
+		json.NewDecoder(w.Body).Decode(&stats)
+		return w, stats
+	}
+
+	t.Run("should count every task by default", func(t *testing.T) {
+		w, stats := get("")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		if stats.Total != 3 || stats.ByStatus[domain.StatusDone] != 1 || stats.ByPriority[domain.PriorityHigh] != 2 {
+			t.Errorf("Unexpected counts: %+v", stats)
+		}
+		if stats.ByPriority[domain.PriorityCritical] != 0 || len(stats.ByStatus) != 3 {
+			t.Errorf("Expected every status and priority to be listed, got %+v", stats)
+		}
+		if stats.Overdue != 1 {
+			t.Errorf("Expected 1 overdue task, got %d", stats.Overdue)
+		}
+		if len(stats.Daily) != domain.DefaultStatsDays {
+			t.Errorf("Expected %d days, got %d", domain.DefaultStatsDays, len(stats.Daily))
+		}
+	})
+
+	t.Run("should apply the task filters", func(t *testing.T) {
+		_, stats := get("?priority=high&status=todo,doing")
+		if stats.Total != 2 || stats.ByPriority[domain.PriorityLow] != 0 {
+			t.Errorf("Expected only the open high priority tasks, got %+v", stats)
+		}
+		_, stats = get("?q=-status:done")
+		if stats.Total != 2 {
+			t.Errorf("Expected the filter query to apply, got %+v", stats)
+		}
+	})
+
+	t.Run("should use the requested window", func(t *testing.T) {
+		_, stats := get("?from=2026-02-27&to=2026-03-02")
+		if len(stats.Daily) != 4 || stats.Daily[0].Date != "2026-02-27" || stats.Daily[3].Date != "2026-03-02" {
+			t.Errorf("Expected the days from February 27 to March 2, got %+v", stats.Daily)
+		}
+		_, stats = get("?to=2026-03-02&days=7")
+		if len(stats.Daily) != 7 || stats.Daily[0].Date != "2026-02-24" {
+			t.Errorf("Expected the 7 days up to March 2, got %+v", stats.Daily)
+		}
+	})
+
+	t.Run("should reject invalid parameters", func(t *testing.T) {
+		for _, query := range []string{
+			"?days=0",
+			"?days=1000",
+			"?from=yesterday",
+			"?from=2026-03-02&to=2026-03-01",
+			"?from=2024-01-01&to=2026-01-01",
+			"?status=later",
+			"?q=status:",
+		} {
+			if w, _ := get(query); w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, w.Code)
+			}
+		}
+	})
+}
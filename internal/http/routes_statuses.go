@@ -0,0 +1,51 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"task-manager/internal/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// Workflow status handlers. The workflow is shared by all users.
+func (h *Handler) getStatuses(w http.ResponseWriter, r *http.Request) {
+	writeJSONResponse(w, http.StatusOK, h.statusService.GetWorkflow())
+}
+
+func (h *Handler) saveStatus(w http.ResponseWriter, r *http.Request) {
+	var req domain.SaveStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	status, err := h.statusService.SaveStatus(domain.TaskStatus(mux.Vars(r)["key"]), &req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, status)
+}
+
+func (h *Handler) deleteStatus(w http.ResponseWriter, r *http.Request) {
+	if err := h.statusService.DeleteStatus(domain.TaskStatus(mux.Vars(r)["key"])); err != nil {
+		writeErrorResponse(w, statusErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func statusErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, domain.ErrStatusNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrStatusInUse):
+		return http.StatusConflict
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,210 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+// F2P Tests for Workflow Statuses
+// These tests verify the /v1/statuses endpoints and that task updates follow
+// the workflow's transitions
+
+type mockStatusService struct {
+	workflow *domain.Workflow
+	inUse    map[domain.TaskStatus]bool
+}
+
+func newMockStatusService() *mockStatusService {
+	return &mockStatusService{
+		workflow: domain.DefaultWorkflow(),
+		inUse:    make(map[domain.TaskStatus]bool),
+	}
+}
+
+func (m *mockStatusService) LoadWorkflow() error {
+	return nil
+}
+
+func (m *mockStatusService) GetWorkflow() *domain.Workflow {
+	return m.workflow
+}
+
+// SaveStatus appends new statuses and ignores positions
+func (m *mockStatusService) SaveStatus(key domain.TaskStatus, req *domain.SaveStatusRequest) (*domain.StatusDefinition, error) {
+	if err := domain.ValidateStatusKey(key); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	status := domain.StatusDefinition{Key: key, Label: req.Label, Category: req.Category, Transitions: req.Transitions}
+
+	workflow := &domain.Workflow{}
+	for _, existing := range m.workflow.Statuses {
+		if existing.Key != key {
+			workflow.Statuses = append(workflow.Statuses, existing)
+		}
+	}
+	status.Position = len(workflow.Statuses)
+	workflow.Statuses = append(workflow.Statuses, status)
+	if err := workflow.Validate(); err != nil {
+		return nil, err
+	}
+	m.workflow = workflow
+	return &status, nil
+}
+
+func (m *mockStatusService) DeleteStatus(key domain.TaskStatus) error {
+	if m.workflow.Status(key) == nil {
+		return domain.ErrStatusNotFound
+	}
+	if m.inUse[key] {
+		return fmt.Errorf("cannot delete status %s: %w", key, domain.ErrStatusInUse)
+	}
+	workflow := &domain.Workflow{}
+	for _, existing := range m.workflow.Statuses {
+		if existing.Key != key {
+			workflow.Statuses = append(workflow.Statuses, existing)
+		}
+	}
+	m.workflow = workflow
+	return nil
+}
+
+func TestStatuses_F2P(t *testing.T) {
+	statusService := newMockStatusService()
+	statusService.inUse[domain.StatusTodo] = true
+	authService := newMockAuthService()
+	authService.users["test@example.com"].IsAdmin = true
+	handler := NewHandler(newMockTaskService(), newMockCategoryService(), authService, newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), statusService, newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	request := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if s, ok := body.(string); ok {
+			buf.WriteString(s)
+		} else if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		req := httptest.NewRequest(method, path, &buf)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authenticated(req))
+		return w
+	}
+
+	t.Run("should list the seeded statuses", func(t *testing.T) {
+		w := request("GET", "/v1/statuses", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var workflow domain.Workflow
+		json.NewDecoder(w.Body).Decode(&workflow)
+		if len(workflow.Statuses) != 3 || workflow.Statuses[2].Category != domain.StatusCategoryClosed {
+			t.Errorf("Expected todo, doing and done, got %+v", workflow.Statuses)
+		}
+	})
+
+	t.Run("should add a status", func(t *testing.T) {
+		w := request("PUT", "/v1/statuses/review", map[string]interface{}{
+			"label": "Review", "category": "in_progress", "transitions": []string{"doing", "done"},
+		})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		if statusService.workflow.Status("review") == nil {
+			t.Error("Expected review to be part of the workflow")
+		}
+	})
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           interface{}
+		expectedStatus int
+	}{
+		{"should reject invalid JSON", "PUT", "/v1/statuses/review", "{", http.StatusBadRequest},
+		{"should reject an invalid key", "PUT", "/v1/statuses/In%20Review", map[string]interface{}{"label": "In review", "category": "open"}, http.StatusBadRequest},
+		{"should reject an unknown category", "PUT", "/v1/statuses/review", map[string]interface{}{"label": "Review", "category": "waiting"}, http.StatusBadRequest},
+		{"should reject a transition to an unknown status", "PUT", "/v1/statuses/review", map[string]interface{}{"label": "Review", "category": "in_progress", "transitions": []string{"later"}}, http.StatusBadRequest},
+		{"should delete an unused status", "DELETE", "/v1/statuses/review", nil, http.StatusNoContent},
+		{"should not delete a status tasks are in", "DELETE", "/v1/statuses/todo", nil, http.StatusConflict},
+		{"should not delete an unknown status", "DELETE", "/v1/statuses/later", nil, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := request(tt.method, tt.path, tt.body); w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestStatusesAdminOnly_F2P(t *testing.T) {
+	statusService := newMockStatusService()
+	handler := NewHandler(newMockTaskService(), newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), statusService, newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{"should list the statuses", "GET", "/v1/statuses", "", http.StatusOK},
+		{"should not add a status", "PUT", "/v1/statuses/review", `{"label": "Review", "category": "in_progress"}`, http.StatusForbidden},
+		{"should not delete a status", "DELETE", "/v1/statuses/doing", "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authenticated(req))
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if statusService.workflow.Status("review") != nil || statusService.workflow.Status(domain.StatusDoing) == nil {
+		t.Errorf("Expected users who are not administrators to leave the workflow unchanged, got %+v", statusService.workflow.Statuses)
+	}
+}
+
+func TestStatusTransitions_F2P(t *testing.T) {
+	workflow := domain.DefaultWorkflow()
+	workflow.Statuses[0].Transitions = []domain.TaskStatus{domain.StatusDoing}
+
+	mockService := newMockTaskService()
+	mockService.workflow = workflow
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+	mockService.tasks[1] = &domain.Task{ID: 1, Title: "Task", Status: domain.StatusTodo, Priority: domain.PriorityLow, Version: 1}
+
+	update := func(status string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]string{"status": status})
+		req := httptest.NewRequest("PATCH", "/v1/tasks/1", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authenticated(req))
+		return w
+	}
+
+	if w := update("done"); w.Code != http.StatusConflict {
+		t.Errorf("Expected a skipped step to return %d, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+	}
+	if w := update("cancelled"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected an unknown status to return %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+	}
+	if w := update("doing"); w.Code != http.StatusOK {
+		t.Errorf("Expected an allowed transition to return %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if w := update("done"); w.Code != http.StatusOK {
+		t.Errorf("Expected an allowed transition to return %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+// F2P Tests for Subtasks Feature
+// These tests verify the subtask listing, tree and delete mode endpoints
+
+func TestSubtaskEndpoints_F2P(t *testing.T) {
+	mockService := newMockTaskService()
+	handler := NewHandler(mockService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	root, _ := mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Root", Priority: domain.PriorityMedium})
+	child, _ := mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Child", Priority: domain.PriorityMedium, ParentID: &root.ID})
+	mockService.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Grandchild", Priority: domain.PriorityMedium, ParentID: &child.ID})
+
+	t.Run("should list direct subtasks", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/v1/tasks/1/subtasks", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authenticated(req))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var subtasks []domain.Task
+		json.Unmarshal(w.Body.Bytes(), &subtasks)
+		if len(subtasks) != 1 || subtasks[0].ID != child.ID {
+			t.Errorf("Expected only task %d, got %+v", child.ID, subtasks)
+		}
+	})
+
+	t.Run("should return nested tree", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/v1/tasks/1/tree", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authenticated(req))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var tree struct {
+			ID       int64 `json:"id"`
+			Children []struct {
+				ID       int64             `json:"id"`
+				Children []json.RawMessage `json:"children"`
+			} `json:"children"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &tree)
+		if tree.ID != root.ID || len(tree.Children) != 1 || len(tree.Children[0].Children) != 1 {
+			t.Errorf("Unexpected tree shape: %s", w.Body.String())
+		}
+	})
+
+	t.Run("should return 404 for unknown task", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/v1/tasks/999/tree", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authenticated(req))
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("should reject unknown delete mode", func(t *testing.T) {
+		req := httptest.NewRequest("DELETE", "/v1/tasks/1?subtasks=orphan", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authenticated(req))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("should cascade delete", func(t *testing.T) {
+		req := httptest.NewRequest("DELETE", "/v1/tasks/1?subtasks=cascade", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authenticated(req))
+
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("Expected status 204, got %d: %s", w.Code, w.Body.String())
+		}
+		if len(mockService.tasks) != 0 {
+			t.Errorf("Expected subtree to be deleted, %d tasks remain", len(mockService.tasks))
+		}
+	})
+}
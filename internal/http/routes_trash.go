@@ -0,0 +1,116 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"task-manager/internal/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// Trash handlers
+func (h *Handler) getTrash(w http.ResponseWriter, r *http.Request) {
+	userID := currentUserID(r)
+
+	tasks, err := h.taskService.GetTrashedTasks(userID)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	categories, err := h.categoryService.GetTrashedCategories(userID)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, &domain.Trash{Tasks: tasks, Categories: categories})
+}
+
+func (h *Handler) emptyTrash(w http.ResponseWriter, r *http.Request) {
+	userID := currentUserID(r)
+
+	if err := h.taskService.PurgeTrash(userID); err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := h.categoryService.PurgeTrash(userID); err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) restoreTask(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	task, err := h.taskService.RestoreTask(currentUserID(r), id)
+	if err != nil {
+		writeErrorResponse(w, trashErrorStatus(err), err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, task)
+}
+
+func (h *Handler) purgeTask(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	if err := h.taskService.PurgeTask(currentUserID(r), id); err != nil {
+		writeErrorResponse(w, trashErrorStatus(err), err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) restoreCategory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid category ID")
+		return
+	}
+
+	category, err := h.categoryService.RestoreCategory(currentUserID(r), id)
+	if err != nil {
+		writeErrorResponse(w, trashErrorStatus(err), err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, category)
+}
+
+func (h *Handler) purgeCategory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid category ID")
+		return
+	}
+
+	if err := h.categoryService.PurgeCategory(currentUserID(r), id); err != nil {
+		writeErrorResponse(w, trashErrorStatus(err), err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func trashErrorStatus(err error) int {
+	if errors.Is(err, domain.ErrNotInTrash) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
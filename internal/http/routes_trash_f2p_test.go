@@ -0,0 +1,86 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+// F2P Tests for Trash Feature
+// These tests verify that deleted tasks and categories can be listed,
+// restored and purged through /v1/trash
+
+func TestTrash_F2P(t *testing.T) {
+	mockTasks := newMockTaskService()
+	mockCategories := newMockCategoryService()
+	handler := NewHandler(mockTasks, mockCategories, newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	mockTasks.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Oops", Priority: domain.PriorityLow})
+	mockTasks.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Gone", Priority: domain.PriorityLow})
+	mockCategories.CreateCategory(testUserID, &domain.CreateCategoryRequest{Name: "Work"})
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		expectedStatus int
+	}{
+		{name: "should move task to trash", method: "DELETE", url: "/v1/tasks/1", expectedStatus: http.StatusNoContent},
+		{name: "should hide trashed task", method: "GET", url: "/v1/tasks/1", expectedStatus: http.StatusNotFound},
+		{name: "should move second task to trash", method: "DELETE", url: "/v1/tasks/2", expectedStatus: http.StatusNoContent},
+		{name: "should move category to trash", method: "DELETE", url: "/v1/categories/1", expectedStatus: http.StatusNoContent},
+		{name: "should list trash", method: "GET", url: "/v1/trash", expectedStatus: http.StatusOK},
+		{name: "should restore task", method: "POST", url: "/v1/trash/tasks/1/restore", expectedStatus: http.StatusOK},
+		{name: "should show restored task", method: "GET", url: "/v1/tasks/1", expectedStatus: http.StatusOK},
+		{name: "should not restore live task", method: "POST", url: "/v1/trash/tasks/1/restore", expectedStatus: http.StatusNotFound},
+		{name: "should purge task", method: "DELETE", url: "/v1/trash/tasks/2", expectedStatus: http.StatusNoContent},
+		{name: "should not purge task twice", method: "DELETE", url: "/v1/trash/tasks/2", expectedStatus: http.StatusNotFound},
+		{name: "should reject invalid task ID", method: "POST", url: "/v1/trash/tasks/abc/restore", expectedStatus: http.StatusBadRequest},
+		{name: "should restore category", method: "POST", url: "/v1/trash/categories/1/restore", expectedStatus: http.StatusOK},
+		{name: "should not purge live category", method: "DELETE", url: "/v1/trash/categories/1", expectedStatus: http.StatusNotFound},
+		{name: "should empty trash", method: "DELETE", url: "/v1/trash", expectedStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authenticated(req))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTrashListing_F2P(t *testing.T) {
+	mockTasks := newMockTaskService()
+	mockCategories := newMockCategoryService()
+	handler := NewHandler(mockTasks, mockCategories, newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	mockTasks.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Oops", Priority: domain.PriorityLow})
+	mockTasks.DeleteTask(testUserID, 1)
+
+	req := httptest.NewRequest("GET", "/v1/trash", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authenticated(req))
+
+	var trash struct {
+		Tasks      []map[string]interface{} `json:"tasks"`
+		Categories []map[string]interface{} `json:"categories"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &trash); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(trash.Tasks) != 1 || trash.Tasks[0]["deleted_at"] == nil {
+		t.Errorf("Expected one trashed task with deleted_at, got %v", trash.Tasks)
+	}
+	if trash.Categories == nil {
+		t.Error("Expected an empty categories list, got null")
+	}
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"task-manager/internal/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// Saved view handlers
+func (h *Handler) createView(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateViewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	view, err := h.viewService.CreateView(currentUserID(r), &req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, view)
+}
+
+func (h *Handler) getAllViews(w http.ResponseWriter, r *http.Request) {
+	views, err := h.viewService.GetAllViews(currentUserID(r))
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, views)
+}
+
+func (h *Handler) getView(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseViewID(w, r)
+	if !ok {
+		return
+	}
+
+	view, err := h.viewService.GetView(currentUserID(r), id)
+	if err != nil {
+		writeErrorResponse(w, viewErrorStatus(err, http.StatusInternalServerError), err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, view)
+}
+
+func (h *Handler) updateView(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseViewID(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateViewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	view, err := h.viewService.UpdateView(currentUserID(r), id, &req)
+	if err != nil {
+		writeErrorResponse(w, viewErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, view)
+}
+
+func (h *Handler) deleteView(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseViewID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.viewService.DeleteView(currentUserID(r), id); err != nil {
+		writeErrorResponse(w, viewErrorStatus(err, http.StatusInternalServerError), err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getViewTasks lists the user's tasks matching a saved view. Shared views
+// run against the tasks of whoever opens them.
+func (h *Handler) getViewTasks(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseViewID(w, r)
+	if !ok {
+		return
+	}
+
+	view, err := h.viewService.GetView(currentUserID(r), id)
+	if err != nil {
+		writeErrorResponse(w, viewErrorStatus(err, http.StatusInternalServerError), err.Error())
+		return
+	}
+
+	tasks, err := h.taskService.GetTasksWithFilters(currentUserID(r), &view.Filters)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, tasks)
+}
+
+func parseViewID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid view ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func viewErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, domain.ErrNotViewOwner):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrViewNotFound):
+		return http.StatusNotFound
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,214 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+// F2P Tests for Saved Views
+// These tests verify /v1/views and /v1/views/{id}/tasks
+
+// Mock view service for testing
+type mockViewService struct {
+	views  map[int64]*domain.SavedView
+	nextID int64
+}
+
+func newMockViewService() *mockViewService {
+	return &mockViewService{
+		views:  make(map[int64]*domain.SavedView),
+		nextID: 1,
+	}
+}
+
+func (m *mockViewService) CreateView(userID int64, req *domain.CreateViewRequest) (*domain.SavedView, error) {
+	if err := req.Validate(domain.DefaultWorkflow()); err != nil {
+		return nil, err
+	}
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = domain.ViewPrivate
+	}
+	view := &domain.SavedView{ID: m.nextID, OwnerID: userID, Name: req.Name, Filters: req.Filters, Visibility: visibility}
+	m.views[view.ID] = view
+	m.nextID++
+	return view, nil
+}
+
+func (m *mockViewService) GetView(userID, id int64) (*domain.SavedView, error) {
+	view, ok := m.views[id]
+	if !ok || (view.OwnerID != userID && view.Visibility != domain.ViewShared) {
+		return nil, domain.ErrViewNotFound
+	}
+	return view, nil
+}
+
+func (m *mockViewService) GetAllViews(userID int64) ([]domain.SavedView, error) {
+	views := []domain.SavedView{}
+	for id := int64(1); id < m.nextID; id++ {
+		if view, err := m.GetView(userID, id); err == nil {
+			views = append(views, *view)
+		}
+	}
+	return views, nil
+}
+
+func (m *mockViewService) UpdateView(userID, id int64, req *domain.UpdateViewRequest) (*domain.SavedView, error) {
+	if err := req.Validate(domain.DefaultWorkflow()); err != nil {
+		return nil, err
+	}
+	view, err := m.GetView(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if view.OwnerID != userID {
+		return nil, domain.ErrNotViewOwner
+	}
+	if req.Name != nil {
+		view.Name = *req.Name
+	}
+	if req.Filters != nil {
+		view.Filters = *req.Filters
+	}
+	if req.Visibility != nil {
+		view.Visibility = *req.Visibility
+	}
+	return view, nil
+}
+
+func (m *mockViewService) DeleteView(userID, id int64) error {
+	view, err := m.GetView(userID, id)
+	if err != nil {
+		return err
+	}
+	if view.OwnerID != userID {
+		return domain.ErrNotViewOwner
+	}
+	delete(m.views, id)
+	return nil
+}
+
+func TestSavedViews_F2P(t *testing.T) {
+	taskService := newMockTaskService()
+	viewService := newMockViewService()
+	handler := NewHandler(taskService, newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), viewService, newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	taskService.tasks[1] = &domain.Task{ID: 1, Title: "Urgent bug", Status: domain.StatusTodo, Priority: domain.PriorityCritical}
+	taskService.tasks[2] = &domain.Task{ID: 2, Title: "Chore", Status: domain.StatusTodo, Priority: domain.PriorityLow}
+	taskService.tasks[3] = &domain.Task{ID: 3, Title: "Old bug", Status: domain.StatusDone, Priority: domain.PriorityHigh}
+
+	// Views of another user, one of them shared
+	viewService.views[100] = &domain.SavedView{ID: 100, OwnerID: testUserID + 1, Name: "Shared", Visibility: domain.ViewShared,
+		Filters: domain.TaskFilters{Priorities: []domain.TaskPriority{domain.PriorityLow}}}
+	viewService.views[101] = &domain.SavedView{ID: 101, OwnerID: testUserID + 1, Name: "Private", Visibility: domain.ViewPrivate}
+	viewService.nextID = 102
+
+	do := func(method, url string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		req := httptest.NewRequest(method, url, &buf)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authenticated(req))
+		return w
+	}
+
+	var created domain.SavedView
+	t.Run("should create a view with its filters and sort", func(t *testing.T) {
+		w := do("POST", "/v1/views", map[string]interface{}{
+			"name": "Open urgent",
+			"filters": map[string]interface{}{
+				"statuses":   []string{"todo", "doing"},
+				"priorities": []string{"high", "critical"},
+				"sort":       []map[string]interface{}{{"field": "priority", "descending": true}},
+			},
+		})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+		json.NewDecoder(w.Body).Decode(&created)
+		if created.Visibility != domain.ViewPrivate || created.OwnerID != testUserID || len(created.Filters.Sort) != 1 {
+			t.Errorf("Expected a private view with the sort, got %+v", created)
+		}
+	})
+
+	t.Run("should reject invalid views", func(t *testing.T) {
+		for _, body := range []map[string]interface{}{
+			{"name": ""},
+			{"name": "Bad status", "filters": map[string]interface{}{"statuses": []string{"later"}}},
+			{"name": "Bad visibility", "visibility": "public"},
+		} {
+			if w := do("POST", "/v1/views", body); w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d for %v, got %d", http.StatusBadRequest, body, w.Code)
+			}
+		}
+	})
+
+	t.Run("should list own and shared views", func(t *testing.T) {
+		w := do("GET", "/v1/views", nil)
+		var views []domain.SavedView
+		json.NewDecoder(w.Body).Decode(&views)
+		if len(views) != 2 {
+			t.Errorf("Expected the shared view and the created one, got %+v", views)
+		}
+	})
+
+	t.Run("should run the stored filters", func(t *testing.T) {
+		w := do("GET", fmt.Sprintf("/v1/views/%d/tasks", created.ID), nil)
+		var tasks []*domain.Task
+		json.NewDecoder(w.Body).Decode(&tasks)
+		if w.Code != http.StatusOK || len(tasks) != 1 || tasks[0].ID != 1 {
+			t.Errorf("Expected only the urgent open task, got %d %+v", w.Code, tasks)
+		}
+
+		w = do("GET", "/v1/views/100/tasks", nil)
+		json.NewDecoder(w.Body).Decode(&tasks)
+		if w.Code != http.StatusOK || len(tasks) != 1 || tasks[0].ID != 2 {
+			t.Errorf("Expected a shared view to run against the user's tasks, got %d %+v", w.Code, tasks)
+		}
+	})
+
+	t.Run("should update and delete own views", func(t *testing.T) {
+		w := do("PATCH", fmt.Sprintf("/v1/views/%d", created.ID), map[string]interface{}{"name": "Renamed", "visibility": "shared"})
+		var updated domain.SavedView
+		json.NewDecoder(w.Body).Decode(&updated)
+		if w.Code != http.StatusOK || updated.Name != "Renamed" || updated.Visibility != domain.ViewShared || len(updated.Filters.Statuses) != 2 {
+			t.Errorf("Expected the renamed shared view, got %d %+v", w.Code, updated)
+		}
+
+		if w := do("DELETE", fmt.Sprintf("/v1/views/%d", created.ID), nil); w.Code != http.StatusNoContent {
+			t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+		}
+		if w := do("GET", fmt.Sprintf("/v1/views/%d", created.ID), nil); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d after delete, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+
+	t.Run("should protect views of other users", func(t *testing.T) {
+		tests := []struct {
+			method, url    string
+			body           interface{}
+			expectedStatus int
+		}{
+			{"GET", "/v1/views/100", nil, http.StatusOK},
+			{"PATCH", "/v1/views/100", map[string]string{"name": "Mine"}, http.StatusForbidden},
+			{"DELETE", "/v1/views/100", nil, http.StatusForbidden},
+			{"GET", "/v1/views/101", nil, http.StatusNotFound},
+			{"GET", "/v1/views/101/tasks", nil, http.StatusNotFound},
+			{"DELETE", "/v1/views/101", nil, http.StatusNotFound},
+			{"GET", "/v1/views/abc", nil, http.StatusBadRequest},
+		}
+		for _, tt := range tests {
+			if w := do(tt.method, tt.url, tt.body); w.Code != tt.expectedStatus {
+				t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.url, tt.expectedStatus, w.Code)
+			}
+		}
+	})
+}
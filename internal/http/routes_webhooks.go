@@ -0,0 +1,169 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"task-manager/internal/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// Webhook handlers
+func (h *Handler) createWebhook(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(currentUserID(r), &req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, webhook)
+}
+
+func (h *Handler) getAllWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.webhookService.GetAllWebhooks(currentUserID(r))
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, webhooks)
+}
+
+func (h *Handler) getWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookService.GetWebhook(currentUserID(r), id)
+	if err != nil {
+		writeErrorResponse(w, webhookErrorStatus(err, http.StatusInternalServerError), err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, webhook)
+}
+
+func (h *Handler) updateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(currentUserID(r), id, &req)
+	if err != nil {
+		writeErrorResponse(w, webhookErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, webhook)
+}
+
+func (h *Handler) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(currentUserID(r), id); err != nil {
+		writeErrorResponse(w, webhookErrorStatus(err, http.StatusInternalServerError), err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) getWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	status := domain.WebhookDeliveryStatus(r.URL.Query().Get("status"))
+	deliveries, err := h.webhookService.GetDeliveries(currentUserID(r), id, status)
+	if err != nil {
+		writeErrorResponse(w, webhookErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, deliveries)
+}
+
+func (h *Handler) getWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	webhookID, deliveryID, ok := parseDeliveryPath(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.GetDelivery(currentUserID(r), webhookID, deliveryID)
+	if err != nil {
+		writeErrorResponse(w, webhookErrorStatus(err, http.StatusInternalServerError), err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, delivery)
+}
+
+func (h *Handler) replayWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	webhookID, deliveryID, ok := parseDeliveryPath(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.ReplayDelivery(currentUserID(r), webhookID, deliveryID)
+	if err != nil {
+		writeErrorResponse(w, webhookErrorStatus(err, http.StatusInternalServerError), err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusAccepted, delivery)
+}
+
+// parseWebhookID reads the webhook ID, writing a 400 response if it is
+// malformed
+func parseWebhookID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid webhook ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// parseDeliveryPath reads the webhook and delivery IDs, writing a 400 response
+// if either is malformed
+func parseDeliveryPath(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	webhookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+	deliveryID, err := strconv.ParseInt(mux.Vars(r)["deliveryId"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid delivery ID")
+		return 0, 0, false
+	}
+	return webhookID, deliveryID, true
+}
+
+func webhookErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, domain.ErrWebhookNotFound), errors.Is(err, domain.ErrDeliveryNotFound):
+		return http.StatusNotFound
+	default:
+		return fallback
+	}
+}
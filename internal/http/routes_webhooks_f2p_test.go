@@ -0,0 +1,286 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+// F2P Tests for Outgoing Webhooks
+// These tests verify the /v1/webhooks endpoints and their delivery log
+
+type mockWebhookService struct {
+	webhooks   map[int64]*domain.Webhook
+	deliveries map[int64]*domain.WebhookDelivery
+	nextID     int64
+}
+
+func newMockWebhookService() *mockWebhookService {
+	return &mockWebhookService{
+		webhooks:   make(map[int64]*domain.Webhook),
+		deliveries: make(map[int64]*domain.WebhookDelivery),
+		nextID:     1,
+	}
+}
+
+func (m *mockWebhookService) CreateWebhook(userID int64, req *domain.CreateWebhookRequest) (*domain.Webhook, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	secret := req.Secret
+	if secret == "" {
+		secret = "whsec_generated"
+	}
+	webhook := &domain.Webhook{ID: m.nextID, UserID: userID, URL: req.URL, Secret: secret, Events: req.Events, Active: true}
+	m.webhooks[webhook.ID] = webhook
+	m.nextID++
+	created := *webhook
+	return &created, nil
+}
+
+func (m *mockWebhookService) GetWebhook(userID, id int64) (*domain.Webhook, error) {
+	webhook, ok := m.webhooks[id]
+	if !ok || webhook.UserID != userID {
+		return nil, domain.ErrWebhookNotFound
+	}
+	found := *webhook
+	found.Secret = ""
+	return &found, nil
+}
+
+func (m *mockWebhookService) GetAllWebhooks(userID int64) ([]domain.Webhook, error) {
+	webhooks := []domain.Webhook{}
+	for _, webhook := range m.webhooks {
+		if webhook.UserID == userID {
+			found := *webhook
+			found.Secret = ""
+			webhooks = append(webhooks, found)
+		}
+	}
+	return webhooks, nil
+}
+
+func (m *mockWebhookService) UpdateWebhook(userID, id int64, req *domain.UpdateWebhookRequest) (*domain.Webhook, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	webhook, ok := m.webhooks[id]
+	if !ok || webhook.UserID != userID {
+		return nil, domain.ErrWebhookNotFound
+	}
+	if req.URL != nil {
+		webhook.URL = *req.URL
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+	return m.GetWebhook(userID, id)
+}
+
+func (m *mockWebhookService) DeleteWebhook(userID, id int64) error {
+	if _, err := m.GetWebhook(userID, id); err != nil {
+		return err
+	}
+	delete(m.webhooks, id)
+	return nil
+}
+
+func (m *mockWebhookService) GetDeliveries(userID, webhookID int64, status domain.WebhookDeliveryStatus) ([]domain.WebhookDelivery, error) {
+	if _, err := m.GetWebhook(userID, webhookID); err != nil {
+		return nil, err
+	}
+	deliveries := []domain.WebhookDelivery{}
+	for _, delivery := range m.deliveries {
+		if delivery.WebhookID == webhookID && (status == "" || delivery.Status == status) {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *mockWebhookService) GetDelivery(userID, webhookID, id int64) (*domain.WebhookDelivery, error) {
+	if _, err := m.GetWebhook(userID, webhookID); err != nil {
+		return nil, err
+	}
+	delivery, ok := m.deliveries[id]
+	if !ok || delivery.WebhookID != webhookID {
+		return nil, domain.ErrDeliveryNotFound
+	}
+	return delivery, nil
+}
+
+func (m *mockWebhookService) ReplayDelivery(userID, webhookID, id int64) (*domain.WebhookDelivery, error) {
+	original, err := m.GetDelivery(userID, webhookID, id)
+	if err != nil {
+		return nil, err
+	}
+	replay := &domain.WebhookDelivery{ID: m.nextID, WebhookID: webhookID, EventID: original.EventID, EventType: original.EventType, Status: domain.DeliveryPending}
+	m.deliveries[replay.ID] = replay
+	m.nextID++
+	return replay, nil
+}
+
+func (m *mockWebhookService) Enqueue(event domain.Event) error {
+	return nil
+}
+
+func (m *mockWebhookService) DeliverDue() (int, error) {
+	return 0, nil
+}
+
+func TestWebhooks_F2P(t *testing.T) {
+	mockService := newMockWebhookService()
+	handler := NewHandler(newMockTaskService(), newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), mockService, newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	mockService.webhooks[50] = &domain.Webhook{ID: 50, UserID: testUserID + 1, URL: "https://other.example.com", Events: []string{"*"}}
+	mockService.deliveries[60] = &domain.WebhookDelivery{ID: 60, WebhookID: 1, EventID: "evt_1", EventType: domain.EventTaskCreated, Status: domain.DeliveryFailed}
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           interface{}
+		expectedStatus int
+		check          func(t *testing.T, body []byte)
+	}{
+		{
+			name:           "should create a webhook and reveal its secret",
+			method:         "POST",
+			url:            "/v1/webhooks",
+			body:           map[string]interface{}{"url": "https://example.com/hook", "events": []string{"task.*"}},
+			expectedStatus: http.StatusCreated,
+			check: func(t *testing.T, body []byte) {
+				var webhook domain.Webhook
+				json.Unmarshal(body, &webhook)
+				if webhook.ID != 1 || webhook.Secret == "" {
+					t.Errorf("Expected webhook 1 with a secret, got %+v", webhook)
+				}
+			},
+		},
+		{
+			name:           "should reject an unknown event type",
+			method:         "POST",
+			url:            "/v1/webhooks",
+			body:           map[string]interface{}{"url": "https://example.com/hook", "events": []string{"task.exploded"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "should reject a non-http URL",
+			method:         "POST",
+			url:            "/v1/webhooks",
+			body:           map[string]interface{}{"url": "ftp://example.com", "events": []string{"*"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "should get a webhook without its secret",
+			method:         "GET",
+			url:            "/v1/webhooks/1",
+			expectedStatus: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				if bytes.Contains(body, []byte("secret")) {
+					t.Errorf("Expected no secret, got %s", body)
+				}
+			},
+		},
+		{
+			name:           "should not show another user's webhook",
+			method:         "GET",
+			url:            "/v1/webhooks/50",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "should pause a webhook",
+			method:         "PATCH",
+			url:            "/v1/webhooks/1",
+			body:           map[string]interface{}{"active": false},
+			expectedStatus: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				var webhook domain.Webhook
+				json.Unmarshal(body, &webhook)
+				if webhook.Active {
+					t.Error("Expected the webhook to be inactive")
+				}
+			},
+		},
+		{
+			name:           "should list failed deliveries",
+			method:         "GET",
+			url:            "/v1/webhooks/1/deliveries?status=failed",
+			expectedStatus: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				var deliveries []domain.WebhookDelivery
+				json.Unmarshal(body, &deliveries)
+				if len(deliveries) != 1 || deliveries[0].ID != 60 {
+					t.Errorf("Expected delivery 60, got %+v", deliveries)
+				}
+			},
+		},
+		{
+			name:           "should get a delivery",
+			method:         "GET",
+			url:            "/v1/webhooks/1/deliveries/60",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "should return 404 for an unknown delivery",
+			method:         "GET",
+			url:            "/v1/webhooks/1/deliveries/999",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "should replay a delivery",
+			method:         "POST",
+			url:            "/v1/webhooks/1/deliveries/60/replay",
+			expectedStatus: http.StatusAccepted,
+			check: func(t *testing.T, body []byte) {
+				var delivery domain.WebhookDelivery
+				json.Unmarshal(body, &delivery)
+				if delivery.EventID != "evt_1" || delivery.Status != domain.DeliveryPending {
+					t.Errorf("Expected a pending copy of evt_1, got %+v", delivery)
+				}
+			},
+		},
+		{
+			name:           "should reject an invalid webhook ID",
+			method:         "GET",
+			url:            "/v1/webhooks/abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "should delete a webhook",
+			method:         "DELETE",
+			url:            "/v1/webhooks/1",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "should return 404 for a deleted webhook",
+			method:         "DELETE",
+			url:            "/v1/webhooks/1",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			if tt.body != nil {
+				json.NewEncoder(&body).Encode(tt.body)
+			}
+			req := httptest.NewRequest(tt.method, tt.url, &body)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authenticated(req))
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.check != nil {
+				tt.check(t, w.Body.Bytes())
+			}
+		})
+	}
+}
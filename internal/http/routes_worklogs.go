@@ -0,0 +1,179 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"task-manager/internal/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// Worklog handlers
+func (h *Handler) createWorklog(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var req domain.CreateWorklogRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	worklog, err := h.worklogService.CreateWorklog(currentUserID(r), taskID, &req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, worklog)
+}
+
+func (h *Handler) getWorklogs(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	worklogs, err := h.worklogService.GetWorklogs(currentUserID(r), taskID)
+	if err != nil {
+		writeErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, worklogs)
+}
+
+func (h *Handler) updateWorklog(w http.ResponseWriter, r *http.Request) {
+	taskID, worklogID, ok := parseWorklogPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateWorklogRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	worklog, err := h.worklogService.UpdateWorklog(currentUserID(r), taskID, worklogID, &req)
+	if err != nil {
+		writeErrorResponse(w, worklogErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, worklog)
+}
+
+func (h *Handler) deleteWorklog(w http.ResponseWriter, r *http.Request) {
+	taskID, worklogID, ok := parseWorklogPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.worklogService.DeleteWorklog(currentUserID(r), taskID, worklogID); err != nil {
+		writeErrorResponse(w, worklogErrorStatus(err, http.StatusNotFound), err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// startTimer and stopTimer accept an optional body with a note for the
+// worklog
+func (h *Handler) startTimer(w http.ResponseWriter, r *http.Request) {
+	taskID, req, ok := parseTimerRequest(w, r)
+	if !ok {
+		return
+	}
+
+	worklog, err := h.worklogService.StartTimer(currentUserID(r), taskID, req)
+	if err != nil {
+		writeErrorResponse(w, worklogErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, worklog)
+}
+
+func (h *Handler) stopTimer(w http.ResponseWriter, r *http.Request) {
+	taskID, req, ok := parseTimerRequest(w, r)
+	if !ok {
+		return
+	}
+
+	worklog, err := h.worklogService.StopTimer(currentUserID(r), taskID, req)
+	if err != nil {
+		writeErrorResponse(w, worklogErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, worklog)
+}
+
+// getTimeReport sums the logged time by task, category and day. The days
+// are selected with from, to and days as for GET /v1/stats.
+func (h *Handler) getTimeReport(w http.ResponseWriter, r *http.Request) {
+	window, err := parseStatsWindow(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.worklogService.GetTimeReport(currentUserID(r), window)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, report)
+}
+
+// parseWorklogPath reads the task and worklog IDs, writing a 400 response if
+// either is malformed
+func parseWorklogPath(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	vars := mux.Vars(r)
+	taskID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid task ID")
+		return 0, 0, false
+	}
+	worklogID, err := strconv.ParseInt(vars["worklogId"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid worklog ID")
+		return 0, 0, false
+	}
+	return taskID, worklogID, true
+}
+
+func parseTimerRequest(w http.ResponseWriter, r *http.Request) (int64, *domain.TimerRequest, bool) {
+	taskID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid task ID")
+		return 0, nil, false
+	}
+
+	var req domain.TimerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		return 0, nil, false
+	}
+	return taskID, &req, true
+}
+
+func worklogErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, domain.ErrWorklogNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrTimerRunning), errors.Is(err, domain.ErrNoTimerRunning):
+		return http.StatusConflict
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,215 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/domain"
+	"testing"
+	"time"
+)
+
+// F2P Tests for Time Tracking
+// These tests verify the worklog, timer and time report endpoints
+
+type mockWorklogService struct {
+	worklogs map[int64]*domain.Worklog
+	nextID   int64
+}
+
+func newMockWorklogService() *mockWorklogService {
+	return &mockWorklogService{
+		worklogs: make(map[int64]*domain.Worklog),
+		nextID:   1,
+	}
+}
+
+func (m *mockWorklogService) add(worklog *domain.Worklog) *domain.Worklog {
+	worklog.ID = m.nextID
+	m.worklogs[worklog.ID] = worklog
+	m.nextID++
+	return worklog
+}
+
+func (m *mockWorklogService) CreateWorklog(userID, taskID int64, req *domain.CreateWorklogRequest) (*domain.Worklog, error) {
+	start, end, err := req.Span(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	worklog := &domain.Worklog{TaskID: taskID, UserID: userID, StartedAt: start, Note: req.Note}
+	worklog.Finish(end)
+	return m.add(worklog), nil
+}
+
+func (m *mockWorklogService) GetWorklogs(userID, taskID int64) ([]domain.Worklog, error) {
+	worklogs := []domain.Worklog{}
+	for _, worklog := range m.worklogs {
+		if worklog.TaskID == taskID {
+			worklogs = append(worklogs, *worklog)
+		}
+	}
+	return worklogs, nil
+}
+
+func (m *mockWorklogService) UpdateWorklog(userID, taskID, id int64, req *domain.UpdateWorklogRequest) (*domain.Worklog, error) {
+	worklog, ok := m.worklogs[id]
+	if !ok || worklog.TaskID != taskID {
+		return nil, domain.ErrWorklogNotFound
+	}
+	if err := req.Apply(worklog, time.Now()); err != nil {
+		return nil, err
+	}
+	return worklog, nil
+}
+
+func (m *mockWorklogService) DeleteWorklog(userID, taskID, id int64) error {
+	worklog, ok := m.worklogs[id]
+	if !ok || worklog.TaskID != taskID {
+		return domain.ErrWorklogNotFound
+	}
+	delete(m.worklogs, id)
+	return nil
+}
+
+func (m *mockWorklogService) running(userID int64) *domain.Worklog {
+	for _, worklog := range m.worklogs {
+		if worklog.UserID == userID && worklog.IsRunning() {
+			return worklog
+		}
+	}
+	return nil
+}
+
+func (m *mockWorklogService) StartTimer(userID, taskID int64, req *domain.TimerRequest) (*domain.Worklog, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if m.running(userID) != nil {
+		return nil, domain.ErrTimerRunning
+	}
+	return m.add(&domain.Worklog{TaskID: taskID, UserID: userID, StartedAt: time.Now().Add(-time.Minute)}), nil
+}
+
+func (m *mockWorklogService) StopTimer(userID, taskID int64, req *domain.TimerRequest) (*domain.Worklog, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	worklog := m.running(userID)
+	if worklog == nil || worklog.TaskID != taskID {
+		return nil, domain.ErrNoTimerRunning
+	}
+	worklog.Finish(time.Now())
+	if req.Note != nil {
+		worklog.Note = *req.Note
+	}
+	return worklog, nil
+}
+
+func (m *mockWorklogService) GetTimeReport(userID int64, window domain.StatsWindow) (*domain.TimeReport, error) {
+	if err := window.Validate(); err != nil {
+		return nil, err
+	}
+	report := domain.NewTimeReport(window)
+	for _, worklog := range m.worklogs {
+		if worklog.UserID == userID && !worklog.IsRunning() {
+			report.TotalSeconds += worklog.DurationSeconds
+		}
+	}
+	return report, nil
+}
+
+func TestWorklogs_F2P(t *testing.T) {
+	handler := NewHandler(newMockTaskService(), newMockCategoryService(), newMockAuthService(), newMockCommentService(), newMockAttachmentService(), newMockWebhookService(), newMockViewService(), newMockStatusService(), newMockWorklogService(), newTestEventStream())
+	router := handler.SetupRoutes()
+
+	request := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if s, ok := body.(string); ok {
+			buf.WriteString(s)
+		} else if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		req := httptest.NewRequest(method, path, &buf)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authenticated(req))
+		return w
+	}
+
+	t.Run("should log time by duration", func(t *testing.T) {
+		w := request("POST", "/v1/tasks/1/worklogs", map[string]interface{}{"duration_seconds": 5400, "note": "Design review"})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+		var worklog domain.Worklog
+		json.NewDecoder(w.Body).Decode(&worklog)
+		if worklog.DurationSeconds != 5400 || worklog.EndedAt == nil || worklog.EndedAt.Sub(worklog.StartedAt) != 90*time.Minute {
+			t.Errorf("Expected a finished 90 minute worklog, got %+v", worklog)
+		}
+	})
+
+	t.Run("should run one timer at a time", func(t *testing.T) {
+		if w := request("POST", "/v1/tasks/2/timer/start", nil); w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+		if w := request("POST", "/v1/tasks/1/timer/start", nil); w.Code != http.StatusConflict {
+			t.Errorf("Expected a second timer to return %d, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+		}
+		if w := request("POST", "/v1/tasks/1/timer/stop", nil); w.Code != http.StatusConflict {
+			t.Errorf("Expected stopping another task's timer to return %d, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+		}
+
+		w := request("POST", "/v1/tasks/2/timer/stop", map[string]string{"note": "Done for today"})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var worklog domain.Worklog
+		json.NewDecoder(w.Body).Decode(&worklog)
+		if worklog.IsRunning() || worklog.DurationSeconds < 60 || worklog.Note != "Done for today" {
+			t.Errorf("Expected a stopped worklog with its note, got %+v", worklog)
+		}
+	})
+
+	t.Run("should report time", func(t *testing.T) {
+		w := request("GET", "/v1/reports/time?days=7", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var report domain.TimeReport
+		json.NewDecoder(w.Body).Decode(&report)
+		if report.TotalSeconds < 5460 || len(report.Daily) != 7 {
+			t.Errorf("Expected both worklogs over 7 days, got %+v", report)
+		}
+	})
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           interface{}
+		expectedStatus int
+	}{
+		{"should reject an invalid task ID", "POST", "/v1/tasks/abc/worklogs", map[string]interface{}{"duration_seconds": 60}, http.StatusBadRequest},
+		{"should reject invalid JSON", "POST", "/v1/tasks/1/worklogs", "{", http.StatusBadRequest},
+		{"should reject a worklog without a duration or end", "POST", "/v1/tasks/1/worklogs", map[string]interface{}{"started_at": "2026-01-05T09:00:00Z"}, http.StatusBadRequest},
+		{"should reject a worklog ending before it starts", "POST", "/v1/tasks/1/worklogs", map[string]interface{}{"started_at": "2026-01-05T09:00:00Z", "ended_at": "2026-01-05T08:00:00Z"}, http.StatusBadRequest},
+		{"should reject a negative duration", "POST", "/v1/tasks/1/worklogs", map[string]interface{}{"duration_seconds": -60}, http.StatusBadRequest},
+		{"should reject invalid timer JSON", "POST", "/v1/tasks/1/timer/start", "{", http.StatusBadRequest},
+		{"should reject stopping without a timer", "POST", "/v1/tasks/1/timer/stop", nil, http.StatusConflict},
+		{"should list worklogs", "GET", "/v1/tasks/1/worklogs", nil, http.StatusOK},
+		{"should correct a worklog", "PATCH", "/v1/tasks/1/worklogs/1", map[string]interface{}{"note": "Design review with client"}, http.StatusOK},
+		{"should reject an invalid worklog ID", "PATCH", "/v1/tasks/1/worklogs/abc", map[string]interface{}{}, http.StatusBadRequest},
+		{"should not correct a worklog of another task", "PATCH", "/v1/tasks/2/worklogs/1", map[string]interface{}{"note": "x"}, http.StatusNotFound},
+		{"should delete a worklog", "DELETE", "/v1/tasks/1/worklogs/1", nil, http.StatusNoContent},
+		{"should not delete a missing worklog", "DELETE", "/v1/tasks/1/worklogs/1", nil, http.StatusNotFound},
+		{"should reject an invalid report range", "GET", "/v1/reports/time?from=2026-02-01&to=2026-01-01", nil, http.StatusBadRequest},
+		{"should reject an invalid report date", "GET", "/v1/reports/time?from=yesterday", nil, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := request(tt.method, tt.path, tt.body); w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package notify
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"task-manager/internal/domain"
+)
+
+// Notifier delivers reminders to the owners of tasks
+type Notifier interface {
+	Notify(reminder *domain.Reminder) error
+}
+
+type logNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogNotifier returns a Notifier that writes each reminder to w instead
+// of sending it, for development
+func NewLogNotifier(w io.Writer) Notifier {
+	return &logNotifier{logger: log.New(w, "", log.LstdFlags)}
+}
+
+func (n *logNotifier) Notify(reminder *domain.Reminder) error {
+	if err := n.logger.Output(2, fmt.Sprintf("Reminder to %s: %s", reminder.Email, reminder.Subject())); err != nil {
+		return fmt.Errorf("failed to write reminder: %w", err)
+	}
+	return nil
+}
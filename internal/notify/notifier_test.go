@@ -0,0 +1,135 @@
+package notify
+
+import (
+	"bytes"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"task-manager/internal/domain"
+	"testing"
+	"time"
+)
+
+func testReminder() *domain.Reminder {
+	return &domain.Reminder{
+		TaskID:  7,
+		Email:   "owner@example.com",
+		Title:   "Ship\nrelease",
+		Kind:    domain.ReminderOverdue,
+		DueDate: time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC),
+	}
+}
+
+// fakeSMTPServer accepts one session without authentication or TLS and
+// records its envelope and message
+type fakeSMTPServer struct {
+	addr string
+	done chan struct{}
+	from string
+	to   []string
+	data string
+}
+
+func startFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeSMTPServer{addr: listener.Addr().String(), done: make(chan struct{})}
+	go func() {
+		defer close(server.done)
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		server.serve(textproto.NewConn(conn))
+	}()
+	return server
+}
+
+func splitAddr(addr string) (string, int) {
+	host, port, _ := net.SplitHostPort(addr)
+	portNumber, _ := strconv.Atoi(port)
+	return host, portNumber
+}
+
+func (s *fakeSMTPServer) serve(conn *textproto.Conn) {
+	conn.PrintfLine("220 localhost fake SMTP")
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			conn.PrintfLine("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			s.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+			conn.PrintfLine("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			s.to = append(s.to, strings.Trim(line[len("RCPT TO:"):], "<> "))
+			conn.PrintfLine("250 OK")
+		case command == "DATA":
+			conn.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := conn.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.data = string(data)
+			conn.PrintfLine("250 OK")
+		case command == "QUIT":
+			conn.PrintfLine("221 Bye")
+			return
+		default:
+			conn.PrintfLine("250 OK")
+		}
+	}
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	server := startFakeSMTPServer(t)
+	host, port := splitAddr(server.addr)
+
+	notifier := NewSMTPNotifier(host, port, "", "", "tasks@example.com")
+	if err := notifier.Notify(testReminder()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	<-server.done
+
+	if server.from != "tasks@example.com" {
+		t.Errorf("MAIL FROM = %q, want tasks@example.com", server.from)
+	}
+	if len(server.to) != 1 || server.to[0] != "owner@example.com" {
+		t.Errorf("RCPT TO = %v, want [owner@example.com]", server.to)
+	}
+	for _, want := range []string{"To: owner@example.com", "Subject: Overdue: Ship release", "was due on Mon, 02 Mar 2026 09:30 UTC"} {
+		if !strings.Contains(server.data, want) {
+			t.Errorf("Expected the message to contain %q, got:\n%s", want, server.data)
+		}
+	}
+}
+
+func TestSMTPNotifier_Unreachable(t *testing.T) {
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	host, port := splitAddr(listener.Addr().String())
+	listener.Close()
+
+	if err := NewSMTPNotifier(host, port, "", "", "tasks@example.com").Notify(testReminder()); err == nil {
+		t.Error("Expected an error when the server cannot be reached")
+	}
+}
+
+func TestLogNotifier(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewLogNotifier(&buf).Notify(testReminder()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Reminder to owner@example.com: Overdue: Ship release") {
+		t.Errorf("Unexpected log output: %q", buf.String())
+	}
+}
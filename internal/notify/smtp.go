@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"task-manager/internal/domain"
+	"time"
+)
+
+type smtpNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPNotifier returns a Notifier that mails reminders through the SMTP
+// server at host:port. The connection is upgraded with STARTTLS when the
+// server offers it, and authenticates only when a username is given.
+func NewSMTPNotifier(host string, port int, username, password, from string) Notifier {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &smtpNotifier{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		auth: auth,
+		from: from,
+	}
+}
+
+func (n *smtpNotifier) Notify(reminder *domain.Reminder) error {
+	if err := smtp.SendMail(n.addr, n.auth, n.from, []string{reminder.Email}, n.message(reminder)); err != nil {
+		return fmt.Errorf("failed to send reminder to %s: %w", reminder.Email, err)
+	}
+	return nil
+}
+
+// message formats the reminder as a plain text mail
+func (n *smtpNotifier) message(reminder *domain.Reminder) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", reminder.Email)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", reminder.Subject()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(reminder.Body(), "\n", "\r\n"))
+	return msg.Bytes()
+}
@@ -0,0 +1,166 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"task-manager/internal/domain"
+)
+
+type AttachmentRepository interface {
+	Create(attachment *domain.Attachment) error
+	GetByID(taskID, id int64) (*domain.Attachment, error)
+	GetByTaskID(taskID int64) ([]domain.Attachment, error)
+	Delete(taskID, id int64) error
+	// GetPendingBlobDeletions returns up to limit storage keys of deleted
+	// attachments whose blobs have not been removed yet
+	GetPendingBlobDeletions(limit int) ([]string, error)
+	ClearBlobDeletion(storageKey string) error
+}
+
+type attachmentRepository struct {
+	db *sql.DB
+}
+
+func NewAttachmentRepository(db *sql.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) Create(attachment *domain.Attachment) error {
+	query := `
+		INSERT INTO attachments (task_id, user_id, filename, content_type, size, storage_key, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.Exec(query, attachment.TaskID, attachment.UserID, attachment.Filename, attachment.ContentType, attachment.Size, attachment.StorageKey, attachment.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	attachment.ID = id
+	return nil
+}
+
+func (r *attachmentRepository) GetByID(taskID, id int64) (*domain.Attachment, error) {
+	query := `
+		SELECT id, task_id, user_id, filename, content_type, size, storage_key, created_at
+		FROM attachments
+		WHERE id = ? AND task_id = ?
+	`
+
+	var attachment domain.Attachment
+	err := r.db.QueryRow(query, id, taskID).Scan(
+		&attachment.ID,
+		&attachment.TaskID,
+		&attachment.UserID,
+		&attachment.Filename,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.StorageKey,
+		&attachment.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return &attachment, nil
+}
+
+func (r *attachmentRepository) GetByTaskID(taskID int64) ([]domain.Attachment, error) {
+	query := `
+		SELECT id, task_id, user_id, filename, content_type, size, storage_key, created_at
+		FROM attachments
+		WHERE task_id = ?
+		ORDER BY created_at ASC, id ASC
+	`
+
+	rows, err := r.db.Query(query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := []domain.Attachment{}
+	for rows.Next() {
+		var attachment domain.Attachment
+		err := rows.Scan(
+			&attachment.ID,
+			&attachment.TaskID,
+			&attachment.UserID,
+			&attachment.Filename,
+			&attachment.ContentType,
+			&attachment.Size,
+			&attachment.StorageKey,
+			&attachment.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+func (r *attachmentRepository) Delete(taskID, id int64) error {
+	query := `DELETE FROM attachments WHERE id = ? AND task_id = ?`
+
+	result, err := r.db.Exec(query, id, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAttachmentNotFound
+	}
+
+	return nil
+}
+
+func (r *attachmentRepository) GetPendingBlobDeletions(limit int) ([]string, error) {
+	query := `SELECT storage_key FROM blob_deletions ORDER BY created_at ASC LIMIT ?`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending blob deletions: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan blob deletion: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating blob deletions: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (r *attachmentRepository) ClearBlobDeletion(storageKey string) error {
+	if _, err := r.db.Exec(`DELETE FROM blob_deletions WHERE storage_key = ?`, storageKey); err != nil {
+		return fmt.Errorf("failed to clear blob deletion: %w", err)
+	}
+	return nil
+}
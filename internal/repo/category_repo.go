@@ -4,14 +4,19 @@ import (
 	"database/sql"
 	"fmt"
 	"task-manager/internal/domain"
+	"time"
 )
 
 type CategoryRepository interface {
 	Create(category *domain.Category) error
-	GetByID(id int64) (*domain.Category, error)
-	GetAll() ([]domain.Category, error)
+	GetByID(userID, id int64) (*domain.Category, error)
+	GetAll(userID int64) ([]domain.Category, error)
 	Update(category *domain.Category) error
-	Delete(id int64) error
+	Delete(userID, id int64, deletedAt time.Time) error
+	GetTrash(userID int64) ([]domain.Category, error)
+	Restore(userID, id int64) error
+	Purge(userID, id int64) error
+	PurgeDeletedBefore(cutoff time.Time) (int64, error)
 	GetByTaskID(taskID int64) ([]domain.Category, error)
 	AddTaskCategory(taskID, categoryID int64) error
 	RemoveTaskCategory(taskID, categoryID int64) error
@@ -19,7 +24,7 @@ type CategoryRepository interface {
 }
 
 type categoryRepository struct {
-	db *sql.DB
+	db dbtx
 }
 
 func NewCategoryRepository(db *sql.DB) CategoryRepository {
@@ -28,10 +33,10 @@ func NewCategoryRepository(db *sql.DB) CategoryRepository {
 
 func (r *categoryRepository) Create(category *domain.Category) error {
 	query := `
-		INSERT INTO categories (name, description, color, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO categories (user_id, name, description, color, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
 	`
-	result, err := r.db.Exec(query, category.Name, category.Description, category.Color, category.CreatedAt, category.UpdatedAt)
+	result, err := r.db.Exec(query, category.UserID, category.Name, category.Description, category.Color, category.CreatedAt, category.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create category: %w", err)
 	}
@@ -42,23 +47,26 @@ func (r *categoryRepository) Create(category *domain.Category) error {
 	}
 
 	category.ID = id
+	category.Version = 1
 	return nil
 }
 
-func (r *categoryRepository) GetByID(id int64) (*domain.Category, error) {
+func (r *categoryRepository) GetByID(userID, id int64) (*domain.Category, error) {
 	query := `
-		SELECT id, name, description, color, created_at, updated_at
+		SELECT id, user_id, name, description, color, created_at, updated_at, version
 		FROM categories
-		WHERE id = ?
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL
 	`
 	category := &domain.Category{}
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.db.QueryRow(query, id, userID).Scan(
 		&category.ID,
+		&category.UserID,
 		&category.Name,
 		&category.Description,
 		&category.Color,
 		&category.CreatedAt,
 		&category.UpdatedAt,
+		&category.Version,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -70,13 +78,14 @@ func (r *categoryRepository) GetByID(id int64) (*domain.Category, error) {
 	return category, nil
 }
 
-func (r *categoryRepository) GetAll() ([]domain.Category, error) {
+func (r *categoryRepository) GetAll(userID int64) ([]domain.Category, error) {
 	query := `
-		SELECT id, name, description, color, created_at, updated_at
+		SELECT id, user_id, name, description, color, created_at, updated_at, version
 		FROM categories
+		WHERE user_id = ? AND deleted_at IS NULL
 		ORDER BY name ASC
 	`
-	rows, err := r.db.Query(query)
+	rows, err := r.db.Query(query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get categories: %w", err)
 	}
@@ -87,11 +96,13 @@ func (r *categoryRepository) GetAll() ([]domain.Category, error) {
 		var category domain.Category
 		err := rows.Scan(
 			&category.ID,
+			&category.UserID,
 			&category.Name,
 			&category.Description,
 			&category.Color,
 			&category.CreatedAt,
 			&category.UpdatedAt,
+			&category.Version,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan category: %w", err)
@@ -106,13 +117,16 @@ func (r *categoryRepository) GetAll() ([]domain.Category, error) {
 	return categories, nil
 }
 
+// Update saves a category if it is still at category.Version, and moves it to
+// the next version. A category changed since it was read yields
+// ErrVersionConflict.
 func (r *categoryRepository) Update(category *domain.Category) error {
 	query := `
 		UPDATE categories
-		SET name = ?, description = ?, color = ?, updated_at = ?
-		WHERE id = ?
+		SET name = ?, description = ?, color = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL AND version = ?
 	`
-	result, err := r.db.Exec(query, category.Name, category.Description, category.Color, category.UpdatedAt, category.ID)
+	result, err := r.db.Exec(query, category.Name, category.Description, category.Color, category.UpdatedAt, category.ID, category.UserID, category.Version)
 	if err != nil {
 		return fmt.Errorf("failed to update category: %w", err)
 	}
@@ -122,15 +136,26 @@ func (r *categoryRepository) Update(category *domain.Category) error {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
+		var exists bool
+		existsQuery := `SELECT EXISTS (SELECT 1 FROM categories WHERE id = ? AND user_id = ? AND deleted_at IS NULL)`
+		if err := r.db.QueryRow(existsQuery, category.ID, category.UserID).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check category: %w", err)
+		}
+		if exists {
+			return fmt.Errorf("failed to update category: %w", domain.ErrVersionConflict)
+		}
 		return fmt.Errorf("category not found")
 	}
 
+	category.Version++
 	return nil
 }
 
-func (r *categoryRepository) Delete(id int64) error {
-	query := `DELETE FROM categories WHERE id = ?`
-	result, err := r.db.Exec(query, id)
+// Delete moves a category to the trash. Its task assignments are kept so
+// that restoring it puts it back on the same tasks.
+func (r *categoryRepository) Delete(userID, id int64, deletedAt time.Time) error {
+	query := `UPDATE categories SET deleted_at = ? WHERE id = ? AND user_id = ? AND deleted_at IS NULL`
+	result, err := r.db.Exec(query, deletedAt, id, userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete category: %w", err)
 	}
@@ -146,12 +171,102 @@ func (r *categoryRepository) Delete(id int64) error {
 	return nil
 }
 
+// GetTrash returns the user's deleted categories, most recently deleted first
+func (r *categoryRepository) GetTrash(userID int64) ([]domain.Category, error) {
+	query := `
+		SELECT id, user_id, name, description, color, created_at, updated_at, deleted_at, version
+		FROM categories
+		WHERE user_id = ? AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted categories: %w", err)
+	}
+	defer rows.Close()
+
+	categories := []domain.Category{}
+	for rows.Next() {
+		var category domain.Category
+		err := rows.Scan(
+			&category.ID,
+			&category.UserID,
+			&category.Name,
+			&category.Description,
+			&category.Color,
+			&category.CreatedAt,
+			&category.UpdatedAt,
+			&category.DeletedAt,
+			&category.Version,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		categories = append(categories, category)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+func (r *categoryRepository) Restore(userID, id int64) error {
+	query := `UPDATE categories SET deleted_at = NULL, version = version + 1 WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL`
+	result, err := r.db.Exec(query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to restore category: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("category %w", domain.ErrNotInTrash)
+	}
+
+	return nil
+}
+
+// Purge permanently deletes a category from the trash
+func (r *categoryRepository) Purge(userID, id int64) error {
+	query := `DELETE FROM categories WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL`
+	result, err := r.db.Exec(query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to purge category: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("category %w", domain.ErrNotInTrash)
+	}
+
+	return nil
+}
+
+// PurgeDeletedBefore permanently deletes every user's categories that went to
+// the trash before cutoff
+func (r *categoryRepository) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	query := `DELETE FROM categories WHERE deleted_at IS NOT NULL AND deleted_at < ?`
+	result, err := r.db.Exec(query, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
 func (r *categoryRepository) GetByTaskID(taskID int64) ([]domain.Category, error) {
 	query := `
-		SELECT c.id, c.name, c.description, c.color, c.created_at, c.updated_at
+		SELECT c.id, c.user_id, c.name, c.description, c.color, c.created_at, c.updated_at, c.version
 		FROM categories c
 		INNER JOIN task_categories tc ON c.id = tc.category_id
-		WHERE tc.task_id = ?
+		WHERE tc.task_id = ? AND c.deleted_at IS NULL
 		ORDER BY c.name ASC
 	`
 	rows, err := r.db.Query(query, taskID)
@@ -165,11 +280,13 @@ func (r *categoryRepository) GetByTaskID(taskID int64) ([]domain.Category, error
 		var category domain.Category
 		err := rows.Scan(
 			&category.ID,
+			&category.UserID,
 			&category.Name,
 			&category.Description,
 			&category.Color,
 			&category.CreatedAt,
 			&category.UpdatedAt,
+			&category.Version,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan category: %w", err)
@@ -0,0 +1,158 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"task-manager/internal/domain"
+)
+
+type CommentRepository interface {
+	Create(comment *domain.Comment) error
+	GetByID(taskID, id int64) (*domain.Comment, error)
+	GetByTaskID(taskID int64) ([]domain.Comment, error)
+	Update(comment *domain.Comment) error
+	Delete(taskID, id int64) error
+}
+
+type commentRepository struct {
+	db *sql.DB
+}
+
+func NewCommentRepository(db *sql.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+// commentColumns selects a comment together with its author's email
+const commentColumns = `c.id, c.task_id, c.user_id, COALESCE(u.email, ''), c.body, c.created_at, c.updated_at`
+
+func (r *commentRepository) Create(comment *domain.Comment) error {
+	query := `
+		INSERT INTO comments (task_id, user_id, body, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.Exec(query, comment.TaskID, comment.AuthorID, comment.Body, comment.CreatedAt, comment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	comment.ID = id
+	return nil
+}
+
+func (r *commentRepository) GetByID(taskID, id int64) (*domain.Comment, error) {
+	query := `
+		SELECT ` + commentColumns + `
+		FROM comments c
+		LEFT JOIN users u ON u.id = c.user_id
+		WHERE c.id = ? AND c.task_id = ?
+	`
+
+	var comment domain.Comment
+	err := r.db.QueryRow(query, id, taskID).Scan(
+		&comment.ID,
+		&comment.TaskID,
+		&comment.AuthorID,
+		&comment.AuthorEmail,
+		&comment.Body,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	return &comment, nil
+}
+
+func (r *commentRepository) GetByTaskID(taskID int64) ([]domain.Comment, error) {
+	query := `
+		SELECT ` + commentColumns + `
+		FROM comments c
+		LEFT JOIN users u ON u.id = c.user_id
+		WHERE c.task_id = ?
+		ORDER BY c.created_at ASC, c.id ASC
+	`
+
+	rows, err := r.db.Query(query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []domain.Comment{}
+	for rows.Next() {
+		var comment domain.Comment
+		err := rows.Scan(
+			&comment.ID,
+			&comment.TaskID,
+			&comment.AuthorID,
+			&comment.AuthorEmail,
+			&comment.Body,
+			&comment.CreatedAt,
+			&comment.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comments: %w", err)
+	}
+
+	return comments, nil
+}
+
+func (r *commentRepository) Update(comment *domain.Comment) error {
+	query := `
+		UPDATE comments
+		SET body = ?, updated_at = ?
+		WHERE id = ? AND task_id = ?
+	`
+
+	result, err := r.db.Exec(query, comment.Body, comment.UpdatedAt, comment.ID, comment.TaskID)
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrCommentNotFound
+	}
+
+	return nil
+}
+
+func (r *commentRepository) Delete(taskID, id int64) error {
+	query := `DELETE FROM comments WHERE id = ? AND task_id = ?`
+
+	result, err := r.db.Exec(query, id, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrCommentNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package repo
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"task-manager/internal/domain"
+)
+
+// orderKey is one term of a task ORDER BY clause. The expression must never
+// be NULL, and date columns must be cast to TEXT so that the driver returns
+// the raw stored value which can be round-tripped through a cursor.
+type orderKey struct {
+	expr string
+	desc bool
+}
+
+func orderByClause(keys []orderKey) string {
+	terms := make([]string, len(keys))
+	for i, key := range keys {
+		direction := "ASC"
+		if key.desc {
+			direction = "DESC"
+		}
+		terms[i] = key.expr + " " + direction
+	}
+	return "ORDER BY " + strings.Join(terms, ", ")
+}
+
+// orderSignature identifies an order so a cursor cannot be replayed against
+// a listing sorted differently
+func orderSignature(keys []orderKey) string {
+	h := fnv.New32a()
+	h.Write([]byte(orderByClause(keys)))
+	return strconv.FormatUint(uint64(h.Sum32()), 36)
+}
+
+// afterCursorClause builds a keyset condition selecting the rows that sort
+// strictly after the given key values, expanded as
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...
+func afterCursorClause(keys []orderKey, values []interface{}) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+	for i, key := range keys {
+		var terms []string
+		for j := 0; j < i; j++ {
+			terms = append(terms, keys[j].expr+" = ?")
+			args = append(args, values[j])
+		}
+		op := ">"
+		if key.desc {
+			op = "<"
+		}
+		terms = append(terms, key.expr+" "+op+" ?")
+		args = append(args, values[i])
+		clauses = append(clauses, "("+strings.Join(terms, " AND ")+")")
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
+
+// pageCursor is the decoded form of the opaque cursor handed to clients
+type pageCursor struct {
+	Order  string        `json:"o"`
+	Values []interface{} `json:"v"`
+}
+
+func encodeCursor(keys []orderKey, values []interface{}) (string, error) {
+	data, err := json.Marshal(pageCursor{Order: orderSignature(keys), Values: values})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(keys []orderKey, cursor string) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var decoded pageCursor
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+	if decoded.Order != orderSignature(keys) || len(decoded.Values) != len(keys) {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	values := make([]interface{}, len(decoded.Values))
+	for i, value := range decoded.Values {
+		switch v := value.(type) {
+		case json.Number:
+			if n, err := v.Int64(); err == nil {
+				values[i] = n
+			} else if f, err := v.Float64(); err == nil {
+				values[i] = f
+			} else {
+				return nil, domain.ErrInvalidCursor
+			}
+		case string:
+			values[i] = v
+		default:
+			return nil, domain.ErrInvalidCursor
+		}
+	}
+
+	return values, nil
+}
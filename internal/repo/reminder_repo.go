@@ -0,0 +1,106 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"task-manager/internal/domain"
+	"time"
+)
+
+type ReminderRepository interface {
+	// GetDue returns the reminders for open tasks due before now plus lead
+	// that have not been sent and are not waiting to be retried: overdue for
+	// those due before now and due soon for the rest
+	GetDue(now time.Time, lead time.Duration, limit int) ([]domain.Reminder, error)
+	// Claim records a reminder as sent at its SentAt. It returns false if
+	// the reminder already was, or is not yet due to be retried, so that
+	// each one is delivered once.
+	Claim(reminder *domain.Reminder) (bool, error)
+	// RecordFailure records a failed delivery of a claimed reminder, after
+	// which it has failed reminder.Attempts times. It is tried again at
+	// nextAttemptAt, or never when that is nil.
+	RecordFailure(reminder *domain.Reminder, nextAttemptAt *time.Time, lastError string) error
+}
+
+type reminderRepository struct {
+	db *sql.DB
+}
+
+func NewReminderRepository(db *sql.DB) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+func (r *reminderRepository) GetDue(now time.Time, lead time.Duration, limit int) ([]domain.Reminder, error) {
+	// TaskService writes due dates in UTC, whose stored form orders as text,
+	// so now must be bound in UTC too
+	query := `
+		SELECT due.id, due.user_id, due.email, due.title, due.due_date, due.kind, COALESCE(task_reminders.attempts, 0) FROM (
+			SELECT tasks.id, tasks.user_id, users.email, tasks.title, tasks.due_date,
+				CASE WHEN tasks.due_date < ? THEN ? ELSE ? END AS kind
+			FROM tasks
+			JOIN users ON users.id = tasks.user_id
+			WHERE tasks.deleted_at IS NULL AND tasks.due_date IS NOT NULL AND tasks.due_date < ?
+				AND tasks.status NOT IN ` + closedStatusesSQL + `
+		) due
+		LEFT JOIN task_reminders
+			ON task_reminders.task_id = due.id AND task_reminders.kind = due.kind AND task_reminders.due_date = due.due_date
+		WHERE task_reminders.task_id IS NULL OR task_reminders.next_attempt_at <= ?
+		ORDER BY due.due_date, due.id
+		LIMIT ?
+	`
+	now = now.UTC()
+	rows, err := r.db.Query(query, now, domain.ReminderOverdue, domain.ReminderDueSoon, now.Add(lead), now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due reminders: %w", err)
+	}
+	defer rows.Close()
+
+	reminders := []domain.Reminder{}
+	for rows.Next() {
+		var reminder domain.Reminder
+		if err := rows.Scan(&reminder.TaskID, &reminder.UserID, &reminder.Email, &reminder.Title, &reminder.DueDate, &reminder.Kind, &reminder.Attempts); err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		reminders = append(reminders, reminder)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return reminders, nil
+}
+
+func (r *reminderRepository) Claim(reminder *domain.Reminder) (bool, error) {
+	// A failed reminder is claimed again by clearing its next attempt, once
+	// that is due
+	query := `
+		INSERT INTO task_reminders (task_id, kind, due_date, sent_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (task_id, kind, due_date) DO UPDATE SET sent_at = excluded.sent_at, next_attempt_at = NULL
+		WHERE task_reminders.next_attempt_at <= excluded.sent_at
+	`
+	result, err := r.db.Exec(query, reminder.TaskID, reminder.Kind, reminder.DueDate.UTC(), reminder.SentAt.UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to record reminder: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+func (r *reminderRepository) RecordFailure(reminder *domain.Reminder, nextAttemptAt *time.Time, lastError string) error {
+	if nextAttemptAt != nil {
+		next := nextAttemptAt.UTC()
+		nextAttemptAt = &next
+	}
+	query := `
+		UPDATE task_reminders SET attempts = ?, next_attempt_at = ?, last_error = ?
+		WHERE task_id = ? AND kind = ? AND due_date = ?
+	`
+	if _, err := r.db.Exec(query, reminder.Attempts, nextAttemptAt, lastError, reminder.TaskID, reminder.Kind, reminder.DueDate.UTC()); err != nil {
+		return fmt.Errorf("failed to record reminder failure: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package repo
+
+import (
+	"task-manager/internal/domain"
+	"testing"
+	"time"
+)
+
+// createDueTask inserts an open task due at due
+func createDueTask(t *testing.T, tasks TaskRepository, userID int64, title string, due time.Time) *domain.Task {
+	t.Helper()
+	now := time.Now().UTC()
+	task := &domain.Task{
+		UserID:    userID,
+		Title:     title,
+		Status:    domain.StatusTodo,
+		Priority:  domain.PriorityLow,
+		DueDate:   &due,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := tasks.Create(task); err != nil {
+		t.Fatalf("Create() task error = %v", err)
+	}
+	return task
+}
+
+// dueReminders returns the due reminders by task title
+func dueReminders(t *testing.T, reminders ReminderRepository, now time.Time) map[string]domain.Reminder {
+	t.Helper()
+	due, err := reminders.GetDue(now, time.Hour, 10)
+	if err != nil {
+		t.Fatalf("GetDue() error = %v", err)
+	}
+	byTitle := map[string]domain.Reminder{}
+	for _, reminder := range due {
+		byTitle[reminder.Title] = reminder
+	}
+	return byTitle
+}
+
+func TestReminderRepository_Retries(t *testing.T) {
+	db := openTestDB(t)
+	reminders := NewReminderRepository(db)
+	userID := createTestUser(t, db, "alice@example.com")
+
+	now := time.Now().UTC()
+	createDueTask(t, NewTaskRepository(db), userID, "Late", now.Add(-time.Hour))
+
+	reminder := dueReminders(t, reminders, now)["Late"]
+	if reminder.Kind != domain.ReminderOverdue || reminder.Attempts != 0 {
+		t.Fatalf("reminder = %+v, want a first overdue reminder", reminder)
+	}
+	reminder.SentAt = now
+	if claimed, err := reminders.Claim(&reminder); err != nil || !claimed {
+		t.Fatalf("Claim() = %v, %v; want the reminder to be claimed", claimed, err)
+	}
+	if claimed, _ := reminders.Claim(&reminder); claimed {
+		t.Error("a sent reminder should not be claimed twice")
+	}
+
+	reminder.Attempts = 1
+	next := now.Add(10 * time.Minute)
+	if err := reminders.RecordFailure(&reminder, &next, "server unavailable"); err != nil {
+		t.Fatalf("RecordFailure() error = %v", err)
+	}
+	if got := dueReminders(t, reminders, now); len(got) != 0 {
+		t.Errorf("failed reminder should wait for its next attempt, got %v", got)
+	}
+	retry, ok := dueReminders(t, reminders, next)["Late"]
+	if !ok || retry.Attempts != 1 {
+		t.Fatalf("reminder at its next attempt = %+v, %v; want one failed attempt", retry, ok)
+	}
+	retry.SentAt = next
+	if claimed, err := reminders.Claim(&retry); err != nil || !claimed {
+		t.Fatalf("Claim() retry = %v, %v; want the reminder to be claimed again", claimed, err)
+	}
+	if claimed, _ := reminders.Claim(&retry); claimed {
+		t.Error("a retried reminder should not be claimed twice")
+	}
+
+	// Giving up leaves no next attempt
+	retry.Attempts = 2
+	if err := reminders.RecordFailure(&retry, nil, "server unavailable"); err != nil {
+		t.Fatalf("RecordFailure() error = %v", err)
+	}
+	if got := dueReminders(t, reminders, now.Add(24*time.Hour)); len(got) != 0 {
+		t.Errorf("given up reminder should not be due again, got %v", got)
+	}
+}
+
+func TestMigrateTaskTimes_KeepsSentReminders(t *testing.T) {
+	db := openTestDB(t)
+	reminders := NewReminderRepository(db)
+	userID := createTestUser(t, db, "alice@example.com")
+
+	// A reminder sent for a due date stored before times were normalized
+	now := time.Now().UTC()
+	due := now.Add(-time.Hour).In(time.FixedZone("", 3*60*60))
+	task := createDueTask(t, NewTaskRepository(db), userID, "Late", due)
+	if _, err := db.Exec(`INSERT INTO task_reminders (task_id, kind, due_date, sent_at) VALUES (?, ?, ?, ?)`, task.ID, domain.ReminderOverdue, due, now); err != nil {
+		t.Fatalf("failed to record reminder: %v", err)
+	}
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if got := dueReminders(t, reminders, now); len(got) != 0 {
+		t.Errorf("reminder sent before the migration should not be sent again, got %v", got)
+	}
+}
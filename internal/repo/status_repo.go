@@ -0,0 +1,141 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"task-manager/internal/domain"
+)
+
+// closedStatusesSQL selects the keys of the statuses that finish a task
+const closedStatusesSQL = `(SELECT key FROM task_statuses WHERE category = 'closed')`
+
+// StatusRepository stores the workflow shared by all users
+type StatusRepository interface {
+	// GetWorkflow returns the statuses ordered by position
+	GetWorkflow() (*domain.Workflow, error)
+	// SaveWorkflow replaces every status with those of the workflow
+	SaveWorkflow(workflow *domain.Workflow) error
+	// DeleteStatus removes a status and saves the remaining workflow in one
+	// transaction. It fails with domain.ErrStatusInUse if any task is in the
+	// status, including those in the trash.
+	DeleteStatus(key domain.TaskStatus, remaining *domain.Workflow) error
+}
+
+type statusRepository struct {
+	db *sql.DB
+}
+
+func NewStatusRepository(db *sql.DB) StatusRepository {
+	return &statusRepository{db: db}
+}
+
+// migrateStatuses seeds the statuses that existed before the workflow could
+// be configured. Tasks can only be written in a status of the workflow, so a
+// status being deleted cannot gain a task once its deletion has begun.
+func migrateStatuses(db *sql.DB) error {
+	query := `
+	CREATE TRIGGER IF NOT EXISTS trg_tasks_status_insert
+	BEFORE INSERT ON tasks
+	WHEN NEW.status NOT IN (SELECT key FROM task_statuses)
+	BEGIN
+		SELECT RAISE(ABORT, 'status not found');
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS trg_tasks_status_update
+	BEFORE UPDATE OF status ON tasks
+	WHEN NEW.status IS NOT OLD.status AND NEW.status NOT IN (SELECT key FROM task_statuses)
+	BEGIN
+		SELECT RAISE(ABORT, 'status not found');
+	END;
+	`
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create status triggers: %w", err)
+	}
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM task_statuses`).Scan(&count); err != nil {
+		return fmt.Errorf("failed to count statuses: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+	return saveWorkflow(db, domain.DefaultWorkflow())
+}
+
+func (r *statusRepository) GetWorkflow() (*domain.Workflow, error) {
+	rows, err := r.db.Query(`SELECT key, label, category, position, transitions FROM task_statuses ORDER BY position, key`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query statuses: %w", err)
+	}
+	defer rows.Close()
+
+	workflow := &domain.Workflow{Statuses: []domain.StatusDefinition{}}
+	for rows.Next() {
+		var status domain.StatusDefinition
+		var transitions string
+		if err := rows.Scan(&status.Key, &status.Label, &status.Category, &status.Position, &transitions); err != nil {
+			return nil, fmt.Errorf("failed to scan status: %w", err)
+		}
+		if err := json.Unmarshal([]byte(transitions), &status.Transitions); err != nil {
+			return nil, fmt.Errorf("failed to decode transitions of %s: %w", status.Key, err)
+		}
+		workflow.Statuses = append(workflow.Statuses, status)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return workflow, nil
+}
+
+func (r *statusRepository) SaveWorkflow(workflow *domain.Workflow) error {
+	return saveWorkflow(r.db, workflow)
+}
+
+func saveWorkflow(db dbtx, workflow *domain.Workflow) error {
+	return inTx(db, func(tx dbtx) error {
+		if _, err := tx.Exec(`DELETE FROM task_statuses`); err != nil {
+			return fmt.Errorf("failed to clear statuses: %w", err)
+		}
+		for _, status := range workflow.Statuses {
+			transitions, err := json.Marshal(status.Transitions)
+			if err != nil {
+				return fmt.Errorf("failed to encode transitions of %s: %w", status.Key, err)
+			}
+			query := `INSERT INTO task_statuses (key, label, category, position, transitions) VALUES (?, ?, ?, ?, ?)`
+			if _, err := tx.Exec(query, status.Key, status.Label, status.Category, status.Position, string(transitions)); err != nil {
+				return fmt.Errorf("failed to save status %s: %w", status.Key, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *statusRepository) DeleteStatus(key domain.TaskStatus, remaining *domain.Workflow) error {
+	return inTx(r.db, func(tx dbtx) error {
+		// Deleting first takes the write lock, so no task can move into the
+		// status between the check and the commit
+		result, err := tx.Exec(`DELETE FROM task_statuses WHERE key = ?`, key)
+		if err != nil {
+			return fmt.Errorf("failed to delete status %s: %w", key, err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return domain.ErrStatusNotFound
+		}
+
+		var inUse bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM tasks WHERE status = ?)`, key).Scan(&inUse); err != nil {
+			return fmt.Errorf("failed to check tasks in status %s: %w", key, err)
+		}
+		if inUse {
+			return fmt.Errorf("cannot delete status %s: %w", key, domain.ErrStatusInUse)
+		}
+
+		return saveWorkflow(tx, remaining)
+	})
+}
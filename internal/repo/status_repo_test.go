@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"errors"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+func TestStatusRepository_DeleteStatus(t *testing.T) {
+	db := openTestDB(t)
+	statuses := NewStatusRepository(db)
+	tasks := NewTaskRepository(db)
+	userID := createTestUser(t, db, "alice@example.com")
+	otherID := createTestUser(t, db, "bob@example.com")
+
+	// Only another user's task is in doing, which must still keep it
+	task := createTestTask(t, tasks, otherID, "Someone else's", "")
+	task.Status = domain.StatusDoing
+	if err := tasks.Update(task); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	createTestTask(t, tasks, userID, "Mine", "")
+
+	remaining := domain.DefaultWorkflow()
+	remaining.Statuses = append(remaining.Statuses[:1], remaining.Statuses[2:]...)
+	if err := statuses.DeleteStatus(domain.StatusDoing, remaining); !errors.Is(err, domain.ErrStatusInUse) {
+		t.Fatalf("DeleteStatus() error = %v, want ErrStatusInUse", err)
+	}
+	workflow, err := statuses.GetWorkflow()
+	if err != nil {
+		t.Fatalf("GetWorkflow() error = %v", err)
+	}
+	if workflow.Status(domain.StatusDoing) == nil {
+		t.Fatal("a status in use should be kept")
+	}
+
+	if err := statuses.DeleteStatus("later", remaining); !errors.Is(err, domain.ErrStatusNotFound) {
+		t.Errorf("DeleteStatus() error = %v, want ErrStatusNotFound", err)
+	}
+
+	task.Status = domain.StatusTodo
+	if err := tasks.Update(task); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := statuses.DeleteStatus(domain.StatusDoing, remaining); err != nil {
+		t.Fatalf("DeleteStatus() error = %v", err)
+	}
+	if workflow, _ = statuses.GetWorkflow(); workflow.Status(domain.StatusDoing) != nil || len(workflow.Statuses) != 2 {
+		t.Errorf("workflow after deletion = %+v", workflow.Statuses)
+	}
+
+	// A task validated against the old workflow cannot move into the status
+	task.Status = domain.StatusDoing
+	if err := tasks.Update(task); err == nil {
+		t.Error("Update() into a deleted status should fail")
+	}
+}
@@ -0,0 +1,114 @@
+package repo
+
+import (
+	"fmt"
+	"task-manager/internal/domain"
+)
+
+// AddDependency records that taskID is blocked by blockedByID. Adding an
+// existing edge is a no-op.
+func (r *taskRepository) AddDependency(taskID, blockedByID int64) error {
+	query := `INSERT OR IGNORE INTO task_dependencies (task_id, blocked_by_id) VALUES (?, ?)`
+
+	if _, err := r.db.Exec(query, taskID, blockedByID); err != nil {
+		return fmt.Errorf("failed to add dependency: %w", err)
+	}
+	return nil
+}
+
+func (r *taskRepository) RemoveDependency(userID, taskID, blockedByID int64) error {
+	query := `
+		DELETE FROM task_dependencies
+		WHERE task_id = ? AND blocked_by_id = ?
+		AND task_id IN (SELECT id FROM tasks WHERE user_id = ?)
+	`
+
+	result, err := r.db.Exec(query, taskID, blockedByID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove dependency: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrDependencyNotFound
+	}
+
+	return nil
+}
+
+// HasDependencyPath reports whether fromID is blocked, directly or through
+// other tasks, by toID
+func (r *taskRepository) HasDependencyPath(fromID, toID int64) (bool, error) {
+	query := `
+		WITH RECURSIVE reachable(task_id) AS (
+			SELECT ?
+			UNION
+			SELECT d.blocked_by_id FROM task_dependencies d JOIN reachable r ON d.task_id = r.task_id
+		)
+		SELECT EXISTS (SELECT 1 FROM reachable WHERE task_id = ?)
+	`
+
+	var found bool
+	if err := r.db.QueryRow(query, fromID, toID).Scan(&found); err != nil {
+		return false, fmt.Errorf("failed to check dependencies: %w", err)
+	}
+	return found, nil
+}
+
+// loadDependencies fills in the tasks blocking and blocked by task
+func (r *taskRepository) loadDependencies(task *domain.Task) error {
+	blockedByQuery := `
+		SELECT t.id, t.title, t.status
+		FROM task_dependencies d
+		INNER JOIN tasks t ON t.id = d.blocked_by_id
+		WHERE d.task_id = ? AND t.deleted_at IS NULL
+		ORDER BY t.id
+	`
+	blockedBy, err := r.queryTaskRefs(blockedByQuery, task.ID)
+	if err != nil {
+		return err
+	}
+
+	blockingQuery := `
+		SELECT t.id, t.title, t.status
+		FROM task_dependencies d
+		INNER JOIN tasks t ON t.id = d.task_id
+		WHERE d.blocked_by_id = ? AND t.deleted_at IS NULL
+		ORDER BY t.id
+	`
+	blocking, err := r.queryTaskRefs(blockingQuery, task.ID)
+	if err != nil {
+		return err
+	}
+
+	task.BlockedBy = blockedBy
+	task.Blocking = blocking
+	return nil
+}
+
+func (r *taskRepository) queryTaskRefs(query string, args ...interface{}) ([]domain.TaskRef, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	var refs []domain.TaskRef
+	for rows.Next() {
+		var ref domain.TaskRef
+		if err := rows.Scan(&ref.ID, &ref.Title, &ref.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan task dependency: %w", err)
+		}
+		refs = append(refs, ref)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating task dependencies: %w", err)
+	}
+
+	return refs, nil
+}
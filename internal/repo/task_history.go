@@ -0,0 +1,71 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"task-manager/internal/domain"
+)
+
+func (r *taskRepository) AddHistory(entry *domain.TaskHistoryEntry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode history changes: %w", err)
+	}
+
+	query := `
+		INSERT INTO task_history (task_id, user_id, action, changes, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.Exec(query, entry.TaskID, entry.UserID, entry.Action, string(changes), entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add history entry: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	entry.ID = id
+	return nil
+}
+
+// GetHistory returns the history of a task, oldest entry first
+func (r *taskRepository) GetHistory(userID, taskID int64) ([]domain.TaskHistoryEntry, error) {
+	query := `
+		SELECT h.id, h.task_id, h.user_id, u.email, h.action, h.changes, h.created_at
+		FROM task_history h
+		LEFT JOIN users u ON u.id = h.user_id
+		WHERE h.task_id = ? AND h.user_id = ?
+		ORDER BY h.created_at, h.id
+	`
+
+	rows, err := r.db.Query(query, taskID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task history: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []domain.TaskHistoryEntry{}
+	for rows.Next() {
+		var entry domain.TaskHistoryEntry
+		var email sql.NullString
+		var changes string
+		if err := rows.Scan(&entry.ID, &entry.TaskID, &entry.UserID, &email, &entry.Action, &changes, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan history entry: %w", err)
+		}
+		entry.UserEmail = email.String
+		if err := json.Unmarshal([]byte(changes), &entry.Changes); err != nil {
+			return nil, fmt.Errorf("failed to decode history changes: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,314 @@
+package repo
+
+import (
+	"fmt"
+	"strings"
+	"task-manager/internal/domain"
+	"time"
+)
+
+// taskColumns lists the columns scanned by taskRow, in order. The subqueries
+// fetch the series rule, count comments and direct subtasks and sum the
+// finished worklogs so those are loaded without extra round trips.
+const taskColumns = `id, user_id, title, description, status, priority, rank, due_date, parent_id, series_id, created_at, updated_at, completed_at, deleted_at, version,
+			COALESCE((SELECT rrule FROM task_series WHERE task_series.id = tasks.series_id), ''),
+			(SELECT COUNT(*) FROM comments WHERE comments.task_id = tasks.id),
+			(SELECT COALESCE(SUM(duration_seconds), 0) FROM worklogs WHERE worklogs.task_id = tasks.id AND worklogs.ended_at IS NOT NULL),
+			(SELECT COUNT(*) FROM tasks sub WHERE sub.parent_id = tasks.id AND sub.deleted_at IS NULL),
+			(SELECT COUNT(*) FROM tasks sub WHERE sub.parent_id = tasks.id AND sub.deleted_at IS NULL AND sub.status IN ` + closedStatusesSQL + `)`
+
+// taskRow is the scan target for one row of taskColumns, optionally
+// followed by searchColumns
+type taskRow struct {
+	task         domain.Task
+	subtasks     int
+	doneSubtasks int
+	match        *domain.TaskSearchMatch
+}
+
+func (r *taskRow) dest() []interface{} {
+	return []interface{}{
+		&r.task.ID,
+		&r.task.UserID,
+		&r.task.Title,
+		&r.task.Description,
+		&r.task.Status,
+		&r.task.Priority,
+		&r.task.Rank,
+		&r.task.DueDate,
+		&r.task.ParentID,
+		&r.task.SeriesID,
+		&r.task.CreatedAt,
+		&r.task.UpdatedAt,
+		&r.task.CompletedAt,
+		&r.task.DeletedAt,
+		&r.task.Version,
+		&r.task.RRule,
+		&r.task.CommentCount,
+		&r.task.TrackedSeconds,
+		&r.subtasks,
+		&r.doneSubtasks,
+	}
+}
+
+// searchDest returns the scan targets of searchColumns
+func (r *taskRow) searchDest() []interface{} {
+	r.match = &domain.TaskSearchMatch{}
+	return []interface{}{&r.match.Rank, &r.match.Title, &r.match.Snippet}
+}
+
+func (r *taskRow) toTask() *domain.Task {
+	task := r.task
+	task.Progress = domain.NewTaskProgress(r.doneSubtasks, r.subtasks)
+	if r.match != nil {
+		task.Match = &domain.TaskSearchMatch{
+			Rank:    r.match.Rank,
+			Title:   markMatches(r.match.Title),
+			Snippet: markMatches(r.match.Snippet),
+		}
+	}
+	return &task
+}
+
+// buildFilterClause builds the WHERE clause shared by filtered task queries.
+// Tasks in the trash are never listed. Searches are applied by taskSource.
+func buildFilterClause(userID int64, filters *domain.TaskFilters) (string, []interface{}, error) {
+	conditions := []string{"user_id = ?", "deleted_at IS NULL"}
+	args := []interface{}{userID}
+
+	if len(filters.Statuses) > 0 {
+		for _, status := range filters.Statuses {
+			args = append(args, string(status))
+		}
+		conditions = append(conditions, "status IN ("+placeholders(len(filters.Statuses))+")")
+	}
+
+	if len(filters.Priorities) > 0 {
+		for _, priority := range filters.Priorities {
+			args = append(args, string(priority))
+		}
+		conditions = append(conditions, "priority IN ("+placeholders(len(filters.Priorities))+")")
+	}
+
+	if len(filters.CategoryIDs) > 0 {
+		ids := map[int64]bool{}
+		for _, id := range filters.CategoryIDs {
+			if !ids[id] {
+				ids[id] = true
+				args = append(args, id)
+			}
+		}
+		matching := "SELECT COUNT(*) FROM task_categories WHERE task_categories.task_id = tasks.id AND category_id IN (" + placeholders(len(ids)) + ")"
+		if filters.CategoryMode == domain.CategoryMatchAll {
+			conditions = append(conditions, fmt.Sprintf("(%s) = %d", matching, len(ids)))
+		} else {
+			conditions = append(conditions, "("+matching+") > 0")
+		}
+	}
+
+	if filters.HasDueDate != nil {
+		if *filters.HasDueDate {
+			conditions = append(conditions, "due_date IS NOT NULL")
+		} else {
+			conditions = append(conditions, "due_date IS NULL")
+		}
+	}
+
+	if filters.Overdue {
+		conditions = append(conditions, "due_date IS NOT NULL AND due_date < ? AND status NOT IN "+closedStatusesSQL)
+		args = append(args, time.Now().UTC())
+	}
+
+	// Task times are written in UTC, whose stored form orders as text
+	for _, r := range []struct {
+		column        string
+		after, before *time.Time
+	}{
+		{"due_date", filters.DueAfter, filters.DueBefore},
+		{"created_at", filters.CreatedAfter, filters.CreatedBefore},
+		{"updated_at", filters.UpdatedAfter, filters.UpdatedBefore},
+	} {
+		if r.after != nil {
+			conditions = append(conditions, r.column+" >= ?")
+			args = append(args, r.after.UTC())
+		}
+		if r.before != nil {
+			conditions = append(conditions, r.column+" < ?")
+			args = append(args, r.before.UTC())
+		}
+	}
+
+	if filters.Where != nil {
+		clause, whereArgs, err := filterExprClause(filters.Where)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, clause)
+		args = append(args, whereArgs...)
+	}
+
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+// dueDaySQL is the UTC day of the due date
+const dueDaySQL = "substr(due_date, 1, 10)"
+
+// filterExprClause compiles a validated filter expression into a condition.
+// Every condition is true or false, never NULL, so that negating it selects
+// exactly the other tasks.
+func filterExprClause(expr *domain.FilterExpr) (string, []interface{}, error) {
+	var operands []*domain.FilterExpr
+	separator := " AND "
+	switch {
+	case expr.Not != nil:
+		clause, args, err := filterExprClause(expr.Not)
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT " + clause, args, nil
+	case expr.Condition != nil:
+		return filterConditionClause(expr.Condition)
+	case expr.Or != nil:
+		operands = expr.Or
+		separator = " OR "
+	default:
+		operands = expr.And
+	}
+
+	clauses := make([]string, len(operands))
+	var args []interface{}
+	for i, operand := range operands {
+		clause, operandArgs, err := filterExprClause(operand)
+		if err != nil {
+			return "", nil, err
+		}
+		clauses[i] = clause
+		args = append(args, operandArgs...)
+	}
+	return "(" + strings.Join(clauses, separator) + ")", args, nil
+}
+
+func filterConditionClause(condition *domain.FilterCondition) (string, []interface{}, error) {
+	args := make([]interface{}, len(condition.Values))
+	for i, value := range condition.Values {
+		args[i] = value
+	}
+	list := "(" + placeholders(len(args)) + ")"
+
+	switch condition.Field {
+	case domain.FilterStatus:
+		return "(status IN " + list + ")", args, nil
+	case domain.FilterPriority:
+		return "(priority IN " + list + ")", args, nil
+	case domain.FilterCategory:
+		return `EXISTS (
+			SELECT 1 FROM task_categories
+			JOIN categories ON categories.id = task_categories.category_id
+			WHERE task_categories.task_id = tasks.id AND categories.deleted_at IS NULL
+				AND categories.name COLLATE NOCASE IN ` + list + `)`, args, nil
+	case domain.FilterText:
+		terms := make([]string, len(condition.Values))
+		for i, value := range condition.Values {
+			query, err := domain.ParseSearchQuery(value)
+			if err != nil {
+				return "", nil, err
+			}
+			terms[i] = query.MatchExpression()
+		}
+		return "(tasks.id IN (SELECT rowid FROM tasks_fts WHERE tasks_fts MATCH ?))", []interface{}{strings.Join(terms, " OR ")}, nil
+	case domain.FilterDue:
+		op := string(condition.Op)
+		if condition.Op == domain.FilterEqual {
+			op = "="
+		}
+		var terms []string
+		var dueArgs []interface{}
+		for _, value := range condition.Values {
+			if value == domain.FilterNone {
+				terms = append(terms, "due_date IS NULL")
+				continue
+			}
+			terms = append(terms, "(due_date IS NOT NULL AND "+dueDaySQL+" "+op+" ?)")
+			dueArgs = append(dueArgs, value)
+		}
+		return "(" + strings.Join(terms, " OR ") + ")", dueArgs, nil
+	}
+	return "", nil, fmt.Errorf("invalid filter field: %s", condition.Field)
+}
+
+// placeholders returns n comma-separated bind parameters
+func placeholders(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strings.Repeat("?,", n-1) + "?"
+}
+
+// priorityRankSQL maps the priority column to a number so that it can be
+// ordered from least to most urgent
+const priorityRankSQL = `CASE priority
+				WHEN 'critical' THEN 4
+				WHEN 'high' THEN 3
+				WHEN 'medium' THEN 2
+				WHEN 'low' THEN 1
+				ELSE 0
+			END`
+
+// statusRankSQL maps the status column to its position in the workflow,
+// sorting statuses missing from it first
+const statusRankSQL = `COALESCE((SELECT position + 1 FROM task_statuses WHERE task_statuses.key = tasks.status), 0)`
+
+const dueDateNullsLastSQL = "CASE WHEN due_date IS NULL THEN 1 ELSE 0 END"
+
+// defaultTaskOrder sorts by due date (nulls last), then priority (highest
+// first), then creation time
+var defaultTaskOrder = []domain.SortField{
+	{Field: domain.SortByDueDate},
+	{Field: domain.SortByPriority, Descending: true},
+	{Field: domain.SortByCreatedAt},
+}
+
+// taskOrder turns validated sort fields into ORDER BY keys, falling back to
+// defaultTaskOrder. The id is always appended so the order is total, which
+// keyset pagination relies on.
+func taskOrder(sort []domain.SortField) []orderKey {
+	if len(sort) == 0 {
+		sort = defaultTaskOrder
+	}
+
+	var keys []orderKey
+	hasID := false
+	for _, field := range sort {
+		switch field.Field {
+		case domain.SortByID:
+			keys = append(keys, orderKey{expr: "id", desc: field.Descending})
+			hasID = true
+		case domain.SortByTitle:
+			keys = append(keys, orderKey{expr: "title COLLATE NOCASE", desc: field.Descending})
+		case domain.SortByStatus:
+			keys = append(keys, orderKey{expr: statusRankSQL, desc: field.Descending})
+		case domain.SortByPriority:
+			keys = append(keys, orderKey{expr: priorityRankSQL, desc: field.Descending})
+		case domain.SortByDueDate:
+			// Tasks without a due date sort last in either direction
+			keys = append(keys,
+				orderKey{expr: dueDateNullsLastSQL},
+				orderKey{expr: "COALESCE(CAST(due_date AS TEXT), '')", desc: field.Descending},
+			)
+		case domain.SortByCreatedAt:
+			keys = append(keys, orderKey{expr: "CAST(created_at AS TEXT)", desc: field.Descending})
+		case domain.SortByUpdatedAt:
+			keys = append(keys, orderKey{expr: "CAST(updated_at AS TEXT)", desc: field.Descending})
+		case domain.SortByRank:
+			keys = append(keys, orderKey{expr: "tasks.rank", desc: field.Descending})
+		case domain.SortByRelevance:
+			// Only valid with a search, whose join provides the rank
+			keys = append(keys, orderKey{expr: "search.search_rank", desc: field.Descending})
+		}
+	}
+	if !hasID {
+		keys = append(keys, orderKey{expr: "id"})
+	}
+
+	return keys
+}
@@ -0,0 +1,131 @@
+package repo
+
+import (
+	"strings"
+	"task-manager/internal/domain"
+	"testing"
+	"time"
+)
+
+// filterTitles returns the titles of the user's tasks matching filters
+func filterTitles(t *testing.T, tasks TaskRepository, userID int64, filters *domain.TaskFilters) []string {
+	t.Helper()
+	found, err := tasks.GetWithFilters(userID, filters)
+	if err != nil {
+		t.Fatalf("GetWithFilters() error = %v", err)
+	}
+	titles := make([]string, len(found))
+	for i, task := range found {
+		titles[i] = task.Title
+	}
+	return titles
+}
+
+func TestMigrateTaskTimes_NormalizesOffsets(t *testing.T) {
+	db := openTestDB(t)
+	tasks := NewTaskRepository(db)
+	userID := createTestUser(t, db, "alice@example.com")
+
+	// Rows written before times were normalized kept the client's offset and
+	// the server's local time with its monotonic clock reading
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	due := time.Date(2030, 1, 2, 1, 0, 0, 0, zone) // 2030-01-01 23:00 UTC
+	created := time.Now().In(zone)
+	task := &domain.Task{
+		UserID:    userID,
+		Title:     "Offset",
+		Status:    domain.StatusTodo,
+		Priority:  domain.PriorityLow,
+		DueDate:   &due,
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+	if err := tasks.Create(task); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	var dueText, createdText string
+	if err := db.QueryRow(`SELECT CAST(due_date AS TEXT), CAST(created_at AS TEXT) FROM tasks WHERE id = ?`, task.ID).Scan(&dueText, &createdText); err != nil {
+		t.Fatalf("failed to read task times: %v", err)
+	}
+	for _, text := range []string{dueText, createdText} {
+		if !strings.HasSuffix(text, " +0000 UTC") {
+			t.Errorf("stored time %q should be in UTC", text)
+		}
+	}
+
+	stored, err := tasks.GetByID(userID, task.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if !stored.DueDate.Equal(due) || !stored.CreatedAt.Equal(created.Round(0)) {
+		t.Errorf("migration changed the instants: due %v, created %v", stored.DueDate, stored.CreatedAt)
+	}
+
+	after := time.Date(2030, 1, 1, 23, 30, 0, 0, time.UTC)
+	if got := filterTitles(t, tasks, userID, &domain.TaskFilters{DueAfter: &after}); len(got) != 0 {
+		t.Errorf("due_after %v matched a task due at 23:00Z: %v", after, got)
+	}
+	if got := filterTitles(t, tasks, userID, &domain.TaskFilters{DueBefore: &after}); len(got) != 1 {
+		t.Errorf("due_before %v should match the task due at 23:00Z, got %v", after, got)
+	}
+	if got := filterTitles(t, tasks, userID, &domain.TaskFilters{Where: &domain.FilterExpr{Condition: &domain.FilterCondition{
+		Field: domain.FilterDue, Op: domain.FilterEqual, Values: []string{"2030-01-01"},
+	}}}); len(got) != 1 {
+		t.Errorf("due:2030-01-01 should match the UTC day of the due date, got %v", got)
+	}
+}
+
+func TestTaskFilters_TimeRanges(t *testing.T) {
+	db := openTestDB(t)
+	tasks := NewTaskRepository(db)
+	userID := createTestUser(t, db, "alice@example.com")
+
+	// Times written in UTC compare correctly whatever offset the filter uses
+	base := time.Date(2030, 6, 1, 12, 0, 0, 0, time.UTC)
+	for i, title := range []string{"First", "Second", "Third"} {
+		due := base.Add(time.Duration(i) * time.Hour)
+		created := base.Add(time.Duration(i)*time.Minute + 500*time.Millisecond)
+		task := &domain.Task{
+			UserID:    userID,
+			Title:     title,
+			Status:    domain.StatusTodo,
+			Priority:  domain.PriorityLow,
+			DueDate:   &due,
+			CreatedAt: created,
+			UpdatedAt: created,
+		}
+		if err := tasks.Create(task); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	zone := time.FixedZone("UTC-5", -5*60*60)
+	dueAfter := base.Add(30 * time.Minute).In(zone)
+	createdBefore := base.Add(90 * time.Second).In(zone)
+	tests := []struct {
+		name    string
+		filters *domain.TaskFilters
+		want    string
+	}{
+		{"due after", &domain.TaskFilters{DueAfter: &dueAfter}, "Second|Third"},
+		{"created before", &domain.TaskFilters{CreatedBefore: &createdBefore}, "First|Second"},
+		{"created after a fraction of a second", &domain.TaskFilters{CreatedAfter: timePtr(base.Add(time.Minute + 400*time.Millisecond))}, "Second|Third"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(filterTitles(t, tasks, userID, tt.filters), "|"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
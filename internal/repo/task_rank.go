@@ -0,0 +1,65 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"task-manager/internal/domain"
+)
+
+// migrateRanks ranks the tasks created before ranks existed, keeping them in
+// the default listing order so boards start out as the lists were
+func migrateRanks(db *sql.DB) error {
+	return inTx(db, func(tx dbtx) error {
+		var rank string
+		if err := tx.QueryRow(`SELECT COALESCE(MAX(rank), '') FROM tasks`).Scan(&rank); err != nil {
+			return fmt.Errorf("failed to get last rank: %w", err)
+		}
+
+		rows, err := tx.Query(`SELECT id FROM tasks WHERE rank = '' ` + orderByClause(taskOrder(nil)))
+		if err != nil {
+			return fmt.Errorf("failed to query unranked tasks: %w", err)
+		}
+		var ids []int64
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan task id: %w", err)
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating rows: %w", err)
+		}
+
+		for _, id := range ids {
+			if rank, err = domain.RankBetween(rank, ""); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(`UPDATE tasks SET rank = ? WHERE id = ?`, rank, id); err != nil {
+				return fmt.Errorf("failed to rank task: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *taskRepository) GetAdjacentRank(userID int64, status domain.TaskStatus, rank string, excludeID int64, next bool) (string, error) {
+	query := `SELECT COALESCE(MAX(rank), '') FROM tasks WHERE user_id = ? AND status = ? AND deleted_at IS NULL AND id != ?`
+	args := []interface{}{userID, string(status), excludeID}
+	switch {
+	case next:
+		query = `SELECT COALESCE(MIN(rank), '') FROM tasks WHERE user_id = ? AND status = ? AND deleted_at IS NULL AND id != ? AND rank > ?`
+		args = append(args, rank)
+	case rank != "":
+		query += ` AND rank < ?`
+		args = append(args, rank)
+	}
+
+	var adjacent string
+	if err := r.db.QueryRow(query, args...).Scan(&adjacent); err != nil {
+		return "", fmt.Errorf("failed to get adjacent rank: %w", err)
+	}
+	return adjacent, nil
+}
@@ -3,22 +3,57 @@ package repo
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"task-manager/internal/domain"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
 
 type TaskRepository interface {
 	Create(task *domain.Task) error
-	GetByID(id int64) (*domain.Task, error)
-	GetAll() ([]*domain.Task, error)
-	GetWithFilters(filters *domain.TaskFilters) ([]*domain.Task, error)
+	GetByID(userID, id int64) (*domain.Task, error)
+	GetAll(userID int64) ([]*domain.Task, error)
+	GetWithFilters(userID int64, filters *domain.TaskFilters) ([]*domain.Task, error)
+	GetPage(userID int64, filters *domain.TaskFilters, page *domain.PageRequest) (*domain.TaskPage, error)
+	GetChildren(userID, parentID int64) ([]*domain.Task, error)
+	GetDescendants(userID, rootID int64) ([]*domain.Task, error)
+	// GetDetachedSubtasks returns the subtasks that moved up when parentID
+	// went to the trash and have not been moved since
+	GetDetachedSubtasks(userID, parentID int64) ([]*domain.Task, error)
 	Update(task *domain.Task) error
-	Delete(id int64) error
+	Delete(userID, id int64, deletedAt time.Time) error
+	DeleteTree(userID, id int64, deletedAt time.Time) error
+	GetTrash(userID int64) ([]*domain.Task, error)
+	Restore(userID, id int64) error
+	Purge(userID, id int64) error
+	PurgeDeletedBefore(cutoff time.Time) (int64, error)
+	AddDependency(taskID, blockedByID int64) error
+	RemoveDependency(userID, taskID, blockedByID int64) error
+	HasDependencyPath(fromID, toID int64) (bool, error)
+	CreateSeries(series *domain.TaskSeries) error
+	GetSeries(userID, id int64) (*domain.TaskSeries, error)
+	UpdateSeries(series *domain.TaskSeries) error
+	DeleteSeries(userID, id int64) error
+	GetSeriesTasks(userID, seriesID int64) ([]*domain.Task, error)
+	AddHistory(entry *domain.TaskHistoryEntry) error
+	GetHistory(userID, taskID int64) ([]domain.TaskHistoryEntry, error)
+	// GetAdjacentRank returns the rank of the task next to rank in a status
+	// column, the following one if next is set and the preceding one
+	// otherwise, or "" at the end of the column. An empty rank with next
+	// unset finds the last task. The task excludeID is skipped.
+	GetAdjacentRank(userID int64, status domain.TaskStatus, rank string, excludeID int64, next bool) (string, error)
+	// GetStats aggregates the tasks matching filters, counting due tasks
+	// relative to now and tasks by every status of workflow
+	GetStats(userID int64, filters *domain.TaskFilters, workflow *domain.Workflow, window domain.StatsWindow, now time.Time) (*domain.TaskStats, error)
+	// WithTx runs fn with task and category repositories bound to one
+	// transaction, which is committed if fn returns nil and rolled back
+	// otherwise
+	WithTx(fn func(tasks TaskRepository, categories CategoryRepository) error) error
 }
 
 type taskRepository struct {
-	db           *sql.DB
+	db           dbtx
 	categoryRepo CategoryRepository
 }
 
@@ -29,26 +64,69 @@ func NewTaskRepository(db *sql.DB) TaskRepository {
 	}
 }
 
+func (r *taskRepository) WithTx(fn func(tasks TaskRepository, categories CategoryRepository) error) error {
+	return inTx(r.db, func(tx dbtx) error {
+		categories := &categoryRepository{db: tx}
+		return fn(&taskRepository{db: tx, categoryRepo: categories}, categories)
+	})
+}
+
+// Open opens the SQLite database at path with foreign key enforcement enabled,
+// which the ON DELETE clauses in the schema rely on. Writers wait for a
+// running transaction instead of failing with SQLITE_BUSY.
+func Open(path string) (*sql.DB, error) {
+	return sql.Open("sqlite", path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+}
+
 func Migrate(db *sql.DB) error {
 	query := `
+	CREATE TABLE IF NOT EXISTS users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		email VARCHAR(254) NOT NULL UNIQUE,
+		password_hash TEXT NOT NULL,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS task_series (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		rrule TEXT NOT NULL,
+		dtstart DATETIME NOT NULL,
+		title TEXT NOT NULL,
+		description TEXT,
+		priority TEXT NOT NULL DEFAULT 'medium',
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
 	CREATE TABLE IF NOT EXISTS tasks (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
 		title TEXT NOT NULL,
 		description TEXT,
 		status TEXT NOT NULL DEFAULT 'todo',
 		priority TEXT NOT NULL DEFAULT 'medium',
 		due_date DATE,
+		parent_id INTEGER REFERENCES tasks(id) ON DELETE SET NULL,
+		series_id INTEGER REFERENCES task_series(id) ON DELETE SET NULL,
 		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		deleted_at DATETIME,
+		version INTEGER NOT NULL DEFAULT 1
 	);
 	
 	CREATE TABLE IF NOT EXISTS categories (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		name VARCHAR(100) NOT NULL UNIQUE,
+		user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+		name VARCHAR(100) NOT NULL,
 		description TEXT,
 		color VARCHAR(7),
 		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		deleted_at DATETIME,
+		version INTEGER NOT NULL DEFAULT 1,
+		UNIQUE (user_id, name)
 	);
 	
 	CREATE TABLE IF NOT EXISTS task_categories (
@@ -58,6 +136,132 @@ func Migrate(db *sql.DB) error {
 		FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
 		FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE
 	);
+
+	CREATE TABLE IF NOT EXISTS task_dependencies (
+		task_id INTEGER NOT NULL,
+		blocked_by_id INTEGER NOT NULL,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (task_id, blocked_by_id),
+		FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+		FOREIGN KEY (blocked_by_id) REFERENCES tasks(id) ON DELETE CASCADE
+	);
+	
+	CREATE TABLE IF NOT EXISTS comments (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		body TEXT NOT NULL,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+	
+	CREATE TABLE IF NOT EXISTS attachments (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		filename TEXT NOT NULL,
+		content_type TEXT NOT NULL,
+		size INTEGER NOT NULL,
+		storage_key TEXT NOT NULL UNIQUE,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- Blobs of deleted attachments wait here until the blob store removes
+	-- them. The trigger also covers rows removed by ON DELETE CASCADE.
+	CREATE TABLE IF NOT EXISTS blob_deletions (
+		storage_key TEXT PRIMARY KEY,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- task_id has no foreign key so that a task's history outlives the task
+	CREATE TABLE IF NOT EXISTS task_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		task_id INTEGER NOT NULL,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		action TEXT NOT NULL,
+		changes TEXT NOT NULL,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS webhooks (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		url TEXT NOT NULL,
+		secret TEXT NOT NULL,
+		events TEXT NOT NULL,
+		active BOOLEAN NOT NULL DEFAULT 1,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+		event_id TEXT NOT NULL,
+		event_type TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		attempt_count INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at DATETIME,
+		last_status_code INTEGER,
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		delivery_id INTEGER NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+		attempt INTEGER NOT NULL,
+		status_code INTEGER,
+		error TEXT NOT NULL DEFAULT '',
+		duration_ms INTEGER NOT NULL,
+		attempted_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS saved_views (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		name VARCHAR(100) NOT NULL,
+		filters TEXT NOT NULL,
+		visibility TEXT NOT NULL DEFAULT 'private',
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS task_statuses (
+		key TEXT PRIMARY KEY,
+		label VARCHAR(50) NOT NULL,
+		category TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		transitions TEXT NOT NULL DEFAULT '[]'
+	);
+
+	CREATE TABLE IF NOT EXISTS task_reminders (
+		task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		kind TEXT NOT NULL,
+		due_date DATETIME NOT NULL,
+		sent_at DATETIME NOT NULL,
+		PRIMARY KEY (task_id, kind, due_date)
+	);
+
+	CREATE TABLE IF NOT EXISTS worklogs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		started_at DATETIME NOT NULL,
+		ended_at DATETIME,
+		duration_seconds INTEGER NOT NULL DEFAULT 0,
+		note TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TRIGGER IF NOT EXISTS trg_attachments_blob_deletion
+	AFTER DELETE ON attachments
+	BEGIN
+		INSERT OR IGNORE INTO blob_deletions (storage_key) VALUES (OLD.storage_key);
+	END;
 	
 	CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status);
 	CREATE INDEX IF NOT EXISTS idx_tasks_priority ON tasks(priority);
@@ -66,6 +270,19 @@ func Migrate(db *sql.DB) error {
 	CREATE INDEX IF NOT EXISTS idx_categories_name ON categories(name);
 	CREATE INDEX IF NOT EXISTS idx_task_categories_task_id ON task_categories(task_id);
 	CREATE INDEX IF NOT EXISTS idx_task_categories_category_id ON task_categories(category_id);
+	CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocked_by_id ON task_dependencies(blocked_by_id);
+	CREATE INDEX IF NOT EXISTS idx_comments_task_id ON comments(task_id);
+	CREATE INDEX IF NOT EXISTS idx_attachments_task_id ON attachments(task_id);
+	CREATE INDEX IF NOT EXISTS idx_task_history_task_id ON task_history(task_id);
+	CREATE INDEX IF NOT EXISTS idx_webhooks_user_id ON webhooks(user_id);
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries(webhook_id);
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);
+	CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery_id ON webhook_delivery_attempts(delivery_id);
+	CREATE INDEX IF NOT EXISTS idx_saved_views_user_id ON saved_views(user_id);
+	CREATE INDEX IF NOT EXISTS idx_saved_views_visibility ON saved_views(visibility);
+	CREATE INDEX IF NOT EXISTS idx_worklogs_task_id ON worklogs(task_id);
+	CREATE INDEX IF NOT EXISTS idx_worklogs_user_started_at ON worklogs(user_id, started_at);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_worklogs_running ON worklogs(user_id) WHERE ended_at IS NULL;
 	`
 
 	_, err := db.Exec(query)
@@ -73,20 +290,204 @@ func Migrate(db *sql.DB) error {
 		return err
 	}
 
-	// Add due_date column if it doesn't exist (for existing databases)
-	alterQuery := `ALTER TABLE tasks ADD COLUMN due_date DATE;`
-	db.Exec(alterQuery) // Ignore error if column already exists
+	// Add columns that may be missing from existing databases.
+	// Errors are ignored because the column already exists on fresh databases.
+	// Rows created before user accounts existed keep a NULL user_id until
+	// the first user signs up and takes them over.
+	alterQueries := []string{
+		`ALTER TABLE tasks ADD COLUMN due_date DATE;`,
+		`ALTER TABLE tasks ADD COLUMN user_id INTEGER REFERENCES users(id) ON DELETE CASCADE;`,
+		`ALTER TABLE categories ADD COLUMN user_id INTEGER REFERENCES users(id) ON DELETE CASCADE;`,
+		`ALTER TABLE tasks ADD COLUMN parent_id INTEGER REFERENCES tasks(id) ON DELETE SET NULL;`,
+		`ALTER TABLE tasks ADD COLUMN series_id INTEGER REFERENCES task_series(id) ON DELETE SET NULL;`,
+		`ALTER TABLE tasks ADD COLUMN deleted_at DATETIME;`,
+		`ALTER TABLE categories ADD COLUMN deleted_at DATETIME;`,
+		`ALTER TABLE tasks ADD COLUMN version INTEGER NOT NULL DEFAULT 1;`,
+		`ALTER TABLE categories ADD COLUMN version INTEGER NOT NULL DEFAULT 1;`,
+		`ALTER TABLE tasks ADD COLUMN completed_at DATETIME;`,
+		`ALTER TABLE tasks ADD COLUMN rank TEXT NOT NULL DEFAULT '';`,
+		`ALTER TABLE users ADD COLUMN calendar_token_hash TEXT;`,
+		`ALTER TABLE task_reminders ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;`,
+		`ALTER TABLE task_reminders ADD COLUMN next_attempt_at DATETIME;`,
+		`ALTER TABLE task_reminders ADD COLUMN last_error TEXT NOT NULL DEFAULT '';`,
+		// detached_from records the parent a subtask was moved up from when
+		// the parent went to the trash, so restoring it can take it back
+		`ALTER TABLE tasks ADD COLUMN detached_from INTEGER REFERENCES tasks(id) ON DELETE SET NULL;`,
+		// Tasks finished before completed_at existed count as completed at
+		// their last update
+		`UPDATE tasks SET completed_at = updated_at WHERE status = 'done' AND completed_at IS NULL;`,
+		// Receiver responses are no longer kept, since they could expose
+		// internal services; scrub the ones recorded before
+		`UPDATE webhook_delivery_attempts SET response_body = '' WHERE response_body != '';`,
+	}
+	for _, alterQuery := range alterQueries {
+		db.Exec(alterQuery)
+	}
 
-	return nil
+	// Indexes on added columns must be created after the ALTERs above
+	indexQuery := `
+	CREATE INDEX IF NOT EXISTS idx_tasks_user_id ON tasks(user_id);
+	CREATE INDEX IF NOT EXISTS idx_categories_user_id ON categories(user_id);
+	CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks(parent_id);
+	CREATE INDEX IF NOT EXISTS idx_tasks_detached_from ON tasks(detached_from);
+	CREATE INDEX IF NOT EXISTS idx_tasks_series_id ON tasks(series_id);
+	CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks(deleted_at);
+	CREATE INDEX IF NOT EXISTS idx_tasks_completed_at ON tasks(completed_at);
+	CREATE INDEX IF NOT EXISTS idx_tasks_rank ON tasks(user_id, status, rank);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_users_calendar_token_hash ON users(calendar_token_hash) WHERE calendar_token_hash IS NOT NULL;
+	`
+	if _, err := db.Exec(indexQuery); err != nil {
+		return err
+	}
+
+	if err := migrateStatuses(db); err != nil {
+		return err
+	}
+
+	if err := migrateRanks(db); err != nil {
+		return err
+	}
+
+	if err := migrateTaskTimes(db); err != nil {
+		return err
+	}
+
+	return migrateSearch(db)
+}
+
+// utcTimeSuffix ends every time.Time written in UTC. The driver stores times
+// in their String form, which only orders as text when they share a zone.
+const utcTimeSuffix = "% +0000 UTC"
+
+// migrateTaskTimes rewrites task times that were stored in another zone, or
+// with a monotonic clock reading, in UTC so that they compare as text. The
+// due dates of sent reminders are rewritten too, so that they still match
+// their tasks and are not sent again.
+func migrateTaskTimes(db *sql.DB) error {
+	query := `
+		SELECT id, CAST(due_date AS TEXT), CAST(created_at AS TEXT), CAST(updated_at AS TEXT),
+			CAST(completed_at AS TEXT), CAST(deleted_at AS TEXT)
+		FROM tasks
+		WHERE (due_date IS NOT NULL AND CAST(due_date AS TEXT) NOT LIKE ?)
+			OR CAST(created_at AS TEXT) NOT LIKE ?
+			OR CAST(updated_at AS TEXT) NOT LIKE ?
+			OR (completed_at IS NOT NULL AND CAST(completed_at AS TEXT) NOT LIKE ?)
+			OR (deleted_at IS NOT NULL AND CAST(deleted_at AS TEXT) NOT LIKE ?)
+	`
+	rows, err := db.Query(query, utcTimeSuffix, utcTimeSuffix, utcTimeSuffix, utcTimeSuffix, utcTimeSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to find task times to migrate: %w", err)
+	}
+
+	type taskTimes struct {
+		id     int64
+		values [5]sql.NullString
+	}
+	var pending []taskTimes
+	for rows.Next() {
+		var row taskTimes
+		dest := []interface{}{&row.id}
+		for i := range row.values {
+			dest = append(dest, &row.values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan task times: %w", err)
+		}
+		pending = append(pending, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read task times: %w", err)
+	}
+
+	reminderRows, err := db.Query(`SELECT rowid, CAST(due_date AS TEXT) FROM task_reminders WHERE CAST(due_date AS TEXT) NOT LIKE ?`, utcTimeSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to find reminder times to migrate: %w", err)
+	}
+	reminderDues := map[int64]time.Time{}
+	for reminderRows.Next() {
+		var rowID int64
+		var value string
+		if err := reminderRows.Scan(&rowID, &value); err != nil {
+			reminderRows.Close()
+			return fmt.Errorf("failed to scan reminder times: %w", err)
+		}
+		if t, ok := parseStoredTime(value); ok {
+			reminderDues[rowID] = t.UTC()
+		}
+	}
+	reminderRows.Close()
+	if err := reminderRows.Err(); err != nil {
+		return fmt.Errorf("failed to read reminder times: %w", err)
+	}
+
+	return inTx(db, func(tx dbtx) error {
+		for rowID, due := range reminderDues {
+			if _, err := tx.Exec(`UPDATE OR IGNORE task_reminders SET due_date = ? WHERE rowid = ?`, due, rowID); err != nil {
+				return fmt.Errorf("failed to migrate reminder times: %w", err)
+			}
+		}
+
+		for _, row := range pending {
+			args := make([]interface{}, 0, len(row.values)+1)
+			for _, value := range row.values {
+				if !value.Valid {
+					args = append(args, nil)
+				} else if t, ok := parseStoredTime(value.String); ok {
+					args = append(args, t.UTC())
+				} else {
+					// Values that cannot be parsed are left as they are
+					args = append(args, value.String)
+				}
+			}
+			args = append(args, row.id)
+
+			update := `UPDATE tasks SET due_date = ?, created_at = ?, updated_at = ?, completed_at = ?, deleted_at = ? WHERE id = ?`
+			if _, err := tx.Exec(update, args...); err != nil {
+				return fmt.Errorf("failed to migrate task times: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// storedTimeLayouts are the forms times have been written in: the String
+// form of time.Time, with its zone name dropped, and SQLite's own formats
+var storedTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999 -0700",
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// parseStoredTime parses a time as stored by the driver or by SQLite. Times
+// without an offset are in UTC.
+func parseStoredTime(value string) (time.Time, bool) {
+	if i := strings.Index(value, " m="); i >= 0 {
+		value = value[:i]
+	}
+	// The zone name after the offset, such as "CEST", is not needed
+	if fields := strings.Fields(value); len(fields) > 3 {
+		value = strings.Join(fields[:3], " ")
+	}
+	for _, layout := range storedTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
 }
 
 func (r *taskRepository) Create(task *domain.Task) error {
 	query := `
-		INSERT INTO tasks (title, description, status, priority, due_date, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO tasks (user_id, title, description, status, priority, rank, due_date, parent_id, series_id, created_at, updated_at, completed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := r.db.Exec(query, task.Title, task.Description, task.Status, task.Priority, task.DueDate, task.CreatedAt, task.UpdatedAt)
+	result, err := r.db.Exec(query, task.UserID, task.Title, task.Description, task.Status, task.Priority, task.Rank, task.DueDate, task.ParentID, task.SeriesID, task.CreatedAt, task.UpdatedAt, task.CompletedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create task: %w", err)
 	}
@@ -97,27 +498,19 @@ func (r *taskRepository) Create(task *domain.Task) error {
 	}
 
 	task.ID = id
+	task.Version = 1
 	return nil
 }
 
-func (r *taskRepository) GetByID(id int64) (*domain.Task, error) {
+func (r *taskRepository) GetByID(userID, id int64) (*domain.Task, error) {
 	query := `
-		SELECT id, title, description, status, priority, due_date, created_at, updated_at
+		SELECT ` + taskColumns + `
 		FROM tasks
-		WHERE id = ?
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL
 	`
 
-	task := &domain.Task{}
-	err := r.db.QueryRow(query, id).Scan(
-		&task.ID,
-		&task.Title,
-		&task.Description,
-		&task.Status,
-		&task.Priority,
-		&task.DueDate,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-	)
+	row := &taskRow{}
+	err := r.db.QueryRow(query, id, userID).Scan(row.dest()...)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -125,6 +518,7 @@ func (r *taskRepository) GetByID(id int64) (*domain.Task, error) {
 		}
 		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
+	task := row.toTask()
 
 	// Load categories for the task
 	categories, err := r.categoryRepo.GetByTaskID(id)
@@ -133,178 +527,226 @@ func (r *taskRepository) GetByID(id int64) (*domain.Task, error) {
 	}
 	task.Categories = categories
 
+	if err := r.loadDependencies(task); err != nil {
+		return nil, err
+	}
+
 	return task, nil
 }
 
-func (r *taskRepository) GetAll() ([]*domain.Task, error) {
+func (r *taskRepository) GetAll(userID int64) ([]*domain.Task, error) {
+	return r.GetWithFilters(userID, &domain.TaskFilters{})
+}
+
+func (r *taskRepository) GetWithFilters(userID int64, filters *domain.TaskFilters) ([]*domain.Task, error) {
+	source, args, err := taskSource(filters)
+	if err != nil {
+		return nil, err
+	}
+	whereClause, whereArgs, err := buildFilterClause(userID, filters)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, whereArgs...)
+
+	columns := taskColumns
+	if filters.Search != "" {
+		columns += ",\n\t\t\t" + searchColumns
+	}
+
 	query := `
-		SELECT id, title, description, status, priority, due_date, created_at, updated_at
-		FROM tasks
-		ORDER BY 
-			CASE WHEN due_date IS NULL THEN 1 ELSE 0 END,
-			due_date ASC,
-			CASE priority 
-				WHEN 'critical' THEN 4
-				WHEN 'high' THEN 3
-				WHEN 'medium' THEN 2
-				WHEN 'low' THEN 1
-				ELSE 0
-			END DESC,
-			created_at ASC
-	`
+		SELECT ` + columns + `
+		FROM ` + source + `
+		WHERE ` + whereClause + `
+		` + orderByClause(filterOrder(filters))
+
+	return r.queryTasks(query, args...)
+}
 
-	rows, err := r.db.Query(query)
+func (r *taskRepository) GetPage(userID int64, filters *domain.TaskFilters, page *domain.PageRequest) (*domain.TaskPage, error) {
+	if filters == nil {
+		filters = &domain.TaskFilters{}
+	}
+	source, args, err := taskSource(filters)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query tasks: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
+	whereClause, whereArgs, err := buildFilterClause(userID, filters)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, whereArgs...)
+	order := filterOrder(filters)
 
-	var tasks []*domain.Task
-	for rows.Next() {
-		task := &domain.Task{}
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.Priority,
-			&task.DueDate,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
-		}
+	result := &domain.TaskPage{Tasks: []*domain.Task{}}
+	countQuery := "SELECT COUNT(*) FROM " + source + " WHERE " + whereClause
+	if err := r.db.QueryRow(countQuery, args...).Scan(&result.TotalCount); err != nil {
+		return nil, fmt.Errorf("failed to count tasks: %w", err)
+	}
 
-		// Load categories for the task
-		categories, err := r.categoryRepo.GetByTaskID(task.ID)
+	if page.Cursor != "" {
+		values, err := decodeCursor(order, page.Cursor)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get task categories: %w", err)
+			return nil, err
 		}
-		task.Categories = categories
-
-		tasks = append(tasks, task)
+		cursorClause, cursorArgs := afterCursorClause(order, values)
+		whereClause += " AND " + cursorClause
+		args = append(args, cursorArgs...)
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
+	columns := []string{taskColumns}
+	if filters.Search != "" {
+		columns = append(columns, searchColumns)
+	}
+	for _, key := range order {
+		columns = append(columns, key.expr)
 	}
 
-	return tasks, nil
-}
+	query := `
+		SELECT ` + strings.Join(columns, ",\n\t\t\t") + `
+		FROM ` + source + `
+		WHERE ` + whereClause + `
+		` + orderByClause(order) + `
+		LIMIT ?`
+	// Fetch one extra row to learn whether another page follows
+	args = append(args, page.Limit+1)
 
-func (r *taskRepository) GetWithFilters(filters *domain.TaskFilters) ([]*domain.Task, error) {
-	// Build the WHERE clause dynamically
-	whereClause := ""
-	args := []interface{}{}
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task page: %w", err)
+	}
+	defer rows.Close()
 
-	if len(filters.Statuses) > 0 {
-		placeholders := ""
-		for i, status := range filters.Statuses {
-			if i > 0 {
-				placeholders += ","
+	var lastKey []interface{}
+	for rows.Next() {
+		if len(result.Tasks) == page.Limit {
+			cursor, err := encodeCursor(order, lastKey)
+			if err != nil {
+				return nil, err
 			}
-			placeholders += "?"
-			args = append(args, string(status))
+			result.NextCursor = cursor
+			break
 		}
-		whereClause += "status IN (" + placeholders + ")"
-	}
 
-	if len(filters.Priorities) > 0 {
-		if whereClause != "" {
-			whereClause += " AND "
+		row := &taskRow{}
+		key := make([]interface{}, len(order))
+		dest := row.dest()
+		if filters.Search != "" {
+			dest = append(dest, row.searchDest()...)
 		}
-		placeholders := ""
-		for i, priority := range filters.Priorities {
-			if i > 0 {
-				placeholders += ","
-			}
-			placeholders += "?"
-			args = append(args, string(priority))
+		for i := range key {
+			dest = append(dest, &key[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
-		whereClause += "priority IN (" + placeholders + ")"
+		lastKey = key
+
+		result.Tasks = append(result.Tasks, row.toTask())
 	}
 
-	if filters.Search != "" {
-		if whereClause != "" {
-			whereClause += " AND "
-		}
-		searchPattern := "%" + filters.Search + "%"
-		whereClause += "(title LIKE ? OR description LIKE ?)"
-		args = append(args, searchPattern, searchPattern)
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	rows.Close()
+
+	if err := r.loadCategories(result.Tasks); err != nil {
+		return nil, err
 	}
 
-	// Build the complete query
+	return result, nil
+}
+
+func (r *taskRepository) GetChildren(userID, parentID int64) ([]*domain.Task, error) {
 	query := `
-		SELECT id, title, description, status, priority, due_date, created_at, updated_at
-		FROM tasks`
-
-	if whereClause != "" {
-		query += " WHERE " + whereClause
-	}
-
-	query += `
-		ORDER BY 
-			CASE WHEN due_date IS NULL THEN 1 ELSE 0 END,
-			due_date ASC,
-			CASE priority 
-				WHEN 'critical' THEN 4
-				WHEN 'high' THEN 3
-				WHEN 'medium' THEN 2
-				WHEN 'low' THEN 1
-				ELSE 0
-			END DESC,
-			created_at ASC
-	`
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE parent_id = ? AND user_id = ? AND deleted_at IS NULL
+		` + orderByClause(taskOrder(nil))
+
+	return r.queryTasks(query, parentID, userID)
+}
+
+// GetDescendants returns every task below rootID, parents before children
+func (r *taskRepository) GetDescendants(userID, rootID int64) ([]*domain.Task, error) {
+	query := `
+		WITH RECURSIVE subtree(task_id, depth) AS (
+			SELECT id, 0 FROM tasks WHERE parent_id = ? AND user_id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id, s.depth + 1 FROM tasks t JOIN subtree s ON t.parent_id = s.task_id
+			WHERE t.deleted_at IS NULL
+		)
+		SELECT ` + taskColumns + `
+		FROM tasks
+		JOIN subtree ON subtree.task_id = tasks.id
+		ORDER BY subtree.depth, ` + strings.TrimPrefix(orderByClause(taskOrder(nil)), "ORDER BY ")
 
+	return r.queryTasks(query, rootID, userID)
+}
+
+// queryTasks runs a query selecting taskColumns, optionally followed by
+// searchColumns, and loads categories
+func (r *taskRepository) queryTasks(query string, args ...interface{}) ([]*domain.Task, error) {
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query tasks with filters: %w", err)
+		return nil, fmt.Errorf("failed to query tasks: %w", err)
 	}
 	defer rows.Close()
 
-	var tasks []*domain.Task
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task columns: %w", err)
+	}
+
+	tasks := []*domain.Task{}
 	for rows.Next() {
-		task := &domain.Task{}
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.Priority,
-			&task.DueDate,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
+		row := &taskRow{}
+		dest := row.dest()
+		if len(columns) > len(dest) {
+			dest = append(dest, row.searchDest()...)
 		}
-
-		// Load categories for the task
-		categories, err := r.categoryRepo.GetByTaskID(task.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get task categories: %w", err)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
-		task.Categories = categories
-
-		tasks = append(tasks, task)
+		tasks = append(tasks, row.toTask())
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
+	rows.Close()
+
+	if err := r.loadCategories(tasks); err != nil {
+		return nil, err
+	}
 
 	return tasks, nil
 }
 
+// loadCategories attaches categories to tasks once the task query is closed
+func (r *taskRepository) loadCategories(tasks []*domain.Task) error {
+	for _, task := range tasks {
+		categories, err := r.categoryRepo.GetByTaskID(task.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get task categories: %w", err)
+		}
+		task.Categories = categories
+	}
+	return nil
+}
+
+// Update saves a task if it is still at task.Version, and moves it to the
+// next version. A task changed since it was read yields ErrVersionConflict.
 func (r *taskRepository) Update(task *domain.Task) error {
 	query := `
 		UPDATE tasks 
-		SET title = ?, description = ?, status = ?, priority = ?, due_date = ?, updated_at = ?
-		WHERE id = ?
+		SET title = ?, description = ?, status = ?, priority = ?, rank = ?, due_date = ?, parent_id = ?, series_id = ?, updated_at = ?,
+			completed_at = ?, version = version + 1,
+			detached_from = CASE WHEN parent_id IS ? THEN detached_from END
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL AND version = ?
 	`
 
-	result, err := r.db.Exec(query, task.Title, task.Description, task.Status, task.Priority, task.DueDate, task.UpdatedAt, task.ID)
+	result, err := r.db.Exec(query, task.Title, task.Description, task.Status, task.Priority, task.Rank, task.DueDate, task.ParentID, task.SeriesID, task.UpdatedAt, task.CompletedAt, task.ParentID, task.ID, task.UserID, task.Version)
 	if err != nil {
 		return fmt.Errorf("failed to update task: %w", err)
 	}
@@ -315,16 +757,42 @@ func (r *taskRepository) Update(task *domain.Task) error {
 	}
 
 	if rowsAffected == 0 {
+		var exists bool
+		existsQuery := `SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND user_id = ? AND deleted_at IS NULL)`
+		if err := r.db.QueryRow(existsQuery, task.ID, task.UserID).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check task: %w", err)
+		}
+		if exists {
+			return fmt.Errorf("failed to update task: %w", domain.ErrVersionConflict)
+		}
 		return fmt.Errorf("task not found")
 	}
 
+	task.Version++
 	return nil
 }
 
-func (r *taskRepository) Delete(id int64) error {
-	query := `DELETE FROM tasks WHERE id = ?`
+// Delete moves a task to the trash and its subtasks up to the task's own
+// parent, recording the task they were detached from
+func (r *taskRepository) Delete(userID, id int64, deletedAt time.Time) error {
+	return inTx(r.db, func(tx dbtx) error {
+		return deleteTask(tx, userID, id, deletedAt)
+	})
+}
 
-	result, err := r.db.Exec(query, id)
+func deleteTask(tx dbtx, userID, id int64, deletedAt time.Time) error {
+	reparentQuery := `
+		UPDATE tasks
+		SET parent_id = (SELECT parent_id FROM tasks WHERE id = ? AND user_id = ?), detached_from = ?, version = version + 1
+		WHERE parent_id = ? AND user_id = ?
+	`
+	if _, err := tx.Exec(reparentQuery, id, userID, id, id, userID); err != nil {
+		return fmt.Errorf("failed to reparent subtasks: %w", err)
+	}
+
+	query := `UPDATE tasks SET deleted_at = ? WHERE id = ? AND user_id = ? AND deleted_at IS NULL`
+
+	result, err := tx.Exec(query, deletedAt, id, userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
@@ -340,3 +808,32 @@ func (r *taskRepository) Delete(id int64) error {
 
 	return nil
 }
+
+// DeleteTree moves a task together with all of its descendants to the trash
+func (r *taskRepository) DeleteTree(userID, id int64, deletedAt time.Time) error {
+	query := `
+		WITH RECURSIVE subtree(task_id) AS (
+			SELECT id FROM tasks WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.task_id
+			WHERE t.deleted_at IS NULL
+		)
+		UPDATE tasks SET deleted_at = ? WHERE id IN (SELECT task_id FROM subtree)
+	`
+
+	result, err := r.db.Exec(query, id, userID, deletedAt)
+	if err != nil {
+		return fmt.Errorf("failed to delete task tree: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("task not found")
+	}
+
+	return nil
+}
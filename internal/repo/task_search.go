@@ -0,0 +1,110 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"html"
+	"strings"
+	"task-manager/internal/domain"
+)
+
+// migrateSearch creates the full-text index of task titles and descriptions.
+// It reads its content from the tasks table and is kept in sync by triggers;
+// an index created for an existing database is filled from it once.
+func migrateSearch(db *sql.DB) error {
+	var exists int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'tasks_fts'`).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check search index: %w", err)
+	}
+
+	query := `
+	CREATE VIRTUAL TABLE IF NOT EXISTS tasks_fts USING fts5(
+		title,
+		description,
+		content = 'tasks',
+		content_rowid = 'id'
+	);
+
+	CREATE TRIGGER IF NOT EXISTS trg_tasks_fts_insert
+	AFTER INSERT ON tasks
+	BEGIN
+		INSERT INTO tasks_fts (rowid, title, description) VALUES (new.id, new.title, new.description);
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS trg_tasks_fts_delete
+	AFTER DELETE ON tasks
+	BEGIN
+		INSERT INTO tasks_fts (tasks_fts, rowid, title, description) VALUES ('delete', old.id, old.title, old.description);
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS trg_tasks_fts_update
+	AFTER UPDATE OF title, description ON tasks
+	BEGIN
+		INSERT INTO tasks_fts (tasks_fts, rowid, title, description) VALUES ('delete', old.id, old.title, old.description);
+		INSERT INTO tasks_fts (rowid, title, description) VALUES (new.id, new.title, new.description);
+	END;
+	`
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create search index: %w", err)
+	}
+
+	if exists == 0 {
+		if _, err := db.Exec(`INSERT INTO tasks_fts (tasks_fts) VALUES ('rebuild')`); err != nil {
+			return fmt.Errorf("failed to build search index: %w", err)
+		}
+	}
+	return nil
+}
+
+// Matched words are marked with private use characters, which cannot clash
+// with the HTML escaping applied afterwards
+const (
+	matchStart = "\ue000"
+	matchEnd   = "\ue001"
+)
+
+// searchJoin joins the tasks matching a search with their rank and
+// highlights. Title matches weigh ten times as much as description matches.
+const searchJoin = `
+		JOIN (
+			SELECT rowid AS search_task_id,
+				bm25(tasks_fts, 10.0, 1.0) AS search_rank,
+				highlight(tasks_fts, 0, char(57344), char(57345)) AS search_title,
+				snippet(tasks_fts, 1, char(57344), char(57345), '…', 24) AS search_snippet
+			FROM tasks_fts
+			WHERE tasks_fts MATCH ?
+		) search ON search.search_task_id = tasks.id`
+
+// searchColumns are selected after taskColumns from a searchJoin
+const searchColumns = `search.search_rank, search.search_title, COALESCE(search.search_snippet, '')`
+
+// taskSource returns the FROM clause of a filtered task query, which joins
+// the search index when the filters search
+func taskSource(filters *domain.TaskFilters) (string, []interface{}, error) {
+	if filters.Search == "" {
+		return "tasks", nil, nil
+	}
+
+	query, err := domain.ParseSearchQuery(filters.Search)
+	if err != nil {
+		return "", nil, err
+	}
+	return "tasks" + searchJoin, []interface{}{query.MatchExpression()}, nil
+}
+
+// filterOrder returns the ORDER BY keys of a filtered task query. Searches
+// default to the best matches first.
+func filterOrder(filters *domain.TaskFilters) []orderKey {
+	if filters.Search != "" && len(filters.Sort) == 0 {
+		return taskOrder([]domain.SortField{{Field: domain.SortByRelevance}})
+	}
+	return taskOrder(filters.Sort)
+}
+
+// markMatches HTML-escapes highlighted text and turns the match markers into
+// <mark> tags
+func markMatches(text string) string {
+	text = html.EscapeString(text)
+	text = strings.ReplaceAll(text, matchStart, "<mark>")
+	return strings.ReplaceAll(text, matchEnd, "</mark>")
+}
@@ -0,0 +1,202 @@
+package repo
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"task-manager/internal/domain"
+	"testing"
+	"time"
+)
+
+// openTestDB opens a migrated database in a temporary directory
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := Open(filepath.Join(t.TempDir(), "tasks.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	return db
+}
+
+// createTestUser inserts a user to own test tasks and returns its ID
+func createTestUser(t *testing.T, db *sql.DB, email string) int64 {
+	t.Helper()
+	now := time.Now().UTC()
+	user := &domain.User{Email: email, PasswordHash: "x", CreatedAt: now, UpdatedAt: now}
+	if err := NewUserRepository(db).Create(user); err != nil {
+		t.Fatalf("Create() user error = %v", err)
+	}
+	return user.ID
+}
+
+// createTestTask inserts a task with the given title and description
+func createTestTask(t *testing.T, tasks TaskRepository, userID int64, title, description string) *domain.Task {
+	t.Helper()
+	now := time.Now().UTC()
+	task := &domain.Task{
+		UserID:      userID,
+		Title:       title,
+		Description: description,
+		Status:      domain.StatusTodo,
+		Priority:    domain.PriorityMedium,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := tasks.Create(task); err != nil {
+		t.Fatalf("Create() task error = %v", err)
+	}
+	return task
+}
+
+// searchTitles returns the titles of the user's tasks matching search, in
+// the order they are listed
+func searchTitles(t *testing.T, tasks TaskRepository, userID int64, search string) []string {
+	t.Helper()
+	found, err := tasks.GetWithFilters(userID, &domain.TaskFilters{Search: search})
+	if err != nil {
+		t.Fatalf("GetWithFilters(%q) error = %v", search, err)
+	}
+	titles := make([]string, len(found))
+	for i, task := range found {
+		titles[i] = task.Title
+	}
+	return titles
+}
+
+// indexedCount counts the rows of the search index matching an FTS5 query,
+// including those of trashed tasks
+func indexedCount(t *testing.T, db *sql.DB, match string) int {
+	t.Helper()
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM tasks_fts WHERE tasks_fts MATCH ?`, match).Scan(&count); err != nil {
+		t.Fatalf("failed to query search index: %v", err)
+	}
+	return count
+}
+
+func TestTaskSearch_IndexFollowsTasks(t *testing.T) {
+	db := openTestDB(t)
+	tasks := NewTaskRepository(db)
+	userID := createTestUser(t, db, "alice@example.com")
+	otherID := createTestUser(t, db, "bob@example.com")
+
+	task := createTestTask(t, tasks, userID, "Quarterly report", "numbers for finance")
+	createTestTask(t, tasks, otherID, "Quarterly report", "someone else's")
+
+	if got := searchTitles(t, tasks, userID, "quarterly"); len(got) != 1 {
+		t.Fatalf("inserted task: got %v, want one match for this user", got)
+	}
+	if got := searchTitles(t, tasks, userID, "finance"); len(got) != 1 {
+		t.Errorf("description should be indexed, got %v", got)
+	}
+
+	task.Title = "Annual summary"
+	task.Description = "budget"
+	task.UpdatedAt = time.Now().UTC()
+	if err := tasks.Update(task); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got := searchTitles(t, tasks, userID, "quarterly OR finance"); len(got) != 0 {
+		t.Errorf("old text should be removed from the index, got %v", got)
+	}
+	if got := searchTitles(t, tasks, userID, "annual budget"); len(got) != 1 {
+		t.Errorf("new text should be indexed, got %v", got)
+	}
+
+	// Trashed tasks stay indexed so they can be restored, but are not listed
+	if err := tasks.Delete(userID, task.ID, time.Now().UTC()); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if got := searchTitles(t, tasks, userID, "annual"); len(got) != 0 {
+		t.Errorf("trashed task should not be found, got %v", got)
+	}
+	if err := tasks.Restore(userID, task.ID); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if got := searchTitles(t, tasks, userID, "annual"); len(got) != 1 {
+		t.Errorf("restored task should be found again, got %v", got)
+	}
+
+	if err := tasks.Delete(userID, task.ID, time.Now().UTC()); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := tasks.Purge(userID, task.ID); err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if count := indexedCount(t, db, "annual"); count != 0 {
+		t.Errorf("purged task should be removed from the index, found %d rows", count)
+	}
+}
+
+func TestTaskSearch_RanksTitleMatchesFirst(t *testing.T) {
+	db := openTestDB(t)
+	tasks := NewTaskRepository(db)
+	userID := createTestUser(t, db, "alice@example.com")
+
+	createTestTask(t, tasks, userID, "Call the plumber", "mention the invoice")
+	createTestTask(t, tasks, userID, "Pay invoice", "before Friday")
+	createTestTask(t, tasks, userID, "Unrelated", "nothing to see")
+
+	got := searchTitles(t, tasks, userID, "invoice")
+	if strings.Join(got, "|") != "Pay invoice|Call the plumber" {
+		t.Errorf("search order = %v, want the title match first", got)
+	}
+
+	found, err := tasks.GetWithFilters(userID, &domain.TaskFilters{Search: "invoice"})
+	if err != nil {
+		t.Fatalf("GetWithFilters() error = %v", err)
+	}
+	if found[0].Match == nil || found[1].Match == nil || found[0].Match.Rank >= found[1].Match.Rank {
+		t.Errorf("expected ascending bm25 ranks, got %+v and %+v", found[0].Match, found[1].Match)
+	}
+}
+
+func TestTaskSearch_HighlightsAndSnippets(t *testing.T) {
+	db := openTestDB(t)
+	tasks := NewTaskRepository(db)
+	userID := createTestUser(t, db, "alice@example.com")
+
+	long := strings.Repeat("filler ", 40) + "deploy <b>staging</b> " + strings.Repeat("padding ", 40)
+	createTestTask(t, tasks, userID, "Deploy & verify", long)
+
+	found, err := tasks.GetWithFilters(userID, &domain.TaskFilters{Search: "deploy"})
+	if err != nil {
+		t.Fatalf("GetWithFilters() error = %v", err)
+	}
+	if len(found) != 1 || found[0].Match == nil {
+		t.Fatalf("expected one match with highlights, got %+v", found)
+	}
+	match := found[0].Match
+	if match.Title != "<mark>Deploy</mark> &amp; verify" {
+		t.Errorf("title highlight = %q", match.Title)
+	}
+	if !strings.Contains(match.Snippet, "<mark>deploy</mark> &lt;b&gt;staging&lt;/b&gt;") {
+		t.Errorf("snippet should mark the match and escape the text, got %q", match.Snippet)
+	}
+	if !strings.Contains(match.Snippet, "…") || len(match.Snippet) >= len(long) {
+		t.Errorf("snippet should be a shortened excerpt, got %q", match.Snippet)
+	}
+}
+
+func TestTaskSearch_Prefix(t *testing.T) {
+	db := openTestDB(t)
+	tasks := NewTaskRepository(db)
+	userID := createTestUser(t, db, "alice@example.com")
+
+	createTestTask(t, tasks, userID, "Refactor parser", "")
+	createTestTask(t, tasks, userID, "Write reference docs", "")
+	createTestTask(t, tasks, userID, "Prefer tabs", "")
+
+	got := searchTitles(t, tasks, userID, "ref*")
+	if len(got) != 2 || strings.Contains(strings.Join(got, "|"), "Prefer") {
+		t.Errorf("prefix search = %v, want the two words starting with ref", got)
+	}
+	if got := searchTitles(t, tasks, userID, "ref"); len(got) != 0 {
+		t.Errorf("search without * should only match whole words, got %v", got)
+	}
+}
@@ -0,0 +1,111 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"task-manager/internal/domain"
+)
+
+func (r *taskRepository) CreateSeries(series *domain.TaskSeries) error {
+	query := `
+		INSERT INTO task_series (user_id, rrule, dtstart, title, description, priority, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.Exec(query, series.UserID, series.RRule, series.DTStart, series.Title, series.Description, series.Priority, series.CreatedAt, series.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create task series: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	series.ID = id
+	return nil
+}
+
+func (r *taskRepository) GetSeries(userID, id int64) (*domain.TaskSeries, error) {
+	query := `
+		SELECT id, user_id, rrule, dtstart, title, description, priority, created_at, updated_at
+		FROM task_series
+		WHERE id = ? AND user_id = ?
+	`
+
+	series := &domain.TaskSeries{}
+	err := r.db.QueryRow(query, id, userID).Scan(
+		&series.ID,
+		&series.UserID,
+		&series.RRule,
+		&series.DTStart,
+		&series.Title,
+		&series.Description,
+		&series.Priority,
+		&series.CreatedAt,
+		&series.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("task series not found")
+		}
+		return nil, fmt.Errorf("failed to get task series: %w", err)
+	}
+
+	return series, nil
+}
+
+func (r *taskRepository) UpdateSeries(series *domain.TaskSeries) error {
+	query := `
+		UPDATE task_series
+		SET rrule = ?, title = ?, description = ?, priority = ?, updated_at = ?
+		WHERE id = ? AND user_id = ?
+	`
+
+	result, err := r.db.Exec(query, series.RRule, series.Title, series.Description, series.Priority, series.UpdatedAt, series.ID, series.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to update task series: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("task series not found")
+	}
+
+	return nil
+}
+
+// DeleteSeries ends a recurrence. Its occurrences are kept as plain tasks.
+func (r *taskRepository) DeleteSeries(userID, id int64) error {
+	query := `DELETE FROM task_series WHERE id = ? AND user_id = ?`
+
+	result, err := r.db.Exec(query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete task series: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("task series not found")
+	}
+
+	return nil
+}
+
+func (r *taskRepository) GetSeriesTasks(userID, seriesID int64) ([]*domain.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE series_id = ? AND user_id = ? AND deleted_at IS NULL
+		` + orderByClause(taskOrder(nil))
+
+	return r.queryTasks(query, seriesID, userID)
+}
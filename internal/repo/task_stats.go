@@ -0,0 +1,159 @@
+package repo
+
+import (
+	"fmt"
+	"task-manager/internal/domain"
+	"time"
+)
+
+// GetStats aggregates the tasks matching filters. Every query starts from
+// the same matching common table expression, so the counts agree with what
+// GetWithFilters lists.
+func (r *taskRepository) GetStats(userID int64, filters *domain.TaskFilters, workflow *domain.Workflow, window domain.StatsWindow, now time.Time) (*domain.TaskStats, error) {
+	source, sourceArgs, err := taskSource(filters)
+	if err != nil {
+		return nil, err
+	}
+	whereClause, whereArgs, err := buildFilterClause(userID, filters)
+	if err != nil {
+		return nil, err
+	}
+	matching := `
+		WITH matching AS (
+			SELECT tasks.id, status, priority, due_date, created_at, completed_at
+			FROM ` + source + `
+			WHERE ` + whereClause + `
+		)`
+	matchingArgs := append(sourceArgs, whereArgs...)
+	withMatching := func(args ...interface{}) []interface{} {
+		return append(append([]interface{}{}, matchingArgs...), args...)
+	}
+
+	stats := domain.NewTaskStats(window, workflow)
+
+	statusCounts, err := r.countGroups(matching+` SELECT status, COUNT(*) FROM matching GROUP BY status`, withMatching()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks by status: %w", err)
+	}
+	for status, count := range statusCounts {
+		stats.ByStatus[domain.TaskStatus(status)] = count
+		stats.Total += count
+	}
+
+	priorityCounts, err := r.countGroups(matching+` SELECT priority, COUNT(*) FROM matching GROUP BY priority`, withMatching()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks by priority: %w", err)
+	}
+	for priority, count := range priorityCounts {
+		stats.ByPriority[domain.TaskPriority(priority)] = count
+	}
+
+	dayStart, dayEnd, weekStart, weekEnd := domain.DueRanges(now)
+	dueQuery := matching + `
+		SELECT COALESCE(SUM(due_date < ?), 0),
+			COALESCE(SUM(due_date >= ? AND due_date < ?), 0),
+			COALESCE(SUM(due_date >= ? AND due_date < ?), 0)
+		FROM matching
+		WHERE due_date IS NOT NULL AND status NOT IN ` + closedStatusesSQL
+	dueArgs := withMatching(now.UTC(), dayStart, dayEnd, weekStart, weekEnd)
+	if err := r.db.QueryRow(dueQuery, dueArgs...).Scan(&stats.Overdue, &stats.DueToday, &stats.DueThisWeek); err != nil {
+		return nil, fmt.Errorf("failed to count due tasks: %w", err)
+	}
+
+	if err := r.countCategories(stats, userID, matching, withMatching()); err != nil {
+		return nil, err
+	}
+
+	// Task times are written in UTC, so the date prefix of a stored time is
+	// its UTC day and the window bounds are compared in UTC too
+	from, to := window.From.UTC(), window.To.AddDate(0, 0, 1).UTC()
+	for _, daily := range []struct {
+		column string
+		set    func(day *domain.DailyTaskCount, count int)
+	}{
+		{"created_at", func(day *domain.DailyTaskCount, count int) { day.Created = count }},
+		{"completed_at", func(day *domain.DailyTaskCount, count int) { day.Completed = count }},
+	} {
+		query := matching + `
+			SELECT substr(` + daily.column + `, 1, 10), COUNT(*)
+			FROM matching
+			WHERE ` + daily.column + ` >= ? AND ` + daily.column + ` < ?
+			GROUP BY 1`
+		counts, err := r.countGroups(query, withMatching(from, to)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count tasks by day: %w", err)
+		}
+		for date, count := range counts {
+			day, err := time.Parse("2006-01-02", date)
+			if err != nil {
+				continue
+			}
+			if i := int(day.Sub(from).Hours() / 24); i >= 0 && i < len(stats.Daily) {
+				daily.set(&stats.Daily[i], count)
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// countCategories counts the matching tasks in each of the user's
+// categories, busiest first, and those in no category
+func (r *taskRepository) countCategories(stats *domain.TaskStats, userID int64, matching string, args []interface{}) error {
+	query := matching + `
+		SELECT c.id, c.name, COUNT(tc.task_id)
+		FROM categories c
+		LEFT JOIN task_categories tc ON tc.category_id = c.id AND tc.task_id IN (SELECT id FROM matching)
+		WHERE c.user_id = ? AND c.deleted_at IS NULL
+		GROUP BY c.id, c.name
+		ORDER BY COUNT(tc.task_id) DESC, c.name COLLATE NOCASE`
+
+	rows, err := r.db.Query(query, append(args, userID)...)
+	if err != nil {
+		return fmt.Errorf("failed to count tasks by category: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var count domain.CategoryCount
+		if err := rows.Scan(&count.CategoryID, &count.Name, &count.Count); err != nil {
+			return fmt.Errorf("failed to scan category count: %w", err)
+		}
+		stats.ByCategory = append(stats.ByCategory, count)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	uncategorized := matching + `
+		SELECT COUNT(*) FROM matching
+		WHERE NOT EXISTS (
+			SELECT 1 FROM task_categories
+			JOIN categories ON categories.id = task_categories.category_id
+			WHERE task_categories.task_id = matching.id AND categories.deleted_at IS NULL
+		)`
+	if err := r.db.QueryRow(uncategorized, args...).Scan(&stats.Uncategorized); err != nil {
+		return fmt.Errorf("failed to count uncategorized tasks: %w", err)
+	}
+	return nil
+}
+
+// countGroups runs a query selecting a key and a count per row
+func (r *taskRepository) countGroups(query string, args ...interface{}) (map[string]int, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var key string
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			return nil, err
+		}
+		counts[key] = count
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,104 @@
+package repo
+
+import (
+	"fmt"
+	"task-manager/internal/domain"
+	"time"
+)
+
+// GetTrash returns the user's deleted tasks, most recently deleted first
+func (r *taskRepository) GetTrash(userID int64) ([]*domain.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE user_id = ? AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id
+	`
+
+	return r.queryTasks(query, userID)
+}
+
+// GetDetachedSubtasks returns the live subtasks that moved up when parentID
+// went to the trash
+func (r *taskRepository) GetDetachedSubtasks(userID, parentID int64) ([]*domain.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE detached_from = ? AND user_id = ? AND deleted_at IS NULL
+		ORDER BY id
+	`
+
+	return r.queryTasks(query, parentID, userID)
+}
+
+// Restore takes a task out of the trash. A task whose parent is still in
+// the trash is detached from it. The subtasks detached from the task no
+// longer refer to it; the caller reads them with GetDetachedSubtasks first.
+func (r *taskRepository) Restore(userID, id int64) error {
+	return inTx(r.db, func(tx dbtx) error {
+		query := `
+			UPDATE tasks
+			SET deleted_at = NULL,
+				version = version + 1,
+				parent_id = CASE
+					WHEN parent_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL) THEN NULL
+					ELSE parent_id
+				END
+			WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL
+		`
+
+		result, err := tx.Exec(query, id, userID)
+		if err != nil {
+			return fmt.Errorf("failed to restore task: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("task %w", domain.ErrNotInTrash)
+		}
+
+		clearQuery := `UPDATE tasks SET detached_from = NULL WHERE detached_from = ? AND user_id = ?`
+		if _, err := tx.Exec(clearQuery, id, userID); err != nil {
+			return fmt.Errorf("failed to clear detached subtasks: %w", err)
+		}
+		return nil
+	})
+}
+
+// Purge permanently deletes a task from the trash
+func (r *taskRepository) Purge(userID, id int64) error {
+	query := `DELETE FROM tasks WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL`
+
+	result, err := r.db.Exec(query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to purge task: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("task %w", domain.ErrNotInTrash)
+	}
+
+	return nil
+}
+
+// PurgeDeletedBefore permanently deletes every user's tasks that went to the
+// trash before cutoff
+func (r *taskRepository) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	query := `DELETE FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?`
+
+	result, err := r.db.Exec(query, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+
+	return result.RowsAffected()
+}
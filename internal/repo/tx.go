@@ -0,0 +1,37 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// dbtx is the part of *sql.DB and *sql.Tx the repositories query through, so
+// the same repository code can run inside or outside a transaction
+type dbtx interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// inTx runs fn in a new transaction, or in the caller's transaction when db
+// already is one. The transaction is rolled back if fn returns an error.
+func inTx(db dbtx, fn func(tx dbtx) error) error {
+	sqlDB, ok := db.(*sql.DB)
+	if !ok {
+		return fn(db)
+	}
+
+	tx, err := sqlDB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"task-manager/internal/domain"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+type UserRepository interface {
+	Create(user *domain.User) error
+	GetByID(id int64) (*domain.User, error)
+	GetByEmail(email string) (*domain.User, error)
+	// GetByCalendarTokenHash returns the user whose calendar token hashes to
+	// hash
+	GetByCalendarTokenHash(hash string) (*domain.User, error)
+	// SetCalendarTokenHash replaces the user's calendar token hash; an empty
+	// hash revokes the token
+	SetCalendarTokenHash(userID int64, hash string) error
+}
+
+type userRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+// Create inserts the user. The first user also takes over the tasks and
+// categories created before user accounts existed, which have no owner.
+func (r *userRepository) Create(user *domain.User) error {
+	return inTx(r.db, func(tx dbtx) error {
+		query := `
+			INSERT INTO users (email, password_hash, created_at, updated_at)
+			VALUES (?, ?, ?, ?)
+		`
+		result, err := tx.Exec(query, user.Email, user.PasswordHash, user.CreatedAt, user.UpdatedAt)
+		if err != nil {
+			if isUniqueViolation(err) {
+				return domain.ErrEmailTaken
+			}
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get user ID: %w", err)
+		}
+
+		if err := claimOrphanedRows(tx, id); err != nil {
+			return err
+		}
+
+		user.ID = id
+		return nil
+	})
+}
+
+// claimOrphanedRows gives the tasks and categories without an owner to
+// userID if it is the only user
+func claimOrphanedRows(tx dbtx, userID int64) error {
+	var others bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM users WHERE id != ?)`, userID).Scan(&others); err != nil {
+		return fmt.Errorf("failed to count users: %w", err)
+	}
+	if others {
+		return nil
+	}
+
+	for _, table := range []string{"tasks", "categories"} {
+		if _, err := tx.Exec(`UPDATE `+table+` SET user_id = ? WHERE user_id IS NULL`, userID); err != nil {
+			return fmt.Errorf("failed to assign existing %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+func (r *userRepository) GetByID(id int64) (*domain.User, error) {
+	query := `
+		SELECT id, email, password_hash, created_at, updated_at
+		FROM users
+		WHERE id = ?
+	`
+	return r.scanOne(r.db.QueryRow(query, id))
+}
+
+func (r *userRepository) GetByEmail(email string) (*domain.User, error) {
+	query := `
+		SELECT id, email, password_hash, created_at, updated_at
+		FROM users
+		WHERE email = ?
+	`
+	return r.scanOne(r.db.QueryRow(query, email))
+}
+
+func (r *userRepository) GetByCalendarTokenHash(hash string) (*domain.User, error) {
+	query := `
+		SELECT id, email, password_hash, created_at, updated_at
+		FROM users
+		WHERE calendar_token_hash = ?
+	`
+	return r.scanOne(r.db.QueryRow(query, hash))
+}
+
+func (r *userRepository) SetCalendarTokenHash(userID int64, hash string) error {
+	var value interface{}
+	if hash != "" {
+		value = hash
+	}
+
+	result, err := r.db.Exec(`UPDATE users SET calendar_token_hash = ? WHERE id = ?`, value, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update calendar token: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) scanOne(row *sql.Row) (*domain.User, error) {
+	user := &domain.User{}
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.PasswordHash,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// isUniqueViolation reports whether err is SQLite rejecting a duplicate value
+// for a UNIQUE column
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
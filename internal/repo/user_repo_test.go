@@ -0,0 +1,35 @@
+package repo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUserRepository_FirstUserClaimsOrphanedRows(t *testing.T) {
+	db := openTestDB(t)
+
+	// Rows stored before user accounts existed have no owner
+	now := time.Now().UTC()
+	if _, err := db.Exec(`INSERT INTO tasks (title, description, status, priority, created_at, updated_at) VALUES ('Old task', '', 'todo', 'low', ?, ?)`, now, now); err != nil {
+		t.Fatalf("failed to insert task: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO categories (name, description, color, created_at, updated_at) VALUES ('Old category', '', '', ?, ?)`, now, now); err != nil {
+		t.Fatalf("failed to insert category: %v", err)
+	}
+
+	first := createTestUser(t, db, "alice@example.com")
+	second := createTestUser(t, db, "bob@example.com")
+
+	tasks, err := NewTaskRepository(db).GetAll(first)
+	if err != nil || len(tasks) != 1 || tasks[0].Title != "Old task" {
+		t.Errorf("first user's tasks = %v, %v; want the old task", tasks, err)
+	}
+	categories, err := NewCategoryRepository(db).GetAll(first)
+	if err != nil || len(categories) != 1 || categories[0].Name != "Old category" {
+		t.Errorf("first user's categories = %v, %v; want the old category", categories, err)
+	}
+
+	if tasks, _ := NewTaskRepository(db).GetAll(second); len(tasks) != 0 {
+		t.Errorf("second user's tasks = %v, want none", tasks)
+	}
+}
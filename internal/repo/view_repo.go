@@ -0,0 +1,157 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"task-manager/internal/domain"
+)
+
+type ViewRepository interface {
+	Create(view *domain.SavedView) error
+	// GetByID returns a view the user owns or that is shared
+	GetByID(userID, id int64) (*domain.SavedView, error)
+	// GetAll returns the user's views followed by those shared by others
+	GetAll(userID int64) ([]domain.SavedView, error)
+	Update(view *domain.SavedView) error
+	Delete(userID, id int64) error
+}
+
+type viewRepository struct {
+	db *sql.DB
+}
+
+func NewViewRepository(db *sql.DB) ViewRepository {
+	return &viewRepository{db: db}
+}
+
+const viewColumns = `id, user_id, name, filters, visibility, created_at, updated_at`
+
+func scanView(row interface{ Scan(...interface{}) error }) (*domain.SavedView, error) {
+	var view domain.SavedView
+	var filters string
+	err := row.Scan(
+		&view.ID,
+		&view.OwnerID,
+		&view.Name,
+		&filters,
+		&view.Visibility,
+		&view.CreatedAt,
+		&view.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(filters), &view.Filters); err != nil {
+		return nil, fmt.Errorf("failed to decode view filters: %w", err)
+	}
+	return &view, nil
+}
+
+func (r *viewRepository) Create(view *domain.SavedView) error {
+	filters, err := json.Marshal(view.Filters)
+	if err != nil {
+		return fmt.Errorf("failed to encode view filters: %w", err)
+	}
+
+	query := `
+		INSERT INTO saved_views (user_id, name, filters, visibility, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query, view.OwnerID, view.Name, string(filters), view.Visibility, view.CreatedAt, view.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create view: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	view.ID = id
+	return nil
+}
+
+func (r *viewRepository) GetByID(userID, id int64) (*domain.SavedView, error) {
+	query := `SELECT ` + viewColumns + ` FROM saved_views WHERE id = ? AND (user_id = ? OR visibility = ?)`
+
+	view, err := scanView(r.db.QueryRow(query, id, userID, domain.ViewShared))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrViewNotFound
+		}
+		return nil, fmt.Errorf("failed to get view: %w", err)
+	}
+
+	return view, nil
+}
+
+func (r *viewRepository) GetAll(userID int64) ([]domain.SavedView, error) {
+	query := `
+		SELECT ` + viewColumns + `
+		FROM saved_views
+		WHERE user_id = ? OR visibility = ?
+		ORDER BY user_id != ?, name COLLATE NOCASE, id
+	`
+
+	rows, err := r.db.Query(query, userID, domain.ViewShared, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get views: %w", err)
+	}
+	defer rows.Close()
+
+	views := []domain.SavedView{}
+	for rows.Next() {
+		view, err := scanView(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan view: %w", err)
+		}
+		views = append(views, *view)
+	}
+
+	return views, rows.Err()
+}
+
+func (r *viewRepository) Update(view *domain.SavedView) error {
+	filters, err := json.Marshal(view.Filters)
+	if err != nil {
+		return fmt.Errorf("failed to encode view filters: %w", err)
+	}
+
+	query := `
+		UPDATE saved_views
+		SET name = ?, filters = ?, visibility = ?, updated_at = ?
+		WHERE id = ? AND user_id = ?
+	`
+	result, err := r.db.Exec(query, view.Name, string(filters), view.Visibility, view.UpdatedAt, view.ID, view.OwnerID)
+	if err != nil {
+		return fmt.Errorf("failed to update view: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrViewNotFound
+	}
+
+	return nil
+}
+
+func (r *viewRepository) Delete(userID, id int64) error {
+	result, err := r.db.Exec(`DELETE FROM saved_views WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete view: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrViewNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,355 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"task-manager/internal/domain"
+	"time"
+)
+
+type WebhookRepository interface {
+	Create(webhook *domain.Webhook) error
+	GetByID(userID, id int64) (*domain.Webhook, error)
+	GetAll(userID int64) ([]domain.Webhook, error)
+	Update(webhook *domain.Webhook) error
+	Delete(userID, id int64) error
+	CreateDelivery(delivery *domain.WebhookDelivery) error
+	// GetDeliveries returns up to limit deliveries of a webhook, newest
+	// first, optionally only those with status
+	GetDeliveries(webhookID int64, status domain.WebhookDeliveryStatus, limit int) ([]domain.WebhookDelivery, error)
+	// GetDelivery returns a delivery together with its attempts
+	GetDelivery(webhookID, id int64) (*domain.WebhookDelivery, error)
+	// GetDueDeliveries returns up to limit pending deliveries to active
+	// webhooks whose next attempt is due at now, oldest first
+	GetDueDeliveries(now time.Time, limit int) ([]domain.WebhookDispatch, error)
+	// RecordAttempt stores an attempt together with the delivery state it
+	// resulted in
+	RecordAttempt(delivery *domain.WebhookDelivery, attempt *domain.WebhookDeliveryAttempt) error
+}
+
+type webhookRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+const webhookColumns = `id, user_id, url, secret, events, active, created_at, updated_at`
+
+func scanWebhook(row interface{ Scan(...interface{}) error }) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+	var events string
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.UserID,
+		&webhook.URL,
+		&webhook.Secret,
+		&events,
+		&webhook.Active,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(events), &webhook.Events); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook events: %w", err)
+	}
+	return &webhook, nil
+}
+
+func (r *webhookRepository) Create(webhook *domain.Webhook) error {
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook events: %w", err)
+	}
+
+	query := `
+		INSERT INTO webhooks (user_id, url, secret, events, active, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query, webhook.UserID, webhook.URL, webhook.Secret, string(events), webhook.Active, webhook.CreatedAt, webhook.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	webhook.ID = id
+	return nil
+}
+
+func (r *webhookRepository) GetByID(userID, id int64) (*domain.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = ? AND user_id = ?`
+
+	webhook, err := scanWebhook(r.db.QueryRow(query, id, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+func (r *webhookRepository) GetAll(userID int64) ([]domain.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE user_id = ? ORDER BY id`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []domain.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (r *webhookRepository) Update(webhook *domain.Webhook) error {
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook events: %w", err)
+	}
+
+	query := `
+		UPDATE webhooks
+		SET url = ?, secret = ?, events = ?, active = ?, updated_at = ?
+		WHERE id = ? AND user_id = ?
+	`
+	result, err := r.db.Exec(query, webhook.URL, webhook.Secret, string(events), webhook.Active, webhook.UpdatedAt, webhook.ID, webhook.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) Delete(userID, id int64) error {
+	result, err := r.db.Exec(`DELETE FROM webhooks WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+const deliveryColumns = `id, webhook_id, event_id, event_type, payload, status, attempt_count, next_attempt_at, last_status_code, last_error, created_at, updated_at`
+
+func scanDelivery(row interface{ Scan(...interface{}) error }) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	var payload string
+	var statusCode sql.NullInt64
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&payload,
+		&delivery.Status,
+		&delivery.AttemptCount,
+		&delivery.NextAttemptAt,
+		&statusCode,
+		&delivery.LastError,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	delivery.Payload = json.RawMessage(payload)
+	if statusCode.Valid {
+		code := int(statusCode.Int64)
+		delivery.LastStatusCode = &code
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) CreateDelivery(delivery *domain.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, attempt_count, next_attempt_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query, delivery.WebhookID, delivery.EventID, delivery.EventType, string(delivery.Payload), delivery.Status, delivery.AttemptCount, delivery.NextAttemptAt, delivery.CreatedAt, delivery.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	delivery.ID = id
+	return nil
+}
+
+func (r *webhookRepository) GetDeliveries(webhookID int64, status domain.WebhookDeliveryStatus, limit int) ([]domain.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE webhook_id = ?`
+	args := []interface{}{webhookID}
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []domain.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (r *webhookRepository) GetDelivery(webhookID, id int64) (*domain.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE id = ? AND webhook_id = ?`
+
+	delivery, err := scanDelivery(r.db.QueryRow(query, id, webhookID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrDeliveryNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	attemptsQuery := `
+		SELECT id, delivery_id, attempt, status_code, error, duration_ms, attempted_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = ?
+		ORDER BY attempt, id
+	`
+	rows, err := r.db.Query(attemptsQuery, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delivery attempts: %w", err)
+	}
+	defer rows.Close()
+
+	delivery.Attempts = []domain.WebhookDeliveryAttempt{}
+	for rows.Next() {
+		var attempt domain.WebhookDeliveryAttempt
+		var statusCode sql.NullInt64
+		err := rows.Scan(&attempt.ID, &attempt.DeliveryID, &attempt.Attempt, &statusCode, &attempt.Error, &attempt.DurationMS, &attempt.AttemptedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan delivery attempt: %w", err)
+		}
+		if statusCode.Valid {
+			code := int(statusCode.Int64)
+			attempt.StatusCode = &code
+		}
+		delivery.Attempts = append(delivery.Attempts, attempt)
+	}
+
+	return delivery, rows.Err()
+}
+
+func (r *webhookRepository) GetDueDeliveries(now time.Time, limit int) ([]domain.WebhookDispatch, error) {
+	query := `
+		SELECT d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempt_count, d.next_attempt_at,
+			d.last_status_code, d.last_error, d.created_at, d.updated_at,
+			w.id, w.user_id, w.url, w.secret, w.events, w.active, w.created_at, w.updated_at
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = ? AND d.next_attempt_at <= ? AND w.active = 1
+		ORDER BY d.next_attempt_at, d.id
+		LIMIT ?
+	`
+	rows, err := r.db.Query(query, domain.DeliveryPending, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	dispatches := []domain.WebhookDispatch{}
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		var webhook domain.Webhook
+		var payload, events string
+		var statusCode sql.NullInt64
+		err := rows.Scan(
+			&delivery.ID, &delivery.WebhookID, &delivery.EventID, &delivery.EventType, &payload, &delivery.Status,
+			&delivery.AttemptCount, &delivery.NextAttemptAt, &statusCode, &delivery.LastError, &delivery.CreatedAt, &delivery.UpdatedAt,
+			&webhook.ID, &webhook.UserID, &webhook.URL, &webhook.Secret, &events, &webhook.Active, &webhook.CreatedAt, &webhook.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		delivery.Payload = json.RawMessage(payload)
+		if statusCode.Valid {
+			code := int(statusCode.Int64)
+			delivery.LastStatusCode = &code
+		}
+		if err := json.Unmarshal([]byte(events), &webhook.Events); err != nil {
+			return nil, fmt.Errorf("failed to decode webhook events: %w", err)
+		}
+		dispatches = append(dispatches, domain.WebhookDispatch{Delivery: &delivery, Webhook: &webhook})
+	}
+
+	return dispatches, rows.Err()
+}
+
+func (r *webhookRepository) RecordAttempt(delivery *domain.WebhookDelivery, attempt *domain.WebhookDeliveryAttempt) error {
+	return inTx(r.db, func(tx dbtx) error {
+		attemptQuery := `
+			INSERT INTO webhook_delivery_attempts (delivery_id, attempt, status_code, error, duration_ms, attempted_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`
+		result, err := tx.Exec(attemptQuery, attempt.DeliveryID, attempt.Attempt, attempt.StatusCode, attempt.Error, attempt.DurationMS, attempt.AttemptedAt)
+		if err != nil {
+			return fmt.Errorf("failed to record delivery attempt: %w", err)
+		}
+		if attempt.ID, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+
+		deliveryQuery := `
+			UPDATE webhook_deliveries
+			SET status = ?, attempt_count = ?, next_attempt_at = ?, last_status_code = ?, last_error = ?, updated_at = ?
+			WHERE id = ?
+		`
+		_, err = tx.Exec(deliveryQuery, delivery.Status, delivery.AttemptCount, delivery.NextAttemptAt, delivery.LastStatusCode, delivery.LastError, delivery.UpdatedAt, delivery.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update webhook delivery: %w", err)
+		}
+		return nil
+	})
+}
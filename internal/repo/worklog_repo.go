@@ -0,0 +1,281 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"task-manager/internal/domain"
+)
+
+type WorklogRepository interface {
+	Create(worklog *domain.Worklog) error
+	// StartTimer creates a running worklog, failing with ErrTimerRunning if
+	// the user already has one
+	StartTimer(worklog *domain.Worklog) error
+	GetByID(taskID, id int64) (*domain.Worklog, error)
+	GetByTaskID(taskID int64) ([]domain.Worklog, error)
+	// GetRunning returns the user's running timer
+	GetRunning(userID int64) (*domain.Worklog, error)
+	Update(worklog *domain.Worklog) error
+	Delete(taskID, id int64) error
+	// GetTimeReport sums the user's finished worklogs on tasks outside the
+	// trash, by the UTC day they started on
+	GetTimeReport(userID int64, window domain.StatsWindow) (*domain.TimeReport, error)
+}
+
+type worklogRepository struct {
+	db *sql.DB
+}
+
+func NewWorklogRepository(db *sql.DB) WorklogRepository {
+	return &worklogRepository{db: db}
+}
+
+const worklogColumns = `id, task_id, user_id, started_at, ended_at, duration_seconds, note, created_at, updated_at`
+
+func scanWorklog(row interface{ Scan(...interface{}) error }) (*domain.Worklog, error) {
+	var worklog domain.Worklog
+	err := row.Scan(
+		&worklog.ID,
+		&worklog.TaskID,
+		&worklog.UserID,
+		&worklog.StartedAt,
+		&worklog.EndedAt,
+		&worklog.DurationSeconds,
+		&worklog.Note,
+		&worklog.CreatedAt,
+		&worklog.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &worklog, nil
+}
+
+func (r *worklogRepository) Create(worklog *domain.Worklog) error {
+	query := `
+		INSERT INTO worklogs (task_id, user_id, started_at, ended_at, duration_seconds, note, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query, worklog.TaskID, worklog.UserID, worklog.StartedAt, worklog.EndedAt, worklog.DurationSeconds, worklog.Note, worklog.CreatedAt, worklog.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create worklog: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	worklog.ID = id
+	return nil
+}
+
+// StartTimer checks for a running timer in the same statement as the insert,
+// so two concurrent starts cannot both succeed
+func (r *worklogRepository) StartTimer(worklog *domain.Worklog) error {
+	query := `
+		INSERT INTO worklogs (task_id, user_id, started_at, note, created_at, updated_at)
+		SELECT ?, ?, ?, ?, ?, ?
+		WHERE NOT EXISTS (SELECT 1 FROM worklogs WHERE user_id = ? AND ended_at IS NULL)
+	`
+	result, err := r.db.Exec(query, worklog.TaskID, worklog.UserID, worklog.StartedAt, worklog.Note, worklog.CreatedAt, worklog.UpdatedAt, worklog.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to start timer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrTimerRunning
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	worklog.ID = id
+	worklog.EndedAt = nil
+	worklog.DurationSeconds = 0
+	return nil
+}
+
+func (r *worklogRepository) GetByID(taskID, id int64) (*domain.Worklog, error) {
+	query := `SELECT ` + worklogColumns + ` FROM worklogs WHERE id = ? AND task_id = ?`
+
+	worklog, err := scanWorklog(r.db.QueryRow(query, id, taskID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrWorklogNotFound
+		}
+		return nil, fmt.Errorf("failed to get worklog: %w", err)
+	}
+	return worklog, nil
+}
+
+func (r *worklogRepository) GetByTaskID(taskID int64) ([]domain.Worklog, error) {
+	query := `SELECT ` + worklogColumns + ` FROM worklogs WHERE task_id = ? ORDER BY started_at, id`
+
+	rows, err := r.db.Query(query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query worklogs: %w", err)
+	}
+	defer rows.Close()
+
+	worklogs := []domain.Worklog{}
+	for rows.Next() {
+		worklog, err := scanWorklog(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan worklog: %w", err)
+		}
+		worklogs = append(worklogs, *worklog)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return worklogs, nil
+}
+
+func (r *worklogRepository) GetRunning(userID int64) (*domain.Worklog, error) {
+	query := `SELECT ` + worklogColumns + ` FROM worklogs WHERE user_id = ? AND ended_at IS NULL`
+
+	worklog, err := scanWorklog(r.db.QueryRow(query, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNoTimerRunning
+		}
+		return nil, fmt.Errorf("failed to get running timer: %w", err)
+	}
+	return worklog, nil
+}
+
+func (r *worklogRepository) Update(worklog *domain.Worklog) error {
+	query := `
+		UPDATE worklogs
+		SET started_at = ?, ended_at = ?, duration_seconds = ?, note = ?, updated_at = ?
+		WHERE id = ? AND task_id = ?
+	`
+	result, err := r.db.Exec(query, worklog.StartedAt, worklog.EndedAt, worklog.DurationSeconds, worklog.Note, worklog.UpdatedAt, worklog.ID, worklog.TaskID)
+	if err != nil {
+		return fmt.Errorf("failed to update worklog: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrWorklogNotFound
+	}
+	return nil
+}
+
+func (r *worklogRepository) Delete(taskID, id int64) error {
+	result, err := r.db.Exec(`DELETE FROM worklogs WHERE id = ? AND task_id = ?`, id, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to delete worklog: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrWorklogNotFound
+	}
+	return nil
+}
+
+func (r *worklogRepository) GetTimeReport(userID int64, window domain.StatsWindow) (*domain.TimeReport, error) {
+	report := domain.NewTimeReport(window)
+
+	// Times are stored in UTC, in a format that orders as text and starts
+	// with the date
+	logged := `
+		WITH logged AS (
+			SELECT worklogs.task_id, tasks.title, worklogs.duration_seconds, substr(worklogs.started_at, 1, 10) AS day
+			FROM worklogs
+			JOIN tasks ON tasks.id = worklogs.task_id
+			WHERE worklogs.user_id = ? AND worklogs.ended_at IS NOT NULL AND tasks.deleted_at IS NULL
+				AND worklogs.started_at >= ? AND worklogs.started_at < ?
+		)`
+	args := []interface{}{userID, window.From, window.To.AddDate(0, 0, 1)}
+
+	rows, err := r.db.Query(logged+` SELECT task_id, title, SUM(duration_seconds) FROM logged GROUP BY task_id ORDER BY SUM(duration_seconds) DESC, task_id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum time by task: %w", err)
+	}
+	for rows.Next() {
+		var taskTime domain.TaskTime
+		if err := rows.Scan(&taskTime.TaskID, &taskTime.Title, &taskTime.Seconds); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan task time: %w", err)
+		}
+		report.ByTask = append(report.ByTask, taskTime)
+		report.TotalSeconds += taskTime.Seconds
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	rows, err = r.db.Query(logged+`
+		SELECT categories.id, categories.name, SUM(logged.duration_seconds)
+		FROM logged
+		JOIN task_categories ON task_categories.task_id = logged.task_id
+		JOIN categories ON categories.id = task_categories.category_id AND categories.deleted_at IS NULL
+		GROUP BY categories.id
+		ORDER BY SUM(logged.duration_seconds) DESC, categories.id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum time by category: %w", err)
+	}
+	for rows.Next() {
+		var categoryTime domain.CategoryTime
+		if err := rows.Scan(&categoryTime.CategoryID, &categoryTime.Name, &categoryTime.Seconds); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan category time: %w", err)
+		}
+		report.ByCategory = append(report.ByCategory, categoryTime)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	uncategorized := logged + `
+		SELECT COALESCE(SUM(duration_seconds), 0) FROM logged
+		WHERE NOT EXISTS (
+			SELECT 1 FROM task_categories
+			JOIN categories ON categories.id = task_categories.category_id AND categories.deleted_at IS NULL
+			WHERE task_categories.task_id = logged.task_id
+		)`
+	if err := r.db.QueryRow(uncategorized, args...).Scan(&report.UncategorizedSeconds); err != nil {
+		return nil, fmt.Errorf("failed to sum uncategorized time: %w", err)
+	}
+
+	rows, err = r.db.Query(logged+` SELECT day, SUM(duration_seconds) FROM logged GROUP BY day`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum time by day: %w", err)
+	}
+	defer rows.Close()
+	days := make(map[string]int64)
+	for rows.Next() {
+		var day string
+		var seconds int64
+		if err := rows.Scan(&day, &seconds); err != nil {
+			return nil, fmt.Errorf("failed to scan daily time: %w", err)
+		}
+		days[day] = seconds
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	for i := range report.Daily {
+		report.Daily[i].Seconds = days[report.Daily[i].Date]
+	}
+
+	return report, nil
+}
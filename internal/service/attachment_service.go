@@ -0,0 +1,194 @@
+package service
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"task-manager/internal/domain"
+	"task-manager/internal/repo"
+	"task-manager/internal/storage"
+	"time"
+)
+
+// blobPurgeBatchSize is how many queued blob deletions are handled per query
+const blobPurgeBatchSize = 100
+
+type AttachmentService interface {
+	UploadAttachment(userID, taskID int64, req *domain.UploadAttachmentRequest) (*domain.Attachment, error)
+	GetAttachments(userID, taskID int64) ([]domain.Attachment, error)
+	// OpenAttachment returns the attachment and its contents, which the
+	// caller must close
+	OpenAttachment(userID, taskID, id int64) (*domain.Attachment, io.ReadCloser, error)
+	DeleteAttachment(userID, taskID, id int64) error
+	// PurgeDeletedBlobs removes the blobs of deleted attachments, including
+	// those deleted along with their task, and returns how many were removed
+	PurgeDeletedBlobs() (int, error)
+}
+
+type attachmentService struct {
+	attachmentRepo repo.AttachmentRepository
+	taskRepo       repo.TaskRepository
+	blobs          storage.BlobStore
+	limits         domain.AttachmentLimits
+}
+
+func NewAttachmentService(attachmentRepo repo.AttachmentRepository, taskRepo repo.TaskRepository, blobs storage.BlobStore, limits domain.AttachmentLimits) AttachmentService {
+	return &attachmentService{
+		attachmentRepo: attachmentRepo,
+		taskRepo:       taskRepo,
+		blobs:          blobs,
+		limits:         limits,
+	}
+}
+
+func (s *attachmentService) UploadAttachment(userID, taskID int64, req *domain.UploadAttachmentRequest) (*domain.Attachment, error) {
+	if _, err := s.taskRepo.GetByID(userID, taskID); err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	content := bufio.NewReaderSize(req.Content, 512)
+	contentType, err := s.contentType(req.ContentType, content)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := newStorageKey()
+	if err != nil {
+		return nil, err
+	}
+
+	var reader io.Reader = content
+	if s.limits.MaxSize > 0 {
+		// Read one byte past the limit to detect oversized uploads
+		reader = io.LimitReader(content, s.limits.MaxSize+1)
+	}
+	size, err := s.blobs.Put(key, reader)
+	if err != nil {
+		s.blobs.Delete(key)
+		return nil, err
+	}
+	if s.limits.MaxSize > 0 && size > s.limits.MaxSize {
+		s.blobs.Delete(key)
+		return nil, fmt.Errorf("%w of %d bytes", domain.ErrAttachmentTooLarge, s.limits.MaxSize)
+	}
+	if size == 0 {
+		s.blobs.Delete(key)
+		return nil, errors.New("attachment is empty")
+	}
+
+	attachment := &domain.Attachment{
+		TaskID:      taskID,
+		UserID:      userID,
+		Filename:    domain.SanitizeFilename(req.Filename),
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  key,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.attachmentRepo.Create(attachment); err != nil {
+		s.blobs.Delete(key)
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// contentType returns the declared type, or the sniffed type when the client
+// sent none, and checks it against the allowed types
+func (s *attachmentService) contentType(declared string, content *bufio.Reader) (string, error) {
+	if declared == "" || declared == "application/octet-stream" {
+		head, err := content.Peek(512)
+		if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+			return "", fmt.Errorf("failed to read attachment: %w", err)
+		}
+		declared = http.DetectContentType(head)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(declared)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", domain.ErrAttachmentType, declared)
+	}
+	contentType := mime.FormatMediaType(mediaType, params)
+
+	if !s.limits.Allows(contentType) {
+		return "", fmt.Errorf("%w: %s", domain.ErrAttachmentType, mediaType)
+	}
+	return contentType, nil
+}
+
+func (s *attachmentService) GetAttachments(userID, taskID int64) ([]domain.Attachment, error) {
+	if _, err := s.taskRepo.GetByID(userID, taskID); err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	return s.attachmentRepo.GetByTaskID(taskID)
+}
+
+func (s *attachmentService) OpenAttachment(userID, taskID, id int64) (*domain.Attachment, io.ReadCloser, error) {
+	if _, err := s.taskRepo.GetByID(userID, taskID); err != nil {
+		return nil, nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	attachment, err := s.attachmentRepo.GetByID(taskID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.blobs.Get(attachment.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+
+	return attachment, content, nil
+}
+
+func (s *attachmentService) DeleteAttachment(userID, taskID, id int64) error {
+	if _, err := s.taskRepo.GetByID(userID, taskID); err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	if err := s.attachmentRepo.Delete(taskID, id); err != nil {
+		return err
+	}
+
+	// The blob stays queued for the next purge if removing it now fails
+	s.PurgeDeletedBlobs()
+	return nil
+}
+
+func (s *attachmentService) PurgeDeletedBlobs() (int, error) {
+	purged := 0
+	for {
+		keys, err := s.attachmentRepo.GetPendingBlobDeletions(blobPurgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, key := range keys {
+			if err := s.blobs.Delete(key); err != nil {
+				return purged, err
+			}
+			if err := s.attachmentRepo.ClearBlobDeletion(key); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+
+		if len(keys) < blobPurgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+func newStorageKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate storage key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"task-manager/internal/domain"
+	"task-manager/internal/storage"
+	"testing"
+)
+
+type mockAttachmentRepository struct {
+	attachments   map[int64]*domain.Attachment
+	blobDeletions []string
+	nextID        int64
+}
+
+func newMockAttachmentRepository() *mockAttachmentRepository {
+	return &mockAttachmentRepository{
+		attachments: make(map[int64]*domain.Attachment),
+		nextID:      1,
+	}
+}
+
+func (m *mockAttachmentRepository) Create(attachment *domain.Attachment) error {
+	attachment.ID = m.nextID
+	m.attachments[m.nextID] = attachment
+	m.nextID++
+	return nil
+}
+
+func (m *mockAttachmentRepository) GetByID(taskID, id int64) (*domain.Attachment, error) {
+	attachment, exists := m.attachments[id]
+	if !exists || attachment.TaskID != taskID {
+		return nil, domain.ErrAttachmentNotFound
+	}
+	copied := *attachment
+	return &copied, nil
+}
+
+func (m *mockAttachmentRepository) GetByTaskID(taskID int64) ([]domain.Attachment, error) {
+	attachments := []domain.Attachment{}
+	for id := int64(1); id < m.nextID; id++ {
+		if attachment, exists := m.attachments[id]; exists && attachment.TaskID == taskID {
+			attachments = append(attachments, *attachment)
+		}
+	}
+	return attachments, nil
+}
+
+// Delete queues the blob like the attachments trigger does
+func (m *mockAttachmentRepository) Delete(taskID, id int64) error {
+	attachment, err := m.GetByID(taskID, id)
+	if err != nil {
+		return err
+	}
+	delete(m.attachments, id)
+	m.blobDeletions = append(m.blobDeletions, attachment.StorageKey)
+	return nil
+}
+
+func (m *mockAttachmentRepository) GetPendingBlobDeletions(limit int) ([]string, error) {
+	if len(m.blobDeletions) > limit {
+		return append([]string(nil), m.blobDeletions[:limit]...), nil
+	}
+	return append([]string(nil), m.blobDeletions...), nil
+}
+
+func (m *mockAttachmentRepository) ClearBlobDeletion(storageKey string) error {
+	for i, key := range m.blobDeletions {
+		if key == storageKey {
+			m.blobDeletions = append(m.blobDeletions[:i], m.blobDeletions[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+type memoryBlobStore struct {
+	blobs map[string][]byte
+}
+
+func newMemoryBlobStore() *memoryBlobStore {
+	return &memoryBlobStore{blobs: make(map[string][]byte)}
+}
+
+func (m *memoryBlobStore) Put(key string, r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	m.blobs[key] = data
+	return int64(len(data)), nil
+}
+
+func (m *memoryBlobStore) Get(key string) (io.ReadCloser, error) {
+	data, exists := m.blobs[key]
+	if !exists {
+		return nil, storage.ErrBlobNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memoryBlobStore) Delete(key string) error {
+	delete(m.blobs, key)
+	return nil
+}
+
+func TestAttachmentService_Upload(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	attachmentRepo := newMockAttachmentRepository()
+	blobs := newMemoryBlobStore()
+	limits := domain.AttachmentLimits{MaxSize: 10, AllowedTypes: []string{"image/*", "text/plain"}}
+	service := NewAttachmentService(attachmentRepo, taskRepo, blobs, limits)
+
+	task := &domain.Task{Title: "Has files", UserID: testUserID}
+	taskRepo.Create(task)
+
+	upload := func(userID, taskID int64, filename, contentType, content string) (*domain.Attachment, error) {
+		return service.UploadAttachment(userID, taskID, &domain.UploadAttachmentRequest{
+			Filename:    filename,
+			ContentType: contentType,
+			Content:     strings.NewReader(content),
+		})
+	}
+
+	attachment, err := upload(testUserID, task.ID, "../notes.txt", "text/plain", "hello")
+	if err != nil {
+		t.Fatalf("UploadAttachment() error = %v", err)
+	}
+	if attachment.Filename != "notes.txt" || attachment.Size != 5 || attachment.StorageKey == "" {
+		t.Errorf("unexpected attachment %+v", attachment)
+	}
+
+	// Without a declared type the content is sniffed
+	sniffed, err := upload(testUserID, task.ID, "shot", "", "\x89PNG\r\n\x1a\n")
+	if err != nil {
+		t.Fatalf("UploadAttachment() without a type error = %v", err)
+	}
+	if sniffed.ContentType != "image/png" {
+		t.Errorf("expected sniffed type image/png, got %s", sniffed.ContentType)
+	}
+
+	if _, err := upload(testUserID, task.ID, "big.txt", "text/plain", strings.Repeat("x", 11)); !errors.Is(err, domain.ErrAttachmentTooLarge) {
+		t.Errorf("expected ErrAttachmentTooLarge, got %v", err)
+	}
+	if _, err := upload(testUserID, task.ID, "page.html", "text/html", "<html>"); !errors.Is(err, domain.ErrAttachmentType) {
+		t.Errorf("expected ErrAttachmentType, got %v", err)
+	}
+	if _, err := upload(testUserID, 999, "notes.txt", "text/plain", "hello"); err == nil {
+		t.Error("uploading to a missing task should fail")
+	}
+
+	// Rejected uploads must not leave blobs behind
+	if len(blobs.blobs) != 2 {
+		t.Errorf("expected 2 stored blobs, got %d", len(blobs.blobs))
+	}
+}
+
+func TestAttachmentService_DeletePurgesBlob(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	attachmentRepo := newMockAttachmentRepository()
+	blobs := newMemoryBlobStore()
+	service := NewAttachmentService(attachmentRepo, taskRepo, blobs, domain.AttachmentLimits{MaxSize: 100})
+
+	task := &domain.Task{Title: "Has files", UserID: testUserID}
+	taskRepo.Create(task)
+
+	attachment, err := service.UploadAttachment(testUserID, task.ID, &domain.UploadAttachmentRequest{
+		Filename:    "notes.txt",
+		ContentType: "text/plain",
+		Content:     strings.NewReader("hello"),
+	})
+	if err != nil {
+		t.Fatalf("UploadAttachment() error = %v", err)
+	}
+
+	_, content, err := service.OpenAttachment(testUserID, task.ID, attachment.ID)
+	if err != nil {
+		t.Fatalf("OpenAttachment() error = %v", err)
+	}
+	data, _ := io.ReadAll(content)
+	content.Close()
+	if string(data) != "hello" {
+		t.Errorf("OpenAttachment() contents = %q", data)
+	}
+
+	if err := service.DeleteAttachment(testUserID, 999, attachment.ID); err == nil {
+		t.Error("deleting from a missing task should fail")
+	}
+	if err := service.DeleteAttachment(testUserID, task.ID, attachment.ID); err != nil {
+		t.Fatalf("DeleteAttachment() error = %v", err)
+	}
+	if len(blobs.blobs) != 0 || len(attachmentRepo.blobDeletions) != 0 {
+		t.Errorf("expected blob to be purged, have %d blobs and %d queued", len(blobs.blobs), len(attachmentRepo.blobDeletions))
+	}
+
+	// Blobs queued by task deletion are removed by the next purge
+	blobs.blobs["orphan"] = []byte("x")
+	attachmentRepo.blobDeletions = []string{"orphan"}
+	purged, err := service.PurgeDeletedBlobs()
+	if err != nil || purged != 1 || len(blobs.blobs) != 0 {
+		t.Errorf("PurgeDeletedBlobs() = %d, %v; want 1 blob removed", purged, err)
+	}
+}
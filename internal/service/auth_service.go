@@ -0,0 +1,341 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"task-manager/internal/domain"
+	"task-manager/internal/repo"
+	"time"
+)
+
+var (
+	ErrEmailTaken         = domain.ErrEmailTaken
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+)
+
+// CalendarFeedPath is the route that accepts calendar tokens
+const CalendarFeedPath = "/v1/tasks/calendar.ics"
+
+const (
+	calendarTokenLength    = 32
+	passwordHashIterations = 100000
+	passwordSaltLength     = 16
+	passwordKeyLength      = 32
+)
+
+type AuthService interface {
+	Signup(req *domain.SignupRequest) (*domain.AuthResponse, error)
+	Login(req *domain.LoginRequest) (*domain.AuthResponse, error)
+	Authenticate(token string) (*domain.User, error)
+	// IssueCalendarToken creates a calendar feed token for the user,
+	// revoking the previous one
+	IssueCalendarToken(userID int64) (*domain.CalendarToken, error)
+	RevokeCalendarToken(userID int64) error
+	AuthenticateCalendarToken(token string) (*domain.User, error)
+}
+
+type authService struct {
+	userRepo repo.UserRepository
+	secret   []byte
+	tokenTTL time.Duration
+	// admins holds the normalized emails of the administrators
+	admins map[string]bool
+}
+
+// NewAuthService returns a service that signs tokens with secret. The users
+// whose emails are listed in admins are administrators.
+func NewAuthService(userRepo repo.UserRepository, secret string, tokenTTL time.Duration, admins []string) AuthService {
+	adminEmails := make(map[string]bool, len(admins))
+	for _, email := range admins {
+		if email = domain.NormalizeEmail(email); email != "" {
+			adminEmails[email] = true
+		}
+	}
+
+	return &authService{
+		userRepo: userRepo,
+		secret:   []byte(secret),
+		tokenTTL: tokenTTL,
+		admins:   adminEmails,
+	}
+}
+
+func (s *authService) Signup(req *domain.SignupRequest) (*domain.AuthResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	email := domain.NormalizeEmail(req.Email)
+	if _, err := s.userRepo.GetByEmail(email); err == nil {
+		return nil, ErrEmailTaken
+	} else if !errors.Is(err, domain.ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to check email: %w", err)
+	}
+
+	passwordHash, err := hashPassword(req.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	now := time.Now()
+	user := &domain.User{
+		Email:        email,
+		PasswordHash: passwordHash,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	// A concurrent signup can take the email after the check above
+	if err := s.userRepo.Create(user); err != nil {
+		if errors.Is(err, domain.ErrEmailTaken) {
+			return nil, ErrEmailTaken
+		}
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return s.issueToken(user)
+}
+
+func (s *authService) Login(req *domain.LoginRequest) (*domain.AuthResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	user, err := s.userRepo.GetByEmail(domain.NormalizeEmail(req.Email))
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if !verifyPassword(req.Password, user.PasswordHash) {
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.issueToken(user)
+}
+
+func (s *authService) Authenticate(token string) (*domain.User, error) {
+	claims, err := s.parseToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrInvalidToken
+	}
+
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	user.IsAdmin = s.admins[user.Email]
+	return user, nil
+}
+
+func (s *authService) IssueCalendarToken(userID int64) (*domain.CalendarToken, error) {
+	raw := make([]byte, calendarTokenLength)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate calendar token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	if err := s.userRepo.SetCalendarTokenHash(userID, hashCalendarToken(token)); err != nil {
+		return nil, fmt.Errorf("failed to save calendar token: %w", err)
+	}
+
+	return &domain.CalendarToken{
+		Token: token,
+		URL:   CalendarFeedPath + "?token=" + token,
+	}, nil
+}
+
+func (s *authService) RevokeCalendarToken(userID int64) error {
+	if err := s.userRepo.SetCalendarTokenHash(userID, ""); err != nil {
+		return fmt.Errorf("failed to revoke calendar token: %w", err)
+	}
+	return nil
+}
+
+func (s *authService) AuthenticateCalendarToken(token string) (*domain.User, error) {
+	if token == "" {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByCalendarTokenHash(hashCalendarToken(token))
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	user.IsAdmin = s.admins[user.Email]
+	return user, nil
+}
+
+// hashCalendarToken returns the form of a calendar token that is stored.
+// Tokens are random, so an unsalted hash is enough to look them up.
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// tokenClaims is the JWT payload issued by the auth service
+type tokenClaims struct {
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// tokenHeader is the fixed JWT header; only HS256 is issued or accepted
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+func (s *authService) issueToken(user *domain.User) (*domain.AuthResponse, error) {
+	user.IsAdmin = s.admins[user.Email]
+	now := time.Now()
+	expiresAt := now.Add(s.tokenTTL)
+
+	payload, err := json.Marshal(tokenClaims{
+		Subject:   strconv.FormatInt(user.ID, 10),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode token: %w", err)
+	}
+
+	signingInput := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	token := signingInput + "." + base64.RawURLEncoding.EncodeToString(s.sign(signingInput))
+
+	return &domain.AuthResponse{
+		Token:     token,
+		ExpiresAt: time.Unix(expiresAt.Unix(), 0),
+		User:      user,
+	}, nil
+}
+
+func (s *authService) parseToken(token string) (*tokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if !hmac.Equal(signature, s.sign(parts[0]+"."+parts[1])) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims tokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	return &claims, nil
+}
+
+func (s *authService) sign(input string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(input))
+	return mac.Sum(nil)
+}
+
+// hashPassword derives a PBKDF2-HMAC-SHA256 key from the password and encodes
+// it together with its parameters as "pbkdf2-sha256$iterations$salt$key".
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := pbkdf2SHA256([]byte(password), salt, passwordHashIterations, passwordKeyLength)
+	return fmt.Sprintf("pbkdf2-sha256$%d$%s$%s",
+		passwordHashIterations,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func verifyPassword(password, encoded string) bool {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 4 || parts[0] != "pbkdf2-sha256" {
+		return false
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	expected, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+
+	key := pbkdf2SHA256([]byte(password), salt, iterations, len(expected))
+	return subtle.ConstantTimeCompare(key, expected) == 1
+}
+
+// pbkdf2SHA256 implements PBKDF2 (RFC 8018) with HMAC-SHA256 as the PRF
+func pbkdf2SHA256(password, salt []byte, iterations, keyLength int) []byte {
+	prf := hmac.New(sha256.New, password)
+	hashLength := prf.Size()
+	blocks := (keyLength + hashLength - 1) / hashLength
+
+	var counter [4]byte
+	derived := make([]byte, 0, blocks*hashLength)
+	u := make([]byte, hashLength)
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(counter[:], uint32(block))
+		prf.Write(counter[:])
+		u = prf.Sum(u[:0])
+
+		t := make([]byte, hashLength)
+		copy(t, u)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		derived = append(derived, t...)
+	}
+
+	return derived[:keyLength]
+}
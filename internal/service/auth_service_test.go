@@ -0,0 +1,264 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"task-manager/internal/domain"
+	"testing"
+	"time"
+)
+
+type mockUserRepository struct {
+	users          map[int64]*domain.User
+	calendarTokens map[int64]string
+	nextID         int64
+}
+
+func newMockUserRepository() *mockUserRepository {
+	return &mockUserRepository{
+		users:          make(map[int64]*domain.User),
+		calendarTokens: make(map[int64]string),
+		nextID:         1,
+	}
+}
+
+func (m *mockUserRepository) Create(user *domain.User) error {
+	user.ID = m.nextID
+	m.users[m.nextID] = user
+	m.nextID++
+	return nil
+}
+
+func (m *mockUserRepository) GetByID(id int64) (*domain.User, error) {
+	user, exists := m.users[id]
+	if !exists {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (m *mockUserRepository) GetByEmail(email string) (*domain.User, error) {
+	for _, user := range m.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (m *mockUserRepository) GetByCalendarTokenHash(hash string) (*domain.User, error) {
+	for id, stored := range m.calendarTokens {
+		if stored == hash {
+			return m.users[id], nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (m *mockUserRepository) SetCalendarTokenHash(userID int64, hash string) error {
+	if _, exists := m.users[userID]; !exists {
+		return domain.ErrUserNotFound
+	}
+	if hash == "" {
+		delete(m.calendarTokens, userID)
+	} else {
+		m.calendarTokens[userID] = hash
+	}
+	return nil
+}
+
+func TestAuthService_SignupAndLogin(t *testing.T) {
+	service := NewAuthService(newMockUserRepository(), "test-secret", time.Hour, nil)
+
+	signup, err := service.Signup(&domain.SignupRequest{Email: "Alice@Example.com", Password: "correct-horse"})
+	if err != nil {
+		t.Fatalf("Signup() error = %v", err)
+	}
+	if signup.User.Email != "alice@example.com" {
+		t.Errorf("Signup() email = %v, want normalized alice@example.com", signup.User.Email)
+	}
+	if signup.User.PasswordHash == "correct-horse" || !strings.HasPrefix(signup.User.PasswordHash, "pbkdf2-sha256$") {
+		t.Errorf("Signup() stored an unhashed password: %v", signup.User.PasswordHash)
+	}
+
+	_, err = service.Signup(&domain.SignupRequest{Email: "alice@example.com", Password: "another-pass"})
+	if !errors.Is(err, ErrEmailTaken) {
+		t.Errorf("Signup() duplicate email error = %v, want %v", err, ErrEmailTaken)
+	}
+
+	tests := []struct {
+		name     string
+		req      *domain.LoginRequest
+		wantErr  error
+		wantUser int64
+	}{
+		{
+			name:     "valid credentials",
+			req:      &domain.LoginRequest{Email: "alice@example.com", Password: "correct-horse"},
+			wantUser: signup.User.ID,
+		},
+		{
+			name:     "email is case insensitive",
+			req:      &domain.LoginRequest{Email: " ALICE@example.com", Password: "correct-horse"},
+			wantUser: signup.User.ID,
+		},
+		{
+			name:    "wrong password",
+			req:     &domain.LoginRequest{Email: "alice@example.com", Password: "wrong-horse"},
+			wantErr: ErrInvalidCredentials,
+		},
+		{
+			name:    "unknown email",
+			req:     &domain.LoginRequest{Email: "bob@example.com", Password: "correct-horse"},
+			wantErr: ErrInvalidCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := service.Login(tt.req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Login() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Login() error = %v", err)
+			}
+			user, err := service.Authenticate(resp.Token)
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if user.ID != tt.wantUser {
+				t.Errorf("Authenticate() user = %v, want %v", user.ID, tt.wantUser)
+			}
+		})
+	}
+}
+
+// racingUserRepository lets the email check pass and then loses the insert to
+// a concurrent signup, as the UNIQUE constraint would
+type racingUserRepository struct {
+	*mockUserRepository
+}
+
+func (m *racingUserRepository) Create(user *domain.User) error {
+	return domain.ErrEmailTaken
+}
+
+func TestAuthService_SignupRace(t *testing.T) {
+	service := NewAuthService(&racingUserRepository{newMockUserRepository()}, "test-secret", time.Hour, nil)
+
+	_, err := service.Signup(&domain.SignupRequest{Email: "alice@example.com", Password: "correct-horse"})
+	if !errors.Is(err, ErrEmailTaken) {
+		t.Errorf("Signup() error = %v, want %v", err, ErrEmailTaken)
+	}
+}
+
+func TestAuthService_Authenticate(t *testing.T) {
+	userRepo := newMockUserRepository()
+	service := NewAuthService(userRepo, "test-secret", time.Hour, nil)
+
+	signup, err := service.Signup(&domain.SignupRequest{Email: "alice@example.com", Password: "correct-horse"})
+	if err != nil {
+		t.Fatalf("Signup() error = %v", err)
+	}
+
+	otherSecret := NewAuthService(userRepo, "other-secret", time.Hour, nil)
+	expired := NewAuthService(userRepo, "test-secret", -time.Minute, nil)
+	expiredResp, _ := expired.Login(&domain.LoginRequest{Email: "alice@example.com", Password: "correct-horse"})
+
+	parts := strings.Split(signup.Token, ".")
+	tampered := parts[0] + "." + parts[1] + "x." + parts[2]
+
+	tests := []struct {
+		name    string
+		service AuthService
+		token   string
+	}{
+		{name: "empty token", service: service, token: ""},
+		{name: "malformed token", service: service, token: "not-a-token"},
+		{name: "tampered payload", service: service, token: tampered},
+		{name: "signed with another secret", service: otherSecret, token: signup.Token},
+		{name: "expired token", service: service, token: expiredResp.Token},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.service.Authenticate(tt.token); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Authenticate() error = %v, want %v", err, ErrInvalidToken)
+			}
+		})
+	}
+}
+
+func TestAuthService_CalendarToken(t *testing.T) {
+	userRepo := newMockUserRepository()
+	service := NewAuthService(userRepo, "test-secret", time.Hour, nil)
+
+	signup, err := service.Signup(&domain.SignupRequest{Email: "alice@example.com", Password: "correct-horse"})
+	if err != nil {
+		t.Fatalf("Signup() error = %v", err)
+	}
+
+	first, err := service.IssueCalendarToken(signup.User.ID)
+	if err != nil {
+		t.Fatalf("IssueCalendarToken() error = %v", err)
+	}
+	if userRepo.calendarTokens[signup.User.ID] == first.Token {
+		t.Error("calendar token should be stored hashed")
+	}
+	if first.URL != CalendarFeedPath+"?token="+first.Token {
+		t.Errorf("URL = %q", first.URL)
+	}
+	user, err := service.AuthenticateCalendarToken(first.Token)
+	if err != nil || user.ID != signup.User.ID {
+		t.Fatalf("AuthenticateCalendarToken() = %v, %v; want user %d", user, err, signup.User.ID)
+	}
+	if _, err := service.Authenticate(first.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("calendar token should not work as a bearer token, got %v", err)
+	}
+
+	second, err := service.IssueCalendarToken(signup.User.ID)
+	if err != nil {
+		t.Fatalf("IssueCalendarToken() error = %v", err)
+	}
+	if _, err := service.AuthenticateCalendarToken(first.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("reissuing should revoke the previous token, got %v", err)
+	}
+
+	if err := service.RevokeCalendarToken(signup.User.ID); err != nil {
+		t.Fatalf("RevokeCalendarToken() error = %v", err)
+	}
+	if _, err := service.AuthenticateCalendarToken(second.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("revoked token error = %v, want %v", err, ErrInvalidToken)
+	}
+	if _, err := service.AuthenticateCalendarToken(""); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("empty token error = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestAuthService_Admins(t *testing.T) {
+	service := NewAuthService(newMockUserRepository(), "test-secret", time.Hour, []string{" Admin@Example.com"})
+
+	admin, err := service.Signup(&domain.SignupRequest{Email: "admin@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Signup() error = %v", err)
+	}
+	member, err := service.Signup(&domain.SignupRequest{Email: "member@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Signup() error = %v", err)
+	}
+	if !admin.User.IsAdmin || member.User.IsAdmin {
+		t.Errorf("IsAdmin = %v, %v; want only the listed email to be an administrator", admin.User.IsAdmin, member.User.IsAdmin)
+	}
+
+	user, err := service.Authenticate(admin.Token)
+	if err != nil || !user.IsAdmin {
+		t.Errorf("Authenticate() = %+v, %v; want an administrator", user, err)
+	}
+	if user, err := service.Authenticate(member.Token); err != nil || user.IsAdmin {
+		t.Errorf("Authenticate() = %+v, %v; want a user who is not an administrator", user, err)
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"task-manager/internal/domain"
+	"task-manager/internal/repo"
+)
+
+// BulkTasks applies one update or delete to every selected task in a single
+// transaction. If any task fails, every change is rolled back and the result
+// reports which tasks failed and why.
+func (s *taskService) BulkTasks(userID int64, req *domain.BulkTaskRequest) (*domain.BulkTaskResult, error) {
+	if err := req.Validate(s.workflow.Get()); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	var result *domain.BulkTaskResult
+	events := &eventBuffer{}
+	err := s.taskRepo.WithTx(func(tasks repo.TaskRepository, categories repo.CategoryRepository) error {
+		tx := &taskService{taskRepo: tasks, categoryRepo: categories, workflow: s.workflow, events: events}
+
+		ids, err := tx.bulkTaskIDs(userID, req)
+		if err != nil {
+			return err
+		}
+
+		result = tx.applyBulk(userID, req, ids)
+		if !result.Applied {
+			return domain.ErrBulkRolledBack
+		}
+		return nil
+	})
+	if errors.Is(err, domain.ErrBulkRolledBack) {
+		for i := range result.Results {
+			if result.Results[i].Status != domain.BulkItemFailed {
+				result.Results[i].Status = domain.BulkItemRolledBack
+				result.Results[i].Task = nil
+			}
+		}
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	events.flush(s.events)
+	return result, nil
+}
+
+// bulkTaskIDs resolves the tasks a bulk request selects
+func (s *taskService) bulkTaskIDs(userID int64, req *domain.BulkTaskRequest) ([]int64, error) {
+	if req.Filters == nil {
+		return req.IDs, nil
+	}
+
+	matched, err := s.taskRepo.GetWithFilters(userID, req.Filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks with filters: %w", err)
+	}
+	if len(matched) > domain.MaxBulkTasks {
+		return nil, fmt.Errorf("validation failed: filters match %d tasks, at most %d can be changed at once", len(matched), domain.MaxBulkTasks)
+	}
+
+	ids := make([]int64, len(matched))
+	for i, task := range matched {
+		ids[i] = task.ID
+	}
+	return ids, nil
+}
+
+// applyBulk applies the request to each task in turn, carrying on past
+// failures so the report covers every task
+func (s *taskService) applyBulk(userID int64, req *domain.BulkTaskRequest, ids []int64) *domain.BulkTaskResult {
+	result := &domain.BulkTaskResult{Applied: true, Results: make([]domain.BulkItemResult, 0, len(ids))}
+	// Tasks already trashed along with an ancestor earlier in the batch
+	cascaded := make(map[int64]bool)
+
+	for _, id := range ids {
+		item := domain.BulkItemResult{ID: id}
+
+		switch {
+		case req.Action == domain.BulkUpdate:
+			task, err := s.UpdateTask(userID, id, req.Update)
+			if err != nil {
+				item.Status, item.Error = domain.BulkItemFailed, err.Error()
+				break
+			}
+			item.Status, item.Task = domain.BulkItemUpdated, task
+		case cascaded[id]:
+			item.Status = domain.BulkItemDeleted
+		case req.Subtasks == domain.SubtaskDeleteCascade:
+			descendants, err := s.taskRepo.GetDescendants(userID, id)
+			if err == nil {
+				err = s.DeleteTaskTree(userID, id)
+			}
+			if err != nil {
+				item.Status, item.Error = domain.BulkItemFailed, err.Error()
+				break
+			}
+			for _, descendant := range descendants {
+				cascaded[descendant.ID] = true
+			}
+			item.Status = domain.BulkItemDeleted
+		default:
+			if err := s.DeleteTask(userID, id); err != nil {
+				item.Status, item.Error = domain.BulkItemFailed, err.Error()
+				break
+			}
+			item.Status = domain.BulkItemDeleted
+		}
+
+		if item.Status == domain.BulkItemFailed {
+			result.Applied = false
+		}
+		result.Results = append(result.Results, item)
+	}
+
+	return result
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+func TestTaskService_BulkTasks_Update(t *testing.T) {
+	service := NewTaskService(newMockTaskRepository(), newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	first, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "First", Priority: domain.PriorityLow})
+	second, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Second", Priority: domain.PriorityLow})
+
+	priority := domain.PriorityHigh
+	result, err := service.BulkTasks(testUserID, &domain.BulkTaskRequest{
+		Action: domain.BulkUpdate,
+		IDs:    []int64{first.ID, second.ID},
+		Update: &domain.UpdateTaskRequest{Priority: &priority},
+	})
+	if err != nil {
+		t.Fatalf("BulkTasks() error = %v", err)
+	}
+	if !result.Applied || len(result.Results) != 2 {
+		t.Fatalf("expected 2 applied results, got %+v", result)
+	}
+	for _, item := range result.Results {
+		if item.Status != domain.BulkItemUpdated || item.Task == nil || item.Task.Priority != domain.PriorityHigh {
+			t.Errorf("unexpected result %+v", item)
+		}
+	}
+}
+
+func TestTaskService_BulkTasks_RollsBackOnFailure(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	service := NewTaskService(taskRepo, newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	first, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "First", Priority: domain.PriorityLow})
+
+	status := domain.StatusDone
+	result, err := service.BulkTasks(testUserID, &domain.BulkTaskRequest{
+		Action: domain.BulkUpdate,
+		IDs:    []int64{first.ID, 999},
+		Update: &domain.UpdateTaskRequest{Status: &status},
+	})
+	if err != nil {
+		t.Fatalf("BulkTasks() error = %v", err)
+	}
+	if result.Applied {
+		t.Fatal("bulk update with a missing task should not be applied")
+	}
+	if got := result.Results[0]; got.Status != domain.BulkItemRolledBack || got.Task != nil {
+		t.Errorf("first result = %+v, want rolled_back", got)
+	}
+	if got := result.Results[1]; got.Status != domain.BulkItemFailed || got.Error == "" {
+		t.Errorf("second result = %+v, want failed with error", got)
+	}
+
+	task, _ := service.GetTask(testUserID, first.ID)
+	if task.Status != domain.StatusTodo {
+		t.Errorf("rolled back task status = %s, want %s", task.Status, domain.StatusTodo)
+	}
+	if history, _ := service.GetTaskHistory(testUserID, first.ID); len(history) != 1 {
+		t.Errorf("rolled back update should leave no history, got %d entries", len(history))
+	}
+}
+
+func TestTaskService_BulkTasks_Delete(t *testing.T) {
+	service := NewTaskService(newMockTaskRepository(), newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	parent, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Parent", Priority: domain.PriorityLow})
+	child, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Child", Priority: domain.PriorityLow, ParentID: &parent.ID})
+	other, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Other", Priority: domain.PriorityHigh})
+
+	// The child goes with its parent, so listing it too must not fail
+	result, err := service.BulkTasks(testUserID, &domain.BulkTaskRequest{
+		Action:   domain.BulkDelete,
+		IDs:      []int64{parent.ID, child.ID},
+		Subtasks: domain.SubtaskDeleteCascade,
+	})
+	if err != nil {
+		t.Fatalf("BulkTasks() error = %v", err)
+	}
+	if !result.Applied {
+		t.Fatalf("expected cascade delete to be applied, got %+v", result)
+	}
+	for _, item := range result.Results {
+		if item.Status != domain.BulkItemDeleted {
+			t.Errorf("unexpected result %+v", item)
+		}
+	}
+	if _, err := service.GetTask(testUserID, other.ID); err != nil {
+		t.Errorf("unselected task should remain, got %v", err)
+	}
+	if trash, _ := service.GetTrashedTasks(testUserID); len(trash) != 2 {
+		t.Errorf("expected 2 trashed tasks, got %d", len(trash))
+	}
+}
+
+func TestTaskService_BulkTasks_Validation(t *testing.T) {
+	service := NewTaskService(newMockTaskRepository(), newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+	rrule := "FREQ=DAILY"
+
+	tests := []struct {
+		name string
+		req  *domain.BulkTaskRequest
+	}{
+		{name: "unknown action", req: &domain.BulkTaskRequest{Action: "archive", IDs: []int64{1}}},
+		{name: "update without changes", req: &domain.BulkTaskRequest{Action: domain.BulkUpdate, IDs: []int64{1}}},
+		{name: "neither ids nor filters", req: &domain.BulkTaskRequest{Action: domain.BulkDelete}},
+		{name: "both ids and filters", req: &domain.BulkTaskRequest{Action: domain.BulkDelete, IDs: []int64{1}, Filters: &domain.TaskFilters{}}},
+		{name: "duplicate ids", req: &domain.BulkTaskRequest{Action: domain.BulkDelete, IDs: []int64{1, 1}}},
+		{name: "recurrence change", req: &domain.BulkTaskRequest{Action: domain.BulkUpdate, IDs: []int64{1}, Update: &domain.UpdateTaskRequest{RRule: &rrule}}},
+		{name: "too many ids", req: &domain.BulkTaskRequest{Action: domain.BulkDelete, IDs: make([]int64, domain.MaxBulkTasks+1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.BulkTasks(testUserID, tt.req); err == nil || errors.Is(err, domain.ErrBulkRolledBack) {
+				t.Errorf("BulkTasks() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
@@ -2,38 +2,62 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"time"
 	"task-manager/internal/domain"
 	"task-manager/internal/repo"
 )
 
 type CategoryService interface {
-	CreateCategory(req *domain.CreateCategoryRequest) (*domain.Category, error)
-	GetCategory(id int64) (*domain.Category, error)
-	GetAllCategories() ([]domain.Category, error)
-	UpdateCategory(id int64, req *domain.UpdateCategoryRequest) (*domain.Category, error)
-	DeleteCategory(id int64) error
+	CreateCategory(userID int64, req *domain.CreateCategoryRequest) (*domain.Category, error)
+	GetCategory(userID, id int64) (*domain.Category, error)
+	GetAllCategories(userID int64) ([]domain.Category, error)
+	UpdateCategory(userID, id int64, req *domain.UpdateCategoryRequest) (*domain.Category, error)
+	DeleteCategory(userID, id int64) error
+	GetTrashedCategories(userID int64) ([]domain.Category, error)
+	RestoreCategory(userID, id int64) (*domain.Category, error)
+	PurgeCategory(userID, id int64) error
+	PurgeTrash(userID int64) error
+	// PurgeExpiredTrash permanently deletes every user's categories that
+	// were moved to the trash before cutoff
+	PurgeExpiredTrash(cutoff time.Time) (int64, error)
 }
 
 type categoryService struct {
 	categoryRepo repo.CategoryRepository
+	events       EventPublisher
 }
 
-func NewCategoryService(categoryRepo repo.CategoryRepository) CategoryService {
+// NewCategoryService returns a CategoryService that announces category
+// changes to events, which may be nil
+func NewCategoryService(categoryRepo repo.CategoryRepository, events EventPublisher) CategoryService {
 	return &categoryService{
 		categoryRepo: categoryRepo,
+		events:       events,
 	}
 }
 
 var ErrCategoryNotFound = errors.New("category not found")
 
-func (s *categoryService) CreateCategory(req *domain.CreateCategoryRequest) (*domain.Category, error) {
+func (s *categoryService) CreateCategory(userID int64, req *domain.CreateCategoryRequest) (*domain.Category, error) {
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 
+	// Trashed categories keep their name until they are purged
+	trash, err := s.categoryRepo.GetTrash(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, trashed := range trash {
+		if trashed.Name == req.Name {
+			return nil, fmt.Errorf("a category named %q is in the trash: restore it or delete it permanently", req.Name)
+		}
+	}
+
 	now := time.Now()
 	category := &domain.Category{
+		UserID:      userID,
 		Name:        req.Name,
 		Description: req.Description,
 		Color:       req.Color,
@@ -45,34 +69,39 @@ func (s *categoryService) CreateCategory(req *domain.CreateCategoryRequest) (*do
 		return nil, err
 	}
 
+	publish(s.events, newEvent(userID, domain.EventCategoryCreated, category))
 	return category, nil
 }
 
-func (s *categoryService) GetCategory(id int64) (*domain.Category, error) {
-	category, err := s.categoryRepo.GetByID(id)
+func (s *categoryService) GetCategory(userID, id int64) (*domain.Category, error) {
+	category, err := s.categoryRepo.GetByID(userID, id)
 	if err != nil {
 		return nil, err
 	}
 	return category, nil
 }
 
-func (s *categoryService) GetAllCategories() ([]domain.Category, error) {
-	categories, err := s.categoryRepo.GetAll()
+func (s *categoryService) GetAllCategories(userID int64) ([]domain.Category, error) {
+	categories, err := s.categoryRepo.GetAll(userID)
 	if err != nil {
 		return nil, err
 	}
 	return categories, nil
 }
 
-func (s *categoryService) UpdateCategory(id int64, req *domain.UpdateCategoryRequest) (*domain.Category, error) {
+func (s *categoryService) UpdateCategory(userID, id int64, req *domain.UpdateCategoryRequest) (*domain.Category, error) {
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 
-	category, err := s.categoryRepo.GetByID(id)
+	category, err := s.categoryRepo.GetByID(userID, id)
 	if err != nil {
 		return nil, ErrCategoryNotFound
 	}
+	if req.ExpectedVersion != nil && *req.ExpectedVersion != category.Version {
+		return nil, domain.ErrVersionConflict
+	}
+	before := *category
 
 	// Update fields if provided
 	if req.Name != nil {
@@ -90,15 +119,79 @@ func (s *categoryService) UpdateCategory(id int64, req *domain.UpdateCategoryReq
 		return nil, err
 	}
 
+	event := newEvent(userID, domain.EventCategoryUpdated, category)
+	event.Previous = &before
+	publish(s.events, event)
 	return category, nil
 }
 
-func (s *categoryService) DeleteCategory(id int64) error {
+func (s *categoryService) DeleteCategory(userID, id int64) error {
 	// Check if category exists
-	_, err := s.categoryRepo.GetByID(id)
+	category, err := s.categoryRepo.GetByID(userID, id)
 	if err != nil {
 		return ErrCategoryNotFound
 	}
 
-	return s.categoryRepo.Delete(id)
+	if err := s.categoryRepo.Delete(userID, id, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	publish(s.events, newEvent(userID, domain.EventCategoryDeleted, category))
+	return nil
+}
+
+func (s *categoryService) GetTrashedCategories(userID int64) ([]domain.Category, error) {
+	return s.categoryRepo.GetTrash(userID)
+}
+
+func (s *categoryService) RestoreCategory(userID, id int64) (*domain.Category, error) {
+	if err := s.categoryRepo.Restore(userID, id); err != nil {
+		return nil, err
+	}
+
+	category, err := s.categoryRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	publish(s.events, newEvent(userID, domain.EventCategoryRestored, category))
+	return category, nil
+}
+
+func (s *categoryService) PurgeCategory(userID, id int64) error {
+	trash, err := s.categoryRepo.GetTrash(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.categoryRepo.Purge(userID, id); err != nil {
+		return err
+	}
+
+	for i := range trash {
+		if trash[i].ID == id {
+			publish(s.events, newEvent(userID, domain.EventCategoryPurged, &trash[i]))
+		}
+	}
+	return nil
+}
+
+func (s *categoryService) PurgeTrash(userID int64) error {
+	trash, err := s.categoryRepo.GetTrash(userID)
+	if err != nil {
+		return err
+	}
+
+	for i := range trash {
+		if err := s.categoryRepo.Purge(userID, trash[i].ID); err != nil {
+			return err
+		}
+		publish(s.events, newEvent(userID, domain.EventCategoryPurged, &trash[i]))
+	}
+	return nil
+}
+
+func (s *categoryService) PurgeExpiredTrash(cutoff time.Time) (int64, error) {
+	// Deletion times are stored in UTC so that they compare correctly
+	return s.categoryRepo.PurgeDeletedBefore(cutoff.UTC())
 }
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"fmt"
+	"task-manager/internal/domain"
+	"task-manager/internal/repo"
+	"time"
+)
+
+type CommentService interface {
+	CreateComment(userID, taskID int64, req *domain.CreateCommentRequest) (*domain.Comment, error)
+	GetComments(userID, taskID int64) ([]domain.Comment, error)
+	UpdateComment(userID, taskID, id int64, req *domain.UpdateCommentRequest) (*domain.Comment, error)
+	DeleteComment(userID, taskID, id int64) error
+}
+
+type commentService struct {
+	commentRepo repo.CommentRepository
+	taskRepo    repo.TaskRepository
+}
+
+func NewCommentService(commentRepo repo.CommentRepository, taskRepo repo.TaskRepository) CommentService {
+	return &commentService{
+		commentRepo: commentRepo,
+		taskRepo:    taskRepo,
+	}
+}
+
+func (s *commentService) CreateComment(userID, taskID int64, req *domain.CreateCommentRequest) (*domain.Comment, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := s.checkTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	comment := &domain.Comment{
+		TaskID:    taskID,
+		AuthorID:  userID,
+		Body:      req.Body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.commentRepo.Create(comment); err != nil {
+		return nil, err
+	}
+
+	// Reload to pick up the author's email
+	return s.commentRepo.GetByID(taskID, comment.ID)
+}
+
+func (s *commentService) GetComments(userID, taskID int64) ([]domain.Comment, error) {
+	if err := s.checkTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.commentRepo.GetByTaskID(taskID)
+}
+
+func (s *commentService) UpdateComment(userID, taskID, id int64, req *domain.UpdateCommentRequest) (*domain.Comment, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	comment, err := s.authoredComment(userID, taskID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	comment.Body = req.Body
+	comment.UpdatedAt = time.Now()
+
+	if err := s.commentRepo.Update(comment); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+func (s *commentService) DeleteComment(userID, taskID, id int64) error {
+	if _, err := s.authoredComment(userID, taskID, id); err != nil {
+		return err
+	}
+
+	return s.commentRepo.Delete(taskID, id)
+}
+
+// checkTask ensures the task exists and belongs to the user
+func (s *commentService) checkTask(userID, taskID int64) error {
+	if _, err := s.taskRepo.GetByID(userID, taskID); err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+	return nil
+}
+
+// authoredComment loads a comment on one of the user's tasks and checks that
+// the user wrote it
+func (s *commentService) authoredComment(userID, taskID, id int64) (*domain.Comment, error) {
+	if err := s.checkTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.commentRepo.GetByID(taskID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.AuthorID != userID {
+		return nil, domain.ErrNotCommentAuthor
+	}
+
+	return comment, nil
+}
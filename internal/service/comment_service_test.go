@@ -0,0 +1,111 @@
+package service
+
+import (
+	"errors"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+type mockCommentRepository struct {
+	comments map[int64]*domain.Comment
+	nextID   int64
+}
+
+func newMockCommentRepository() *mockCommentRepository {
+	return &mockCommentRepository{
+		comments: make(map[int64]*domain.Comment),
+		nextID:   1,
+	}
+}
+
+func (m *mockCommentRepository) Create(comment *domain.Comment) error {
+	comment.ID = m.nextID
+	m.comments[m.nextID] = comment
+	m.nextID++
+	return nil
+}
+
+func (m *mockCommentRepository) GetByID(taskID, id int64) (*domain.Comment, error) {
+	comment, exists := m.comments[id]
+	if !exists || comment.TaskID != taskID {
+		return nil, domain.ErrCommentNotFound
+	}
+	copied := *comment
+	return &copied, nil
+}
+
+func (m *mockCommentRepository) GetByTaskID(taskID int64) ([]domain.Comment, error) {
+	comments := []domain.Comment{}
+	for id := int64(1); id < m.nextID; id++ {
+		if comment, exists := m.comments[id]; exists && comment.TaskID == taskID {
+			comments = append(comments, *comment)
+		}
+	}
+	return comments, nil
+}
+
+func (m *mockCommentRepository) Update(comment *domain.Comment) error {
+	if _, exists := m.comments[comment.ID]; !exists {
+		return domain.ErrCommentNotFound
+	}
+	m.comments[comment.ID] = comment
+	return nil
+}
+
+func (m *mockCommentRepository) Delete(taskID, id int64) error {
+	if _, err := m.GetByID(taskID, id); err != nil {
+		return err
+	}
+	delete(m.comments, id)
+	return nil
+}
+
+func TestCommentService(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	commentRepo := newMockCommentRepository()
+	service := NewCommentService(commentRepo, taskRepo)
+
+	task := &domain.Task{Title: "Discuss me", UserID: testUserID}
+	taskRepo.Create(task)
+
+	if _, err := service.CreateComment(testUserID, task.ID, &domain.CreateCommentRequest{Body: ""}); err == nil {
+		t.Error("CreateComment() with empty body should fail")
+	}
+	if _, err := service.CreateComment(testUserID, 999, &domain.CreateCommentRequest{Body: "Hello"}); err == nil {
+		t.Error("CreateComment() on missing task should fail")
+	}
+
+	comment, err := service.CreateComment(testUserID, task.ID, &domain.CreateCommentRequest{Body: "First"})
+	if err != nil {
+		t.Fatalf("CreateComment() error = %v", err)
+	}
+	if comment.AuthorID != testUserID || comment.CreatedAt.IsZero() {
+		t.Errorf("CreateComment() = %+v, want author and timestamps set", comment)
+	}
+
+	updated, err := service.UpdateComment(testUserID, task.ID, comment.ID, &domain.UpdateCommentRequest{Body: "Edited"})
+	if err != nil || updated.Body != "Edited" {
+		t.Errorf("UpdateComment() = %v, %v; want edited body", updated, err)
+	}
+
+	// Another author's comment on the same task cannot be changed
+	foreign := &domain.Comment{TaskID: task.ID, AuthorID: testUserID + 1, Body: "Theirs"}
+	commentRepo.Create(foreign)
+	if _, err := service.UpdateComment(testUserID, task.ID, foreign.ID, &domain.UpdateCommentRequest{Body: "Mine"}); !errors.Is(err, domain.ErrNotCommentAuthor) {
+		t.Errorf("UpdateComment() foreign error = %v, want %v", err, domain.ErrNotCommentAuthor)
+	}
+	if err := service.DeleteComment(testUserID, task.ID, foreign.ID); !errors.Is(err, domain.ErrNotCommentAuthor) {
+		t.Errorf("DeleteComment() foreign error = %v, want %v", err, domain.ErrNotCommentAuthor)
+	}
+
+	if err := service.DeleteComment(testUserID, task.ID, comment.ID); err != nil {
+		t.Errorf("DeleteComment() error = %v", err)
+	}
+	comments, _ := service.GetComments(testUserID, task.ID)
+	if len(comments) != 1 || comments[0].ID != foreign.ID {
+		t.Errorf("GetComments() = %v, want only the foreign comment", comments)
+	}
+	if err := service.DeleteComment(testUserID, task.ID, comment.ID); !errors.Is(err, domain.ErrCommentNotFound) {
+		t.Errorf("DeleteComment() twice error = %v, want %v", err, domain.ErrCommentNotFound)
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+	"task-manager/internal/domain"
+)
+
+func (s *taskService) AddDependency(userID, id int64, req *domain.AddDependencyRequest) (*domain.Task, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid task id")
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if req.BlockedByID == id {
+		return nil, fmt.Errorf("task cannot block itself: %w", domain.ErrDependencyCycle)
+	}
+
+	before, err := s.taskRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	if _, err := s.taskRepo.GetByID(userID, req.BlockedByID); err != nil {
+		return nil, fmt.Errorf("failed to get blocking task: %w", err)
+	}
+
+	// The new edge closes a cycle if the blocker already waits on this task
+	cycle, err := s.taskRepo.HasDependencyPath(req.BlockedByID, id)
+	if err != nil {
+		return nil, err
+	}
+	if cycle {
+		return nil, fmt.Errorf("task %d already depends on task %d: %w", req.BlockedByID, id, domain.ErrDependencyCycle)
+	}
+
+	if err := s.taskRepo.AddDependency(id, req.BlockedByID); err != nil {
+		return nil, err
+	}
+
+	task, err := s.taskRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recordHistory(userID, domain.HistoryUpdated, before, task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+func (s *taskService) RemoveDependency(userID, id, blockedByID int64) error {
+	if id <= 0 || blockedByID <= 0 {
+		return fmt.Errorf("invalid task id")
+	}
+
+	before, err := s.taskRepo.GetByID(userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	if err := s.taskRepo.RemoveDependency(userID, id, blockedByID); err != nil {
+		return err
+	}
+
+	task, err := s.taskRepo.GetByID(userID, id)
+	if err != nil {
+		return err
+	}
+
+	return s.recordHistory(userID, domain.HistoryUpdated, before, task)
+}
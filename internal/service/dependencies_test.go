@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+func TestTaskService_Dependencies(t *testing.T) {
+	mockRepo := newMockTaskRepository()
+	service := NewTaskService(mockRepo, newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	a, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "A", Priority: domain.PriorityMedium})
+	b, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "B", Priority: domain.PriorityMedium})
+	c, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "C", Priority: domain.PriorityMedium})
+
+	// A is blocked by B, B is blocked by C
+	task, err := service.AddDependency(testUserID, a.ID, &domain.AddDependencyRequest{BlockedByID: b.ID})
+	if err != nil {
+		t.Fatalf("AddDependency() error = %v", err)
+	}
+	if len(task.BlockedBy) != 1 || task.BlockedBy[0].ID != b.ID {
+		t.Errorf("AddDependency() blocked_by = %v, want [%d]", task.BlockedBy, b.ID)
+	}
+	if _, err := service.AddDependency(testUserID, b.ID, &domain.AddDependencyRequest{BlockedByID: c.ID}); err != nil {
+		t.Fatalf("AddDependency() error = %v", err)
+	}
+
+	cycles := []struct {
+		name      string
+		id        int64
+		blockedBy int64
+	}{
+		{name: "self dependency", id: a.ID, blockedBy: a.ID},
+		{name: "direct cycle", id: b.ID, blockedBy: a.ID},
+		{name: "transitive cycle", id: c.ID, blockedBy: a.ID},
+	}
+	for _, tt := range cycles {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.AddDependency(testUserID, tt.id, &domain.AddDependencyRequest{BlockedByID: tt.blockedBy})
+			if !errors.Is(err, domain.ErrDependencyCycle) {
+				t.Errorf("AddDependency() error = %v, want %v", err, domain.ErrDependencyCycle)
+			}
+		})
+	}
+
+	doing := domain.StatusDoing
+	if _, err := service.UpdateTask(testUserID, a.ID, &domain.UpdateTaskRequest{Status: &doing}); !errors.Is(err, domain.ErrBlocked) {
+		t.Errorf("starting blocked task error = %v, want %v", err, domain.ErrBlocked)
+	}
+
+	// Unrelated edits to a blocked task are still allowed
+	title := "A renamed"
+	if _, err := service.UpdateTask(testUserID, a.ID, &domain.UpdateTaskRequest{Title: &title}); err != nil {
+		t.Errorf("renaming blocked task error = %v", err)
+	}
+
+	done := domain.StatusDone
+	if _, err := service.UpdateTask(testUserID, c.ID, &domain.UpdateTaskRequest{Status: &done}); err != nil {
+		t.Fatalf("completing unblocked task error = %v", err)
+	}
+	if _, err := service.UpdateTask(testUserID, b.ID, &domain.UpdateTaskRequest{Status: &done}); err != nil {
+		t.Fatalf("completing task with finished blockers error = %v", err)
+	}
+	if _, err := service.UpdateTask(testUserID, a.ID, &domain.UpdateTaskRequest{Status: &doing}); err != nil {
+		t.Errorf("starting task with finished blockers error = %v", err)
+	}
+
+	if err := service.RemoveDependency(testUserID, a.ID, b.ID); err != nil {
+		t.Errorf("RemoveDependency() error = %v", err)
+	}
+	if err := service.RemoveDependency(testUserID, a.ID, b.ID); !errors.Is(err, domain.ErrDependencyNotFound) {
+		t.Errorf("RemoveDependency() twice error = %v, want %v", err, domain.ErrDependencyNotFound)
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"task-manager/internal/domain"
+	"time"
+)
+
+var ErrStreamClosed = errors.New("event stream is closed")
+
+// subscriberBacklog is how many events a subscriber may fall behind before it
+// is dropped. A dropped client reconnects and resumes from the buffer.
+const subscriberBacklog = 64
+
+// StreamEvent is an event numbered in the order the stream received it
+type StreamEvent struct {
+	ID    string
+	Event domain.Event
+	seq   int64
+}
+
+// StreamSubscription receives one user's events as they are published
+type StreamSubscription struct {
+	userID int64
+	events chan StreamEvent
+	// Replay holds the buffered events published after the ID the
+	// subscription resumed from
+	Replay []StreamEvent
+	// Missed reports that events after that ID are no longer buffered, so
+	// the client has to reload instead of resuming
+	Missed bool
+}
+
+// Events delivers the subscription's events. It is closed when the stream
+// shuts down or the subscriber falls too far behind.
+func (s *StreamSubscription) Events() <-chan StreamEvent {
+	return s.events
+}
+
+// EventStream fans published events out to live subscribers and keeps the
+// most recent ones so that a reconnecting client can resume. Event IDs carry
+// the stream's start time so IDs from before a restart are recognised.
+type EventStream struct {
+	mu          sync.Mutex
+	epoch       string
+	size        int
+	seq         int64
+	buffer      []StreamEvent
+	subscribers map[*StreamSubscription]struct{}
+	closed      bool
+}
+
+// NewEventStream returns an EventStream that keeps the last bufferSize
+// events for resuming
+func NewEventStream(bufferSize int) *EventStream {
+	return &EventStream{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		size:        bufferSize,
+		subscribers: make(map[*StreamSubscription]struct{}),
+	}
+}
+
+func (s *EventStream) Publish(event domain.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	s.seq++
+	streamEvent := StreamEvent{ID: s.epoch + "-" + strconv.FormatInt(s.seq, 10), Event: event, seq: s.seq}
+	s.buffer = append(s.buffer, streamEvent)
+	if len(s.buffer) > s.size {
+		s.buffer = s.buffer[len(s.buffer)-s.size:]
+	}
+
+	for sub := range s.subscribers {
+		if sub.userID != event.UserID {
+			continue
+		}
+		select {
+		case sub.events <- streamEvent:
+		default:
+			s.drop(sub)
+		}
+	}
+}
+
+// Subscribe starts receiving userID's events. A non-empty lastEventID resumes
+// after that event, replaying what is still buffered.
+func (s *EventStream) Subscribe(userID int64, lastEventID string) (*StreamSubscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, ErrStreamClosed
+	}
+
+	sub := &StreamSubscription{
+		userID: userID,
+		events: make(chan StreamEvent, subscriberBacklog),
+	}
+	if lastEventID != "" {
+		sub.Replay, sub.Missed = s.since(userID, lastEventID)
+	}
+
+	s.subscribers[sub] = struct{}{}
+	return sub, nil
+}
+
+// since returns userID's buffered events after lastEventID, or reports that
+// some of them are gone
+func (s *EventStream) since(userID int64, lastEventID string) ([]StreamEvent, bool) {
+	epoch, seqStr, ok := strings.Cut(lastEventID, "-")
+	seq, err := strconv.ParseInt(seqStr, 10, 64)
+	if !ok || err != nil || epoch != s.epoch || seq > s.seq {
+		return nil, true
+	}
+
+	// Everything after seq must still be buffered
+	oldest := s.seq + 1
+	if len(s.buffer) > 0 {
+		oldest = s.buffer[0].seq
+	}
+	if seq < oldest-1 {
+		return nil, true
+	}
+
+	var replay []StreamEvent
+	for _, buffered := range s.buffer {
+		if buffered.seq > seq && buffered.Event.UserID == userID {
+			replay = append(replay, buffered)
+		}
+	}
+	return replay, false
+}
+
+// Unsubscribe stops a subscription and closes its channel
+func (s *EventStream) Unsubscribe(sub *StreamSubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.drop(sub)
+}
+
+// Close ends every subscription and refuses new ones, letting streaming
+// requests finish when the server shuts down
+func (s *EventStream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for sub := range s.subscribers {
+		s.drop(sub)
+	}
+}
+
+func (s *EventStream) drop(sub *StreamSubscription) {
+	if _, ok := s.subscribers[sub]; ok {
+		delete(s.subscribers, sub)
+		close(sub.events)
+	}
+}
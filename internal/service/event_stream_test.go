@@ -0,0 +1,68 @@
+package service
+
+import (
+	"task-manager/internal/domain"
+	"testing"
+)
+
+func TestEventStream_Resume(t *testing.T) {
+	stream := NewEventStream(3)
+	sub, _ := stream.Subscribe(testUserID, "")
+	for i := 0; i < 3; i++ {
+		stream.Publish(domain.Event{Type: domain.EventTaskCreated, UserID: testUserID})
+	}
+	stream.Publish(domain.Event{Type: domain.EventTaskCreated, UserID: testUserID + 1})
+	first := <-sub.Events()
+	second := <-sub.Events()
+
+	// Events of other users are buffered but never replayed
+	resumed, err := stream.Subscribe(testUserID, first.ID)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if resumed.Missed || len(resumed.Replay) != 2 || resumed.Replay[0].ID != second.ID {
+		t.Errorf("Expected to replay the 2 events after %s, got %+v", first.ID, resumed)
+	}
+
+	// The first event has been evicted, so resuming from before it loses
+	// events
+	stream.Publish(domain.Event{Type: domain.EventTaskCreated, UserID: testUserID})
+	if lost, _ := stream.Subscribe(testUserID, first.ID); !lost.Missed {
+		t.Error("Expected resuming past the buffer to report missed events")
+	}
+	if restarted, _ := NewEventStream(3).Subscribe(testUserID, second.ID); !restarted.Missed {
+		t.Error("Expected an ID from another stream to report missed events")
+	}
+}
+
+func TestEventStream_DropsSlowSubscribers(t *testing.T) {
+	stream := NewEventStream(1)
+	sub, _ := stream.Subscribe(testUserID, "")
+
+	for i := 0; i < subscriberBacklog+1; i++ {
+		stream.Publish(domain.Event{Type: domain.EventTaskUpdated, UserID: testUserID})
+	}
+
+	received := 0
+	for range sub.Events() {
+		received++
+	}
+	if received != subscriberBacklog {
+		t.Errorf("Expected %d events before the subscriber was dropped, got %d", subscriberBacklog, received)
+	}
+}
+
+func TestEventStream_Close(t *testing.T) {
+	stream := NewEventStream(1)
+	sub, _ := stream.Subscribe(testUserID, "")
+
+	stream.Close()
+	if _, ok := <-sub.Events(); ok {
+		t.Error("Expected Close to end subscriptions")
+	}
+	if _, err := stream.Subscribe(testUserID, ""); err != ErrStreamClosed {
+		t.Errorf("Subscribe() after Close error = %v, want ErrStreamClosed", err)
+	}
+	// Unsubscribing after Close is harmless
+	stream.Unsubscribe(sub)
+}
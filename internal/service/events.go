@@ -0,0 +1,107 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"task-manager/internal/domain"
+	"time"
+)
+
+// EventPublisher announces changes once they have been written
+type EventPublisher interface {
+	Publish(event domain.Event)
+}
+
+// EventBus hands every published event to its subscribers, in the order they
+// subscribed. Subscribers are called synchronously and must not block.
+type EventBus struct {
+	mu          sync.RWMutex
+	nextID      int
+	subscribers map[int]func(domain.Event)
+	order       []int
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[int]func(domain.Event))}
+}
+
+// Subscribe registers fn for every later event and returns a function that
+// removes it again
+func (b *EventBus) Subscribe(fn func(domain.Event)) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = fn
+	b.order = append(b.order, id)
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		delete(b.subscribers, id)
+		for i, subscribed := range b.order {
+			if subscribed == id {
+				b.order = append(b.order[:i], b.order[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+func (b *EventBus) Publish(event domain.Event) {
+	b.mu.RLock()
+	subscribers := make([]func(domain.Event), 0, len(b.order))
+	for _, id := range b.order {
+		subscribers = append(subscribers, b.subscribers[id])
+	}
+	b.mu.RUnlock()
+
+	for _, fn := range subscribers {
+		fn(event)
+	}
+}
+
+// eventBuffer holds the events of a transaction until it commits
+type eventBuffer struct {
+	events []domain.Event
+}
+
+func (b *eventBuffer) Publish(event domain.Event) {
+	b.events = append(b.events, event)
+}
+
+// flush publishes the buffered events to publisher
+func (b *eventBuffer) flush(publisher EventPublisher) {
+	for _, event := range b.events {
+		publish(publisher, event)
+	}
+	b.events = nil
+}
+
+// publish is a no-op when the service was built without a publisher
+func publish(publisher EventPublisher, event domain.Event) {
+	if publisher != nil {
+		publisher.Publish(event)
+	}
+}
+
+func newEvent(userID int64, eventType domain.EventType, data interface{}) domain.Event {
+	return domain.Event{
+		ID:         newEventID(),
+		Type:       eventType,
+		UserID:     userID,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+func newEventID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		panic("crypto/rand failed: " + err.Error())
+	}
+	return "evt_" + hex.EncodeToString(b)
+}
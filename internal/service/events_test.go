@@ -0,0 +1,143 @@
+package service
+
+import (
+	"task-manager/internal/domain"
+	"testing"
+)
+
+// recordingPublisher collects the events published to it
+type recordingPublisher struct {
+	events []domain.Event
+}
+
+func (p *recordingPublisher) Publish(event domain.Event) {
+	p.events = append(p.events, event)
+}
+
+func (p *recordingPublisher) types() []domain.EventType {
+	types := []domain.EventType{}
+	for _, event := range p.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestEventBus_Subscribe(t *testing.T) {
+	bus := NewEventBus()
+	var first, second []domain.EventType
+	unsubscribe := bus.Subscribe(func(event domain.Event) { first = append(first, event.Type) })
+	bus.Subscribe(func(event domain.Event) { second = append(second, event.Type) })
+
+	bus.Publish(domain.Event{Type: domain.EventTaskCreated})
+	unsubscribe()
+	bus.Publish(domain.Event{Type: domain.EventTaskDeleted})
+
+	if len(first) != 1 || first[0] != domain.EventTaskCreated {
+		t.Errorf("first subscriber got %v, want only task.created", first)
+	}
+	if len(second) != 2 {
+		t.Errorf("second subscriber got %v, want both events", second)
+	}
+}
+
+func TestTaskService_PublishesEvents(t *testing.T) {
+	events := &recordingPublisher{}
+	service := NewTaskService(newMockTaskRepository(), newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), events)
+
+	task, err := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Write report", Priority: domain.PriorityLow})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+	// An update that changes nothing is not announced
+	if _, err := service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Title: stringPtr("Write report")}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if _, err := service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Title: stringPtr("Send report")}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if err := service.DeleteTask(testUserID, task.ID); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+
+	want := []domain.EventType{domain.EventTaskCreated, domain.EventTaskUpdated, domain.EventTaskDeleted}
+	got := events.types()
+	if len(got) != len(want) {
+		t.Fatalf("published %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+
+	deleted, ok := events.events[2].Data.(*domain.Task)
+	if !ok || deleted.ID != task.ID || deleted.Title != "Send report" {
+		t.Errorf("task.deleted should carry the task as it was, got %+v", events.events[2].Data)
+	}
+	if events.events[0].UserID != testUserID || events.events[0].ID == "" {
+		t.Errorf("events should carry an ID and their user, got %+v", events.events[0])
+	}
+}
+
+func TestTaskService_BulkPublishesOnlyAfterCommit(t *testing.T) {
+	events := &recordingPublisher{}
+	service := NewTaskService(newMockTaskRepository(), newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), events)
+	first, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "First", Priority: domain.PriorityLow})
+	second, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Second", Priority: domain.PriorityLow})
+	events.events = nil
+
+	high := domain.PriorityHigh
+	result, err := service.BulkTasks(testUserID, &domain.BulkTaskRequest{
+		Action: domain.BulkUpdate,
+		IDs:    []int64{first.ID, 999},
+		Update: &domain.UpdateTaskRequest{Priority: &high},
+	})
+	if err != nil || result.Applied {
+		t.Fatalf("BulkTasks() = %+v, %v, want a rolled back result", result, err)
+	}
+	if len(events.events) != 0 {
+		t.Errorf("a rolled back bulk change should publish nothing, got %v", events.types())
+	}
+
+	if _, err := service.BulkTasks(testUserID, &domain.BulkTaskRequest{
+		Action: domain.BulkUpdate,
+		IDs:    []int64{first.ID, second.ID},
+		Update: &domain.UpdateTaskRequest{Priority: &high},
+	}); err != nil {
+		t.Fatalf("BulkTasks() error = %v", err)
+	}
+	if len(events.events) != 2 {
+		t.Errorf("an applied bulk change should publish an event per task, got %v", events.types())
+	}
+}
+
+func TestCategoryService_PublishesEvents(t *testing.T) {
+	events := &recordingPublisher{}
+	service := NewCategoryService(newMockCategoryRepository(), events)
+
+	category, err := service.CreateCategory(testUserID, &domain.CreateCategoryRequest{Name: "Work"})
+	if err != nil {
+		t.Fatalf("CreateCategory() error = %v", err)
+	}
+	name := "Office"
+	if _, err := service.UpdateCategory(testUserID, category.ID, &domain.UpdateCategoryRequest{Name: &name}); err != nil {
+		t.Fatalf("UpdateCategory() error = %v", err)
+	}
+	if err := service.DeleteCategory(testUserID, category.ID); err != nil {
+		t.Fatalf("DeleteCategory() error = %v", err)
+	}
+	if _, err := service.RestoreCategory(testUserID, category.ID); err != nil {
+		t.Fatalf("RestoreCategory() error = %v", err)
+	}
+
+	want := []domain.EventType{domain.EventCategoryCreated, domain.EventCategoryUpdated, domain.EventCategoryDeleted, domain.EventCategoryRestored}
+	got := events.types()
+	if len(got) != len(want) {
+		t.Fatalf("published %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"fmt"
+	"task-manager/internal/domain"
+	"time"
+)
+
+func (s *taskService) GetTaskHistory(userID, id int64) ([]domain.TaskHistoryEntry, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid task id")
+	}
+
+	if _, err := s.taskRepo.GetByID(userID, id); err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	history, err := s.taskRepo.GetHistory(userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task history: %w", err)
+	}
+
+	return history, nil
+}
+
+// recordHistory stores the field changes between before and after, either of
+// which is nil for a create or delete, and announces them. Updates that change
+// no tracked field are neither recorded nor announced.
+func (s *taskService) recordHistory(userID int64, action domain.HistoryAction, before, after *domain.Task) error {
+	changes := domain.DiffTasks(before, after)
+	if action == domain.HistoryUpdated && len(changes) == 0 {
+		return nil
+	}
+
+	task := before
+	if task == nil {
+		task = after
+	}
+
+	entry := &domain.TaskHistoryEntry{
+		TaskID:    task.ID,
+		UserID:    userID,
+		Action:    action,
+		Changes:   changes,
+		CreatedAt: time.Now(),
+	}
+	if err := s.taskRepo.AddHistory(entry); err != nil {
+		return fmt.Errorf("failed to record history: %w", err)
+	}
+
+	event := newEvent(userID, domain.TaskEventType(action), after)
+	switch action {
+	case domain.HistoryUpdated:
+		event.Previous = before
+	case domain.HistoryDeleted, domain.HistoryPurged:
+		event.Data = before
+	}
+	publish(s.events, event)
+	return nil
+}
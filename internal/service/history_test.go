@@ -0,0 +1,112 @@
+package service
+
+import (
+	"task-manager/internal/domain"
+	"task-manager/internal/repo"
+	"testing"
+	"time"
+)
+
+func TestTaskHistory(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	service := NewTaskService(taskRepo, newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	task, err := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Tracked", Priority: domain.PriorityLow})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	status := domain.StatusDoing
+	if _, err := service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Status: &status}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+
+	// An update that changes nothing is not recorded
+	title := "Tracked"
+	if _, err := service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Title: &title}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+
+	history, err := service.GetTaskHistory(testUserID, task.ID)
+	if err != nil {
+		t.Fatalf("GetTaskHistory() error = %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 history entries, got %+v", history)
+	}
+	if history[0].Action != domain.HistoryCreated || history[0].UserID != testUserID {
+		t.Errorf("first entry should be the create, got %+v", history[0])
+	}
+	update := history[1]
+	if update.Action != domain.HistoryUpdated || len(update.Changes) != 1 {
+		t.Fatalf("second entry should be a single field update, got %+v", update)
+	}
+	if change := update.Changes[0]; change.Field != "status" || change.Old != "todo" || change.New != "doing" {
+		t.Errorf("unexpected change %+v", change)
+	}
+
+	if _, err := service.GetTaskHistory(testUserID, 999); err == nil {
+		t.Error("GetTaskHistory() on missing task should fail")
+	}
+}
+
+func TestTaskHistory_Delete(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	service := NewTaskService(taskRepo, newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	parent, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Parent", Priority: domain.PriorityLow})
+	child, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Child", Priority: domain.PriorityLow, ParentID: &parent.ID})
+
+	if err := service.DeleteTask(testUserID, parent.ID); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+
+	var deleted, moved bool
+	for _, entry := range taskRepo.history {
+		if entry.TaskID == parent.ID && entry.Action == domain.HistoryDeleted {
+			deleted = true
+		}
+		if entry.TaskID == child.ID && entry.Action == domain.HistoryUpdated {
+			moved = len(entry.Changes) == 1 && entry.Changes[0].Field == "parent_id" && entry.Changes[0].New == nil
+		}
+	}
+	if !deleted {
+		t.Error("expected a delete entry for the parent")
+	}
+	if !moved {
+		t.Error("expected the child's parent change to be recorded")
+	}
+}
+
+func TestTaskHistory_UpdateRollsBackWithHistory(t *testing.T) {
+	db, user := openTestDB(t)
+	categoryRepo := repo.NewCategoryRepository(db)
+	service := NewTaskService(repo.NewTaskRepository(db), categoryRepo, domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	now := time.Now().UTC()
+	category := &domain.Category{UserID: user.ID, Name: "Work", CreatedAt: now, UpdatedAt: now}
+	if err := categoryRepo.Create(category); err != nil {
+		t.Fatalf("Create() category error = %v", err)
+	}
+	task, err := service.CreateTask(user.ID, &domain.CreateTaskRequest{Title: "Tracked", Priority: domain.PriorityLow})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	// The history entry is written last, after the task and its categories
+	if _, err := db.Exec(`CREATE TRIGGER fail_history BEFORE INSERT ON task_history BEGIN SELECT RAISE(ABORT, 'history unavailable'); END`); err != nil {
+		t.Fatalf("failed to create trigger: %v", err)
+	}
+	title := "Renamed"
+	if _, err := service.UpdateTask(user.ID, task.ID, &domain.UpdateTaskRequest{Title: &title, CategoryIDs: &[]int64{category.ID}}); err == nil {
+		t.Fatal("UpdateTask() expected an error when the history cannot be recorded")
+	}
+
+	stored, err := service.GetTask(user.ID, task.ID)
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if stored.Title != "Tracked" || stored.Version != task.Version || len(stored.Categories) != 0 {
+		t.Errorf("failed update should leave the task unchanged, got %+v", stored)
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"task-manager/internal/domain"
+	"task-manager/internal/repo"
+	"time"
+)
+
+// errImportRolledBack undoes an import that is a dry run or has rejected rows
+var errImportRolledBack = errors.New("import rolled back")
+
+// ImportTasks creates a task for every row in a single transaction. Nothing
+// is kept if any row is rejected or the import is a dry run; the result then
+// lists the rejected rows and how many tasks would have been created.
+func (s *taskService) ImportTasks(userID int64, rows []domain.TaskImportRow, opts domain.TaskImportOptions) (*domain.TaskImportResult, error) {
+	var result *domain.TaskImportResult
+	events := &eventBuffer{}
+	err := s.taskRepo.WithTx(func(tasks repo.TaskRepository, categories repo.CategoryRepository) error {
+		tx := &taskService{taskRepo: tasks, categoryRepo: categories, workflow: s.workflow, events: events}
+		result = &domain.TaskImportResult{
+			DryRun:            opts.DryRun,
+			CreatedCategories: []string{},
+			Errors:            []domain.TaskImportError{},
+		}
+
+		importer, err := tx.newCategoryImporter(userID, opts.CreateCategories)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			if err := tx.importRow(userID, row, importer); err != nil {
+				result.Errors = append(result.Errors, domain.TaskImportError{Line: row.Line, Error: err.Error()})
+				continue
+			}
+			result.Imported++
+		}
+		result.CreatedCategories = append(result.CreatedCategories, importer.created...)
+
+		if len(result.Errors) > 0 || opts.DryRun {
+			return errImportRolledBack
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRolledBack) {
+		return nil, fmt.Errorf("failed to import tasks: %w", err)
+	}
+
+	result.Applied = err == nil
+	if result.Applied {
+		events.flush(s.events)
+	}
+	return result, nil
+}
+
+func (s *taskService) importRow(userID int64, row domain.TaskImportRow, importer *categoryImporter) error {
+	if row.Err != nil {
+		return row.Err
+	}
+
+	req := row.Request
+	for _, name := range row.CategoryNames {
+		id, err := importer.categoryID(name)
+		if err != nil {
+			return err
+		}
+		req.CategoryIDs = append(req.CategoryIDs, id)
+	}
+
+	task, err := s.CreateTask(userID, &req)
+	if err != nil {
+		return err
+	}
+	if row.Status != task.Status {
+		if _, err := s.UpdateTask(userID, task.ID, &domain.UpdateTaskRequest{Status: &row.Status}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// categoryImporter resolves the category names of an import, creating the
+// missing ones when allowed
+type categoryImporter struct {
+	userID       int64
+	categoryRepo repo.CategoryRepository
+	events       EventPublisher
+	create       bool
+	byName       map[string]int64
+	trashed      map[string]bool
+	created      []string
+}
+
+func (s *taskService) newCategoryImporter(userID int64, create bool) (*categoryImporter, error) {
+	categories, err := s.categoryRepo.GetAll(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	trash, err := s.categoryRepo.GetTrash(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted categories: %w", err)
+	}
+
+	importer := &categoryImporter{
+		userID:       userID,
+		categoryRepo: s.categoryRepo,
+		events:       s.events,
+		create:       create,
+		byName:       make(map[string]int64, len(categories)),
+		trashed:      make(map[string]bool, len(trash)),
+	}
+	for _, category := range categories {
+		importer.byName[category.Name] = category.ID
+	}
+	for _, category := range trash {
+		importer.trashed[category.Name] = true
+	}
+	return importer, nil
+}
+
+func (c *categoryImporter) categoryID(name string) (int64, error) {
+	if id, ok := c.byName[name]; ok {
+		return id, nil
+	}
+	if c.trashed[name] {
+		return 0, fmt.Errorf("category %q is in the trash", name)
+	}
+	if !c.create {
+		return 0, fmt.Errorf("category %q: %w", name, domain.ErrCategoryNotFound)
+	}
+
+	req := &domain.CreateCategoryRequest{Name: name}
+	if err := req.Validate(); err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	category := &domain.Category{
+		UserID:    c.userID,
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := c.categoryRepo.Create(category); err != nil {
+		return 0, fmt.Errorf("failed to create category %q: %w", name, err)
+	}
+
+	c.byName[name] = category.ID
+	c.created = append(c.created, name)
+	publish(c.events, newEvent(c.userID, domain.EventCategoryCreated, category))
+	return category.ID, nil
+}
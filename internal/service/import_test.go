@@ -0,0 +1,85 @@
+package service
+
+import (
+	"strings"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+func readImportRows(t *testing.T, input string) []domain.TaskImportRow {
+	t.Helper()
+	rows, err := domain.ReadTaskImportRows(strings.NewReader(input), domain.DefaultWorkflow())
+	if err != nil {
+		t.Fatalf("ReadTaskImportRows() error = %v", err)
+	}
+	return rows
+}
+
+func TestTaskService_ImportTasks(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	categoryRepo := newMockCategoryRepository()
+	taskRepo.categoryRepo = categoryRepo
+	service := NewTaskService(taskRepo, categoryRepo, domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+	NewCategoryService(categoryRepo, nil).CreateCategory(testUserID, &domain.CreateCategoryRequest{Name: "Work"})
+
+	rows := readImportRows(t, "title,priority,status,categories\nShip it,high,done,Work\nPlan,low,,Work;Ideas\n")
+
+	result, err := service.ImportTasks(testUserID, rows, domain.TaskImportOptions{CreateCategories: true})
+	if err != nil {
+		t.Fatalf("ImportTasks() error = %v", err)
+	}
+	if !result.Applied || result.Imported != 2 || len(result.Errors) != 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if len(result.CreatedCategories) != 1 || result.CreatedCategories[0] != "Ideas" {
+		t.Errorf("created categories = %v, want [Ideas]", result.CreatedCategories)
+	}
+
+	tasks, _ := service.GetAllTasks(testUserID)
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(tasks))
+	}
+	for _, task := range tasks {
+		if task.Title == "Ship it" && task.Status != domain.StatusDone {
+			t.Errorf("imported status = %s, want %s", task.Status, domain.StatusDone)
+		}
+	}
+}
+
+func TestTaskService_ImportTasks_RejectsWholeFile(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	service := NewTaskService(taskRepo, newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	rows := readImportRows(t, "title,priority,categories\nGood,low,\nNo priority,,\nUnknown category,low,Missing\n")
+
+	result, err := service.ImportTasks(testUserID, rows, domain.TaskImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportTasks() error = %v", err)
+	}
+	if result.Applied || result.Imported != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if len(result.Errors) != 2 || result.Errors[0].Line != 3 || result.Errors[1].Line != 4 {
+		t.Errorf("expected errors on lines 3 and 4, got %+v", result.Errors)
+	}
+	if tasks, _ := service.GetAllTasks(testUserID); len(tasks) != 0 {
+		t.Errorf("a rejected import should create no tasks, got %d", len(tasks))
+	}
+}
+
+func TestTaskService_ImportTasks_DryRun(t *testing.T) {
+	service := NewTaskService(newMockTaskRepository(), newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	rows := readImportRows(t, "title,priority\nOne,low\nTwo,medium\n")
+
+	result, err := service.ImportTasks(testUserID, rows, domain.TaskImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportTasks() error = %v", err)
+	}
+	if result.Applied || !result.DryRun || result.Imported != 2 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if tasks, _ := service.GetAllTasks(testUserID); len(tasks) != 0 {
+		t.Errorf("a dry run should create no tasks, got %d", len(tasks))
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"fmt"
+	"task-manager/internal/domain"
+)
+
+func (s *taskService) MoveTask(userID, id int64, req *domain.MoveTaskRequest) (*domain.Task, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid task id")
+	}
+
+	if err := req.Validate(s.workflow.Get()); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	task, err := s.taskRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	status := req.Status
+	if status == "" {
+		status = task.Status
+	}
+
+	var lower, upper string
+	if req.AfterID != nil {
+		if lower, err = s.neighborRank(userID, id, *req.AfterID, status); err != nil {
+			return nil, err
+		}
+	}
+	if req.BeforeID != nil {
+		if upper, err = s.neighborRank(userID, id, *req.BeforeID, status); err != nil {
+			return nil, err
+		}
+	}
+
+	// A single neighbor places the task right next to it
+	switch {
+	case req.AfterID != nil && req.BeforeID == nil:
+		upper, err = s.taskRepo.GetAdjacentRank(userID, status, lower, id, true)
+	case req.AfterID == nil && req.BeforeID != nil:
+		lower, err = s.taskRepo.GetAdjacentRank(userID, status, upper, id, false)
+	case req.AfterID == nil && req.BeforeID == nil:
+		lower, err = s.taskRepo.GetAdjacentRank(userID, status, "", id, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rank, err := domain.RankBetween(lower, upper)
+	if err != nil {
+		return nil, err
+	}
+
+	// The update leaves the due date alone, so overdue cards can be moved
+	return s.UpdateTask(userID, id, &domain.UpdateTaskRequest{
+		Status:          &status,
+		Rank:            &rank,
+		ExpectedVersion: req.ExpectedVersion,
+	})
+}
+
+// neighborRank returns the rank of a task that a moved task is placed next
+// to, which must be another task in the target column
+func (s *taskService) neighborRank(userID, id, neighborID int64, status domain.TaskStatus) (string, error) {
+	if neighborID == id {
+		return "", fmt.Errorf("%w: a task cannot be placed next to itself", domain.ErrInvalidMove)
+	}
+
+	neighbor, err := s.taskRepo.GetByID(userID, neighborID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get neighbor task: %w", err)
+	}
+	if neighbor.Status != status {
+		return "", fmt.Errorf("%w: task %d is not in the %s column", domain.ErrInvalidMove, neighborID, status)
+	}
+
+	return neighbor.Rank, nil
+}
+
+// rankLast places a task at the end of its status column
+func (s *taskService) rankLast(userID int64, task *domain.Task) error {
+	last, err := s.taskRepo.GetAdjacentRank(userID, task.Status, "", task.ID, false)
+	if err != nil {
+		return err
+	}
+
+	rank, err := domain.RankBetween(last, "")
+	if err != nil {
+		return err
+	}
+	task.Rank = rank
+	return nil
+}
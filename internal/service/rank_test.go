@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"task-manager/internal/domain"
+	"testing"
+	"time"
+)
+
+func (m *mockTaskRepository) GetAdjacentRank(userID int64, status domain.TaskStatus, rank string, excludeID int64, next bool) (string, error) {
+	adjacent := ""
+	for _, task := range m.tasks {
+		if task.Status != status || task.ID == excludeID {
+			continue
+		}
+		switch {
+		case next:
+			if task.Rank > rank && (adjacent == "" || task.Rank < adjacent) {
+				adjacent = task.Rank
+			}
+		case rank == "" || task.Rank < rank:
+			if task.Rank > adjacent {
+				adjacent = task.Rank
+			}
+		}
+	}
+	return adjacent, nil
+}
+
+// columnOrder lists the ids of a status column by rank
+func columnOrder(service TaskService, status domain.TaskStatus) []int64 {
+	tasks, _ := service.GetTasksWithFilters(testUserID, &domain.TaskFilters{Statuses: []domain.TaskStatus{status}})
+	for i := 1; i < len(tasks); i++ {
+		for j := i; j > 0 && tasks[j].Rank < tasks[j-1].Rank; j-- {
+			tasks[j], tasks[j-1] = tasks[j-1], tasks[j]
+		}
+	}
+	ids := make([]int64, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+func sameIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTaskService_MoveTask(t *testing.T) {
+	service := NewTaskService(newMockTaskRepository(), newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+	var ids []int64
+	for _, title := range []string{"One", "Two", "Three", "Four"} {
+		task, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: title, Priority: domain.PriorityLow})
+		ids = append(ids, task.ID)
+	}
+	if order := columnOrder(service, domain.StatusTodo); !sameIDs(order, ids) {
+		t.Fatalf("Expected new tasks to be appended, got %v", order)
+	}
+
+	id := func(i int) *int64 { return &ids[i] }
+	doing := domain.StatusDoing
+
+	steps := []struct {
+		name  string
+		id    int64
+		req   domain.MoveTaskRequest
+		todo  []int64
+		doing []int64
+	}{
+		{"between neighbors", ids[3], domain.MoveTaskRequest{AfterID: id(0), BeforeID: id(1)},
+			[]int64{ids[0], ids[3], ids[1], ids[2]}, nil},
+		{"to the top", ids[2], domain.MoveTaskRequest{BeforeID: id(0)},
+			[]int64{ids[2], ids[0], ids[3], ids[1]}, nil},
+		{"right after a task", ids[1], domain.MoveTaskRequest{AfterID: id(2)},
+			[]int64{ids[2], ids[1], ids[0], ids[3]}, nil},
+		{"to another column", ids[0], domain.MoveTaskRequest{Status: doing},
+			[]int64{ids[2], ids[1], ids[3]}, []int64{ids[0]}},
+		{"above a task of another column", ids[3], domain.MoveTaskRequest{Status: doing, BeforeID: id(0)},
+			[]int64{ids[2], ids[1]}, []int64{ids[3], ids[0]}},
+		{"to the end of its column", ids[2], domain.MoveTaskRequest{},
+			[]int64{ids[1], ids[2]}, []int64{ids[3], ids[0]}},
+	}
+	for _, step := range steps {
+		task, err := service.MoveTask(testUserID, step.id, &step.req)
+		if err != nil {
+			t.Fatalf("%s: MoveTask() error = %v", step.name, err)
+		}
+		if step.req.Status != "" && task.Status != step.req.Status {
+			t.Errorf("%s: expected status %s, got %s", step.name, step.req.Status, task.Status)
+		}
+		if order := columnOrder(service, domain.StatusTodo); !sameIDs(order, step.todo) {
+			t.Errorf("%s: expected todo %v, got %v", step.name, step.todo, order)
+		}
+		if order := columnOrder(service, domain.StatusDoing); !sameIDs(order, step.doing) {
+			t.Errorf("%s: expected doing %v, got %v", step.name, step.doing, order)
+		}
+	}
+
+	invalid := []struct {
+		name string
+		req  domain.MoveTaskRequest
+	}{
+		{"next to itself", domain.MoveTaskRequest{AfterID: id(1)}},
+		{"next to a task of another column", domain.MoveTaskRequest{AfterID: id(0)}},
+		{"between swapped neighbors", domain.MoveTaskRequest{Status: doing, AfterID: id(0), BeforeID: id(3)}},
+	}
+	for _, tt := range invalid {
+		if _, err := service.MoveTask(testUserID, ids[1], &tt.req); !errors.Is(err, domain.ErrInvalidMove) {
+			t.Errorf("%s: MoveTask() error = %v, want ErrInvalidMove", tt.name, err)
+		}
+	}
+}
+
+func TestTaskService_MoveOverdueTask(t *testing.T) {
+	mockRepo := newMockTaskRepository()
+	service := NewTaskService(mockRepo, newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+	first, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "First", Priority: domain.PriorityLow})
+	late, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Late", Priority: domain.PriorityLow})
+
+	overdue := time.Now().AddDate(0, 0, -3)
+	mockRepo.tasks[late.ID].DueDate = &overdue
+
+	task, err := service.MoveTask(testUserID, late.ID, &domain.MoveTaskRequest{BeforeID: &first.ID})
+	if err != nil {
+		t.Fatalf("MoveTask() overdue error = %v", err)
+	}
+	if order := columnOrder(service, domain.StatusTodo); !sameIDs(order, []int64{late.ID, first.ID}) {
+		t.Errorf("Expected the overdue task moved to the top, got %v", order)
+	}
+
+	if task, err = service.MoveTask(testUserID, late.ID, &domain.MoveTaskRequest{Status: domain.StatusDoing}); err != nil {
+		t.Fatalf("MoveTask() overdue to another column error = %v", err)
+	}
+	if task.Status != domain.StatusDoing || task.DueDate == nil || !task.DueDate.Equal(overdue) {
+		t.Errorf("Expected the overdue task in doing with its due date kept, got %+v", task)
+	}
+}
+
+func TestTaskService_StatusChangeRanksLast(t *testing.T) {
+	service := NewTaskService(newMockTaskRepository(), newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+	first, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "First", Priority: domain.PriorityLow})
+	second, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Second", Priority: domain.PriorityLow})
+
+	doing := domain.StatusDoing
+	service.UpdateTask(testUserID, second.ID, &domain.UpdateTaskRequest{Status: &doing})
+	service.UpdateTask(testUserID, first.ID, &domain.UpdateTaskRequest{Status: &doing})
+
+	if order := columnOrder(service, doing); !sameIDs(order, []int64{second.ID, first.ID}) {
+		t.Errorf("Expected tasks to join the end of their new column, got %v", order)
+	}
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"task-manager/internal/domain"
+	"time"
+)
+
+// startSeries makes task the first occurrence of a new series. The task's
+// due date anchors the rule.
+func (s *taskService) startSeries(userID int64, task *domain.Task, rrule string) error {
+	if task.DueDate == nil {
+		return errors.New("recurring tasks require a due date")
+	}
+	if task.ParentID != nil {
+		return domain.ErrSeriesSubtask
+	}
+
+	rule, err := domain.ParseRRule(rrule)
+	if err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	now := time.Now()
+	series := &domain.TaskSeries{
+		UserID:      userID,
+		RRule:       rule.String(),
+		DTStart:     *task.DueDate,
+		Title:       task.Title,
+		Description: task.Description,
+		Priority:    task.Priority,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.taskRepo.CreateSeries(series); err != nil {
+		return err
+	}
+
+	task.SeriesID = &series.ID
+	task.RRule = series.RRule
+	return nil
+}
+
+// applyRecurrence handles the recurrence side of an update. Without the
+// series scope only the task itself changes, apart from an rrule making a
+// plain task recurring. With the series scope the title, description,
+// priority and categories also go to the series template and to every other
+// open occurrence, and an empty rrule ends the series.
+func (s *taskService) applyRecurrence(userID int64, task *domain.Task, req *domain.UpdateTaskRequest) error {
+	if req.Scope != domain.ScopeSeries {
+		if req.RRule == nil {
+			return nil
+		}
+		if task.SeriesID != nil {
+			return fmt.Errorf("changing the rule of a recurring task requires scope %q", domain.ScopeSeries)
+		}
+		if *req.RRule == "" {
+			return fmt.Errorf("cannot end recurrence: %w", domain.ErrNotRecurring)
+		}
+		return s.startSeries(userID, task, *req.RRule)
+	}
+
+	if task.SeriesID == nil {
+		return fmt.Errorf("cannot update series: %w", domain.ErrNotRecurring)
+	}
+
+	series, err := s.taskRepo.GetSeries(userID, *task.SeriesID)
+	if err != nil {
+		return err
+	}
+
+	if req.RRule != nil && *req.RRule == "" {
+		occurrences, err := s.taskRepo.GetSeriesTasks(userID, series.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.taskRepo.DeleteSeries(userID, series.ID); err != nil {
+			return err
+		}
+		task.SeriesID = nil
+		task.RRule = ""
+
+		// The other occurrences become plain tasks as well
+		for _, occurrence := range occurrences {
+			if occurrence.ID == task.ID {
+				continue
+			}
+			detached := *occurrence
+			detached.SeriesID = nil
+			detached.RRule = ""
+			if err := s.recordHistory(userID, domain.HistoryUpdated, occurrence, &detached); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if req.Title != nil {
+		series.Title = *req.Title
+	}
+	if req.Description != nil {
+		series.Description = *req.Description
+	}
+	if req.Priority != nil {
+		series.Priority = *req.Priority
+	}
+	if req.RRule != nil {
+		rule, err := domain.ParseRRule(*req.RRule)
+		if err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+		series.RRule = rule.String()
+	}
+	series.UpdatedAt = time.Now().UTC()
+
+	if err := s.taskRepo.UpdateSeries(series); err != nil {
+		return err
+	}
+	task.RRule = series.RRule
+
+	occurrences, err := s.taskRepo.GetSeriesTasks(userID, series.ID)
+	if err != nil {
+		return err
+	}
+	workflow := s.workflow.Get()
+	for _, occurrence := range occurrences {
+		if occurrence.ID == task.ID || workflow.IsClosed(occurrence.Status) {
+			continue
+		}
+		before := *occurrence
+		occurrence.Title = series.Title
+		occurrence.Description = series.Description
+		occurrence.Priority = series.Priority
+		occurrence.UpdatedAt = series.UpdatedAt
+		if err := s.taskRepo.Update(occurrence); err != nil {
+			return fmt.Errorf("failed to update occurrence: %w", err)
+		}
+		if req.CategoryIDs != nil {
+			if err := s.replaceCategories(occurrence.ID, *req.CategoryIDs); err != nil {
+				return err
+			}
+		}
+
+		updated, err := s.taskRepo.GetByID(userID, occurrence.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.recordHistory(userID, domain.HistoryUpdated, &before, updated); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scheduleNextOccurrence creates the occurrence that follows a completed one,
+// from the series template and with the completed task's categories.
+// Occurrences that fell into the past while the task was open are skipped
+// because due dates may not be in the past.
+func (s *taskService) scheduleNextOccurrence(userID int64, completed *domain.Task) error {
+	series, err := s.taskRepo.GetSeries(userID, *completed.SeriesID)
+	if err != nil {
+		return err
+	}
+
+	// Completing a reopened occurrence must not schedule a second copy
+	occurrences, err := s.taskRepo.GetSeriesTasks(userID, series.ID)
+	if err != nil {
+		return err
+	}
+	workflow := s.workflow.Get()
+	for _, occurrence := range occurrences {
+		if occurrence.ID != completed.ID && !workflow.IsClosed(occurrence.Status) {
+			return nil
+		}
+	}
+
+	rule, err := domain.ParseRRule(series.RRule)
+	if err != nil {
+		return err
+	}
+
+	after := time.Now().Truncate(24 * time.Hour).Add(-time.Nanosecond)
+	if completed.DueDate != nil && completed.DueDate.After(after) {
+		after = *completed.DueDate
+	}
+	due, ok := rule.Next(series.DTStart, after)
+	if !ok {
+		// COUNT or UNTIL has ended the series
+		return nil
+	}
+
+	now := time.Now().UTC()
+	next := &domain.Task{
+		UserID:      userID,
+		Title:       series.Title,
+		Description: series.Description,
+		Status:      workflow.InitialStatus(),
+		Priority:    series.Priority,
+		DueDate:     utcTime(&due),
+		SeriesID:    &series.ID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.rankLast(userID, next); err != nil {
+		return err
+	}
+	if err := s.taskRepo.Create(next); err != nil {
+		return fmt.Errorf("failed to create next occurrence: %w", err)
+	}
+
+	for _, category := range completed.Categories {
+		if err := s.categoryRepo.AddTaskCategory(next.ID, category.ID); err != nil {
+			return fmt.Errorf("failed to add category to next occurrence: %w", err)
+		}
+	}
+
+	created, err := s.taskRepo.GetByID(userID, next.ID)
+	if err != nil {
+		return err
+	}
+
+	return s.recordHistory(userID, domain.HistoryCreated, nil, created)
+}
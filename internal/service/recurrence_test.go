@@ -0,0 +1,188 @@
+package service
+
+import (
+	"errors"
+	"task-manager/internal/domain"
+	"task-manager/internal/repo"
+	"testing"
+	"time"
+)
+
+func TestTaskService_RecurringTask(t *testing.T) {
+	mockRepo := newMockTaskRepository()
+	service := NewTaskService(mockRepo, newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	due := time.Now().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	task, err := service.CreateTask(testUserID, &domain.CreateTaskRequest{
+		Title:    "Weekly report",
+		Priority: domain.PriorityHigh,
+		DueDate:  &due,
+		RRule:    "freq=weekly",
+	})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+	if task.SeriesID == nil || task.RRule != "FREQ=WEEKLY" {
+		t.Fatalf("CreateTask() series = %v, rrule = %q; want a weekly series", task.SeriesID, task.RRule)
+	}
+
+	// Editing one occurrence leaves the series template alone
+	title := "Weekly report (short week)"
+	if _, err := service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Title: &title}); err != nil {
+		t.Fatalf("UpdateTask() occurrence error = %v", err)
+	}
+
+	done := domain.StatusDone
+	if _, err := service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Status: &done}); err != nil {
+		t.Fatalf("UpdateTask() complete error = %v", err)
+	}
+
+	occurrences, _ := mockRepo.GetSeriesTasks(testUserID, *task.SeriesID)
+	if len(occurrences) != 2 {
+		t.Fatalf("series has %d occurrences after completion, want 2", len(occurrences))
+	}
+	next := occurrences[1]
+	if next.Status != domain.StatusTodo || next.Title != "Weekly report" || next.Priority != domain.PriorityHigh {
+		t.Errorf("next occurrence = %+v, want a todo copy of the series template", next)
+	}
+	if next.DueDate == nil || !next.DueDate.Equal(due.AddDate(0, 0, 7)) {
+		t.Errorf("next occurrence due = %v, want %v", next.DueDate, due.AddDate(0, 0, 7))
+	}
+
+	// Completing the same occurrence again must not schedule another copy
+	todo := domain.StatusTodo
+	service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Status: &todo})
+	service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Status: &done})
+	if occurrences, _ := mockRepo.GetSeriesTasks(testUserID, *task.SeriesID); len(occurrences) != 2 {
+		t.Errorf("series has %d occurrences after re-completion, want 2", len(occurrences))
+	}
+
+	// Editing the series updates the template and open occurrences only
+	seriesTitle := "Team report"
+	monthly := "FREQ=MONTHLY"
+	if _, err := service.UpdateTask(testUserID, next.ID, &domain.UpdateTaskRequest{Title: &seriesTitle, RRule: &monthly, Scope: domain.ScopeSeries}); err != nil {
+		t.Fatalf("UpdateTask() series error = %v", err)
+	}
+	series, _ := mockRepo.GetSeries(testUserID, *task.SeriesID)
+	if series.Title != seriesTitle || series.RRule != "FREQ=MONTHLY" {
+		t.Errorf("series = %+v, want updated title and rule", series)
+	}
+	if completed, _ := mockRepo.GetByID(testUserID, task.ID); completed.Title != title {
+		t.Errorf("completed occurrence title = %q, want it unchanged", completed.Title)
+	}
+
+	// Changing the rule requires the series scope
+	if _, err := service.UpdateTask(testUserID, next.ID, &domain.UpdateTaskRequest{RRule: &monthly}); err == nil {
+		t.Error("UpdateTask() rrule without series scope should fail")
+	}
+
+	// An empty rule ends the series
+	none := ""
+	ended, err := service.UpdateTask(testUserID, next.ID, &domain.UpdateTaskRequest{RRule: &none, Scope: domain.ScopeSeries})
+	if err != nil || ended.SeriesID != nil {
+		t.Fatalf("UpdateTask() end series = %v, %v; want task without series", ended, err)
+	}
+	if _, err := service.UpdateTask(testUserID, next.ID, &domain.UpdateTaskRequest{Title: &title, Scope: domain.ScopeSeries}); !errors.Is(err, domain.ErrNotRecurring) {
+		t.Errorf("UpdateTask() series scope on plain task error = %v, want %v", err, domain.ErrNotRecurring)
+	}
+}
+
+func TestTaskService_RecurringTaskCount(t *testing.T) {
+	mockRepo := newMockTaskRepository()
+	service := NewTaskService(mockRepo, newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	due := time.Now().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	task, err := service.CreateTask(testUserID, &domain.CreateTaskRequest{
+		Title:    "Twice",
+		Priority: domain.PriorityLow,
+		DueDate:  &due,
+		RRule:    "FREQ=DAILY;COUNT=2",
+	})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	done := domain.StatusDone
+	service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Status: &done})
+	occurrences, _ := mockRepo.GetSeriesTasks(testUserID, *task.SeriesID)
+	if len(occurrences) != 2 {
+		t.Fatalf("series has %d occurrences, want 2", len(occurrences))
+	}
+
+	service.UpdateTask(testUserID, occurrences[1].ID, &domain.UpdateTaskRequest{Status: &done})
+	if occurrences, _ := mockRepo.GetSeriesTasks(testUserID, *task.SeriesID); len(occurrences) != 2 {
+		t.Errorf("series has %d occurrences after COUNT is reached, want 2", len(occurrences))
+	}
+}
+
+func TestTaskService_CompleteOverdueRecurringTask(t *testing.T) {
+	mockRepo := newMockTaskRepository()
+	service := NewTaskService(mockRepo, newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	today := time.Now().Truncate(24 * time.Hour)
+	tomorrow := today.AddDate(0, 0, 1)
+	task, err := service.CreateTask(testUserID, &domain.CreateTaskRequest{
+		Title:    "Water plants",
+		Priority: domain.PriorityLow,
+		DueDate:  &tomorrow,
+		RRule:    "FREQ=DAILY",
+	})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	// The occurrence was left open for three days
+	overdue := today.AddDate(0, 0, -3)
+	mockRepo.series[*task.SeriesID].DTStart = overdue
+	mockRepo.tasks[task.ID].DueDate = &overdue
+
+	done := domain.StatusDone
+	if _, err := service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Status: &done}); err != nil {
+		t.Fatalf("UpdateTask() complete overdue error = %v", err)
+	}
+
+	occurrences, _ := mockRepo.GetSeriesTasks(testUserID, *task.SeriesID)
+	if len(occurrences) != 2 {
+		t.Fatalf("series has %d occurrences after completion, want 2", len(occurrences))
+	}
+	// The occurrences that fell into the past are skipped
+	if next := occurrences[1]; next.DueDate == nil || !next.DueDate.Equal(today) {
+		t.Errorf("next occurrence due = %v, want %v", next.DueDate, today)
+	}
+}
+
+func TestTaskService_CompleteRecurringTaskIsAtomic(t *testing.T) {
+	db, user := openTestDB(t)
+	taskRepo := repo.NewTaskRepository(db)
+	service := NewTaskService(taskRepo, repo.NewCategoryRepository(db), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	due := time.Now().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	task, err := service.CreateTask(user.ID, &domain.CreateTaskRequest{Title: "Standup", Priority: domain.PriorityLow, DueDate: &due, RRule: "FREQ=DAILY"})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	// The next occurrence cannot be created
+	if _, err := db.Exec(`CREATE TRIGGER fail_occurrence BEFORE INSERT ON tasks WHEN NEW.series_id IS NOT NULL BEGIN SELECT RAISE(ABORT, 'disk full'); END`); err != nil {
+		t.Fatalf("failed to create trigger: %v", err)
+	}
+	done := domain.StatusDone
+	if _, err := service.UpdateTask(user.ID, task.ID, &domain.UpdateTaskRequest{Status: &done}); err == nil {
+		t.Fatal("UpdateTask() expected an error when the next occurrence cannot be created")
+	}
+	if stored, _ := service.GetTask(user.ID, task.ID); stored.Status != domain.StatusTodo || stored.CompletedAt != nil {
+		t.Errorf("failed completion should leave the task open, got %+v", stored)
+	}
+
+	// A retry completes the task and schedules the next occurrence
+	if _, err := db.Exec(`DROP TRIGGER fail_occurrence`); err != nil {
+		t.Fatalf("failed to drop trigger: %v", err)
+	}
+	if _, err := service.UpdateTask(user.ID, task.ID, &domain.UpdateTaskRequest{Status: &done}); err != nil {
+		t.Fatalf("UpdateTask() retry error = %v", err)
+	}
+	occurrences, _ := taskRepo.GetSeriesTasks(user.ID, *task.SeriesID)
+	if len(occurrences) != 2 {
+		t.Errorf("series has %d occurrences after the retry, want 2", len(occurrences))
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"task-manager/internal/domain"
+	"task-manager/internal/notify"
+	"task-manager/internal/repo"
+	"time"
+)
+
+// reminderBatchSize caps how many reminders SendDue sends
+const reminderBatchSize = 100
+
+type ReminderService interface {
+	// SendDue sends the reminders for open tasks that are overdue or due
+	// within the lead time and returns how many were sent
+	SendDue() (int, error)
+}
+
+type reminderService struct {
+	reminderRepo repo.ReminderRepository
+	notifier     notify.Notifier
+	lead         time.Duration
+	policy       domain.ReminderRetryPolicy
+}
+
+func NewReminderService(reminderRepo repo.ReminderRepository, notifier notify.Notifier, lead time.Duration, policy domain.ReminderRetryPolicy) ReminderService {
+	return &reminderService{
+		reminderRepo: reminderRepo,
+		notifier:     notifier,
+		lead:         lead,
+		policy:       policy,
+	}
+}
+
+// SendDue claims each reminder before delivering it, so a crash in between
+// loses that reminder rather than sending it twice. Failed deliveries are
+// retried after a growing delay until the policy gives up.
+func (s *reminderService) SendDue() (int, error) {
+	reminders, err := s.reminderRepo.GetDue(time.Now(), s.lead, reminderBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var errs []error
+	for i := range reminders {
+		reminder := &reminders[i]
+		reminder.SentAt = time.Now().UTC()
+		claimed, err := s.reminderRepo.Claim(reminder)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.notifier.Notify(reminder); err != nil {
+			errs = append(errs, err)
+			reminder.Attempts++
+			var next *time.Time
+			if reminder.Attempts < s.policy.MaxAttempts {
+				retryAt := time.Now().Add(s.policy.RetryDelay(reminder.Attempts)).UTC()
+				next = &retryAt
+			}
+			if err := s.reminderRepo.RecordFailure(reminder, next, err.Error()); err != nil {
+				return sent, err
+			}
+			continue
+		}
+		sent++
+	}
+	return sent, errors.Join(errs...)
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"errors"
+	"task-manager/internal/domain"
+	"task-manager/internal/repo"
+	"testing"
+	"time"
+)
+
+type reminderKey struct {
+	taskID  int64
+	kind    domain.ReminderKind
+	dueDate time.Time
+}
+
+// reminderFailure is the failed delivery state of a reminder
+type reminderFailure struct {
+	attempts      int
+	nextAttemptAt *time.Time
+	lastError     string
+}
+
+// mockReminderRepository derives reminders from the tasks of a mock task
+// repository, all owned by the same user
+type mockReminderRepository struct {
+	taskRepo *mockTaskRepository
+	sent     map[reminderKey]bool
+	failed   map[reminderKey]reminderFailure
+}
+
+func newMockReminderRepository(taskRepo *mockTaskRepository) *mockReminderRepository {
+	return &mockReminderRepository{taskRepo: taskRepo, sent: make(map[reminderKey]bool), failed: make(map[reminderKey]reminderFailure)}
+}
+
+// retryable reports whether a sent reminder failed and is due to be retried
+func (m *mockReminderRepository) retryable(key reminderKey, now time.Time) bool {
+	failure, ok := m.failed[key]
+	return ok && failure.nextAttemptAt != nil && !failure.nextAttemptAt.After(now)
+}
+
+func (m *mockReminderRepository) GetDue(now time.Time, lead time.Duration, limit int) ([]domain.Reminder, error) {
+	reminders := []domain.Reminder{}
+	for _, task := range m.taskRepo.tasks {
+		if task.DueDate == nil || !task.DueDate.Before(now.Add(lead)) || domain.DefaultWorkflow().IsClosed(task.Status) {
+			continue
+		}
+		kind := domain.ReminderDueSoon
+		if task.DueDate.Before(now) {
+			kind = domain.ReminderOverdue
+		}
+		key := reminderKey{task.ID, kind, *task.DueDate}
+		if m.sent[key] && !m.retryable(key, now) {
+			continue
+		}
+		reminders = append(reminders, domain.Reminder{TaskID: task.ID, UserID: task.UserID, Email: "owner@example.com", Title: task.Title, Kind: kind, DueDate: *task.DueDate, Attempts: m.failed[key].attempts})
+	}
+	return reminders, nil
+}
+
+func (m *mockReminderRepository) Claim(reminder *domain.Reminder) (bool, error) {
+	key := reminderKey{reminder.TaskID, reminder.Kind, reminder.DueDate}
+	if m.sent[key] {
+		if !m.retryable(key, reminder.SentAt) {
+			return false, nil
+		}
+		failure := m.failed[key]
+		failure.nextAttemptAt = nil
+		m.failed[key] = failure
+	}
+	m.sent[key] = true
+	return true, nil
+}
+
+func (m *mockReminderRepository) RecordFailure(reminder *domain.Reminder, nextAttemptAt *time.Time, lastError string) error {
+	m.failed[reminderKey{reminder.TaskID, reminder.Kind, reminder.DueDate}] = reminderFailure{reminder.Attempts, nextAttemptAt, lastError}
+	return nil
+}
+
+type recordingNotifier struct {
+	reminders []domain.Reminder
+	err       error
+}
+
+func (n *recordingNotifier) Notify(reminder *domain.Reminder) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.reminders = append(n.reminders, *reminder)
+	return nil
+}
+
+func TestReminderService_SendDue(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	now := time.Now().UTC()
+	soon, later, past := now.Add(time.Hour), now.Add(48*time.Hour), now.Add(-time.Hour)
+	for _, task := range []*domain.Task{
+		{UserID: testUserID, Title: "Soon", Status: domain.StatusTodo, DueDate: &soon},
+		{UserID: testUserID, Title: "Later", Status: domain.StatusTodo, DueDate: &later},
+		{UserID: testUserID, Title: "Late", Status: domain.StatusDoing, DueDate: &past},
+		{UserID: testUserID, Title: "Finished", Status: domain.StatusDone, DueDate: &past},
+		{UserID: testUserID, Title: "Undated", Status: domain.StatusTodo},
+	} {
+		taskRepo.Create(task)
+	}
+
+	notifier := &recordingNotifier{err: errors.New("server unavailable")}
+	reminderRepo := newMockReminderRepository(taskRepo)
+	service := NewReminderService(reminderRepo, notifier, 24*time.Hour, domain.ReminderRetryPolicy{MaxAttempts: 3})
+
+	if sent, err := service.SendDue(); err == nil || sent != 0 {
+		t.Fatalf("SendDue() = %d, %v; want a failure", sent, err)
+	}
+	for key, failure := range reminderRepo.failed {
+		if failure.attempts != 1 || failure.nextAttemptAt == nil || failure.lastError != "server unavailable" {
+			t.Errorf("failure of %v = %+v, want one attempt to be retried", key, failure)
+		}
+	}
+
+	// Failed reminders are sent once the notifier recovers
+	notifier.err = nil
+	sent, err := service.SendDue()
+	if err != nil || sent != 2 {
+		t.Fatalf("SendDue() = %d, %v; want 2 reminders", sent, err)
+	}
+	kinds := map[string]domain.ReminderKind{}
+	for _, reminder := range notifier.reminders {
+		kinds[reminder.Title] = reminder.Kind
+	}
+	if kinds["Soon"] != domain.ReminderDueSoon || kinds["Late"] != domain.ReminderOverdue {
+		t.Errorf("Expected a due soon and an overdue reminder, got %v", kinds)
+	}
+
+	if sent, err := service.SendDue(); err != nil || sent != 0 {
+		t.Errorf("SendDue() = %d, %v; want no reminder to be sent twice", sent, err)
+	}
+}
+
+func TestReminderService_GivesUp(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	past := time.Now().UTC().Add(-time.Hour)
+	taskRepo.Create(&domain.Task{UserID: testUserID, Title: "Late", Status: domain.StatusTodo, DueDate: &past})
+
+	notifier := &recordingNotifier{err: errors.New("mailbox full")}
+	reminderRepo := newMockReminderRepository(taskRepo)
+	service := NewReminderService(reminderRepo, notifier, time.Hour, domain.ReminderRetryPolicy{MaxAttempts: 3})
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		if sent, err := service.SendDue(); err == nil || sent != 0 {
+			t.Fatalf("attempt %d: SendDue() = %d, %v; want a failure", attempt, sent, err)
+		}
+	}
+	for key, failure := range reminderRepo.failed {
+		if failure.attempts != 3 || failure.nextAttemptAt != nil {
+			t.Errorf("failure of %v = %+v, want to give up after 3 attempts", key, failure)
+		}
+	}
+
+	// Once given up, a reminder is not tried on every run
+	notifier.err = nil
+	if sent, err := service.SendDue(); err != nil || sent != 0 {
+		t.Errorf("SendDue() = %d, %v; want the reminder to be given up", sent, err)
+	}
+}
+
+// TestReminderService_OffsetDueDates runs against SQLite with due dates given
+// in offsets far from UTC, which would compare wrongly as text if they were
+// stored as given
+func TestReminderService_OffsetDueDates(t *testing.T) {
+	db, user := openTestDB(t)
+	taskService := NewTaskService(repo.NewTaskRepository(db), repo.NewCategoryRepository(db), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	now := time.Now()
+	east, west := time.FixedZone("", 14*60*60), time.FixedZone("", -12*60*60)
+	dues := map[string]time.Time{
+		"Late":  now.Add(-time.Hour).In(east),
+		"Soon":  now.Add(30 * time.Minute).In(west),
+		"Later": now.Add(2 * time.Hour).In(west),
+	}
+	for title, due := range dues {
+		due := due
+		if _, err := taskService.CreateTask(user.ID, &domain.CreateTaskRequest{Title: title, Priority: domain.PriorityLow, DueDate: &due}); err != nil {
+			t.Fatalf("CreateTask() error = %v", err)
+		}
+	}
+
+	notifier := &recordingNotifier{}
+	service := NewReminderService(repo.NewReminderRepository(db), notifier, time.Hour, domain.ReminderRetryPolicy{MaxAttempts: 3})
+	if sent, err := service.SendDue(); err != nil || sent != 2 {
+		t.Fatalf("SendDue() = %d, %v; want 2 reminders", sent, err)
+	}
+	kinds := map[string]domain.ReminderKind{}
+	for _, reminder := range notifier.reminders {
+		kinds[reminder.Title] = reminder.Kind
+	}
+	if len(kinds) != 2 || kinds["Late"] != domain.ReminderOverdue || kinds["Soon"] != domain.ReminderDueSoon {
+		t.Errorf("reminders = %v, want Late overdue and Soon due soon", kinds)
+	}
+
+	if sent, err := service.SendDue(); err != nil || sent != 0 {
+		t.Errorf("SendDue() = %d, %v; want no reminder to be sent twice", sent, err)
+	}
+}
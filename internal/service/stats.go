@@ -0,0 +1,27 @@
+package service
+
+import (
+	"fmt"
+	"task-manager/internal/domain"
+	"time"
+)
+
+func (s *taskService) GetTaskStats(userID int64, filters *domain.TaskFilters, window domain.StatsWindow) (*domain.TaskStats, error) {
+	if filters == nil {
+		filters = &domain.TaskFilters{}
+	}
+	workflow := s.workflow.Get()
+	if err := filters.Validate(workflow); err != nil {
+		return nil, fmt.Errorf("invalid filters: %w", err)
+	}
+	if err := window.Validate(); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.taskRepo.GetStats(userID, filters, workflow, window, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task stats: %w", err)
+	}
+
+	return stats, nil
+}
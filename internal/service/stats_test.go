@@ -0,0 +1,134 @@
+package service
+
+import (
+	"database/sql"
+	"path/filepath"
+	"task-manager/internal/domain"
+	"task-manager/internal/repo"
+	"testing"
+	"time"
+)
+
+func (m *mockTaskRepository) GetStats(userID int64, filters *domain.TaskFilters, workflow *domain.Workflow, window domain.StatsWindow, now time.Time) (*domain.TaskStats, error) {
+	stats := domain.NewTaskStats(window, workflow)
+	tasks, _ := m.GetWithFilters(userID, filters)
+	for _, task := range tasks {
+		stats.Total++
+		stats.ByStatus[task.Status]++
+		stats.ByPriority[task.Priority]++
+		if task.IsOverdue(workflow, now) {
+			stats.Overdue++
+		}
+	}
+	return stats, nil
+}
+
+func TestTaskService_GetTaskStats(t *testing.T) {
+	service := NewTaskService(newMockTaskRepository(), newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+	service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Low", Priority: domain.PriorityLow})
+	service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "High", Priority: domain.PriorityHigh})
+
+	window := domain.NewStatsWindow(time.Now(), 7)
+	stats, err := service.GetTaskStats(testUserID, &domain.TaskFilters{Priorities: []domain.TaskPriority{domain.PriorityHigh}}, window)
+	if err != nil {
+		t.Fatalf("GetTaskStats() error = %v", err)
+	}
+	if stats.Total != 1 || stats.ByPriority[domain.PriorityHigh] != 1 || stats.ByStatus[domain.StatusTodo] != 1 {
+		t.Errorf("Expected only the high priority task to be counted, got %+v", stats)
+	}
+	if len(stats.Daily) != 7 {
+		t.Errorf("Expected 7 days of counts, got %d", len(stats.Daily))
+	}
+
+	if _, err := service.GetTaskStats(testUserID, &domain.TaskFilters{Statuses: []domain.TaskStatus{"later"}}, window); err == nil {
+		t.Error("Expected invalid filters to be rejected")
+	}
+	if _, err := service.GetTaskStats(testUserID, nil, domain.StatsWindow{From: window.To, To: window.From}); err == nil {
+		t.Error("Expected a reversed window to be rejected")
+	}
+}
+
+func TestTaskService_CompletedAt(t *testing.T) {
+	service := NewTaskService(newMockTaskRepository(), newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+	task, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Finish me", Priority: domain.PriorityLow})
+
+	done := domain.StatusDone
+	task, err := service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Status: &done})
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if task.CompletedAt == nil {
+		t.Fatal("Expected a completion time once done")
+	}
+	completedAt := *task.CompletedAt
+
+	task, _ = service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Title: stringPtr("Renamed")})
+	if task.CompletedAt == nil || !task.CompletedAt.Equal(completedAt) {
+		t.Errorf("Expected other updates to keep the completion time, got %v", task.CompletedAt)
+	}
+
+	todo := domain.StatusTodo
+	task, _ = service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Status: &todo})
+	if task.CompletedAt != nil {
+		t.Errorf("Expected reopening to clear the completion time, got %v", task.CompletedAt)
+	}
+}
+
+// openTestDB opens a migrated SQLite database in a temporary directory, with
+// one user to own test tasks
+func openTestDB(t *testing.T) (*sql.DB, *domain.User) {
+	t.Helper()
+	db, err := repo.Open(filepath.Join(t.TempDir(), "tasks.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := repo.Migrate(db); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	now := time.Now().UTC()
+	user := &domain.User{Email: "alice@example.com", PasswordHash: "x", CreatedAt: now, UpdatedAt: now}
+	if err := repo.NewUserRepository(db).Create(user); err != nil {
+		t.Fatalf("Create() user error = %v", err)
+	}
+	return db, user
+}
+
+// TestTaskService_GetTaskStats_NonUTCLocal runs against SQLite on a server
+// whose local zone is far from UTC, so that stored local times would land in
+// the wrong day and compare wrongly as text
+func TestTaskService_GetTaskStats_NonUTCLocal(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("", 14*60*60)
+	defer func() { time.Local = local }()
+
+	db, user := openTestDB(t)
+	now := time.Now().UTC()
+	service := NewTaskService(repo.NewTaskRepository(db), repo.NewCategoryRepository(db), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	dayStart, dayEnd, _, _ := domain.DueRanges(now)
+	dues := []time.Time{
+		dayStart.In(time.FixedZone("", 9*60*60)),                  // overdue, due today
+		dayEnd.Add(-time.Second).In(time.FixedZone("", 14*60*60)), // due today
+		dayEnd.Add(time.Hour).In(time.FixedZone("", -12*60*60)),   // due tomorrow
+	}
+	for i := range dues {
+		if _, err := service.CreateTask(user.ID, &domain.CreateTaskRequest{Title: "Task", Priority: domain.PriorityLow, DueDate: &dues[i]}); err != nil {
+			t.Fatalf("CreateTask() error = %v", err)
+		}
+	}
+
+	window := domain.NewStatsWindow(time.Now(), 3)
+	stats, err := service.GetTaskStats(user.ID, nil, window)
+	if err != nil {
+		t.Fatalf("GetTaskStats() error = %v", err)
+	}
+	if stats.Overdue != 1 || stats.DueToday != 2 {
+		t.Errorf("overdue, due today = %d, %d; want 1, 2", stats.Overdue, stats.DueToday)
+	}
+
+	today := stats.Daily[len(stats.Daily)-1]
+	if today.Date != now.Format("2006-01-02") || today.Created != len(dues) {
+		t.Errorf("daily counts = %+v, want %d tasks created on the UTC day %s", stats.Daily, len(dues), now.Format("2006-01-02"))
+	}
+}
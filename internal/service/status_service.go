@@ -0,0 +1,146 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"task-manager/internal/domain"
+	"task-manager/internal/repo"
+)
+
+// StatusService manages the workflow statuses shared by all users. Every
+// change is stored and then put into effect in the service's WorkflowStore.
+type StatusService interface {
+	// LoadWorkflow puts the stored workflow into effect
+	LoadWorkflow() error
+	GetWorkflow() *domain.Workflow
+	// SaveStatus creates the status or replaces its definition
+	SaveStatus(key domain.TaskStatus, req *domain.SaveStatusRequest) (*domain.StatusDefinition, error)
+	// DeleteStatus removes a status no task is in, along with the
+	// transitions leading to it
+	DeleteStatus(key domain.TaskStatus) error
+}
+
+type statusService struct {
+	statusRepo repo.StatusRepository
+	workflow   *domain.WorkflowStore
+	// mu serializes changes, which rewrite the whole workflow
+	mu sync.Mutex
+}
+
+// NewStatusService returns a StatusService that puts the workflow into
+// effect in workflow, the store shared with the services that use it
+func NewStatusService(statusRepo repo.StatusRepository, workflow *domain.WorkflowStore) StatusService {
+	return &statusService{statusRepo: statusRepo, workflow: workflow}
+}
+
+func (s *statusService) LoadWorkflow() error {
+	workflow, err := s.statusRepo.GetWorkflow()
+	if err != nil {
+		return err
+	}
+	if err := workflow.Validate(); err != nil {
+		return fmt.Errorf("invalid stored workflow: %w", err)
+	}
+	s.workflow.Set(workflow)
+	return nil
+}
+
+func (s *statusService) GetWorkflow() *domain.Workflow {
+	return s.workflow.Get()
+}
+
+func (s *statusService) SaveStatus(key domain.TaskStatus, req *domain.SaveStatusRequest) (*domain.StatusDefinition, error) {
+	if err := domain.ValidateStatusKey(key); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.workflow.Get()
+	status := domain.StatusDefinition{
+		Key:         key,
+		Label:       req.Label,
+		Category:    req.Category,
+		Transitions: append([]domain.TaskStatus{}, req.Transitions...),
+	}
+
+	// The status keeps its place unless a position is given
+	others := make([]domain.StatusDefinition, 0, len(current.Statuses))
+	position := len(current.Statuses)
+	for i, existing := range current.Statuses {
+		if existing.Key == key {
+			position = i
+			continue
+		}
+		others = append(others, existing)
+	}
+	if req.Position != nil {
+		position = *req.Position
+	}
+	if position > len(others) {
+		position = len(others)
+	}
+
+	statuses := append([]domain.StatusDefinition{}, others[:position]...)
+	statuses = append(statuses, status)
+	statuses = append(statuses, others[position:]...)
+
+	workflow, err := s.replaceWorkflow(statuses, s.statusRepo.SaveWorkflow)
+	if err != nil {
+		return nil, err
+	}
+	return workflow.Status(key), nil
+}
+
+func (s *statusService) DeleteStatus(key domain.TaskStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.workflow.Get()
+	if current.Status(key) == nil {
+		return domain.ErrStatusNotFound
+	}
+
+	statuses := make([]domain.StatusDefinition, 0, len(current.Statuses)-1)
+	for _, status := range current.Statuses {
+		if status.Key == key {
+			continue
+		}
+		transitions := []domain.TaskStatus{}
+		for _, target := range status.Transitions {
+			if target != key {
+				transitions = append(transitions, target)
+			}
+		}
+		status.Transitions = transitions
+		statuses = append(statuses, status)
+	}
+
+	// The tasks are checked in the same transaction as the deletion
+	_, err := s.replaceWorkflow(statuses, func(workflow *domain.Workflow) error {
+		return s.statusRepo.DeleteStatus(key, workflow)
+	})
+	return err
+}
+
+// replaceWorkflow numbers the statuses by their order, then validates,
+// stores with save and applies the resulting workflow
+func (s *statusService) replaceWorkflow(statuses []domain.StatusDefinition, save func(workflow *domain.Workflow) error) (*domain.Workflow, error) {
+	for i := range statuses {
+		statuses[i].Position = i
+	}
+	workflow := &domain.Workflow{Statuses: statuses}
+	if err := workflow.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := save(workflow); err != nil {
+		return nil, err
+	}
+	s.workflow.Set(workflow)
+	return workflow, nil
+}
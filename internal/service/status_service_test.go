@@ -0,0 +1,166 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+type mockStatusRepository struct {
+	workflow *domain.Workflow
+	counts   map[domain.TaskStatus]int
+}
+
+func newMockStatusRepository() *mockStatusRepository {
+	return &mockStatusRepository{
+		workflow: domain.DefaultWorkflow(),
+		counts:   make(map[domain.TaskStatus]int),
+	}
+}
+
+func (m *mockStatusRepository) GetWorkflow() (*domain.Workflow, error) {
+	return m.workflow, nil
+}
+
+func (m *mockStatusRepository) SaveWorkflow(workflow *domain.Workflow) error {
+	m.workflow = workflow
+	return nil
+}
+
+func (m *mockStatusRepository) DeleteStatus(key domain.TaskStatus, remaining *domain.Workflow) error {
+	if m.workflow.Status(key) == nil {
+		return domain.ErrStatusNotFound
+	}
+	if m.counts[key] > 0 {
+		return fmt.Errorf("cannot delete status %s: %w", key, domain.ErrStatusInUse)
+	}
+	m.workflow = remaining
+	return nil
+}
+
+func statusKeys(workflow *domain.Workflow) []domain.TaskStatus {
+	keys := make([]domain.TaskStatus, len(workflow.Statuses))
+	for i, status := range workflow.Statuses {
+		keys[i] = status.Key
+	}
+	return keys
+}
+
+func sameStatuses(a, b []domain.TaskStatus) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestStatusService_SaveAndDelete(t *testing.T) {
+	statusRepo := newMockStatusRepository()
+	store := domain.NewWorkflowStore(domain.DefaultWorkflow())
+	service := NewStatusService(statusRepo, store)
+	if err := service.LoadWorkflow(); err != nil {
+		t.Fatalf("LoadWorkflow() error = %v", err)
+	}
+
+	position := 2
+	review, err := service.SaveStatus("review", &domain.SaveStatusRequest{
+		Label:       "Review",
+		Category:    domain.StatusCategoryInProgress,
+		Position:    &position,
+		Transitions: []domain.TaskStatus{domain.StatusDoing, domain.StatusDone},
+	})
+	if err != nil {
+		t.Fatalf("SaveStatus() error = %v", err)
+	}
+	if review.Position != 2 {
+		t.Errorf("Expected review at position 2, got %d", review.Position)
+	}
+	want := []domain.TaskStatus{domain.StatusTodo, domain.StatusDoing, "review", domain.StatusDone}
+	if keys := statusKeys(store.Get()); !sameStatuses(keys, want) {
+		t.Errorf("Expected workflow %v, got %v", want, keys)
+	}
+	if statusRepo.workflow != store.Get() {
+		t.Error("Expected the saved workflow to be in effect")
+	}
+
+	// Relabelling keeps the status in place
+	if _, err := service.SaveStatus("todo", &domain.SaveStatusRequest{
+		Label:       "Backlog",
+		Category:    domain.StatusCategoryOpen,
+		Transitions: []domain.TaskStatus{domain.StatusDoing, "review"},
+	}); err != nil {
+		t.Fatalf("SaveStatus() error = %v", err)
+	}
+	if status := store.Get().Statuses[0]; status.Key != domain.StatusTodo || status.Label != "Backlog" {
+		t.Errorf("Expected the relabelled todo first, got %+v", status)
+	}
+
+	invalid := []struct {
+		name string
+		key  domain.TaskStatus
+		req  domain.SaveStatusRequest
+	}{
+		{"invalid key", "In Review", domain.SaveStatusRequest{Label: "In review", Category: domain.StatusCategoryOpen}},
+		{"unknown transition", "blocked", domain.SaveStatusRequest{Label: "Blocked", Category: domain.StatusCategoryOpen, Transitions: []domain.TaskStatus{"later"}}},
+		{"last closed status reopened", "done", domain.SaveStatusRequest{Label: "Done", Category: domain.StatusCategoryOpen}},
+	}
+	for _, tt := range invalid {
+		if _, err := service.SaveStatus(tt.key, &tt.req); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+
+	statusRepo.counts[domain.StatusDoing] = 1
+	if err := service.DeleteStatus(domain.StatusDoing); !errors.Is(err, domain.ErrStatusInUse) {
+		t.Errorf("DeleteStatus() error = %v, want ErrStatusInUse", err)
+	}
+	if err := service.DeleteStatus("later"); !errors.Is(err, domain.ErrStatusNotFound) {
+		t.Errorf("DeleteStatus() error = %v, want ErrStatusNotFound", err)
+	}
+	if err := service.DeleteStatus("review"); err != nil {
+		t.Fatalf("DeleteStatus() error = %v", err)
+	}
+	workflow := service.GetWorkflow()
+	if workflow.Status("review") != nil || workflow.Status(domain.StatusDone).Position != 2 {
+		t.Errorf("Expected review removed and done moved up, got %+v", workflow.Statuses)
+	}
+	if workflow.CanTransition(domain.StatusTodo, "review") {
+		t.Error("Expected transitions to a deleted status to be removed")
+	}
+}
+
+func TestTaskService_UpdateTaskFollowsWorkflow(t *testing.T) {
+	store := domain.NewWorkflowStore(&domain.Workflow{Statuses: []domain.StatusDefinition{
+		{Key: domain.StatusTodo, Label: "To do", Category: domain.StatusCategoryOpen, Transitions: []domain.TaskStatus{domain.StatusDoing, "cancelled"}},
+		{Key: domain.StatusDoing, Label: "Doing", Category: domain.StatusCategoryInProgress, Transitions: []domain.TaskStatus{domain.StatusDone}},
+		{Key: domain.StatusDone, Label: "Done", Category: domain.StatusCategoryClosed},
+		{Key: "cancelled", Label: "Cancelled", Category: domain.StatusCategoryClosed},
+	}})
+
+	service := NewTaskService(newMockTaskRepository(), newMockCategoryRepository(), store, nil)
+	task, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Task", Priority: domain.PriorityLow})
+
+	done := domain.StatusDone
+	if _, err := service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Status: &done}); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("UpdateTask() error = %v, want ErrInvalidTransition", err)
+	}
+
+	cancelled := domain.TaskStatus("cancelled")
+	task, err := service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Status: &cancelled})
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if task.CompletedAt == nil {
+		t.Error("Expected a cancelled task to count as completed")
+	}
+
+	todo := domain.StatusTodo
+	if _, err := service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Status: &todo}); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("UpdateTask() error = %v, want ErrInvalidTransition", err)
+	}
+}
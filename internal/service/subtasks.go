@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"task-manager/internal/domain"
+	"time"
+)
+
+// maxTaskDepth bounds ancestor walks so corrupted data cannot loop forever
+const maxTaskDepth = 1000
+
+func (s *taskService) GetSubtasks(userID, id int64) ([]*domain.Task, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid task id")
+	}
+
+	if _, err := s.taskRepo.GetByID(userID, id); err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	children, err := s.taskRepo.GetChildren(userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subtasks: %w", err)
+	}
+
+	return children, nil
+}
+
+func (s *taskService) GetTaskTree(userID, id int64) (*domain.TaskNode, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid task id")
+	}
+
+	root, err := s.taskRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	descendants, err := s.taskRepo.GetDescendants(userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subtasks: %w", err)
+	}
+
+	return domain.BuildTaskTree(root, descendants), nil
+}
+
+func (s *taskService) DeleteTaskTree(userID, id int64) error {
+	if id <= 0 {
+		return fmt.Errorf("invalid task id")
+	}
+
+	root, err := s.taskRepo.GetByID(userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	descendants, err := s.taskRepo.GetDescendants(userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to get subtasks: %w", err)
+	}
+
+	if err := s.taskRepo.DeleteTree(userID, id, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	for _, task := range append([]*domain.Task{root}, descendants...) {
+		if err := s.recordHistory(userID, domain.HistoryDeleted, task, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setParent moves task under parentID, or detaches it when parentID is 0.
+// The new parent must belong to the user and must not be the task itself
+// or one of its descendants.
+func (s *taskService) setParent(userID int64, task *domain.Task, parentID int64) error {
+	if parentID == 0 {
+		task.ParentID = nil
+		return nil
+	}
+	if task.SeriesID != nil {
+		return fmt.Errorf("cannot move task: %w", domain.ErrSeriesSubtask)
+	}
+
+	ancestorID := parentID
+	for depth := 0; depth < maxTaskDepth; depth++ {
+		if ancestorID == task.ID {
+			return fmt.Errorf("cannot move task: %w", domain.ErrInvalidParent)
+		}
+		ancestor, err := s.taskRepo.GetByID(userID, ancestorID)
+		if err != nil {
+			return fmt.Errorf("failed to get parent task: %w", err)
+		}
+		if ancestor.ParentID == nil {
+			task.ParentID = &parentID
+			return nil
+		}
+		ancestorID = *ancestor.ParentID
+	}
+
+	return fmt.Errorf("cannot move task: %w", domain.ErrInvalidParent)
+}
+
+// checkSubtaskStatus enforces that a closed task has no open subtasks, which
+// also means an open task cannot sit under a closed parent
+func (s *taskService) checkSubtaskStatus(userID int64, task *domain.Task) error {
+	workflow := s.workflow.Get()
+	if workflow.IsClosed(task.Status) {
+		if task.HasOpenSubtasks() {
+			return fmt.Errorf("cannot complete task: %w", domain.ErrOpenSubtasks)
+		}
+		return nil
+	}
+
+	if task.ParentID != nil {
+		parent, err := s.taskRepo.GetByID(userID, *task.ParentID)
+		if err != nil {
+			return fmt.Errorf("failed to get parent task: %w", err)
+		}
+		if workflow.IsClosed(parent.Status) {
+			return fmt.Errorf("cannot reopen subtask: %w", domain.ErrParentDone)
+		}
+	}
+
+	return nil
+}
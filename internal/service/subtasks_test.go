@@ -0,0 +1,81 @@
+package service
+
+import (
+	"errors"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+func TestTaskService_SubtaskRules(t *testing.T) {
+	mockRepo := newMockTaskRepository()
+	service := NewTaskService(mockRepo, newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	parent, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Parent", Priority: domain.PriorityMedium})
+	child, err := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Child", Priority: domain.PriorityMedium, ParentID: &parent.ID})
+	if err != nil {
+		t.Fatalf("CreateTask() subtask error = %v", err)
+	}
+	grandchild, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Grandchild", Priority: domain.PriorityMedium, ParentID: &child.ID})
+
+	missing := int64(999)
+	if _, err := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Orphan", Priority: domain.PriorityMedium, ParentID: &missing}); err == nil {
+		t.Error("CreateTask() with missing parent should fail")
+	}
+
+	done := domain.StatusDone
+	if _, err := service.UpdateTask(testUserID, parent.ID, &domain.UpdateTaskRequest{Status: &done}); !errors.Is(err, domain.ErrOpenSubtasks) {
+		t.Errorf("completing parent with open subtasks error = %v, want %v", err, domain.ErrOpenSubtasks)
+	}
+
+	// Moving a task below its own descendant would create a cycle
+	if _, err := service.UpdateTask(testUserID, parent.ID, &domain.UpdateTaskRequest{ParentID: &grandchild.ID}); !errors.Is(err, domain.ErrInvalidParent) {
+		t.Errorf("moving under a descendant error = %v, want %v", err, domain.ErrInvalidParent)
+	}
+	if _, err := service.UpdateTask(testUserID, parent.ID, &domain.UpdateTaskRequest{ParentID: &parent.ID}); !errors.Is(err, domain.ErrInvalidParent) {
+		t.Errorf("moving under itself error = %v, want %v", err, domain.ErrInvalidParent)
+	}
+
+	for _, id := range []int64{grandchild.ID, child.ID, parent.ID} {
+		if _, err := service.UpdateTask(testUserID, id, &domain.UpdateTaskRequest{Status: &done}); err != nil {
+			t.Fatalf("completing task %d bottom-up error = %v", id, err)
+		}
+	}
+
+	todo := domain.StatusTodo
+	if _, err := service.UpdateTask(testUserID, child.ID, &domain.UpdateTaskRequest{Status: &todo}); !errors.Is(err, domain.ErrParentDone) {
+		t.Errorf("reopening child of done parent error = %v, want %v", err, domain.ErrParentDone)
+	}
+	if _, err := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Late", Priority: domain.PriorityMedium, ParentID: &parent.ID}); !errors.Is(err, domain.ErrParentDone) {
+		t.Errorf("adding subtask to done parent error = %v, want %v", err, domain.ErrParentDone)
+	}
+
+	detach := int64(0)
+	updated, err := service.UpdateTask(testUserID, grandchild.ID, &domain.UpdateTaskRequest{ParentID: &detach})
+	if err != nil || updated.ParentID != nil {
+		t.Errorf("detaching subtask = %v, %v; want no parent", updated, err)
+	}
+}
+
+func TestTaskService_GetTaskTree(t *testing.T) {
+	mockRepo := newMockTaskRepository()
+	service := NewTaskService(mockRepo, newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	root, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Root", Priority: domain.PriorityMedium})
+	child, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Child", Priority: domain.PriorityMedium, ParentID: &root.ID})
+	service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Grandchild", Priority: domain.PriorityMedium, ParentID: &child.ID})
+
+	tree, err := service.GetTaskTree(testUserID, root.ID)
+	if err != nil {
+		t.Fatalf("GetTaskTree() error = %v", err)
+	}
+	if len(tree.Children) != 1 || len(tree.Children[0].Children) != 1 {
+		t.Errorf("GetTaskTree() shape = %+v, want root > child > grandchild", tree)
+	}
+
+	if err := service.DeleteTaskTree(testUserID, root.ID); err != nil {
+		t.Fatalf("DeleteTaskTree() error = %v", err)
+	}
+	if len(mockRepo.tasks) != 0 {
+		t.Errorf("DeleteTaskTree() left %d tasks", len(mockRepo.tasks))
+	}
+}
@@ -11,42 +11,97 @@ import (
 var ErrTaskNotFound = errors.New("task not found")
 
 type TaskService interface {
-	CreateTask(req *domain.CreateTaskRequest) (*domain.Task, error)
-	GetTask(id int64) (*domain.Task, error)
-	GetAllTasks() ([]*domain.Task, error)
-	GetTasksWithFilters(filters *domain.TaskFilters) ([]*domain.Task, error)
-	UpdateTask(id int64, req *domain.UpdateTaskRequest) (*domain.Task, error)
-	DeleteTask(id int64) error
+	CreateTask(userID int64, req *domain.CreateTaskRequest) (*domain.Task, error)
+	GetTask(userID, id int64) (*domain.Task, error)
+	GetAllTasks(userID int64) ([]*domain.Task, error)
+	GetTasksWithFilters(userID int64, filters *domain.TaskFilters) ([]*domain.Task, error)
+	GetTasksPage(userID int64, filters *domain.TaskFilters, page *domain.PageRequest) (*domain.TaskPage, error)
+	UpdateTask(userID, id int64, req *domain.UpdateTaskRequest) (*domain.Task, error)
+	// MoveTask places a task between two tasks of a status column, changing
+	// its status if the column differs
+	MoveTask(userID, id int64, req *domain.MoveTaskRequest) (*domain.Task, error)
+	DeleteTask(userID, id int64) error
+	BulkTasks(userID int64, req *domain.BulkTaskRequest) (*domain.BulkTaskResult, error)
+	ImportTasks(userID int64, rows []domain.TaskImportRow, opts domain.TaskImportOptions) (*domain.TaskImportResult, error)
+	GetSubtasks(userID, id int64) ([]*domain.Task, error)
+	GetTaskTree(userID, id int64) (*domain.TaskNode, error)
+	DeleteTaskTree(userID, id int64) error
+	AddDependency(userID, id int64, req *domain.AddDependencyRequest) (*domain.Task, error)
+	RemoveDependency(userID, id, blockedByID int64) error
+	GetTaskHistory(userID, id int64) ([]domain.TaskHistoryEntry, error)
+	GetTrashedTasks(userID int64) ([]*domain.Task, error)
+	RestoreTask(userID, id int64) (*domain.Task, error)
+	PurgeTask(userID, id int64) error
+	PurgeTrash(userID int64) error
+	// PurgeExpiredTrash permanently deletes every user's tasks that were
+	// moved to the trash before cutoff
+	PurgeExpiredTrash(cutoff time.Time) (int64, error)
+	// GetTaskStats summarizes the tasks matching filters, with daily counts
+	// over window
+	GetTaskStats(userID int64, filters *domain.TaskFilters, window domain.StatsWindow) (*domain.TaskStats, error)
 }
 
 type taskService struct {
 	taskRepo     repo.TaskRepository
 	categoryRepo repo.CategoryRepository
+	workflow     *domain.WorkflowStore
+	events       EventPublisher
 }
 
-func NewTaskService(taskRepo repo.TaskRepository, categoryRepo repo.CategoryRepository) TaskService {
+// NewTaskService returns a TaskService that moves tasks through the workflow
+// in effect in workflow and announces task changes to events, which may be
+// nil
+func NewTaskService(taskRepo repo.TaskRepository, categoryRepo repo.CategoryRepository, workflow *domain.WorkflowStore, events EventPublisher) TaskService {
 	return &taskService{
 		taskRepo:     taskRepo,
 		categoryRepo: categoryRepo,
+		workflow:     workflow,
+		events:       events,
 	}
 }
 
-func (s *taskService) CreateTask(req *domain.CreateTaskRequest) (*domain.Task, error) {
+func (s *taskService) CreateTask(userID int64, req *domain.CreateTaskRequest) (*domain.Task, error) {
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
-	now := time.Now()
+	if err := s.checkCategoryOwnership(userID, req.CategoryIDs); err != nil {
+		return nil, err
+	}
+
+	if req.ParentID != nil {
+		parent, err := s.taskRepo.GetByID(userID, *req.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent task: %w", err)
+		}
+		if s.workflow.Get().IsClosed(parent.Status) {
+			return nil, fmt.Errorf("cannot add a subtask: %w", domain.ErrParentDone)
+		}
+	}
+
+	now := time.Now().UTC()
 	task := &domain.Task{
+		UserID:      userID,
 		Title:       req.Title,
 		Description: req.Description,
-		Status:      domain.StatusTodo,
+		Status:      s.workflow.Get().InitialStatus(),
 		Priority:    req.Priority,
-		DueDate:     req.DueDate,
+		DueDate:     utcTime(req.DueDate),
+		ParentID:    req.ParentID,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
 
+	if req.RRule != "" {
+		if err := s.startSeries(userID, task, req.RRule); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.rankLast(userID, task); err != nil {
+		return nil, err
+	}
+
 	if err := s.taskRepo.Create(task); err != nil {
 		return nil, fmt.Errorf("failed to create task: %w", err)
 	}
@@ -59,15 +114,24 @@ func (s *taskService) CreateTask(req *domain.CreateTaskRequest) (*domain.Task, e
 	}
 
 	// Reload the task with categories
-	return s.taskRepo.GetByID(task.ID)
+	created, err := s.taskRepo.GetByID(userID, task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recordHistory(userID, domain.HistoryCreated, nil, created); err != nil {
+		return nil, err
+	}
+
+	return created, nil
 }
 
-func (s *taskService) GetTask(id int64) (*domain.Task, error) {
+func (s *taskService) GetTask(userID, id int64) (*domain.Task, error) {
 	if id <= 0 {
 		return nil, fmt.Errorf("invalid task id")
 	}
 
-	task, err := s.taskRepo.GetByID(id)
+	task, err := s.taskRepo.GetByID(userID, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
@@ -75,8 +139,8 @@ func (s *taskService) GetTask(id int64) (*domain.Task, error) {
 	return task, nil
 }
 
-func (s *taskService) GetAllTasks() ([]*domain.Task, error) {
-	tasks, err := s.taskRepo.GetAll()
+func (s *taskService) GetAllTasks(userID int64) ([]*domain.Task, error) {
+	tasks, err := s.taskRepo.GetAll(userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks: %w", err)
 	}
@@ -84,16 +148,16 @@ func (s *taskService) GetAllTasks() ([]*domain.Task, error) {
 	return tasks, nil
 }
 
-func (s *taskService) GetTasksWithFilters(filters *domain.TaskFilters) ([]*domain.Task, error) {
+func (s *taskService) GetTasksWithFilters(userID int64, filters *domain.TaskFilters) ([]*domain.Task, error) {
 	if filters == nil {
-		return s.GetAllTasks()
+		return s.GetAllTasks(userID)
 	}
 
-	if err := filters.Validate(); err != nil {
+	if err := filters.Validate(s.workflow.Get()); err != nil {
 		return nil, fmt.Errorf("invalid filters: %w", err)
 	}
 
-	tasks, err := s.taskRepo.GetWithFilters(filters)
+	tasks, err := s.taskRepo.GetWithFilters(userID, filters)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks with filters: %w", err)
 	}
@@ -101,19 +165,74 @@ func (s *taskService) GetTasksWithFilters(filters *domain.TaskFilters) ([]*domai
 	return tasks, nil
 }
 
-func (s *taskService) UpdateTask(id int64, req *domain.UpdateTaskRequest) (*domain.Task, error) {
+func (s *taskService) GetTasksPage(userID int64, filters *domain.TaskFilters, page *domain.PageRequest) (*domain.TaskPage, error) {
+	if filters != nil {
+		if err := filters.Validate(s.workflow.Get()); err != nil {
+			return nil, fmt.Errorf("invalid filters: %w", err)
+		}
+	}
+
+	if err := page.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid page: %w", err)
+	}
+
+	result, err := s.taskRepo.GetPage(userID, filters, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task page: %w", err)
+	}
+
+	return result, nil
+}
+
+// UpdateTask saves the task, its categories and its history entry in one
+// transaction, along with the next occurrence when it completes a recurring
+// task
+func (s *taskService) UpdateTask(userID, id int64, req *domain.UpdateTaskRequest) (*domain.Task, error) {
 	if id <= 0 {
 		return nil, fmt.Errorf("invalid task id")
 	}
 
-	if err := req.Validate(); err != nil {
+	workflow := s.workflow.Get()
+	if err := req.Validate(workflow); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
-	existingTask, err := s.taskRepo.GetByID(id)
+	var task *domain.Task
+	events := &eventBuffer{}
+	err := s.taskRepo.WithTx(func(tasks repo.TaskRepository, categories repo.CategoryRepository) error {
+		tx := &taskService{taskRepo: tasks, categoryRepo: categories, workflow: s.workflow, events: events}
+		var err error
+		task, err = tx.updateTask(userID, id, req, workflow)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	events.flush(s.events)
+
+	return task, nil
+}
+
+// updateTask applies a validated request
+func (s *taskService) updateTask(userID, id int64, req *domain.UpdateTaskRequest, workflow *domain.Workflow) (*domain.Task, error) {
+	existingTask, err := s.taskRepo.GetByID(userID, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get existing task: %w", err)
 	}
+	if req.ExpectedVersion != nil && *req.ExpectedVersion != existingTask.Version {
+		return nil, fmt.Errorf("failed to update task: %w", domain.ErrVersionConflict)
+	}
+	if err := req.ValidateTransition(workflow, existingTask.Status); err != nil {
+		return nil, fmt.Errorf("cannot change status: %w", err)
+	}
+	before := *existingTask
+	wasClosed := workflow.IsClosed(existingTask.Status)
+
+	if req.CategoryIDs != nil {
+		if err := s.checkCategoryOwnership(userID, *req.CategoryIDs); err != nil {
+			return nil, err
+		}
+	}
 
 	if req.Title != nil {
 		existingTask.Title = *req.Title
@@ -121,53 +240,155 @@ func (s *taskService) UpdateTask(id int64, req *domain.UpdateTaskRequest) (*doma
 	if req.Description != nil {
 		existingTask.Description = *req.Description
 	}
-	if req.Status != nil {
+	if req.Status != nil && *req.Status != existingTask.Status {
+		// A blocked task may not be started or finished
+		if workflow.Category(*req.Status) != domain.StatusCategoryOpen && existingTask.HasOpenBlockers(workflow) {
+			return nil, fmt.Errorf("cannot change status to %s: %w", *req.Status, domain.ErrBlocked)
+		}
 		existingTask.Status = *req.Status
+		switch {
+		case !workflow.IsClosed(existingTask.Status):
+			existingTask.CompletedAt = nil
+		case !wasClosed:
+			now := time.Now().UTC()
+			existingTask.CompletedAt = &now
+		}
+	}
+	if req.Rank != nil {
+		existingTask.Rank = *req.Rank
+	} else if existingTask.Status != before.Status {
+		// A task changing columns joins the end of its new one
+		if err := s.rankLast(userID, existingTask); err != nil {
+			return nil, err
+		}
 	}
 	if req.Priority != nil {
 		existingTask.Priority = *req.Priority
 	}
 	if req.DueDate != nil {
-		existingTask.DueDate = req.DueDate
+		existingTask.DueDate = utcTime(req.DueDate)
+	}
+	if req.ParentID != nil {
+		if err := s.setParent(userID, existingTask, *req.ParentID); err != nil {
+			return nil, err
+		}
 	}
 
-	existingTask.UpdatedAt = time.Now()
+	existingTask.UpdatedAt = time.Now().UTC()
 
-	if err := existingTask.Validate(); err != nil {
+	if err := existingTask.Validate(workflow); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
+	if req.Status != nil || req.ParentID != nil {
+		if err := s.checkSubtaskStatus(userID, existingTask); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.applyRecurrence(userID, existingTask, req); err != nil {
+		return nil, err
+	}
+
 	if err := s.taskRepo.Update(existingTask); err != nil {
 		return nil, fmt.Errorf("failed to update task: %w", err)
 	}
 
 	// Handle category updates
 	if req.CategoryIDs != nil {
-		// Remove all existing categories
-		if err := s.categoryRepo.RemoveAllTaskCategories(id); err != nil {
-			return nil, fmt.Errorf("failed to remove existing categories: %w", err)
+		if err := s.replaceCategories(id, *req.CategoryIDs); err != nil {
+			return nil, err
 		}
+	}
 
-		// Add new categories
-		for _, categoryID := range *req.CategoryIDs {
-			if err := s.categoryRepo.AddTaskCategory(id, categoryID); err != nil {
-				return nil, fmt.Errorf("failed to add category to task: %w", err)
-			}
+	// Reload the task with updated categories
+	task, err := s.taskRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recordHistory(userID, domain.HistoryUpdated, &before, task); err != nil {
+		return nil, err
+	}
+
+	if !wasClosed && workflow.IsClosed(task.Status) && task.SeriesID != nil {
+		if err := s.scheduleNextOccurrence(userID, task); err != nil {
+			return nil, err
 		}
 	}
 
-	// Reload the task with updated categories
-	return s.taskRepo.GetByID(id)
+	return task, nil
 }
 
-func (s *taskService) DeleteTask(id int64) error {
+func (s *taskService) DeleteTask(userID, id int64) error {
 	if id <= 0 {
 		return fmt.Errorf("invalid task id")
 	}
 
-	if err := s.taskRepo.Delete(id); err != nil {
+	task, err := s.taskRepo.GetByID(userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	children, err := s.taskRepo.GetChildren(userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to get subtasks: %w", err)
+	}
+	subtasks := make([]domain.Task, len(children))
+	for i, child := range children {
+		subtasks[i] = *child
+	}
+
+	if err := s.taskRepo.Delete(userID, id, time.Now().UTC()); err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
 
+	if err := s.recordHistory(userID, domain.HistoryDeleted, task, nil); err != nil {
+		return err
+	}
+	// The subtasks moved up to the deleted task's parent
+	for i := range subtasks {
+		moved := subtasks[i]
+		moved.ParentID = task.ParentID
+		if err := s.recordHistory(userID, domain.HistoryUpdated, &subtasks[i], &moved); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// replaceCategories sets the categories of a task to exactly categoryIDs
+func (s *taskService) replaceCategories(taskID int64, categoryIDs []int64) error {
+	if err := s.categoryRepo.RemoveAllTaskCategories(taskID); err != nil {
+		return fmt.Errorf("failed to remove existing categories: %w", err)
+	}
+
+	for _, categoryID := range categoryIDs {
+		if err := s.categoryRepo.AddTaskCategory(taskID, categoryID); err != nil {
+			return fmt.Errorf("failed to add category to task: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkCategoryOwnership ensures every category exists and belongs to the user
+// before any of them are linked to a task
+func (s *taskService) checkCategoryOwnership(userID int64, categoryIDs []int64) error {
+	for _, categoryID := range categoryIDs {
+		if _, err := s.categoryRepo.GetByID(userID, categoryID); err != nil {
+			return fmt.Errorf("failed to add category to task: %w", err)
+		}
+	}
+	return nil
+}
+
+// utcTime returns a copy of t in UTC. Task times are always written in UTC,
+// whose stored form orders as text, so that SQL range filters compare them
+// correctly.
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
@@ -4,42 +4,61 @@ import (
 	"errors"
 	"strings"
 	"task-manager/internal/domain"
+	"task-manager/internal/repo"
 	"testing"
 	"time"
 )
 
+const testUserID int64 = 1
+
 type mockTaskRepository struct {
 	tasks  map[int64]*domain.Task
+	trash  map[int64]*domain.Task
 	nextID int64
+	// blockedBy maps a task ID to the IDs of tasks blocking it
+	blockedBy map[int64][]int64
+	series    map[int64]*domain.TaskSeries
+	history   []domain.TaskHistoryEntry
+	// detachedFrom maps a subtask ID to the trashed parent it moved up from
+	detachedFrom map[int64]int64
+	// categoryRepo is handed to WithTx callbacks
+	categoryRepo *mockCategoryRepository
 }
 
 func newMockTaskRepository() *mockTaskRepository {
 	return &mockTaskRepository{
-		tasks:  make(map[int64]*domain.Task),
-		nextID: 1,
+		tasks:        make(map[int64]*domain.Task),
+		trash:        make(map[int64]*domain.Task),
+		nextID:       1,
+		blockedBy:    make(map[int64][]int64),
+		series:       make(map[int64]*domain.TaskSeries),
+		detachedFrom: make(map[int64]int64),
 	}
 }
 
 type mockCategoryRepository struct {
 	categories map[int64]*domain.Category
+	trash      map[int64]*domain.Category
 	nextID     int64
 }
 
 func newMockCategoryRepository() *mockCategoryRepository {
 	return &mockCategoryRepository{
 		categories: make(map[int64]*domain.Category),
+		trash:      make(map[int64]*domain.Category),
 		nextID:     1,
 	}
 }
 
 func (m *mockCategoryRepository) Create(category *domain.Category) error {
 	category.ID = m.nextID
+	category.Version = 1
 	m.categories[m.nextID] = category
 	m.nextID++
 	return nil
 }
 
-func (m *mockCategoryRepository) GetByID(id int64) (*domain.Category, error) {
+func (m *mockCategoryRepository) GetByID(userID, id int64) (*domain.Category, error) {
 	category, exists := m.categories[id]
 	if !exists {
 		return nil, errors.New("category not found")
@@ -47,7 +66,7 @@ func (m *mockCategoryRepository) GetByID(id int64) (*domain.Category, error) {
 	return category, nil
 }
 
-func (m *mockCategoryRepository) GetAll() ([]domain.Category, error) {
+func (m *mockCategoryRepository) GetAll(userID int64) ([]domain.Category, error) {
 	var categories []domain.Category
 	for _, category := range m.categories {
 		categories = append(categories, *category)
@@ -60,19 +79,60 @@ func (m *mockCategoryRepository) Update(category *domain.Category) error {
 	if !exists {
 		return errors.New("category not found")
 	}
+	category.Version++
 	m.categories[category.ID] = category
 	return nil
 }
 
-func (m *mockCategoryRepository) Delete(id int64) error {
-	_, exists := m.categories[id]
+func (m *mockCategoryRepository) Delete(userID, id int64, deletedAt time.Time) error {
+	category, exists := m.categories[id]
 	if !exists {
 		return errors.New("category not found")
 	}
+	category.DeletedAt = &deletedAt
+	m.trash[id] = category
 	delete(m.categories, id)
 	return nil
 }
 
+func (m *mockCategoryRepository) GetTrash(userID int64) ([]domain.Category, error) {
+	categories := []domain.Category{}
+	for _, category := range m.trash {
+		categories = append(categories, *category)
+	}
+	return categories, nil
+}
+
+func (m *mockCategoryRepository) Restore(userID, id int64) error {
+	category, exists := m.trash[id]
+	if !exists {
+		return domain.ErrNotInTrash
+	}
+	category.DeletedAt = nil
+	m.categories[id] = category
+	delete(m.trash, id)
+	return nil
+}
+
+func (m *mockCategoryRepository) Purge(userID, id int64) error {
+	if _, exists := m.trash[id]; !exists {
+		return domain.ErrNotInTrash
+	}
+	delete(m.trash, id)
+	return nil
+}
+
+func (m *mockCategoryRepository) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	var purged int64
+	for id, category := range m.trash {
+		if category.DeletedAt.Before(cutoff) {
+			delete(m.trash, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
 func (m *mockCategoryRepository) GetByTaskID(taskID int64) ([]domain.Category, error) {
 	// Simple implementation for testing
 	return []domain.Category{}, nil
@@ -97,20 +157,176 @@ func (m *mockTaskRepository) Create(task *domain.Task) error {
 	task.ID = m.nextID
 	task.CreatedAt = time.Now()
 	task.UpdatedAt = time.Now()
+	task.Version = 1
 	m.tasks[m.nextID] = task
 	m.nextID++
 	return nil
 }
 
-func (m *mockTaskRepository) GetByID(id int64) (*domain.Task, error) {
+func (m *mockTaskRepository) GetByID(userID, id int64) (*domain.Task, error) {
 	task, exists := m.tasks[id]
 	if !exists {
 		return nil, errors.New("task not found")
 	}
+	children, _ := m.GetChildren(userID, id)
+	done := 0
+	for _, child := range children {
+		if child.Status == domain.StatusDone {
+			done++
+		}
+	}
+	task.Progress = domain.NewTaskProgress(done, len(children))
+
+	task.BlockedBy, task.Blocking = nil, nil
+	for taskID, blockers := range m.blockedBy {
+		for _, blockerID := range blockers {
+			if taskID == id {
+				blocker := m.tasks[blockerID]
+				task.BlockedBy = append(task.BlockedBy, domain.TaskRef{ID: blocker.ID, Title: blocker.Title, Status: blocker.Status})
+			}
+			if blockerID == id {
+				blocked := m.tasks[taskID]
+				task.Blocking = append(task.Blocking, domain.TaskRef{ID: blocked.ID, Title: blocked.Title, Status: blocked.Status})
+			}
+		}
+	}
 	return task, nil
 }
 
-func (m *mockTaskRepository) GetAll() ([]*domain.Task, error) {
+func (m *mockTaskRepository) AddDependency(taskID, blockedByID int64) error {
+	for _, id := range m.blockedBy[taskID] {
+		if id == blockedByID {
+			return nil
+		}
+	}
+	m.blockedBy[taskID] = append(m.blockedBy[taskID], blockedByID)
+	return nil
+}
+
+func (m *mockTaskRepository) RemoveDependency(userID, taskID, blockedByID int64) error {
+	blockers := m.blockedBy[taskID]
+	for i, id := range blockers {
+		if id == blockedByID {
+			m.blockedBy[taskID] = append(blockers[:i], blockers[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrDependencyNotFound
+}
+
+func (m *mockTaskRepository) CreateSeries(series *domain.TaskSeries) error {
+	series.ID = int64(len(m.series) + 1)
+	m.series[series.ID] = series
+	return nil
+}
+
+func (m *mockTaskRepository) GetSeries(userID, id int64) (*domain.TaskSeries, error) {
+	series, exists := m.series[id]
+	if !exists {
+		return nil, errors.New("task series not found")
+	}
+	copied := *series
+	return &copied, nil
+}
+
+func (m *mockTaskRepository) UpdateSeries(series *domain.TaskSeries) error {
+	if _, exists := m.series[series.ID]; !exists {
+		return errors.New("task series not found")
+	}
+	m.series[series.ID] = series
+	return nil
+}
+
+func (m *mockTaskRepository) DeleteSeries(userID, id int64) error {
+	if _, exists := m.series[id]; !exists {
+		return errors.New("task series not found")
+	}
+	delete(m.series, id)
+	for _, task := range m.tasks {
+		if task.SeriesID != nil && *task.SeriesID == id {
+			task.SeriesID = nil
+		}
+	}
+	return nil
+}
+
+func (m *mockTaskRepository) GetSeriesTasks(userID, seriesID int64) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	for id := int64(1); id < m.nextID; id++ {
+		task, exists := m.tasks[id]
+		if exists && task.SeriesID != nil && *task.SeriesID == seriesID {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (m *mockTaskRepository) AddHistory(entry *domain.TaskHistoryEntry) error {
+	entry.ID = int64(len(m.history) + 1)
+	m.history = append(m.history, *entry)
+	return nil
+}
+
+func (m *mockTaskRepository) GetHistory(userID, taskID int64) ([]domain.TaskHistoryEntry, error) {
+	history := []domain.TaskHistoryEntry{}
+	for _, entry := range m.history {
+		if entry.TaskID == taskID {
+			history = append(history, entry)
+		}
+	}
+	return history, nil
+}
+
+// WithTx restores the tasks as they were before fn when fn fails, which is
+// enough to observe a rollback in tests
+func (m *mockTaskRepository) WithTx(fn func(tasks repo.TaskRepository, categories repo.CategoryRepository) error) error {
+	snapshot := func(tasks map[int64]*domain.Task) map[int64]domain.Task {
+		copied := make(map[int64]domain.Task, len(tasks))
+		for id, task := range tasks {
+			copied[id] = *task
+		}
+		return copied
+	}
+	restore := func(copied map[int64]domain.Task) map[int64]*domain.Task {
+		tasks := make(map[int64]*domain.Task, len(copied))
+		for id, task := range copied {
+			task := task
+			tasks[id] = &task
+		}
+		return tasks
+	}
+
+	tasks, trash, history := snapshot(m.tasks), snapshot(m.trash), len(m.history)
+	categories := m.categoryRepo
+	if categories == nil {
+		categories = newMockCategoryRepository()
+	}
+	if err := fn(m, categories); err != nil {
+		m.tasks, m.trash, m.history = restore(tasks), restore(trash), m.history[:history]
+		return err
+	}
+	return nil
+}
+
+func (m *mockTaskRepository) HasDependencyPath(fromID, toID int64) (bool, error) {
+	seen := map[int64]bool{}
+	queue := []int64{fromID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == toID {
+			return true, nil
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		queue = append(queue, m.blockedBy[id]...)
+	}
+	return false, nil
+}
+
+func (m *mockTaskRepository) GetAll(userID int64) ([]*domain.Task, error) {
 	var tasks []*domain.Task
 	for _, task := range m.tasks {
 		tasks = append(tasks, task)
@@ -118,9 +334,9 @@ func (m *mockTaskRepository) GetAll() ([]*domain.Task, error) {
 	return tasks, nil
 }
 
-func (m *mockTaskRepository) GetWithFilters(filters *domain.TaskFilters) ([]*domain.Task, error) {
+func (m *mockTaskRepository) GetWithFilters(userID int64, filters *domain.TaskFilters) ([]*domain.Task, error) {
 	if filters == nil {
-		return m.GetAll()
+		return m.GetAll(userID)
 	}
 
 	var filteredTasks []*domain.Task
@@ -175,27 +391,150 @@ func (m *mockTaskRepository) GetWithFilters(filters *domain.TaskFilters) ([]*dom
 	return filteredTasks, nil
 }
 
+func (m *mockTaskRepository) GetPage(userID int64, filters *domain.TaskFilters, page *domain.PageRequest) (*domain.TaskPage, error) {
+	tasks, err := m.GetWithFilters(userID, filters)
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.TaskPage{Tasks: tasks, TotalCount: len(tasks)}
+	if len(tasks) > page.Limit {
+		result.Tasks = tasks[:page.Limit]
+		result.NextCursor = "next"
+	}
+	return result, nil
+}
+
+func (m *mockTaskRepository) GetChildren(userID, parentID int64) ([]*domain.Task, error) {
+	var children []*domain.Task
+	for _, task := range m.tasks {
+		if task.ParentID != nil && *task.ParentID == parentID {
+			children = append(children, task)
+		}
+	}
+	return children, nil
+}
+
+func (m *mockTaskRepository) GetDescendants(userID, rootID int64) ([]*domain.Task, error) {
+	var descendants []*domain.Task
+	queue := []int64{rootID}
+	for len(queue) > 0 {
+		children, _ := m.GetChildren(userID, queue[0])
+		queue = queue[1:]
+		for _, child := range children {
+			descendants = append(descendants, child)
+			queue = append(queue, child.ID)
+		}
+	}
+	return descendants, nil
+}
+
 func (m *mockTaskRepository) Update(task *domain.Task) error {
 	if _, exists := m.tasks[task.ID]; !exists {
 		return errors.New("task not found")
 	}
 	task.UpdatedAt = time.Now()
+	task.Version++
 	m.tasks[task.ID] = task
 	return nil
 }
 
-func (m *mockTaskRepository) Delete(id int64) error {
-	if _, exists := m.tasks[id]; !exists {
+func (m *mockTaskRepository) Delete(userID, id int64, deletedAt time.Time) error {
+	task, exists := m.tasks[id]
+	if !exists {
 		return errors.New("task not found")
 	}
-	delete(m.tasks, id)
+	children, _ := m.GetChildren(userID, id)
+	for _, child := range children {
+		child.ParentID = task.ParentID
+		m.detachedFrom[child.ID] = id
+	}
+	m.moveToTrash(task, deletedAt)
+	return nil
+}
+
+func (m *mockTaskRepository) DeleteTree(userID, id int64, deletedAt time.Time) error {
+	task, exists := m.tasks[id]
+	if !exists {
+		return errors.New("task not found")
+	}
+	descendants, _ := m.GetDescendants(userID, id)
+	for _, descendant := range descendants {
+		m.moveToTrash(descendant, deletedAt)
+	}
+	m.moveToTrash(task, deletedAt)
+	return nil
+}
+
+func (m *mockTaskRepository) moveToTrash(task *domain.Task, deletedAt time.Time) {
+	task.DeletedAt = &deletedAt
+	m.trash[task.ID] = task
+	delete(m.tasks, task.ID)
+}
+
+func (m *mockTaskRepository) GetTrash(userID int64) ([]*domain.Task, error) {
+	tasks := []*domain.Task{}
+	for id := int64(1); id < m.nextID; id++ {
+		if task, exists := m.trash[id]; exists {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (m *mockTaskRepository) Restore(userID, id int64) error {
+	task, exists := m.trash[id]
+	if !exists {
+		return domain.ErrNotInTrash
+	}
+	if task.ParentID != nil {
+		if _, trashed := m.trash[*task.ParentID]; trashed {
+			task.ParentID = nil
+		}
+	}
+	task.DeletedAt = nil
+	m.tasks[id] = task
+	delete(m.trash, id)
+	for subtaskID, parentID := range m.detachedFrom {
+		if parentID == id {
+			delete(m.detachedFrom, subtaskID)
+		}
+	}
+	return nil
+}
+
+func (m *mockTaskRepository) GetDetachedSubtasks(userID, parentID int64) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	for id := int64(1); id < m.nextID; id++ {
+		if task, exists := m.tasks[id]; exists && m.detachedFrom[id] == parentID {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (m *mockTaskRepository) Purge(userID, id int64) error {
+	if _, exists := m.trash[id]; !exists {
+		return domain.ErrNotInTrash
+	}
+	delete(m.trash, id)
 	return nil
 }
 
+func (m *mockTaskRepository) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	var purged int64
+	for id, task := range m.trash {
+		if task.DeletedAt.Before(cutoff) {
+			delete(m.trash, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
 func TestTaskService_CreateTask(t *testing.T) {
 	mockRepo := newMockTaskRepository()
 	mockCategoryRepo := newMockCategoryRepository()
-	service := NewTaskService(mockRepo, mockCategoryRepo)
+	service := NewTaskService(mockRepo, mockCategoryRepo, domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
 
 	tests := []struct {
 		name    string
@@ -224,7 +563,7 @@ func TestTaskService_CreateTask(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			task, err := service.CreateTask(tt.req)
+			task, err := service.CreateTask(testUserID, tt.req)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreateTask() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -248,7 +587,7 @@ func TestTaskService_CreateTask(t *testing.T) {
 func TestTaskService_GetTask(t *testing.T) {
 	mockRepo := newMockTaskRepository()
 	mockCategoryRepo := newMockCategoryRepository()
-	service := NewTaskService(mockRepo, mockCategoryRepo)
+	service := NewTaskService(mockRepo, mockCategoryRepo, domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
 
 	// Create a test task
 	req := &domain.CreateTaskRequest{
@@ -256,7 +595,7 @@ func TestTaskService_GetTask(t *testing.T) {
 		Description: "Test Description",
 		Priority:    domain.PriorityMedium,
 	}
-	createdTask, _ := service.CreateTask(req)
+	createdTask, _ := service.CreateTask(testUserID, req)
 
 	tests := []struct {
 		name    string
@@ -282,7 +621,7 @@ func TestTaskService_GetTask(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			task, err := service.GetTask(tt.id)
+			task, err := service.GetTask(testUserID, tt.id)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetTask() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -297,7 +636,7 @@ func TestTaskService_GetTask(t *testing.T) {
 func TestTaskService_UpdateTask(t *testing.T) {
 	mockRepo := newMockTaskRepository()
 	mockCategoryRepo := newMockCategoryRepository()
-	service := NewTaskService(mockRepo, mockCategoryRepo)
+	service := NewTaskService(mockRepo, mockCategoryRepo, domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
 
 	// Create a test task
 	req := &domain.CreateTaskRequest{
@@ -305,7 +644,7 @@ func TestTaskService_UpdateTask(t *testing.T) {
 		Description: "Test Description",
 		Priority:    domain.PriorityMedium,
 	}
-	createdTask, _ := service.CreateTask(req)
+	createdTask, _ := service.CreateTask(testUserID, req)
 
 	tests := []struct {
 		name    string
@@ -342,7 +681,7 @@ func TestTaskService_UpdateTask(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			task, err := service.UpdateTask(tt.id, tt.req)
+			task, err := service.UpdateTask(testUserID, tt.id, tt.req)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("UpdateTask() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -354,10 +693,71 @@ func TestTaskService_UpdateTask(t *testing.T) {
 	}
 }
 
+// timeRecordingTaskRepository records the times the service sets on every
+// task written, before the mock replaces them
+type timeRecordingTaskRepository struct {
+	*mockTaskRepository
+	written []time.Time
+}
+
+func (m *timeRecordingTaskRepository) record(task *domain.Task) {
+	m.written = append(m.written, task.UpdatedAt)
+	if task.DueDate != nil {
+		m.written = append(m.written, *task.DueDate)
+	}
+	if task.CompletedAt != nil {
+		m.written = append(m.written, *task.CompletedAt)
+	}
+}
+
+func (m *timeRecordingTaskRepository) Create(task *domain.Task) error {
+	m.written = append(m.written, task.CreatedAt)
+	m.record(task)
+	return m.mockTaskRepository.Create(task)
+}
+
+func (m *timeRecordingTaskRepository) Update(task *domain.Task) error {
+	m.record(task)
+	return m.mockTaskRepository.Update(task)
+}
+
+func TestTaskService_WritesTimesInUTC(t *testing.T) {
+	mockRepo := &timeRecordingTaskRepository{mockTaskRepository: newMockTaskRepository()}
+	service := NewTaskService(mockRepo, newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	due := time.Date(2030, 5, 2, 1, 0, 0, 0, zone)
+	task, err := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Offset", Priority: domain.PriorityLow, DueDate: &due})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	later := due.Add(time.Hour)
+	done := domain.StatusDone
+	if _, err := service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{DueDate: &later, Status: &done}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+
+	if len(mockRepo.written) == 0 {
+		t.Fatal("expected task times to be written")
+	}
+	for _, written := range mockRepo.written {
+		if written.Location() != time.UTC {
+			t.Errorf("time %v was written in %v, want UTC", written, written.Location())
+		}
+	}
+	if due.Location() != zone {
+		t.Error("CreateTask() should not modify the request's due date")
+	}
+	if stored := mockRepo.tasks[task.ID].DueDate; stored == nil || !stored.Equal(later) {
+		t.Errorf("due date = %v, want the instant %v", stored, later)
+	}
+}
+
 func TestTaskService_DeleteTask(t *testing.T) {
 	mockRepo := newMockTaskRepository()
 	mockCategoryRepo := newMockCategoryRepository()
-	service := NewTaskService(mockRepo, mockCategoryRepo)
+	service := NewTaskService(mockRepo, mockCategoryRepo, domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
 
 	// Create a test task
 	req := &domain.CreateTaskRequest{
@@ -365,7 +765,7 @@ func TestTaskService_DeleteTask(t *testing.T) {
 		Description: "Test Description",
 		Priority:    domain.PriorityMedium,
 	}
-	createdTask, _ := service.CreateTask(req)
+	createdTask, _ := service.CreateTask(testUserID, req)
 
 	tests := []struct {
 		name    string
@@ -391,7 +791,7 @@ func TestTaskService_DeleteTask(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := service.DeleteTask(tt.id)
+			err := service.DeleteTask(testUserID, tt.id)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("DeleteTask() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"task-manager/internal/domain"
+	"time"
+)
+
+func (s *taskService) GetTrashedTasks(userID int64) ([]*domain.Task, error) {
+	tasks, err := s.taskRepo.GetTrash(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trash: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// RestoreTask takes a task out of the trash together with the subtasks that
+// were deleted along with it. Subtasks that moved up when it was deleted
+// return under it.
+func (s *taskService) RestoreTask(userID, id int64) (*domain.Task, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid task id")
+	}
+
+	trash, err := s.taskRepo.GetTrash(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trash: %w", err)
+	}
+
+	root := findTask(trash, id)
+	if root == nil {
+		return nil, fmt.Errorf("task %w", domain.ErrNotInTrash)
+	}
+	restore := trashedSubtree(trash, root, func(task *domain.Task) bool {
+		return task.DeletedAt.Equal(*root.DeletedAt)
+	})
+
+	// Restoring a task forgets the subtasks detached from it, so they are
+	// read first
+	detached := make(map[int64][]*domain.Task, len(restore))
+	for _, task := range restore {
+		subtasks, err := s.taskRepo.GetDetachedSubtasks(userID, task.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get detached subtasks: %w", err)
+		}
+		detached[task.ID] = subtasks
+	}
+
+	// Parents go first so their subtasks stay attached to them
+	for _, task := range restore {
+		if err := s.taskRepo.Restore(userID, task.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	restored, err := s.taskRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.detachFromDoneParent(userID, restored); err != nil {
+		return nil, err
+	}
+
+	for _, task := range restore {
+		current, err := s.taskRepo.GetByID(userID, task.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.recordHistory(userID, domain.HistoryRestored, nil, current); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, task := range restore {
+		for _, subtask := range detached[task.ID] {
+			if err := s.reattachSubtask(userID, task.ID, subtask.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return s.taskRepo.GetByID(userID, id)
+}
+
+// reattachSubtask moves a subtask back under the restored task it was
+// detached from. It stays where it is if the hierarchy no longer allows it,
+// such as an open subtask under a closed parent.
+func (s *taskService) reattachSubtask(userID, parentID, id int64) error {
+	subtask, err := s.taskRepo.GetByID(userID, id)
+	if err != nil {
+		return err
+	}
+	before := *subtask
+
+	if err := s.setParent(userID, subtask, parentID); err != nil {
+		if errors.Is(err, domain.ErrInvalidParent) || errors.Is(err, domain.ErrSeriesSubtask) {
+			return nil
+		}
+		return err
+	}
+	if err := s.checkSubtaskStatus(userID, subtask); err != nil {
+		if errors.Is(err, domain.ErrParentDone) {
+			return nil
+		}
+		return err
+	}
+
+	subtask.UpdatedAt = time.Now().UTC()
+	if err := s.taskRepo.Update(subtask); err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+	updated, err := s.taskRepo.GetByID(userID, id)
+	if err != nil {
+		return err
+	}
+	return s.recordHistory(userID, domain.HistoryUpdated, &before, updated)
+}
+
+// detachFromDoneParent moves an open task that was restored under a parent
+// completed in the meantime to the top level, since a closed task may not
+// have open subtasks
+func (s *taskService) detachFromDoneParent(userID int64, task *domain.Task) error {
+	workflow := s.workflow.Get()
+	if task.ParentID == nil || workflow.IsClosed(task.Status) {
+		return nil
+	}
+
+	parent, err := s.taskRepo.GetByID(userID, *task.ParentID)
+	if err != nil {
+		return fmt.Errorf("failed to get parent task: %w", err)
+	}
+	if !workflow.IsClosed(parent.Status) {
+		return nil
+	}
+
+	task.ParentID = nil
+	task.UpdatedAt = time.Now().UTC()
+	if err := s.taskRepo.Update(task); err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+	return nil
+}
+
+// PurgeTask permanently deletes a task in the trash and its trashed subtasks
+func (s *taskService) PurgeTask(userID, id int64) error {
+	if id <= 0 {
+		return fmt.Errorf("invalid task id")
+	}
+
+	trash, err := s.taskRepo.GetTrash(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get trash: %w", err)
+	}
+
+	root := findTask(trash, id)
+	if root == nil {
+		return fmt.Errorf("task %w", domain.ErrNotInTrash)
+	}
+
+	return s.purgeTasks(userID, trashedSubtree(trash, root, func(*domain.Task) bool { return true }))
+}
+
+func (s *taskService) PurgeTrash(userID int64) error {
+	trash, err := s.taskRepo.GetTrash(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get trash: %w", err)
+	}
+
+	return s.purgeTasks(userID, trash)
+}
+
+func (s *taskService) purgeTasks(userID int64, tasks []*domain.Task) error {
+	for _, task := range tasks {
+		if err := s.taskRepo.Purge(userID, task.ID); err != nil {
+			return err
+		}
+		// Purging changes no field, so the entry carries no changes
+		if err := s.recordHistory(userID, domain.HistoryPurged, task, task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *taskService) PurgeExpiredTrash(cutoff time.Time) (int64, error) {
+	// Deletion times are stored in UTC so that they compare correctly
+	purged, err := s.taskRepo.PurgeDeletedBefore(cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+
+	return purged, nil
+}
+
+func findTask(tasks []*domain.Task, id int64) *domain.Task {
+	for _, task := range tasks {
+		if task.ID == id {
+			return task
+		}
+	}
+	return nil
+}
+
+// trashedSubtree returns root followed by its descendants in trash that
+// match include, parents before children
+func trashedSubtree(trash []*domain.Task, root *domain.Task, include func(task *domain.Task) bool) []*domain.Task {
+	subtree := []*domain.Task{root}
+	for i := 0; i < len(subtree) && i < len(trash); i++ {
+		for _, task := range trash {
+			if task.ParentID != nil && *task.ParentID == subtree[i].ID && include(task) {
+				subtree = append(subtree, task)
+			}
+		}
+	}
+	return subtree
+}
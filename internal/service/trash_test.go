@@ -0,0 +1,200 @@
+package service
+
+import (
+	"errors"
+	"task-manager/internal/domain"
+	"task-manager/internal/repo"
+	"testing"
+	"time"
+)
+
+func TestTaskService_RestoreTask(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	service := NewTaskService(taskRepo, newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	parent, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Parent", Priority: domain.PriorityLow})
+	child, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Child", Priority: domain.PriorityLow, ParentID: &parent.ID})
+	other, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Other", Priority: domain.PriorityLow, ParentID: &parent.ID})
+
+	// Deleted earlier on its own, so it stays in the trash
+	if err := service.DeleteTaskTree(testUserID, other.ID); err != nil {
+		t.Fatalf("DeleteTaskTree() error = %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	if err := service.DeleteTaskTree(testUserID, parent.ID); err != nil {
+		t.Fatalf("DeleteTaskTree() error = %v", err)
+	}
+
+	if _, err := service.GetTask(testUserID, child.ID); err == nil {
+		t.Error("trashed task should be hidden")
+	}
+	trash, _ := service.GetTrashedTasks(testUserID)
+	if len(trash) != 3 {
+		t.Fatalf("expected 3 trashed tasks, got %d", len(trash))
+	}
+
+	restored, err := service.RestoreTask(testUserID, parent.ID)
+	if err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+	if restored.DeletedAt != nil {
+		t.Error("restored task should have no deleted_at")
+	}
+	if task, err := service.GetTask(testUserID, child.ID); err != nil || task.ParentID == nil || *task.ParentID != parent.ID {
+		t.Errorf("subtask deleted with its parent should be restored under it, got %+v, %v", task, err)
+	}
+	if _, err := service.GetTask(testUserID, other.ID); err == nil {
+		t.Error("subtask deleted separately should stay in the trash")
+	}
+
+	if _, err := service.RestoreTask(testUserID, parent.ID); !errors.Is(err, domain.ErrNotInTrash) {
+		t.Errorf("restoring a live task should fail with ErrNotInTrash, got %v", err)
+	}
+
+	restoredOther, err := service.RestoreTask(testUserID, other.ID)
+	if err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+	if restoredOther.ParentID == nil || *restoredOther.ParentID != parent.ID {
+		t.Error("restored subtask should return to its live parent")
+	}
+}
+
+func TestTaskService_RestoreUnderDoneParent(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	service := NewTaskService(taskRepo, newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	parent, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Parent", Priority: domain.PriorityLow})
+	child, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Child", Priority: domain.PriorityLow, ParentID: &parent.ID})
+
+	if err := service.DeleteTaskTree(testUserID, child.ID); err != nil {
+		t.Fatalf("DeleteTaskTree() error = %v", err)
+	}
+	done := domain.StatusDone
+	if _, err := service.UpdateTask(testUserID, parent.ID, &domain.UpdateTaskRequest{Status: &done}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+
+	restored, err := service.RestoreTask(testUserID, child.ID)
+	if err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+	if restored.ParentID != nil {
+		t.Error("open task restored under a done parent should be detached")
+	}
+}
+
+func TestTaskService_PurgeTrash(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	service := NewTaskService(taskRepo, newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	parent, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Parent", Priority: domain.PriorityLow})
+	service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Child", Priority: domain.PriorityLow, ParentID: &parent.ID})
+	kept, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Kept", Priority: domain.PriorityLow})
+
+	if err := service.PurgeTask(testUserID, parent.ID); !errors.Is(err, domain.ErrNotInTrash) {
+		t.Errorf("purging a live task should fail with ErrNotInTrash, got %v", err)
+	}
+
+	service.DeleteTaskTree(testUserID, parent.ID)
+	if err := service.PurgeTask(testUserID, parent.ID); err != nil {
+		t.Fatalf("PurgeTask() error = %v", err)
+	}
+	if trash, _ := service.GetTrashedTasks(testUserID); len(trash) != 0 {
+		t.Errorf("purging a task should purge its trashed subtasks, %d left", len(trash))
+	}
+
+	service.DeleteTask(testUserID, kept.ID)
+	if purged, _ := service.PurgeExpiredTrash(time.Now().Add(-time.Hour)); purged != 0 {
+		t.Errorf("recently trashed task should be kept, purged %d", purged)
+	}
+	if purged, _ := service.PurgeExpiredTrash(time.Now().Add(time.Second)); purged != 1 {
+		t.Errorf("expired task should be purged, purged %d", purged)
+	}
+}
+
+func TestCategoryService_Trash(t *testing.T) {
+	categoryRepo := newMockCategoryRepository()
+	service := NewCategoryService(categoryRepo, nil)
+
+	category, err := service.CreateCategory(testUserID, &domain.CreateCategoryRequest{Name: "Work"})
+	if err != nil {
+		t.Fatalf("CreateCategory() error = %v", err)
+	}
+	if err := service.DeleteCategory(testUserID, category.ID); err != nil {
+		t.Fatalf("DeleteCategory() error = %v", err)
+	}
+
+	if _, err := service.GetCategory(testUserID, category.ID); err == nil {
+		t.Error("trashed category should be hidden")
+	}
+	if _, err := service.CreateCategory(testUserID, &domain.CreateCategoryRequest{Name: "Work"}); err == nil {
+		t.Error("creating a category named like a trashed one should fail")
+	}
+
+	restored, err := service.RestoreCategory(testUserID, category.ID)
+	if err != nil || restored.DeletedAt != nil {
+		t.Fatalf("RestoreCategory() = %+v, %v", restored, err)
+	}
+
+	service.DeleteCategory(testUserID, category.ID)
+	if err := service.PurgeTrash(testUserID); err != nil {
+		t.Fatalf("PurgeTrash() error = %v", err)
+	}
+	if trash, _ := service.GetTrashedCategories(testUserID); len(trash) != 0 {
+		t.Errorf("expected empty trash, got %d categories", len(trash))
+	}
+}
+
+func TestTaskService_RestoreReattachesSubtasks(t *testing.T) {
+	db, user := openTestDB(t)
+	service := NewTaskService(repo.NewTaskRepository(db), repo.NewCategoryRepository(db), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	create := func(title string, parentID *int64) *domain.Task {
+		task, err := service.CreateTask(user.ID, &domain.CreateTaskRequest{Title: title, Priority: domain.PriorityLow, ParentID: parentID})
+		if err != nil {
+			t.Fatalf("CreateTask() error = %v", err)
+		}
+		return task
+	}
+	parent := create("Parent", nil)
+	child := create("Child", &parent.ID)
+	moved := create("Moved", &parent.ID)
+	other := create("Other", nil)
+
+	if err := service.DeleteTask(user.ID, parent.ID); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if task, _ := service.GetTask(user.ID, child.ID); task.ParentID != nil {
+		t.Fatalf("subtask of a trashed task should move up, got parent %v", *task.ParentID)
+	}
+
+	// A subtask moved elsewhere in the meantime stays where it was put
+	if _, err := service.UpdateTask(user.ID, moved.ID, &domain.UpdateTaskRequest{ParentID: &other.ID}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+
+	if _, err := service.RestoreTask(user.ID, parent.ID); err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+	if task, err := service.GetTask(user.ID, child.ID); err != nil || task.ParentID == nil || *task.ParentID != parent.ID {
+		t.Errorf("subtask should return under its restored parent, got %+v, %v", task, err)
+	}
+	if task, err := service.GetTask(user.ID, moved.ID); err != nil || task.ParentID == nil || *task.ParentID != other.ID {
+		t.Errorf("moved subtask should stay under its new parent, got %+v, %v", task, err)
+	}
+
+	// Deleting and restoring again only takes back the current subtasks
+	if err := service.DeleteTask(user.ID, other.ID); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if _, err := service.RestoreTask(user.ID, other.ID); err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+	if task, _ := service.GetTask(user.ID, moved.ID); task.ParentID == nil || *task.ParentID != other.ID {
+		t.Errorf("subtask should return under its restored parent again, got %+v", task)
+	}
+	if task, _ := service.GetTask(user.ID, child.ID); task.ParentID == nil || *task.ParentID != parent.ID {
+		t.Errorf("subtask of another task should stay put, got %+v", task)
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"errors"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+func TestTaskService_UpdateTask_ExpectedVersion(t *testing.T) {
+	service := NewTaskService(newMockTaskRepository(), newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+
+	task, _ := service.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Original", Priority: domain.PriorityLow})
+	if task.Version != 1 {
+		t.Fatalf("new task version = %d, want 1", task.Version)
+	}
+
+	stale := int64(1)
+	title := "First edit"
+	updated, err := service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Title: &title, ExpectedVersion: &stale})
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if updated.Version != 2 {
+		t.Errorf("updated task version = %d, want 2", updated.Version)
+	}
+
+	title = "Second edit"
+	if _, err := service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Title: &title, ExpectedVersion: &stale}); !errors.Is(err, domain.ErrVersionConflict) {
+		t.Fatalf("stale update error = %v, want %v", err, domain.ErrVersionConflict)
+	}
+	if current, _ := service.GetTask(testUserID, task.ID); current.Title != "First edit" {
+		t.Errorf("stale update should not change the task, title = %q", current.Title)
+	}
+
+	// Without an expected version the write goes through unconditionally
+	if _, err := service.UpdateTask(testUserID, task.ID, &domain.UpdateTaskRequest{Title: &title}); err != nil {
+		t.Errorf("unconditional UpdateTask() error = %v", err)
+	}
+}
+
+func TestCategoryService_UpdateCategory_ExpectedVersion(t *testing.T) {
+	service := NewCategoryService(newMockCategoryRepository(), nil)
+
+	category, _ := service.CreateCategory(testUserID, &domain.CreateCategoryRequest{Name: "Work"})
+
+	name := "Office"
+	current := category.Version
+	updated, err := service.UpdateCategory(testUserID, category.ID, &domain.UpdateCategoryRequest{Name: &name, ExpectedVersion: &current})
+	if err != nil {
+		t.Fatalf("UpdateCategory() error = %v", err)
+	}
+	if updated.Version != current+1 {
+		t.Errorf("updated category version = %d, want %d", updated.Version, current+1)
+	}
+
+	name = "Home"
+	if _, err := service.UpdateCategory(testUserID, category.ID, &domain.UpdateCategoryRequest{Name: &name, ExpectedVersion: &current}); !errors.Is(err, domain.ErrVersionConflict) {
+		t.Errorf("stale update error = %v, want %v", err, domain.ErrVersionConflict)
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"task-manager/internal/domain"
+	"task-manager/internal/repo"
+	"time"
+)
+
+type ViewService interface {
+	CreateView(userID int64, req *domain.CreateViewRequest) (*domain.SavedView, error)
+	// GetView returns a view the user owns or that is shared
+	GetView(userID, id int64) (*domain.SavedView, error)
+	// GetAllViews returns the user's views followed by those shared by others
+	GetAllViews(userID int64) ([]domain.SavedView, error)
+	UpdateView(userID, id int64, req *domain.UpdateViewRequest) (*domain.SavedView, error)
+	DeleteView(userID, id int64) error
+}
+
+type viewService struct {
+	viewRepo repo.ViewRepository
+	workflow *domain.WorkflowStore
+}
+
+// NewViewService returns a ViewService that checks the statuses of filters
+// against the workflow in effect in workflow
+func NewViewService(viewRepo repo.ViewRepository, workflow *domain.WorkflowStore) ViewService {
+	return &viewService{viewRepo: viewRepo, workflow: workflow}
+}
+
+func (s *viewService) CreateView(userID int64, req *domain.CreateViewRequest) (*domain.SavedView, error) {
+	if err := req.Validate(s.workflow.Get()); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = domain.ViewPrivate
+	}
+
+	now := time.Now().UTC()
+	view := &domain.SavedView{
+		OwnerID:    userID,
+		Name:       req.Name,
+		Filters:    req.Filters,
+		Visibility: visibility,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := s.viewRepo.Create(view); err != nil {
+		return nil, err
+	}
+
+	return view, nil
+}
+
+func (s *viewService) GetView(userID, id int64) (*domain.SavedView, error) {
+	return s.viewRepo.GetByID(userID, id)
+}
+
+func (s *viewService) GetAllViews(userID int64) ([]domain.SavedView, error) {
+	return s.viewRepo.GetAll(userID)
+}
+
+func (s *viewService) UpdateView(userID, id int64, req *domain.UpdateViewRequest) (*domain.SavedView, error) {
+	if err := req.Validate(s.workflow.Get()); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	view, err := s.ownedView(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		view.Name = *req.Name
+	}
+	if req.Filters != nil {
+		view.Filters = *req.Filters
+	}
+	if req.Visibility != nil {
+		view.Visibility = *req.Visibility
+	}
+	view.UpdatedAt = time.Now().UTC()
+
+	if err := s.viewRepo.Update(view); err != nil {
+		return nil, err
+	}
+
+	return view, nil
+}
+
+func (s *viewService) DeleteView(userID, id int64) error {
+	if _, err := s.ownedView(userID, id); err != nil {
+		return err
+	}
+
+	return s.viewRepo.Delete(userID, id)
+}
+
+// ownedView returns a view visible to the user, failing unless the user owns
+// it
+func (s *viewService) ownedView(userID, id int64) (*domain.SavedView, error) {
+	view, err := s.viewRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if view.OwnerID != userID {
+		return nil, domain.ErrNotViewOwner
+	}
+
+	return view, nil
+}
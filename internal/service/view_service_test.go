@@ -0,0 +1,123 @@
+package service
+
+import (
+	"errors"
+	"task-manager/internal/domain"
+	"testing"
+)
+
+type mockViewRepository struct {
+	views  map[int64]*domain.SavedView
+	nextID int64
+}
+
+func newMockViewRepository() *mockViewRepository {
+	return &mockViewRepository{
+		views:  make(map[int64]*domain.SavedView),
+		nextID: 1,
+	}
+}
+
+func (m *mockViewRepository) Create(view *domain.SavedView) error {
+	view.ID = m.nextID
+	m.nextID++
+	stored := *view
+	m.views[view.ID] = &stored
+	return nil
+}
+
+func (m *mockViewRepository) GetByID(userID, id int64) (*domain.SavedView, error) {
+	view, ok := m.views[id]
+	if !ok || (view.OwnerID != userID && view.Visibility != domain.ViewShared) {
+		return nil, domain.ErrViewNotFound
+	}
+	found := *view
+	return &found, nil
+}
+
+func (m *mockViewRepository) GetAll(userID int64) ([]domain.SavedView, error) {
+	views := []domain.SavedView{}
+	for id := int64(1); id < m.nextID; id++ {
+		if view, err := m.GetByID(userID, id); err == nil {
+			views = append(views, *view)
+		}
+	}
+	return views, nil
+}
+
+func (m *mockViewRepository) Update(view *domain.SavedView) error {
+	stored, ok := m.views[view.ID]
+	if !ok || stored.OwnerID != view.OwnerID {
+		return domain.ErrViewNotFound
+	}
+	updated := *view
+	m.views[view.ID] = &updated
+	return nil
+}
+
+func (m *mockViewRepository) Delete(userID, id int64) error {
+	view, ok := m.views[id]
+	if !ok || view.OwnerID != userID {
+		return domain.ErrViewNotFound
+	}
+	delete(m.views, id)
+	return nil
+}
+
+func TestViewService_CreateView(t *testing.T) {
+	service := NewViewService(newMockViewRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()))
+
+	view, err := service.CreateView(testUserID, &domain.CreateViewRequest{
+		Name:    "Urgent",
+		Filters: domain.TaskFilters{Priorities: []domain.TaskPriority{domain.PriorityCritical}},
+	})
+	if err != nil {
+		t.Fatalf("CreateView() error = %v", err)
+	}
+	if view.ID == 0 || view.OwnerID != testUserID || view.Visibility != domain.ViewPrivate {
+		t.Errorf("Expected a private view owned by the user, got %+v", view)
+	}
+
+	_, err = service.CreateView(testUserID, &domain.CreateViewRequest{
+		Name:    "Bad",
+		Filters: domain.TaskFilters{Sort: []domain.SortField{{Field: "owner"}}},
+	})
+	if err == nil {
+		t.Error("Expected invalid filters to be rejected")
+	}
+}
+
+func TestViewService_Sharing(t *testing.T) {
+	service := NewViewService(newMockViewRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()))
+	other := testUserID + 1
+
+	shared, _ := service.CreateView(other, &domain.CreateViewRequest{Name: "Team", Visibility: domain.ViewShared})
+	private, _ := service.CreateView(other, &domain.CreateViewRequest{Name: "Mine"})
+
+	if _, err := service.GetView(testUserID, shared.ID); err != nil {
+		t.Errorf("Expected a shared view to be visible, got %v", err)
+	}
+	if _, err := service.GetView(testUserID, private.ID); !errors.Is(err, domain.ErrViewNotFound) {
+		t.Errorf("Expected a private view to be hidden, got %v", err)
+	}
+	if views, _ := service.GetAllViews(testUserID); len(views) != 1 || views[0].ID != shared.ID {
+		t.Errorf("Expected only the shared view to be listed, got %+v", views)
+	}
+
+	name := "Taken over"
+	if _, err := service.UpdateView(testUserID, shared.ID, &domain.UpdateViewRequest{Name: &name}); !errors.Is(err, domain.ErrNotViewOwner) {
+		t.Errorf("UpdateView() of a shared view error = %v, want ErrNotViewOwner", err)
+	}
+	if err := service.DeleteView(testUserID, shared.ID); !errors.Is(err, domain.ErrNotViewOwner) {
+		t.Errorf("DeleteView() of a shared view error = %v, want ErrNotViewOwner", err)
+	}
+
+	visibility := domain.ViewPrivate
+	updated, err := service.UpdateView(other, shared.ID, &domain.UpdateViewRequest{Visibility: &visibility})
+	if err != nil || updated.Visibility != domain.ViewPrivate || updated.Name != "Team" {
+		t.Fatalf("UpdateView() = %+v, %v", updated, err)
+	}
+	if _, err := service.GetView(testUserID, shared.ID); !errors.Is(err, domain.ErrViewNotFound) {
+		t.Errorf("Expected the view to be hidden once private, got %v", err)
+	}
+}
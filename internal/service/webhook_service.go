@@ -0,0 +1,356 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"task-manager/internal/domain"
+	"task-manager/internal/repo"
+	"time"
+)
+
+const (
+	// maxDeliveryList caps how many deliveries are listed per webhook
+	maxDeliveryList = 100
+	// deliveryBatchSize caps how many due deliveries DeliverDue sends
+	deliveryBatchSize = 50
+)
+
+// errRedirectRefused is returned for receivers that answer with a redirect,
+// which could point deliveries at an address the dialer refuses
+var errRedirectRefused = errors.New("webhook receivers must not redirect")
+
+type WebhookService interface {
+	CreateWebhook(userID int64, req *domain.CreateWebhookRequest) (*domain.Webhook, error)
+	GetWebhook(userID, id int64) (*domain.Webhook, error)
+	GetAllWebhooks(userID int64) ([]domain.Webhook, error)
+	UpdateWebhook(userID, id int64, req *domain.UpdateWebhookRequest) (*domain.Webhook, error)
+	DeleteWebhook(userID, id int64) error
+	GetDeliveries(userID, webhookID int64, status domain.WebhookDeliveryStatus) ([]domain.WebhookDelivery, error)
+	GetDelivery(userID, webhookID, id int64) (*domain.WebhookDelivery, error)
+	// ReplayDelivery queues the event of a delivery to be sent again
+	ReplayDelivery(userID, webhookID, id int64) (*domain.WebhookDelivery, error)
+	// Enqueue queues an event for every webhook of its user that subscribes
+	// to it
+	Enqueue(event domain.Event) error
+	// DeliverDue sends the deliveries whose next attempt is due and returns
+	// how many were attempted
+	DeliverDue() (int, error)
+}
+
+type webhookService struct {
+	webhookRepo repo.WebhookRepository
+	client      *http.Client
+	policy      domain.WebhookRetryPolicy
+}
+
+// NewWebhookService returns a service that sends deliveries with client,
+// which is copied so that it never follows redirects. Production clients
+// should come from NewWebhookClient.
+func NewWebhookService(webhookRepo repo.WebhookRepository, client *http.Client, policy domain.WebhookRetryPolicy) WebhookService {
+	noRedirects := *client
+	noRedirects.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return errRedirectRefused
+	}
+
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		client:      &noRedirects,
+		policy:      policy,
+	}
+}
+
+// NewWebhookClient returns an HTTP client for webhook deliveries that only
+// connects to public addresses. The check runs on the address actually
+// dialed, after DNS resolution, so a hostname cannot be rebound to an
+// internal address between validation and delivery.
+func NewWebhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, conn syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("invalid webhook address %q: %w", address, err)
+			}
+			if !domain.WebhookAddressAllowed(addrPort.Addr()) {
+				return fmt.Errorf("webhook address %s is not public", addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialed instead of the receiver and bypass the check
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+func (s *webhookService) CreateWebhook(userID int64, req *domain.CreateWebhookRequest) (*domain.Webhook, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	now := time.Now().UTC()
+	webhook := &domain.Webhook{
+		UserID:    userID,
+		URL:       req.URL,
+		Secret:    secret,
+		Events:    req.Events,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.webhookRepo.Create(webhook); err != nil {
+		return nil, err
+	}
+
+	// The secret is only shown when it is set
+	return webhook, nil
+}
+
+func (s *webhookService) GetWebhook(userID, id int64) (*domain.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+func (s *webhookService) GetAllWebhooks(userID int64) ([]domain.Webhook, error) {
+	webhooks, err := s.webhookRepo.GetAll(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+func (s *webhookService) UpdateWebhook(userID, id int64, req *domain.UpdateWebhookRequest) (*domain.Webhook, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	webhook, err := s.webhookRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		webhook.URL = *req.URL
+	}
+	if req.Secret != nil {
+		webhook.Secret = *req.Secret
+	}
+	if req.Events != nil {
+		webhook.Events = *req.Events
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+	webhook.UpdatedAt = time.Now().UTC()
+
+	if err := s.webhookRepo.Update(webhook); err != nil {
+		return nil, err
+	}
+
+	if req.Secret == nil {
+		webhook.Secret = ""
+	}
+	return webhook, nil
+}
+
+func (s *webhookService) DeleteWebhook(userID, id int64) error {
+	return s.webhookRepo.Delete(userID, id)
+}
+
+func (s *webhookService) GetDeliveries(userID, webhookID int64, status domain.WebhookDeliveryStatus) ([]domain.WebhookDelivery, error) {
+	if status != "" && !status.IsValid() {
+		return nil, fmt.Errorf("validation failed: invalid delivery status: %s", status)
+	}
+
+	if _, err := s.webhookRepo.GetByID(userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	return s.webhookRepo.GetDeliveries(webhookID, status, maxDeliveryList)
+}
+
+func (s *webhookService) GetDelivery(userID, webhookID, id int64) (*domain.WebhookDelivery, error) {
+	if _, err := s.webhookRepo.GetByID(userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	return s.webhookRepo.GetDelivery(webhookID, id)
+}
+
+func (s *webhookService) ReplayDelivery(userID, webhookID, id int64) (*domain.WebhookDelivery, error) {
+	original, err := s.GetDelivery(userID, webhookID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := newDelivery(webhookID, original.EventID, original.EventType, original.Payload)
+	if err := s.webhookRepo.CreateDelivery(delivery); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (s *webhookService) Enqueue(event domain.Event) error {
+	webhooks, err := s.webhookRepo.GetAll(event.UserID)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for i := range webhooks {
+		if !webhooks[i].Subscribes(event.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				return fmt.Errorf("failed to encode event: %w", err)
+			}
+		}
+
+		delivery := newDelivery(webhooks[i].ID, event.ID, event.Type, payload)
+		if err := s.webhookRepo.CreateDelivery(delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *webhookService) DeliverDue() (int, error) {
+	// Times are stored in UTC so that they compare correctly
+	due, err := s.webhookRepo.GetDueDeliveries(time.Now().UTC(), deliveryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, dispatch := range due {
+		if err := s.deliver(dispatch.Webhook, dispatch.Delivery); err != nil {
+			return 0, err
+		}
+	}
+	return len(due), nil
+}
+
+// deliver makes one attempt at a delivery and records its outcome. A failed
+// attempt is retried after a growing delay until the policy gives up.
+func (s *webhookService) deliver(webhook *domain.Webhook, delivery *domain.WebhookDelivery) error {
+	started := time.Now()
+	statusCode, sendErr := s.send(webhook, delivery, started)
+	finished := time.Now()
+
+	delivery.AttemptCount++
+	attempt := &domain.WebhookDeliveryAttempt{
+		DeliveryID:  delivery.ID,
+		Attempt:     delivery.AttemptCount,
+		StatusCode:  statusCode,
+		DurationMS:  finished.Sub(started).Milliseconds(),
+		AttemptedAt: started.UTC(),
+	}
+	if sendErr == nil && (*statusCode < 200 || *statusCode > 299) {
+		sendErr = fmt.Errorf("receiver responded with status %d", *statusCode)
+	}
+	if sendErr != nil {
+		attempt.Error = sendErr.Error()
+	}
+
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = attempt.Error
+	delivery.UpdatedAt = finished.UTC()
+	switch {
+	case sendErr == nil:
+		delivery.Status = domain.DeliverySucceeded
+		delivery.NextAttemptAt = nil
+	case delivery.AttemptCount >= s.policy.MaxAttempts:
+		delivery.Status = domain.DeliveryFailed
+		delivery.NextAttemptAt = nil
+	default:
+		next := finished.Add(s.policy.RetryDelay(delivery.AttemptCount)).UTC()
+		delivery.Status = domain.DeliveryPending
+		delivery.NextAttemptAt = &next
+	}
+
+	return s.webhookRepo.RecordAttempt(delivery, attempt)
+}
+
+// send posts the signed payload and returns the response status, which is nil
+// when no response was received. The body is discarded so that receivers
+// cannot be used to read responses back.
+func (s *webhookService) send(webhook *domain.Webhook, delivery *domain.WebhookDelivery, now time.Time) (*int, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "task-manager-webhooks")
+	req.Header.Set(domain.WebhookEventHeader, string(delivery.EventType))
+	req.Header.Set(domain.WebhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(domain.WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(domain.WebhookSignatureHeader, domain.SignWebhookPayload(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	return &resp.StatusCode, nil
+}
+
+func newDelivery(webhookID int64, eventID string, eventType domain.EventType, payload []byte) *domain.WebhookDelivery {
+	now := time.Now().UTC()
+	return &domain.WebhookDelivery{
+		WebhookID:     webhookID,
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        domain.DeliveryPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
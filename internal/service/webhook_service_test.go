@@ -0,0 +1,356 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"task-manager/internal/domain"
+	"testing"
+	"time"
+)
+
+type mockWebhookRepository struct {
+	webhooks   map[int64]*domain.Webhook
+	deliveries map[int64]*domain.WebhookDelivery
+	nextID     int64
+}
+
+func newMockWebhookRepository() *mockWebhookRepository {
+	return &mockWebhookRepository{
+		webhooks:   make(map[int64]*domain.Webhook),
+		deliveries: make(map[int64]*domain.WebhookDelivery),
+		nextID:     1,
+	}
+}
+
+func (m *mockWebhookRepository) Create(webhook *domain.Webhook) error {
+	webhook.ID = m.nextID
+	m.nextID++
+	stored := *webhook
+	m.webhooks[webhook.ID] = &stored
+	return nil
+}
+
+func (m *mockWebhookRepository) GetByID(userID, id int64) (*domain.Webhook, error) {
+	webhook, ok := m.webhooks[id]
+	if !ok || webhook.UserID != userID {
+		return nil, domain.ErrWebhookNotFound
+	}
+	found := *webhook
+	return &found, nil
+}
+
+func (m *mockWebhookRepository) GetAll(userID int64) ([]domain.Webhook, error) {
+	webhooks := []domain.Webhook{}
+	for _, webhook := range m.webhooks {
+		if webhook.UserID == userID {
+			webhooks = append(webhooks, *webhook)
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+	return webhooks, nil
+}
+
+func (m *mockWebhookRepository) Update(webhook *domain.Webhook) error {
+	if _, err := m.GetByID(webhook.UserID, webhook.ID); err != nil {
+		return err
+	}
+	stored := *webhook
+	m.webhooks[webhook.ID] = &stored
+	return nil
+}
+
+func (m *mockWebhookRepository) Delete(userID, id int64) error {
+	if _, err := m.GetByID(userID, id); err != nil {
+		return err
+	}
+	delete(m.webhooks, id)
+	return nil
+}
+
+func (m *mockWebhookRepository) CreateDelivery(delivery *domain.WebhookDelivery) error {
+	delivery.ID = m.nextID
+	m.nextID++
+	stored := *delivery
+	m.deliveries[delivery.ID] = &stored
+	return nil
+}
+
+func (m *mockWebhookRepository) GetDeliveries(webhookID int64, status domain.WebhookDeliveryStatus, limit int) ([]domain.WebhookDelivery, error) {
+	deliveries := []domain.WebhookDelivery{}
+	for _, delivery := range m.deliveries {
+		if delivery.WebhookID == webhookID && (status == "" || delivery.Status == status) {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *mockWebhookRepository) GetDelivery(webhookID, id int64) (*domain.WebhookDelivery, error) {
+	delivery, ok := m.deliveries[id]
+	if !ok || delivery.WebhookID != webhookID {
+		return nil, domain.ErrDeliveryNotFound
+	}
+	found := *delivery
+	return &found, nil
+}
+
+func (m *mockWebhookRepository) GetDueDeliveries(now time.Time, limit int) ([]domain.WebhookDispatch, error) {
+	dispatches := []domain.WebhookDispatch{}
+	for _, delivery := range m.deliveries {
+		webhook := m.webhooks[delivery.WebhookID]
+		if delivery.Status != domain.DeliveryPending || delivery.NextAttemptAt.After(now) || !webhook.Active {
+			continue
+		}
+		due := *delivery
+		dispatches = append(dispatches, domain.WebhookDispatch{Delivery: &due, Webhook: webhook})
+	}
+	return dispatches, nil
+}
+
+func (m *mockWebhookRepository) RecordAttempt(delivery *domain.WebhookDelivery, attempt *domain.WebhookDeliveryAttempt) error {
+	stored := *delivery
+	stored.Attempts = append(m.deliveries[delivery.ID].Attempts, *attempt)
+	m.deliveries[delivery.ID] = &stored
+	return nil
+}
+
+// dueNow makes every pending delivery due again, as if its backoff had passed
+func (m *mockWebhookRepository) dueNow() {
+	past := time.Now().Add(-time.Second)
+	for _, delivery := range m.deliveries {
+		if delivery.NextAttemptAt != nil {
+			delivery.NextAttemptAt = &past
+		}
+	}
+}
+
+var testRetryPolicy = domain.WebhookRetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour}
+
+func TestWebhookService_CreateWebhook(t *testing.T) {
+	service := NewWebhookService(newMockWebhookRepository(), http.DefaultClient, testRetryPolicy)
+
+	webhook, err := service.CreateWebhook(testUserID, &domain.CreateWebhookRequest{URL: "https://example.com/hook", Events: []string{"*"}})
+	if err != nil {
+		t.Fatalf("CreateWebhook() error = %v", err)
+	}
+	if len(webhook.Secret) < domain.MinWebhookSecretLength || !webhook.Active {
+		t.Errorf("Expected an active webhook with a generated secret, got %+v", webhook)
+	}
+
+	found, err := service.GetWebhook(testUserID, webhook.ID)
+	if err != nil {
+		t.Fatalf("GetWebhook() error = %v", err)
+	}
+	if found.Secret != "" {
+		t.Error("GetWebhook() should not reveal the secret")
+	}
+
+	if _, err := service.GetWebhook(testUserID+1, webhook.ID); !errors.Is(err, domain.ErrWebhookNotFound) {
+		t.Errorf("GetWebhook() for another user error = %v, want ErrWebhookNotFound", err)
+	}
+	if _, err := service.CreateWebhook(testUserID, &domain.CreateWebhookRequest{URL: "https://example.com", Events: []string{"nope"}}); err == nil {
+		t.Error("CreateWebhook() should reject unknown event types")
+	}
+}
+
+func TestWebhookService_Enqueue(t *testing.T) {
+	webhookRepo := newMockWebhookRepository()
+	service := NewWebhookService(webhookRepo, http.DefaultClient, testRetryPolicy)
+
+	tasks, _ := service.CreateWebhook(testUserID, &domain.CreateWebhookRequest{URL: "https://example.com/tasks", Events: []string{"task.*"}})
+	categories, _ := service.CreateWebhook(testUserID, &domain.CreateWebhookRequest{URL: "https://example.com/categories", Events: []string{"category.created"}})
+	paused, _ := service.CreateWebhook(testUserID, &domain.CreateWebhookRequest{URL: "https://example.com/paused", Events: []string{"*"}})
+	active := false
+	service.UpdateWebhook(testUserID, paused.ID, &domain.UpdateWebhookRequest{Active: &active})
+	service.CreateWebhook(testUserID+1, &domain.CreateWebhookRequest{URL: "https://example.com/other", Events: []string{"*"}})
+
+	if err := service.Enqueue(newEvent(testUserID, domain.EventTaskCreated, &domain.Task{ID: 1})); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	if len(webhookRepo.deliveries) != 1 {
+		t.Fatalf("Expected 1 delivery, got %d", len(webhookRepo.deliveries))
+	}
+	for _, delivery := range webhookRepo.deliveries {
+		if delivery.WebhookID != tasks.ID || delivery.Status != domain.DeliveryPending {
+			t.Errorf("Expected a pending delivery to webhook %d, got %+v", tasks.ID, delivery)
+		}
+	}
+
+	deliveries, _ := service.GetDeliveries(testUserID, categories.ID, "")
+	if len(deliveries) != 0 {
+		t.Errorf("category webhook should not receive task events, got %d deliveries", len(deliveries))
+	}
+}
+
+func TestWebhookService_DeliverDue(t *testing.T) {
+	var received []*http.Request
+	var bodies [][]byte
+	status := http.StatusInternalServerError
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, r)
+		bodies = append(bodies, body)
+		w.WriteHeader(status)
+		w.Write([]byte("receiver says hi"))
+	}))
+	defer receiver.Close()
+
+	webhookRepo := newMockWebhookRepository()
+	service := NewWebhookService(webhookRepo, receiver.Client(), testRetryPolicy)
+	webhook, _ := service.CreateWebhook(testUserID, &domain.CreateWebhookRequest{URL: receiver.URL, Secret: "0123456789abcdef", Events: []string{"*"}})
+	service.Enqueue(newEvent(testUserID, domain.EventTaskUpdated, &domain.Task{ID: 7, Title: "Report"}))
+
+	// The first attempt fails and is scheduled for a retry
+	if sent, err := service.DeliverDue(); err != nil || sent != 1 {
+		t.Fatalf("DeliverDue() = %d, %v, want 1 delivery", sent, err)
+	}
+	deliveries, _ := service.GetDeliveries(testUserID, webhook.ID, domain.DeliveryPending)
+	if len(deliveries) != 1 {
+		t.Fatalf("Expected the delivery to stay pending, got %+v", deliveries)
+	}
+	delivery := deliveries[0]
+	if delivery.AttemptCount != 1 || delivery.LastStatusCode == nil || *delivery.LastStatusCode != 500 || delivery.LastError == "" {
+		t.Errorf("Expected a recorded 500, got %+v", delivery)
+	}
+	if wait := time.Until(*delivery.NextAttemptAt); wait < 50*time.Second || wait > time.Minute {
+		t.Errorf("Expected the retry in about a minute, got %v", wait)
+	}
+
+	// Nothing is due until the backoff has passed
+	if sent, _ := service.DeliverDue(); sent != 0 {
+		t.Errorf("DeliverDue() sent %d deliveries before they were due", sent)
+	}
+
+	// The signature covers the timestamp and the exact body
+	req := received[0]
+	timestamp, _ := strconv.ParseInt(req.Header.Get(domain.WebhookTimestampHeader), 10, 64)
+	if want := domain.SignWebhookPayload("0123456789abcdef", timestamp, bodies[0]); req.Header.Get(domain.WebhookSignatureHeader) != want {
+		t.Errorf("signature = %q, want %q", req.Header.Get(domain.WebhookSignatureHeader), want)
+	}
+	if req.Header.Get(domain.WebhookEventHeader) != string(domain.EventTaskUpdated) {
+		t.Errorf("event header = %q", req.Header.Get(domain.WebhookEventHeader))
+	}
+	var event struct {
+		ID   string      `json:"id"`
+		Type string      `json:"type"`
+		Data domain.Task `json:"data"`
+	}
+	if err := json.Unmarshal(bodies[0], &event); err != nil || event.Data.ID != 7 || event.ID == "" {
+		t.Errorf("unexpected payload %s", bodies[0])
+	}
+
+	// The retry succeeds
+	status = http.StatusNoContent
+	webhookRepo.dueNow()
+	service.DeliverDue()
+
+	found, err := service.GetDelivery(testUserID, webhook.ID, delivery.ID)
+	if err != nil {
+		t.Fatalf("GetDelivery() error = %v", err)
+	}
+	if found.Status != domain.DeliverySucceeded || found.NextAttemptAt != nil || len(found.Attempts) != 2 {
+		t.Errorf("Expected a succeeded delivery with 2 attempts, got %+v", found)
+	}
+	if encoded, _ := json.Marshal(found); strings.Contains(string(encoded), "receiver says hi") {
+		t.Errorf("Expected the response body not to be recorded, got %s", encoded)
+	}
+}
+
+func TestWebhookService_RefusesRedirects(t *testing.T) {
+	var redirected bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer receiver.Close()
+
+	webhookRepo := newMockWebhookRepository()
+	service := NewWebhookService(webhookRepo, receiver.Client(), testRetryPolicy)
+	webhook, _ := service.CreateWebhook(testUserID, &domain.CreateWebhookRequest{URL: receiver.URL, Events: []string{"*"}})
+	service.Enqueue(newEvent(testUserID, domain.EventTaskCreated, &domain.Task{ID: 1}))
+	service.DeliverDue()
+
+	if redirected {
+		t.Error("Expected the redirect not to be followed")
+	}
+	deliveries, _ := service.GetDeliveries(testUserID, webhook.ID, domain.DeliveryPending)
+	if len(deliveries) != 1 || deliveries[0].LastError == "" {
+		t.Errorf("Expected a failed attempt, got %+v", deliveries)
+	}
+}
+
+func TestNewWebhookClient_RefusesInternalAddresses(t *testing.T) {
+	var reached bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+	defer receiver.Close()
+
+	webhookRepo := newMockWebhookRepository()
+	service := NewWebhookService(webhookRepo, NewWebhookClient(time.Second), testRetryPolicy)
+	webhook, _ := service.CreateWebhook(testUserID, &domain.CreateWebhookRequest{URL: receiver.URL, Events: []string{"*"}})
+	service.Enqueue(newEvent(testUserID, domain.EventTaskCreated, &domain.Task{ID: 1}))
+	service.DeliverDue()
+
+	if reached {
+		t.Error("Expected the loopback receiver not to be reached")
+	}
+	deliveries, _ := service.GetDeliveries(testUserID, webhook.ID, domain.DeliveryPending)
+	if len(deliveries) != 1 || deliveries[0].LastStatusCode != nil || !strings.Contains(deliveries[0].LastError, "not public") {
+		t.Errorf("Expected the connection to be refused, got %+v", deliveries)
+	}
+}
+
+func TestWebhookService_GivesUpAfterMaxAttempts(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer receiver.Close()
+
+	webhookRepo := newMockWebhookRepository()
+	service := NewWebhookService(webhookRepo, receiver.Client(), testRetryPolicy)
+	webhook, _ := service.CreateWebhook(testUserID, &domain.CreateWebhookRequest{URL: receiver.URL, Events: []string{"*"}})
+	service.Enqueue(newEvent(testUserID, domain.EventCategoryCreated, &domain.Category{ID: 1}))
+
+	for i := 0; i < testRetryPolicy.MaxAttempts+1; i++ {
+		service.DeliverDue()
+		webhookRepo.dueNow()
+	}
+
+	failed, _ := service.GetDeliveries(testUserID, webhook.ID, domain.DeliveryFailed)
+	if len(failed) != 1 || failed[0].AttemptCount != testRetryPolicy.MaxAttempts {
+		t.Fatalf("Expected one failed delivery after %d attempts, got %+v", testRetryPolicy.MaxAttempts, failed)
+	}
+
+	// Replaying queues a fresh copy of the event
+	replay, err := service.ReplayDelivery(testUserID, webhook.ID, failed[0].ID)
+	if err != nil {
+		t.Fatalf("ReplayDelivery() error = %v", err)
+	}
+	if replay.ID == failed[0].ID || replay.EventID != failed[0].EventID || replay.Status != domain.DeliveryPending || replay.AttemptCount != 0 {
+		t.Errorf("Expected a new pending delivery of %s, got %+v", failed[0].EventID, replay)
+	}
+
+	if _, err := service.ReplayDelivery(testUserID+1, webhook.ID, failed[0].ID); !errors.Is(err, domain.ErrWebhookNotFound) {
+		t.Errorf("ReplayDelivery() for another user error = %v, want ErrWebhookNotFound", err)
+	}
+}
+
+func TestWebhookService_GetDeliveries_InvalidStatus(t *testing.T) {
+	service := NewWebhookService(newMockWebhookRepository(), http.DefaultClient, testRetryPolicy)
+	webhook, _ := service.CreateWebhook(testUserID, &domain.CreateWebhookRequest{URL: "https://example.com", Events: []string{"*"}})
+
+	if _, err := service.GetDeliveries(testUserID, webhook.ID, "lost"); err == nil {
+		t.Error("GetDeliveries() should reject an unknown status")
+	}
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"fmt"
+	"task-manager/internal/domain"
+	"task-manager/internal/repo"
+	"time"
+)
+
+type WorklogService interface {
+	CreateWorklog(userID, taskID int64, req *domain.CreateWorklogRequest) (*domain.Worklog, error)
+	GetWorklogs(userID, taskID int64) ([]domain.Worklog, error)
+	UpdateWorklog(userID, taskID, id int64, req *domain.UpdateWorklogRequest) (*domain.Worklog, error)
+	DeleteWorklog(userID, taskID, id int64) error
+	// StartTimer starts a timer on the task unless the user already has one
+	// running
+	StartTimer(userID, taskID int64, req *domain.TimerRequest) (*domain.Worklog, error)
+	// StopTimer stops the user's timer running on the task
+	StopTimer(userID, taskID int64, req *domain.TimerRequest) (*domain.Worklog, error)
+	GetTimeReport(userID int64, window domain.StatsWindow) (*domain.TimeReport, error)
+}
+
+type worklogService struct {
+	worklogRepo repo.WorklogRepository
+	taskRepo    repo.TaskRepository
+}
+
+func NewWorklogService(worklogRepo repo.WorklogRepository, taskRepo repo.TaskRepository) WorklogService {
+	return &worklogService{
+		worklogRepo: worklogRepo,
+		taskRepo:    taskRepo,
+	}
+}
+
+func (s *worklogService) CreateWorklog(userID, taskID int64, req *domain.CreateWorklogRequest) (*domain.Worklog, error) {
+	now := time.Now().UTC()
+	start, end, err := req.Span(now)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := s.checkTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	worklog := &domain.Worklog{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: start,
+		Note:      req.Note,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	worklog.Finish(end)
+
+	if err := s.worklogRepo.Create(worklog); err != nil {
+		return nil, err
+	}
+
+	return worklog, nil
+}
+
+func (s *worklogService) GetWorklogs(userID, taskID int64) ([]domain.Worklog, error) {
+	if err := s.checkTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.worklogRepo.GetByTaskID(taskID)
+}
+
+func (s *worklogService) UpdateWorklog(userID, taskID, id int64, req *domain.UpdateWorklogRequest) (*domain.Worklog, error) {
+	if err := s.checkTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	worklog, err := s.worklogRepo.GetByID(taskID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if err := req.Apply(worklog, now); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	worklog.UpdatedAt = now
+
+	if err := s.worklogRepo.Update(worklog); err != nil {
+		return nil, err
+	}
+
+	return worklog, nil
+}
+
+func (s *worklogService) DeleteWorklog(userID, taskID, id int64) error {
+	if err := s.checkTask(userID, taskID); err != nil {
+		return err
+	}
+
+	return s.worklogRepo.Delete(taskID, id)
+}
+
+func (s *worklogService) StartTimer(userID, taskID int64, req *domain.TimerRequest) (*domain.Worklog, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := s.checkTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	worklog := &domain.Worklog{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: now,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if req.Note != nil {
+		worklog.Note = *req.Note
+	}
+
+	if err := s.worklogRepo.StartTimer(worklog); err != nil {
+		return nil, err
+	}
+
+	return worklog, nil
+}
+
+func (s *worklogService) StopTimer(userID, taskID int64, req *domain.TimerRequest) (*domain.Worklog, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := s.checkTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	worklog, err := s.worklogRepo.GetRunning(userID)
+	if err != nil {
+		return nil, err
+	}
+	if worklog.TaskID != taskID {
+		return nil, domain.ErrNoTimerRunning
+	}
+
+	now := time.Now().UTC()
+	worklog.Finish(now)
+	if req.Note != nil {
+		worklog.Note = *req.Note
+	}
+	worklog.UpdatedAt = now
+
+	if err := s.worklogRepo.Update(worklog); err != nil {
+		return nil, err
+	}
+
+	return worklog, nil
+}
+
+func (s *worklogService) GetTimeReport(userID int64, window domain.StatsWindow) (*domain.TimeReport, error) {
+	if err := window.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	return s.worklogRepo.GetTimeReport(userID, window)
+}
+
+// checkTask ensures the task exists and belongs to the user
+func (s *worklogService) checkTask(userID, taskID int64) error {
+	if _, err := s.taskRepo.GetByID(userID, taskID); err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+	return nil
+}
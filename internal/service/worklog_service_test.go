@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"task-manager/internal/domain"
+	"testing"
+	"time"
+)
+
+type mockWorklogRepository struct {
+	worklogs map[int64]*domain.Worklog
+	nextID   int64
+}
+
+func newMockWorklogRepository() *mockWorklogRepository {
+	return &mockWorklogRepository{
+		worklogs: make(map[int64]*domain.Worklog),
+		nextID:   1,
+	}
+}
+
+func (m *mockWorklogRepository) Create(worklog *domain.Worklog) error {
+	worklog.ID = m.nextID
+	m.nextID++
+	stored := *worklog
+	m.worklogs[worklog.ID] = &stored
+	return nil
+}
+
+func (m *mockWorklogRepository) StartTimer(worklog *domain.Worklog) error {
+	if _, err := m.GetRunning(worklog.UserID); err == nil {
+		return domain.ErrTimerRunning
+	}
+	return m.Create(worklog)
+}
+
+func (m *mockWorklogRepository) GetByID(taskID, id int64) (*domain.Worklog, error) {
+	worklog, ok := m.worklogs[id]
+	if !ok || worklog.TaskID != taskID {
+		return nil, domain.ErrWorklogNotFound
+	}
+	copied := *worklog
+	return &copied, nil
+}
+
+func (m *mockWorklogRepository) GetByTaskID(taskID int64) ([]domain.Worklog, error) {
+	worklogs := []domain.Worklog{}
+	for _, worklog := range m.worklogs {
+		if worklog.TaskID == taskID {
+			worklogs = append(worklogs, *worklog)
+		}
+	}
+	return worklogs, nil
+}
+
+func (m *mockWorklogRepository) GetRunning(userID int64) (*domain.Worklog, error) {
+	for _, worklog := range m.worklogs {
+		if worklog.UserID == userID && worklog.IsRunning() {
+			copied := *worklog
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNoTimerRunning
+}
+
+func (m *mockWorklogRepository) Update(worklog *domain.Worklog) error {
+	if _, ok := m.worklogs[worklog.ID]; !ok {
+		return domain.ErrWorklogNotFound
+	}
+	stored := *worklog
+	m.worklogs[worklog.ID] = &stored
+	return nil
+}
+
+func (m *mockWorklogRepository) Delete(taskID, id int64) error {
+	if _, err := m.GetByID(taskID, id); err != nil {
+		return err
+	}
+	delete(m.worklogs, id)
+	return nil
+}
+
+func (m *mockWorklogRepository) GetTimeReport(userID int64, window domain.StatsWindow) (*domain.TimeReport, error) {
+	report := domain.NewTimeReport(window)
+	for _, worklog := range m.worklogs {
+		if worklog.UserID == userID && !worklog.IsRunning() {
+			report.TotalSeconds += worklog.DurationSeconds
+		}
+	}
+	return report, nil
+}
+
+func TestWorklogService_Timer(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	tasks := NewTaskService(taskRepo, newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+	first, _ := tasks.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "First", Priority: domain.PriorityLow})
+	second, _ := tasks.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Second", Priority: domain.PriorityLow})
+
+	service := NewWorklogService(newMockWorklogRepository(), taskRepo)
+	timer, err := service.StartTimer(testUserID, first.ID, &domain.TimerRequest{})
+	if err != nil {
+		t.Fatalf("StartTimer() error = %v", err)
+	}
+	if !timer.IsRunning() {
+		t.Error("Expected a running timer")
+	}
+
+	if _, err := service.StartTimer(testUserID, second.ID, &domain.TimerRequest{}); !errors.Is(err, domain.ErrTimerRunning) {
+		t.Errorf("StartTimer() error = %v, want ErrTimerRunning", err)
+	}
+	if _, err := service.StopTimer(testUserID, second.ID, &domain.TimerRequest{}); !errors.Is(err, domain.ErrNoTimerRunning) {
+		t.Errorf("StopTimer() error = %v, want ErrNoTimerRunning", err)
+	}
+	if _, err := service.StartTimer(testUserID, 999, &domain.TimerRequest{}); err == nil {
+		t.Error("Expected starting a timer on a missing task to fail")
+	}
+
+	note := "Wrapped up"
+	stopped, err := service.StopTimer(testUserID, first.ID, &domain.TimerRequest{Note: &note})
+	if err != nil {
+		t.Fatalf("StopTimer() error = %v", err)
+	}
+	if stopped.IsRunning() || stopped.Note != note {
+		t.Errorf("Expected a stopped timer with its note, got %+v", stopped)
+	}
+
+	if _, err := service.StartTimer(testUserID, second.ID, &domain.TimerRequest{}); err != nil {
+		t.Errorf("Expected a new timer once the last one stopped, got %v", err)
+	}
+}
+
+func TestWorklogService_CreateWorklog(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	tasks := NewTaskService(taskRepo, newMockCategoryRepository(), domain.NewWorkflowStore(domain.DefaultWorkflow()), nil)
+	task, _ := tasks.CreateTask(testUserID, &domain.CreateTaskRequest{Title: "Billable", Priority: domain.PriorityLow})
+
+	service := NewWorklogService(newMockWorklogRepository(), taskRepo)
+	duration := int64(1800)
+	worklog, err := service.CreateWorklog(testUserID, task.ID, &domain.CreateWorklogRequest{DurationSeconds: &duration, Note: "Call"})
+	if err != nil {
+		t.Fatalf("CreateWorklog() error = %v", err)
+	}
+	if worklog.DurationSeconds != duration || worklog.StartedAt.Location() != time.UTC {
+		t.Errorf("Expected a 30 minute worklog stored in UTC, got %+v", worklog)
+	}
+
+	if _, err := service.CreateWorklog(testUserID, task.ID, &domain.CreateWorklogRequest{}); err == nil {
+		t.Error("Expected a worklog without time to be rejected")
+	}
+
+	report, err := service.GetTimeReport(testUserID, domain.NewStatsWindow(time.Now(), 7))
+	if err != nil || report.TotalSeconds != duration {
+		t.Errorf("GetTimeReport() = %+v, %v; want %d seconds", report, err, duration)
+	}
+	window := domain.NewStatsWindow(time.Now(), 7)
+	if _, err := service.GetTimeReport(testUserID, domain.StatsWindow{From: window.To, To: window.From}); err == nil {
+		t.Error("Expected a reversed window to be rejected")
+	}
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalBlobStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "blobs")
+	store, err := NewLocalBlobStore(dir)
+	if err != nil {
+		t.Fatalf("NewLocalBlobStore() error = %v", err)
+	}
+
+	n, err := store.Put("abc123", strings.NewReader("hello"))
+	if err != nil || n != 5 {
+		t.Fatalf("Put() = %d, %v; want 5 bytes", n, err)
+	}
+
+	r, err := store.Get("abc123")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if string(data) != "hello" {
+		t.Errorf("Get() = %q, want %q", data, "hello")
+	}
+
+	if err := store.Delete("abc123"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Get("abc123"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Get() after delete error = %v, want %v", err, ErrBlobNotFound)
+	}
+	if err := store.Delete("abc123"); err != nil {
+		t.Errorf("Delete() of missing blob error = %v, want nil", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("blob directory has %d leftover files", len(entries))
+	}
+}
+
+func TestLocalBlobStore_RejectsUnsafeKeys(t *testing.T) {
+	store, _ := NewLocalBlobStore(t.TempDir())
+
+	for _, key := range []string{"", "../escape", "a/b", ".hidden"} {
+		if _, err := store.Put(key, strings.NewReader("x")); !errors.Is(err, ErrInvalidBlobKey) {
+			t.Errorf("Put(%q) error = %v, want %v", key, err, ErrInvalidBlobKey)
+		}
+	}
+}
@@ -1,17 +1,17 @@
 package main
 
 import (
-	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"task-manager/internal/domain"
 	"task-manager/internal/repo"
-
-	_ "modernc.org/sqlite"
+	"task-manager/internal/service"
+	"time"
 )
 
 func main() {
-	db, err := sql.Open("sqlite", "tasks.db")
+	db, err := repo.Open("tasks.db")
 	if err != nil {
 		log.Fatalf("Failed to open database: %v", err)
 	}
@@ -22,6 +22,19 @@ func main() {
 	}
 
 	taskRepo := repo.NewTaskRepository(db)
+	userRepo := repo.NewUserRepository(db)
+
+	// Seed a demo user that owns the sample tasks
+	authService := service.NewAuthService(userRepo, "seed", time.Hour, nil)
+	demoUser := &domain.SignupRequest{Email: "demo@example.com", Password: "password123"}
+	if _, err := authService.Signup(demoUser); err != nil && !errors.Is(err, service.ErrEmailTaken) {
+		log.Fatalf("Failed to create demo user: %v", err)
+	}
+	user, err := userRepo.GetByEmail(demoUser.Email)
+	if err != nil {
+		log.Fatalf("Failed to load demo user: %v", err)
+	}
+	fmt.Printf("Demo user: %s / %s\n", demoUser.Email, demoUser.Password)
 
 	// Seed some sample tasks
 	sampleTasks := []domain.CreateTaskRequest{
@@ -54,6 +67,7 @@ func main() {
 
 	for i, taskReq := range sampleTasks {
 		task := &domain.Task{
+			UserID:      user.ID,
 			Title:       taskReq.Title,
 			Description: taskReq.Description,
 			Status:      domain.StatusTodo,
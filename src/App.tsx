@@ -1,24 +1,59 @@
 import React, { useState, useEffect } from 'react'
 import TaskList from './components/TaskList'
 import TaskForm from './components/TaskForm'
+import AuthForm from './components/AuthForm'
 import { Task, CreateTaskRequest } from './types/task'
-import { taskApi } from './api/client'
+import { authApi, taskApi } from './api/client'
+
+const PAGE_SIZE = 50
 
 function App() {
+  const [authenticated, setAuthenticated] = useState(authApi.isAuthenticated())
   const [tasks, setTasks] = useState<Task[]>([])
+  const [totalCount, setTotalCount] = useState(0)
+  const [nextCursor, setNextCursor] = useState<string | undefined>()
   const [loading, setLoading] = useState(true)
   const [error, setError] = useState<string | null>(null)
 
+  // A rejected token sends the user back to the login screen
   useEffect(() => {
-    loadTasks()
+    authApi.onUnauthorized(() => setAuthenticated(false))
+    return () => authApi.onUnauthorized(undefined)
   }, [])
 
+  useEffect(() => {
+    if (authenticated) {
+      loadTasks()
+    } else {
+      setTasks([])
+      setTotalCount(0)
+      setNextCursor(undefined)
+    }
+  }, [authenticated])
+
+  const handleLogin = async (email: string, password: string) => {
+    await authApi.login(email, password)
+    setAuthenticated(true)
+  }
+
+  const handleSignup = async (email: string, password: string) => {
+    await authApi.signup(email, password)
+    setAuthenticated(true)
+  }
+
+  const handleLogout = () => {
+    authApi.logout()
+    setAuthenticated(false)
+  }
+
   const loadTasks = async () => {
     try {
       setLoading(true)
       setError(null)
-      const fetchedTasks = await taskApi.getAllTasks()
-      setTasks(fetchedTasks)
+      const page = await taskApi.getTasksPage(PAGE_SIZE)
+      setTasks(page.tasks)
+      setTotalCount(page.total_count)
+      setNextCursor(page.next_cursor)
     } catch (err) {
       setError('Failed to load tasks')
       console.error('Error loading tasks:', err)
@@ -27,10 +62,24 @@ function App() {
     }
   }
 
+  const loadMoreTasks = async () => {
+    if (!nextCursor) return
+    try {
+      const page = await taskApi.getTasksPage(PAGE_SIZE, nextCursor)
+      setTasks(prev => [...prev, ...page.tasks])
+      setTotalCount(page.total_count)
+      setNextCursor(page.next_cursor)
+    } catch (err) {
+      setError('Failed to load tasks')
+      console.error('Error loading tasks:', err)
+    }
+  }
+
   const handleCreateTask = async (taskData: CreateTaskRequest) => {
     try {
       const newTask = await taskApi.createTask(taskData)
       setTasks(prev => [...prev, newTask])
+      setTotalCount(prev => prev + 1)
     } catch (err) {
       setError('Failed to create task')
       console.error('Error creating task:', err)
@@ -51,12 +100,24 @@ function App() {
     try {
       await taskApi.deleteTask(id)
       setTasks(prev => prev.filter(task => task.id !== id))
+      setTotalCount(prev => prev - 1)
     } catch (err) {
       setError('Failed to delete task')
       console.error('Error deleting task:', err)
     }
   }
 
+  if (!authenticated) {
+    return (
+      <div className="container">
+        <header className="header">
+          <h1>Task Manager</h1>
+        </header>
+        <AuthForm onLogin={handleLogin} onSignup={handleSignup} />
+      </div>
+    )
+  }
+
   if (loading) {
     return (
       <div className="container">
@@ -69,6 +130,9 @@ function App() {
     <div className="container">
       <header className="header">
         <h1>Task Manager</h1>
+        <button type="button" className="btn-link" onClick={handleLogout}>
+          Log out
+        </button>
         {error && <div className="error">{error}</div>}
       </header>
       
@@ -78,6 +142,9 @@ function App() {
           tasks={tasks}
           onUpdate={handleUpdateTask}
           onDelete={handleDeleteTask}
+          totalCount={totalCount}
+          hasMore={nextCursor !== undefined}
+          onLoadMore={loadMoreTasks}
         />
       </main>
     </div>
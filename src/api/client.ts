@@ -1,5 +1,5 @@
 import axios from 'axios'
-import { Task, CreateTaskRequest, UpdateTaskRequest } from '../types/task'
+import { Task, TaskPage, CreateTaskRequest, UpdateTaskRequest, AuthResponse } from '../types/task'
 
 const api = axios.create({
   baseURL: '/v1',
@@ -8,12 +8,66 @@ const api = axios.create({
   },
 })
 
+const TOKEN_KEY = 'token'
+
+api.interceptors.request.use((config) => {
+  const token = localStorage.getItem(TOKEN_KEY)
+  if (token) {
+    config.headers.Authorization = `Bearer ${token}`
+  }
+  return config
+})
+
+let onUnauthorized: (() => void) | undefined
+
+// A rejected token is dropped so the user is asked to log in again
+api.interceptors.response.use(undefined, (error) => {
+  if (error.response?.status === 401 && localStorage.getItem(TOKEN_KEY)) {
+    localStorage.removeItem(TOKEN_KEY)
+    onUnauthorized?.()
+  }
+  return Promise.reject(error)
+})
+
+export const authApi = {
+  isAuthenticated(): boolean {
+    return localStorage.getItem(TOKEN_KEY) !== null
+  },
+
+  // onUnauthorized registers the handler called when the stored token is
+  // rejected
+  onUnauthorized(handler: (() => void) | undefined): void {
+    onUnauthorized = handler
+  },
+
+  async signup(email: string, password: string): Promise<AuthResponse> {
+    const response = await api.post('/auth/signup', { email, password })
+    localStorage.setItem(TOKEN_KEY, response.data.token)
+    return response.data
+  },
+
+  async login(email: string, password: string): Promise<AuthResponse> {
+    const response = await api.post('/auth/login', { email, password })
+    localStorage.setItem(TOKEN_KEY, response.data.token)
+    return response.data
+  },
+
+  logout(): void {
+    localStorage.removeItem(TOKEN_KEY)
+  },
+}
+
 export const taskApi = {
   async getAllTasks(): Promise<Task[]> {
     const response = await api.get('/tasks')
     return response.data
   },
 
+  async getTasksPage(limit: number, cursor?: string): Promise<TaskPage> {
+    const response = await api.get('/tasks', { params: { limit, cursor } })
+    return response.data
+  },
+
   async getTask(id: number): Promise<Task> {
     const response = await api.get(`/tasks/${id}`)
     return response.data
@@ -0,0 +1,48 @@
+import React from 'react'
+import { render, screen, fireEvent, waitFor } from '@testing-library/react'
+import { describe, it, expect, vi } from 'vitest'
+import AuthForm from '../components/AuthForm'
+
+describe('AuthForm', () => {
+  it('logs in with the entered credentials', async () => {
+    const mockOnLogin = vi.fn().mockResolvedValue(undefined)
+    const mockOnSignup = vi.fn()
+    render(<AuthForm onLogin={mockOnLogin} onSignup={mockOnSignup} />)
+
+    fireEvent.change(screen.getByLabelText(/email/i), { target: { value: 'alice@example.com' } })
+    fireEvent.change(screen.getByLabelText(/password/i), { target: { value: 'secret123' } })
+    fireEvent.click(screen.getByRole('button', { name: /^log in$/i }))
+
+    await waitFor(() => {
+      expect(mockOnLogin).toHaveBeenCalledWith('alice@example.com', 'secret123')
+    })
+    expect(mockOnSignup).not.toHaveBeenCalled()
+  })
+
+  it('signs up after switching modes', async () => {
+    const mockOnLogin = vi.fn()
+    const mockOnSignup = vi.fn().mockResolvedValue(undefined)
+    render(<AuthForm onLogin={mockOnLogin} onSignup={mockOnSignup} />)
+
+    fireEvent.click(screen.getByRole('button', { name: /sign up/i }))
+    fireEvent.change(screen.getByLabelText(/email/i), { target: { value: 'bob@example.com' } })
+    fireEvent.change(screen.getByLabelText(/password/i), { target: { value: 'secret123' } })
+    fireEvent.click(screen.getByRole('button', { name: /^sign up$/i }))
+
+    await waitFor(() => {
+      expect(mockOnSignup).toHaveBeenCalledWith('bob@example.com', 'secret123')
+    })
+    expect(mockOnLogin).not.toHaveBeenCalled()
+  })
+
+  it('shows an error when login fails', async () => {
+    const mockOnLogin = vi.fn().mockRejectedValue(new Error('401'))
+    render(<AuthForm onLogin={mockOnLogin} onSignup={vi.fn()} />)
+
+    fireEvent.change(screen.getByLabelText(/email/i), { target: { value: 'alice@example.com' } })
+    fireEvent.change(screen.getByLabelText(/password/i), { target: { value: 'wrong' } })
+    fireEvent.click(screen.getByRole('button', { name: /^log in$/i }))
+
+    expect(await screen.findByText(/invalid email or password/i)).toBeInTheDocument()
+  })
+})
@@ -0,0 +1,78 @@
+import React, { useState } from 'react'
+
+interface AuthFormProps {
+  onLogin: (email: string, password: string) => Promise<void>
+  onSignup: (email: string, password: string) => Promise<void>
+}
+
+const AuthForm: React.FC<AuthFormProps> = ({ onLogin, onSignup }) => {
+  const [mode, setMode] = useState<'login' | 'signup'>('login')
+  const [email, setEmail] = useState('')
+  const [password, setPassword] = useState('')
+  const [error, setError] = useState<string | null>(null)
+  const [submitting, setSubmitting] = useState(false)
+
+  const isLogin = mode === 'login'
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault()
+    if (!email.trim() || !password) return
+    try {
+      setSubmitting(true)
+      setError(null)
+      await (isLogin ? onLogin : onSignup)(email.trim(), password)
+    } catch (err) {
+      setError(isLogin ? 'Invalid email or password' : 'Failed to create account')
+      console.error('Error authenticating:', err)
+    } finally {
+      setSubmitting(false)
+    }
+  }
+
+  const toggleMode = () => {
+    setMode(isLogin ? 'signup' : 'login')
+    setError(null)
+  }
+
+  return (
+    <form className="task-form auth-form" onSubmit={handleSubmit}>
+      <h2>{isLogin ? 'Log In' : 'Sign Up'}</h2>
+      {error && <div className="error">{error}</div>}
+
+      <div className="form-group">
+        <label htmlFor="email">Email</label>
+        <input
+          type="email"
+          id="email"
+          name="email"
+          value={email}
+          onChange={e => setEmail(e.target.value)}
+          required
+          placeholder="you@example.com"
+        />
+      </div>
+
+      <div className="form-group">
+        <label htmlFor="password">Password</label>
+        <input
+          type="password"
+          id="password"
+          name="password"
+          value={password}
+          onChange={e => setPassword(e.target.value)}
+          required
+          placeholder="Enter password"
+        />
+      </div>
+
+      <button type="submit" className="btn btn-primary" disabled={submitting}>
+        {isLogin ? 'Log In' : 'Sign Up'}
+      </button>
+      <button type="button" className="btn-link" onClick={toggleMode}>
+        {isLogin ? 'Need an account? Sign up' : 'Have an account? Log in'}
+      </button>
+    </form>
+  )
+}
+
+export default AuthForm
@@ -5,9 +5,12 @@ interface TaskListProps {
   tasks: Task[]
   onUpdate: (id: number, updates: Partial<Task>) => void
   onDelete: (id: number) => void
+  totalCount?: number
+  hasMore?: boolean
+  onLoadMore?: () => void
 }
 
-const TaskList: React.FC<TaskListProps> = ({ tasks, onUpdate, onDelete }) => {
+const TaskList: React.FC<TaskListProps> = ({ tasks, onUpdate, onDelete, totalCount, hasMore, onLoadMore }) => {
   const getPriorityClass = (priority: string) => {
     return `priority-${priority}`
   }
@@ -37,7 +40,7 @@ const TaskList: React.FC<TaskListProps> = ({ tasks, onUpdate, onDelete }) => {
 
   return (
     <div className="task-list">
-      <h2>Tasks ({tasks.length})</h2>
+      <h2>Tasks ({totalCount ?? tasks.length})</h2>
       
       <div className="tasks-grid">
         {tasks.map(task => (
@@ -97,6 +100,12 @@ const TaskList: React.FC<TaskListProps> = ({ tasks, onUpdate, onDelete }) => {
           </div>
         ))}
       </div>
+
+      {hasMore && onLoadMore && (
+        <button className="btn load-more" onClick={onLoadMore}>
+          Load more
+        </button>
+      )}
     </div>
   )
 }
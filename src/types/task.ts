@@ -20,3 +20,22 @@ export interface UpdateTaskRequest {
   status?: 'todo' | 'doing' | 'done'
   priority?: 'low' | 'medium' | 'high' | 'critical'
 }
+
+export interface User {
+  id: number
+  email: string
+  created_at: string
+  updated_at: string
+}
+
+export interface AuthResponse {
+  token: string
+  expires_at: string
+  user: User
+}
+
+export interface TaskPage {
+  tasks: Task[]
+  next_cursor?: string
+  total_count: number
+}